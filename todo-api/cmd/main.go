@@ -1,18 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"time"
+	_ "time/tzdata" // time zones of clients, whatever the host has installed
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	"todo-api/internal/controllers"
 	"todo-api/internal/models"
+	"todo-api/internal/notify"
 	"todo-api/internal/repositories"
 	"todo-api/internal/services"
 	"todo-api/internal/transport"
+	"todo-api/migrations"
+	"todo-api/pkg/clock"
 	"todo-api/pkg/config"
 	"todo-api/pkg/database"
+	"todo-api/pkg/migrator"
 )
 
 func main() {
@@ -22,27 +31,184 @@ func main() {
 		log.Fatal("Config error:", err)
 	}
 
-	db, err := database.Connect(cfg)
-	if err != nil {
-		log.Fatal("DB error:", err)
-	}
+	ctx := context.Background()
+	migrateCmd := len(os.Args) > 1 && os.Args[1] == "migrate"
 
-	if err = db.AutoMigrate(&models.Task{}); err != nil {
-		log.Fatal("Migration failed:", err)
-	}
+	var repo repositories.TaskRepository
+	var tagRepo repositories.TagRepository
+	var projectRepo repositories.ProjectRepository
+	var reminderRepo repositories.ReminderRepository
+	var webhookRepo repositories.WebhookRepository
+	var outboxRepo repositories.OutboxRepository
+	var historyRepo repositories.HistoryRepository
+	var settingsRepo repositories.SettingsRepository
+	var eventBus repositories.EventBus
+	if cfg.DB.Driver == config.DriverMemory {
+		if migrateCmd {
+			log.Fatal("The memory driver has no schema to migrate")
+		}
+		log.Println("Using in-memory storage, data will be lost on exit")
+		store := repositories.NewMemoryStore(clock.Real())
+		repo = repositories.NewTaskRepositoryMemory(store)
+		tagRepo = repositories.NewTagRepositoryMemory(store)
+		projectRepo = repositories.NewProjectRepositoryMemory(store)
+		reminderRepo = repositories.NewReminderRepositoryMemory(store)
+		webhookRepo = repositories.NewWebhookRepositoryMemory(store)
+		outboxRepo = repositories.NewOutboxRepositoryMemory(store)
+		historyRepo = repositories.NewHistoryRepositoryMemory(store)
+		settingsRepo = repositories.NewSettingsRepositoryMemory(store)
+		eventBus = repositories.NewEventBusMemory()
+	} else {
+		db, err := database.Connect(cfg, clock.Real())
+		if err != nil {
+			log.Fatal("DB error:", err)
+		}
+
+		m, err := newMigrator(db, cfg.DB.Driver)
+		if err != nil {
+			log.Fatal("Migrations error:", err)
+		}
+
+		if migrateCmd {
+			if err = runMigrate(ctx, m, os.Args[2:]); err != nil {
+				log.Fatal("Migration failed:", err)
+			}
+			return
+		}
+
+		if cfg.DB.AutoMigrate {
+			applied, err := m.Up(ctx)
+			if err != nil {
+				log.Fatal("Migration failed:", err)
+			}
+			logMigrations("Applied", applied)
+		}
+
+		if err = m.Check(ctx); err != nil {
+			log.Fatal("Refusing to start, run `migrate up` first:", err)
+		}
 
-	log.Println("Migrations completed")
+		log.Println("Schema is up to date")
+
+		repo = repositories.NewTaskRepositoryImpl(db)
+		tagRepo = repositories.NewTagRepositoryImpl(db)
+		projectRepo = repositories.NewProjectRepositoryImpl(db)
+		reminderRepo = repositories.NewReminderRepositoryImpl(db)
+		webhookRepo = repositories.NewWebhookRepositoryImpl(db)
+		outboxRepo = repositories.NewOutboxRepositoryImpl(db)
+		historyRepo = repositories.NewHistoryRepositoryImpl(db)
+		settingsRepo = repositories.NewSettingsRepositoryImpl(db)
+		if cfg.DB.Driver == config.DriverPostgres {
+			eventBus = repositories.NewEventBusPostgres(db)
+		} else {
+			// A SQLite file is served by one process only.
+			eventBus = repositories.NewEventBusMemory()
+		}
+	}
 
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
 
-	repo := repositories.NewTaskRepositoryImpl(db)
-	service := services.NewTaskServiceImpl(repo)
-	controller := controllers.NewTaskController(service, logger)
+	webhookService := services.NewWebhookServiceImpl(webhookRepo, clock.Real(), logger)
+	dispatcher := services.NewWebhookDispatcher(webhookRepo, nil, clock.Real(), logger,
+		services.SchedulerConfig{
+			Interval:    cfg.Webhooks.PollInterval,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Backoff:     cfg.Webhooks.RetryBackoff,
+			Timeout:     cfg.Webhooks.Timeout,
+		},
+	)
+	go dispatcher.Run(ctx)
+	webhookController := controllers.NewWebhookController(webhookService, logger)
+
+	relay := services.NewOutboxRelay(outboxRepo, clock.Real(), logger,
+		services.SchedulerConfig{
+			Interval: cfg.Outbox.PollInterval,
+			Backoff:  cfg.Outbox.RetryBackoff,
+		},
+		cfg.Outbox.Retention,
+	)
+	streamService := services.NewStreamServiceImpl(eventBus, outboxRepo, logger, cfg.Stream.Backlog)
+	relay.Register("webhooks", webhookService)
+	relay.Register("stream", streamService)
+	go relay.Run(ctx)
+	go streamService.Run(ctx)
+	adminController := controllers.NewAdminController(services.NewOutboxServiceImpl(outboxRepo, clock.Real()), logger)
+
+	service := services.NewTaskServiceImpl(repo,
+		services.WithMaxLimit(cfg.ListMaxLimit),
+		services.WithMaxDepth(cfg.SubtaskMaxDepth),
+		services.WithMaxBatch(cfg.BatchMaxSize),
+		services.WithCompletionPolicy(services.CompletionPolicy(cfg.SubtaskCompletion)),
+		services.WithHistory(historyRepo),
+	)
+	if cfg.Trash.RetentionDays > 0 {
+		purger := services.NewTrashPurger(repo, clock.Real(), logger,
+			services.SchedulerConfig{Interval: cfg.Trash.PurgeInterval},
+			time.Duration(cfg.Trash.RetentionDays)*24*time.Hour,
+		)
+		go purger.Run(ctx)
+	}
+	settingsService := services.NewSettingsServiceImpl(settingsRepo)
+	settingsController := controllers.NewSettingsController(settingsService, logger)
+
+	controller := controllers.NewTaskController(service, logger,
+		controllers.WithRequireIfMatch(cfg.RequireIfMatch),
+		controllers.WithDefaultTimeZone(cfg.TimeZone),
+		controllers.WithUserTimeZones(settingsService),
+		controllers.WithStream(streamService, cfg.Stream.Heartbeat),
+		controllers.WithStreamOrigins(cfg.Stream.AllowedOrigins),
+	)
 
-	router := transport.SetupRouter(controller, logger)
+	tagController := controllers.NewTagController(services.NewTagServiceImpl(tagRepo), logger)
+
+	projectController := controllers.NewProjectController(services.NewProjectServiceImpl(projectRepo), logger)
+
+	scheduler := services.NewReminderScheduler(reminderRepo, repo, newNotifiers(cfg, logger), clock.Real(), logger,
+		services.SchedulerConfig{
+			Interval:    cfg.Reminders.PollInterval,
+			MaxAttempts: cfg.Reminders.MaxAttempts,
+			Backoff:     cfg.Reminders.RetryBackoff,
+		},
+	)
+	go scheduler.Run(ctx)
+
+	reminderService := services.NewReminderServiceImpl(reminderRepo, repo, clock.Real(), scheduler.Channels()...)
+	reminderController := controllers.NewReminderController(reminderService, logger)
+
+	router := transport.SetupRouter(controller, tagController, projectController, reminderController, webhookController, adminController,
+		settingsController, logger)
 
 	if err = router.Run(fmt.Sprintf(":%s", cfg.Port)); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// newNotifiers returns a notifier for every configured reminder channel.
+// The log is always one of them.
+func newNotifiers(cfg *config.Config, logger *zap.Logger) map[string]notify.Notifier {
+	notifiers := map[string]notify.Notifier{
+		models.ChannelLog: notify.NewLogNotifier(logger),
+	}
+	if cfg.Reminders.WebhookURL != "" {
+		notifiers[models.ChannelWebhook] = notify.NewWebhookNotifier(cfg.Reminders.WebhookURL, nil)
+	}
+	if cfg.SMTP.Host != "" {
+		notifiers[models.ChannelEmail] = notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+	}
+	return notifiers
+}
+
+func newMigrator(db *gorm.DB, driver string) (*migrator.Migrator, error) {
+	source, err := migrations.Source(driver)
+	if err != nil {
+		return nil, err
+	}
+	return migrator.New(db, source)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"todo-api/pkg/migrator"
+)
+
+const migrateUsage = "usage: todo-app migrate up|down|status|to <version>"
+
+func runMigrate(ctx context.Context, m *migrator.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		logMigrations("Applied", applied)
+		return err
+	case "down":
+		reverted, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			log.Println("Nothing to revert")
+			return nil
+		}
+		log.Printf("Reverted %d_%s", reverted.Version, reverted.Name)
+		return nil
+	case "to":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		migrated, err := m.To(ctx, uint(version))
+		logMigrations("Migrated", migrated)
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+func logMigrations(verb string, migrations []migrator.Migration) {
+	if len(migrations) == 0 {
+		log.Println("Schema is up to date")
+		return
+	}
+	for _, m := range migrations {
+		log.Printf("%s %d_%s", verb, m.Version, m.Name)
+	}
+}
@@ -15,66 +15,186 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/tasks/create": {
+        "/api/v1/admin/outbox": {
+            "get": {
+                "description": "Every task change records its events in the outbox in the same transaction; the relay publishes them\nto the consumers, webhooks among them. Shows how many events wait, how many of them failed last time,\nand how old the oldest one is.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Show the outbox relay lag",
+                "responses": {
+                    "200": {
+                        "description": "Outbox status retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects": {
+            "get": {
+                "description": "Get projects ordered by name, with the counts of their open and completed tasks",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List projects",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include archived projects",
+                        "name": "include_archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Projects retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Add a new task to the system",
+                "description": "Add a project that tasks can be put into with project_id",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "tasks"
+                    "projects"
                 ],
-                "summary": "Create a new task",
+                "summary": "Create a new project",
                 "parameters": [
                     {
-                        "description": "Task creation data",
+                        "description": "Project creation data",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.CreateTaskRequest"
+                            "$ref": "#/definitions/todo-api_internal_dto.CreateProjectRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Task created successfully",
+                        "description": "Project created successfully",
                         "schema": {
                             "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created project"
+                            }
                         }
                     },
                     "400": {
                         "description": "Invalid input data",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/delete/{id}": {
+        "/api/v1/projects/{id}": {
+            "get": {
+                "description": "Get a project with the counts of its open and completed tasks",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get project by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
             "delete": {
-                "description": "Delete a task by ID",
+                "description": "Delete a project. Its tasks are kept outside any project, each getting a new version and a task.updated event;\narchive the project to hide them instead.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "tasks"
+                    "projects"
                 ],
-                "summary": "Delete a task",
+                "summary": "Delete a project",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -82,206 +202,3574 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Task deleted successfully"
+                        "description": "Project deleted successfully"
                     },
                     "400": {
                         "description": "Invalid ID format",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/tasks/get/{id}": {
-            "get": {
-                "description": "Get a single task by its ID",
-                "produces": [
+            },
+            "patch": {
+                "description": "Update the given fields of a project. Archiving hides its tasks from default task listings without deleting them.",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
                 "tags": [
-                    "tasks"
+                    "projects"
                 ],
-                "summary": "Get task by ID",
+                "summary": "Update a project",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Project update data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.UpdateProjectRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task retrieved successfully",
+                        "description": "Project updated successfully",
                         "schema": {
                             "$ref": "#/definitions/todo-api_internal_dto.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid input data",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "No fields to update",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/list": {
+        "/api/v1/settings": {
             "get": {
-                "description": "Get a list of tasks with optional filtering",
+                "description": "Settings belong to the actor named in X-Actor. An actor who has saved none gets the defaults.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "tasks"
+                    "settings"
                 ],
-                "summary": "List all tasks",
+                "summary": "Get the settings of the actor",
                 "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "Filter by completion status",
-                        "name": "completed",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by start date (format: 2006-01-02)",
-                        "name": "date_from",
-                        "in": "query"
+                        "description": "Who the settings belong to",
+                        "name": "X-Actor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settings retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Filter by end date (format: 2006-01-02)",
-                        "name": "date_to",
-                        "in": "query"
+                    "422": {
+                        "description": "No actor named",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
                     },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "The time zone is used for the dates of task requests that name none in the tz parameter or the\nTime-Zone header. An empty time zone falls back to the server default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Replace the settings of the actor",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Limit number of results (default: 10)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Who the settings belong to",
+                        "name": "X-Actor",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Settings",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.UpdateSettingsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Tasks retrieved successfully",
+                        "description": "Settings updated successfully",
                         "schema": {
                             "$ref": "#/definitions/todo-api_internal_dto.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid filter parameters",
+                        "description": "Invalid input data",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "No actor named, or an unknown time zone",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     }
                 }
             }
         },
-        "/tasks/update/{id}": {
-            "put": {
-                "description": "Update an existing task by ID",
+        "/api/v1/tags": {
+            "get": {
+                "description": "Get every tag ordered by name",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List all tags",
+                "responses": {
+                    "200": {
+                        "description": "Tags retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a tag that can be attached to tasks by name. Names are unique.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "tasks"
+                    "tags"
                 ],
-                "summary": "Update a task",
+                "summary": "Create a new tag",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Task ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Task update data",
+                        "description": "Tag creation data",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.UpdateTaskRequest"
+                            "$ref": "#/definitions/todo-api_internal_dto.CreateTagRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Task updated successfully",
+                    "201": {
+                        "description": "Tag created successfully",
                         "schema": {
                             "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created tag"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid input data or no fields to update",
+                        "description": "Invalid input data",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "A tag with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "todo-api_internal_dto.CreateTaskRequest": {
-            "type": "object",
-            "required": [
-                "date",
-                "title"
-            ],
-            "properties": {
-                "date": {
-                    "description": "\"2006-01-02\"",
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string",
-                    "maxLength": 1000
-                },
-                "title": {
-                    "type": "string",
-                    "maxLength": 255
-                }
-            }
         },
-        "todo-api_internal_dto.Response": {
-            "description": "Стандартная модель ответа сервера на запрос",
+        "/api/v1/tags/{id}": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Get tag by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a tag and detach it from all tasks, each getting a new version and a task.updated event",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tag deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Rename or recolor a tag. Tasks keep the tag under its new name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Update a tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag update data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "A tag with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "No fields to update",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks": {
+            "get": {
+                "description": "Get a page of tasks with optional filtering. The default order is date, then the manual order within the day (rank), or relevance when searching; ID always breaks ties.\nPages are addressed either by an opaque cursor from meta.next_cursor/meta.prev_cursor or by limit/offset.\nWith both date_from and date_to, recurring tasks are expanded into their occurrences in the range, each with series_id and occurrence_date set.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List all tasks",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by completion status",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (format: 2006-01-02)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date (format: 2006-01-02)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit number of results (default: 10, capped by the server maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination, ignored when cursor is set",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "date,-priority,title",
+                        "description": "Comma-separated sort fields, '-' for descending (date, rank, priority, title, completed, created_at, updated_at, id, relevance)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "high,urgent",
+                        "description": "Comma-separated priorities to include (none, low, medium, high, urgent)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "work,urgent",
+                        "description": "Comma-separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match tasks with any (default) or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include tasks of archived projects",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent task ID, or none for top-level tasks",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Nest subtasks under their parents; lists top-level tasks unless parent_id is set",
+                        "name": "tree",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only tasks with (true) or without (false) an open blocker",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full-text search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return a snippet with \u003cmark\u003e highlights for each match (needs q)",
+                        "name": "highlight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the date range is taken in, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tasks retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/todo-api_internal_dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "meta": {
+                                            "$ref": "#/definitions/todo-api_internal_dto.PageMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, next and prev pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Too many recurring tasks or occurrences in the date range to expand",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new task to the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create a new task",
+                "parameters": [
+                    {
+                        "description": "Task creation data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.CreateTaskRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Task created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Task version"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Task date is in the past or invalid recurrence",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/batch/complete": {
+            "post": {
+                "description": "Mark several tasks completed, named by ids in the body or matched by the filter parameters of the task list,\ne.g. date_from=2026-10-15\u0026date_to=2026-10-15 for everything on a day. Works like a batch update setting completed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Complete tasks in a batch",
+                "parameters": [
+                    {
+                        "description": "Tasks to complete and the batch mode",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.BatchSelectRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Complete the tasks even while blockers are open",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Select tasks by completion status",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks from this date (format: 2006-01-02)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks up to this date (format: 2006-01-02)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks with these comma-separated priorities",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks with these comma-separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match tasks with any (default) or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks of a project, or none for tasks outside any project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select subtasks of a task, or none for top-level tasks",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Select tasks with (true) or without (false) an open blocker",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks by full-text search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the date range is taken in, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch processed, see data for the outcome of every task",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data, or tasks selected by neither or both ids and filter",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Too many tasks for one batch",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/batch/create": {
+            "post": {
+                "description": "Create several tasks at once, each checked like a single create.\nAn atomic batch (default) creates all of them or none; a best_effort one creates every task that passes its checks.\nThe result lists the outcome of every task in request order: succeeded with the task, failed with a problem, or skipped when an atomic batch failed on another one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create tasks in a batch",
+                "parameters": [
+                    {
+                        "description": "Tasks to create and the batch mode",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.BatchCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch processed, see data for the outcome of every task",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Too many tasks for one batch",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/batch/delete": {
+            "post": {
+                "description": "Move several tasks to the trash, named by ids in the body or matched by the filter parameters of the task list.\nAn atomic batch (default) deletes all of them or none; a best_effort one deletes every task it finds.\nA date filter fails the recurring tasks it matches with 422; select a series by id to delete it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete tasks in a batch",
+                "parameters": [
+                    {
+                        "description": "Tasks to delete and the batch mode",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.BatchSelectRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "cascade",
+                            "reparent"
+                        ],
+                        "type": "string",
+                        "description": "Delete subtasks too (cascade) or move them up to the nearest parent that stays (reparent, default)",
+                        "name": "subtasks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Select tasks by completion status",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks from this date (format: 2006-01-02)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks up to this date (format: 2006-01-02)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks with these comma-separated priorities",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks with these comma-separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match tasks with any (default) or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks of a project, or none for tasks outside any project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select subtasks of a task, or none for top-level tasks",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Select tasks with (true) or without (false) an open blocker",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks by full-text search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the date range is taken in, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch processed, see data for the outcome of every task",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data, or tasks selected by neither or both ids and filter",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Too many tasks for one batch",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/batch/update": {
+            "post": {
+                "description": "Apply the same changes to several tasks, named by ids in the body or matched by the filter parameters of the task list, e.g.\ncompleted=false\u0026date_from=2026-10-15\u0026date_to=2026-10-15 with changes {\"date\": \"2026-10-16\"} moves the open tasks of a day to the next one.\nEach task is checked like a single update, without versions. An atomic batch (default) updates all of them or none;\na best_effort one updates every task that passes its checks. A filter may match at most as many tasks as a batch can hold.\nA date filter fails the recurring tasks it matches with 422; select a series by id to change it as a whole.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update tasks in a batch",
+                "parameters": [
+                    {
+                        "description": "Tasks to update, the batch mode and the changes",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.BatchUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Complete the tasks even while blockers are open",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Select tasks by completion status",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks from this date (format: 2006-01-02)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks up to this date (format: 2006-01-02)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks with these comma-separated priorities",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks with these comma-separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match tasks with any (default) or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks of a project, or none for tasks outside any project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select subtasks of a task, or none for top-level tasks",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Select tasks with (true) or without (false) an open blocker",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Select tasks by full-text search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the date range is taken in, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch processed, see data for the outcome of every task",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data, or tasks selected by neither or both ids and filter",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "No fields to update, or too many tasks for one batch",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/stream": {
+            "get": {
+                "description": "Server-sent events for the tasks created, updated, deleted and restored from the trash from now on, whichever instance of the service made the change.\nTakes the filters of the task list; paging, sort, tree and highlight do not apply. An updated task that does not match the filter comes as task.left, so that a client showing it can drop it.\nEvery event has its ID as id, its type as event and a dto.StreamEvent as data. Reconnecting with Last-Event-ID replays the events missed since; when they are no longer known, a reset event tells the client to reload its tasks.",
+                "produces": [
+                    "text/event-stream",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Stream task changes",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by completion status",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (format: 2006-01-02)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date (format: 2006-01-02)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "high,urgent",
+                        "description": "Comma-separated priorities to include (none, low, medium, high, urgent)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "work,urgent",
+                        "description": "Comma-separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match tasks with any (default) or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include tasks of archived projects",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent task ID, or none for top-level tasks",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only tasks with (true) or without (false) an open blocker",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the date range is taken in, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received, to resume after it",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same as Last-Event-ID, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.StreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/stream/ws": {
+            "get": {
+                "description": "The events of GET /api/v1/tasks/stream as JSON text messages, one dto.StreamEvent each, for clients that prefer a WebSocket.\nTakes the same filters. Resume with last_event_id; a message of type reset tells the client to reload its tasks. When the server drops the stream it closes the socket with code 1013 (try again later), and the client reconnects with the ID of the last event it got. Messages from the client are ignored.",
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Stream task changes over a WebSocket",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by completion status",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (format: 2006-01-02)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date (format: 2006-01-02)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "high,urgent",
+                        "description": "Comma-separated priorities to include (none, low, medium, high, urgent)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "work,urgent",
+                        "description": "Comma-separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match tasks with any (default) or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include tasks of archived projects",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent task ID, or none for top-level tasks",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only tasks with (true) or without (false) an open blocker",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days of date_from and date_to are taken in",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received, to resume after it",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching to the WebSocket protocol",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.StreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters or not a WebSocket handshake",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "The page's origin is not allowed to open the stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/trash": {
+            "get": {
+                "description": "Get a page of the tasks in the trash, with the filters, sort and pagination of the task list.\nRecurring tasks are not expanded and stored occurrences are left out: they are restored along with their series.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List deleted tasks",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by completion status",
+                        "name": "completed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (format: 2006-01-02)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date (format: 2006-01-02)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit number of results (default: 10, capped by the server maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination, ignored when cursor is set",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "-updated_at",
+                        "description": "Comma-separated sort fields, '-' for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated priorities to include (none, low, medium, high, urgent)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Match tasks with any (default) or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project ID, or none for tasks outside any project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include tasks of archived projects",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent task ID, or none for top-level tasks",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full-text search in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the date range is taken in, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted tasks retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/todo-api_internal_dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "meta": {
+                                            "$ref": "#/definitions/todo-api_internal_dto.PageMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the first, next and prev pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}": {
+            "get": {
+                "description": "Get a single task by its ID.\nWith as_of, the task as it was at that moment, rebuilt from its history; it may be in the trash now.\nFields the history does not follow (updated_at, subtask counters, blocked) are as they are now, and there is no ETag.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-03-01T09:00:00Z",
+                        "description": "RFC 3339 timestamp to see the task at",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Task version, to be sent back in If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or as_of",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found, or it did not exist or was in the trash at as_of",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "The task history is not available for as_of",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace all writable fields of a task with the body, checked by the rules of task creation.\nFields left out take their defaults: no description, tags, project, parent, recurrence or due time, priority none, not completed.\nOnly the fields that differ are written, checked like any update; a body equal to the task changes nothing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Replace a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the task being replaced",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Complete the task even while blockers are open",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "The task as it should be",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.TaskDocument"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Task is blocked by open tasks",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid recurrence, parent or tags",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a task by ID. It goes to the trash, from where it can be restored, unless permanent=true;\nthat also deletes a task already in the trash for good.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the task being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "cascade",
+                            "reparent"
+                        ],
+                        "type": "string",
+                        "description": "Delete subtasks too (cascade) or move them up to the task's parent (reparent, default)",
+                        "name": "subtasks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete the task for good instead of moving it to the trash",
+                        "name": "permanent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Task deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update the given fields of an existing task by ID. The body depends on the Content-Type:\napplication/json lists the fields to change, as in dto.UpdateTaskRequest;\napplication/merge-patch+json is a JSON Merge Patch (RFC 7396) and application/json-patch+json a JSON Patch (RFC 6902)\nof the task as a dto.TaskDocument, where null in a merge patch clears a field and test operations must hold.\nA patched task is checked by the rules of task creation.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the task being updated",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Complete the task even while blockers are open",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Task update data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.UpdateTaskRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data or malformed patch",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Task is blocked by open tasks, or the patch does not apply to the task",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        },
+                        "headers": {
+                            "Accept-Patch": {
+                                "type": "string",
+                                "description": "Content types PATCH takes"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "No fields to update, or the patched task is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/dependencies": {
+            "get": {
+                "description": "Get the tasks that must be completed before this one, open or not.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List the blockers of a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Blockers retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/dependencies/{blocker_id}": {
+            "put": {
+                "description": "The task cannot be completed while the blocker is open. Adding an existing dependency does nothing;\none that would make a task wait for itself, directly or through other tasks, is rejected.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Make a task wait for another",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the blocking task",
+                        "name": "blocker_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Dependency added"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or blocker not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Dependency would create a cycle",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Stop a task waiting for another",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the blocking task",
+                        "name": "blocker_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Dependency removed"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "No such dependency",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/history": {
+            "get": {
+                "description": "Get a page of the history of a task, newest first: every create, update, delete, restore and\npurge, with the actor (X-Actor header), request ID, the task version after the change and the changed fields\nbefore and after it. The history of a task in the trash or purged can be read too.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List the changes to a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit number of entries (default: 10, capped by the server maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task history retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/todo-api_internal_dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "meta": {
+                                            "$ref": "#/definitions/todo-api_internal_dto.PageMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "The task history is not available",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/move": {
+            "post": {
+                "description": "Place a task right after after_id and/or right before before_id, which must be on the target date.\nWithout neighbours the task goes to the end of the day. date moves the task to another day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Move a task in the manual order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the task being moved",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New position",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.MoveTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task moved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Neighbours are not on the target date or not adjacent",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/occurrences/{date}": {
+            "delete": {
+                "description": "Skip the occurrence on the given day: the day is added to the task's exdates and its stored copy, if any, is deleted.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete an occurrence of a recurring task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the recurring task",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day of the occurrence (format: 2006-01-02)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the recurring task",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Occurrence deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID or date format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or occurrence not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Task is not recurring",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Edit only the occurrence on the given day (scope=this, default), or it and every later one (scope=following).\nEditing the following occurrences ends the series the day before and continues it as a new task, unless the day is the first occurrence.\nIf-Match refers to the recurring task.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update an occurrence of a recurring task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the recurring task",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day of the occurrence (format: 2006-01-02)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "this",
+                            "following"
+                        ],
+                        "type": "string",
+                        "description": "Occurrences to edit",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Complete the occurrence even while blockers are open",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the recurring task",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Task update data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.UpdateTaskRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of dates and due times, the server default when absent",
+                        "name": "Time-Zone",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Occurrence updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the returned task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or occurrence not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Task is not recurring or no fields to update",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/reminders": {
+            "get": {
+                "description": "Get the task's reminders, soonest first, with their delivery status, attempts and last error.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "reminders"
+                ],
+                "summary": "List the reminders of a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reminders retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Remind at remind_at, or before_minutes before the task is due; relative reminders follow the due time\nwhen it changes. Reminders are sent through the channel's notifier, log by default, and retried with backoff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "reminders"
+                ],
+                "summary": "Add a reminder to a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reminder data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.CreateReminderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Reminder created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Time in the past, task without due time or channel not configured",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/reminders/{reminder_id}": {
+            "delete": {
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "reminders"
+                ],
+                "summary": "Delete a reminder",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Reminder ID",
+                        "name": "reminder_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Reminder deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Reminder not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/restore": {
+            "post": {
+                "description": "Bring a task back from the trash, along with the occurrences and subtasks deleted with it.\nA subtask deleted on its own cannot be restored while its parent is in the trash.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Restore a deleted task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the deleted task",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task restored successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task is not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Parent task is in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/revert": {
+            "post": {
+                "description": "Set the fields of a task back to their values at an earlier version, given by number or by the history\nentry that made it. The revert is an ordinary update: it is validated, needs If-Match when updates do,\nnotifies subscribers and is recorded in the history as a new change. The rank and the trash state are\nnot reverted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Revert a task to an earlier version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the task being reverted",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Version or history entry to go back to",
+                        "name": "revert",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.RevertTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task reverted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or request data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task, version or history entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The old values conflict with the task's blockers or subtasks",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Task was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "The old values are no longer valid, or the task already has them",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "The task history is not available",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/subtasks": {
+            "get": {
+                "description": "Get the direct subtasks of a task in manual order, or its whole subtree with tree=true.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List subtasks of a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Nest subtasks of subtasks",
+                        "name": "tree",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subtasks retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "Get every webhook subscription, oldest first. Secrets are never returned.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhooks retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Have task.created, task.updated, task.completed, task.deleted, task.restored and task.purged events, or only those listed\nin events, POSTed to url as JSON. Requests carry X-Webhook-Timestamp and X-Webhook-Signature, \"sha256=\" and the hex\nHMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed with secret, and the event ID as Idempotency-Key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Subscribe to task events",
+                "parameters": [
+                    {
+                        "description": "Subscription data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created webhook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook subscription together with its delivery log.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update the given fields of a webhook. A paused webhook (active false) gets no new events; its pending\ndeliveries wait until it is resumed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription update data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "No fields to update",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Get the delivery log of a webhook, newest first: every event sent or to be sent, with its payload, status,\nattempts, last error and last response status. Dead deliveries ran out of attempts.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List the deliveries of a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Only deliveries in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of deliveries (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "Send a delivered or dead delivery again as soon as possible, with a fresh set of attempts. The request\nkeeps its Idempotency-Key.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Redelivery scheduled",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery still pending",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/todo-api_internal_dto.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "todo-api_internal_dto.BatchChangesRequest": {
+            "type": "object",
+            "required": [
+                "add_tags",
+                "remove_tags"
+            ],
+            "properties": {
+                "add_tags": {
+                    "description": "tag names to attach",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "description": "\"2006-01-02\"",
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "project_id": {
+                    "description": "0 takes the tasks out of their projects",
+                    "type": "integer"
+                },
+                "remove_tags": {
+                    "description": "tag names to detach",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "todo-api_internal_dto.BatchCreateRequest": {
+            "type": "object",
+            "required": [
+                "tasks"
+            ],
+            "properties": {
+                "mode": {
+                    "description": "atomic by default",
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ]
+                },
+                "tasks": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/todo-api_internal_dto.CreateTaskRequest"
+                    }
+                }
+            }
+        },
+        "todo-api_internal_dto.BatchSelectRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "mode": {
+                    "description": "atomic by default",
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ]
+                }
+            }
+        },
+        "todo-api_internal_dto.BatchUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "$ref": "#/definitions/todo-api_internal_dto.BatchChangesRequest"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "mode": {
+                    "description": "atomic by default",
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ]
+                }
+            }
+        },
+        "todo-api_internal_dto.CreateProjectRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "description": "\"#rrggbb\"",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "todo-api_internal_dto.CreateReminderRequest": {
+            "type": "object",
+            "properties": {
+                "before_minutes": {
+                    "description": "minutes before the task's due time",
+                    "type": "integer",
+                    "maximum": 43200,
+                    "minimum": 0
+                },
+                "channel": {
+                    "description": "log when empty",
+                    "type": "string",
+                    "enum": [
+                        "log",
+                        "webhook",
+                        "email"
+                    ]
+                },
+                "email": {
+                    "description": "recipient, required by the email channel",
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "remind_at": {
+                    "description": "RFC 3339 moment",
+                    "type": "string"
+                }
+            }
+        },
+        "todo-api_internal_dto.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "description": "\"#rrggbb\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "no commas, they separate names in filters",
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "todo-api_internal_dto.CreateTaskRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "date": {
+                    "description": "\"2006-01-02\"",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "due_time": {
+                    "description": "\"18:30\" in the request time zone; none for all-day tasks",
+                    "type": "string"
+                },
+                "exdates": {
+                    "description": "days skipped by the recurrence",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "project_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "recurrence": {
+                    "description": "RRULE, e.g. \"FREQ=WEEKLY;BYDAY=MO,WE,FR\"",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "tags": {
+                    "description": "tag names",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "todo-api_internal_dto.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "description": "true when omitted",
+                    "type": "boolean"
+                },
+                "events": {
+                    "description": "every event when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo-api_internal_models.EventType"
+                    }
+                },
+                "secret": {
+                    "description": "HMAC-SHA256 key for the signature header",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "todo-api_internal_dto.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "todo-api_internal_dto.MoveTaskRequest": {
+            "type": "object",
+            "properties": {
+                "after_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "before_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "date": {
+                    "description": "\"2006-01-02\"",
+                    "type": "string"
+                }
+            }
+        },
+        "todo-api_internal_dto.PageMeta": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "todo-api_internal_dto.Problem": {
+            "description": "Ошибка в формате RFC 7807 (application/problem+json)",
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo-api_internal_dto.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "todo-api_internal_dto.Response": {
+            "description": "Стандартная модель ответа сервера на запрос",
             "type": "object",
             "properties": {
                 "data": {
@@ -290,14 +3778,170 @@ const docTemplate = `{
                 "message": {
                     "type": "string"
                 },
+                "meta": {
+                    "type": "object"
+                },
                 "status": {
                     "type": "string"
                 }
             }
         },
+        "todo-api_internal_dto.RevertTaskRequest": {
+            "type": "object",
+            "properties": {
+                "history_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "version": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "todo-api_internal_dto.StreamEvent": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "event ID, sent back as Last-Event-ID to resume after it",
+                    "type": "string"
+                },
+                "task": {
+                    "description": "the task after the change, or as it was before it was deleted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/todo-api_internal_models.Task"
+                        }
+                    ]
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "todo-api_internal_dto.TaskDocument": {
+            "type": "object",
+            "required": [
+                "date",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "description": "\"2006-01-02\"",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "due_time": {
+                    "description": "\"18:30\" in the request time zone; none for all-day tasks",
+                    "type": "string"
+                },
+                "exdates": {
+                    "description": "days skipped by the recurrence",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "project_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "recurrence": {
+                    "description": "RRULE, e.g. \"FREQ=WEEKLY;BYDAY=MO,WE,FR\"",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "tags": {
+                    "description": "tag names",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "todo-api_internal_dto.UpdateProjectRequest": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "todo-api_internal_dto.UpdateSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "time_zone": {
+                    "description": "IANA name, empty for the server default",
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "todo-api_internal_dto.UpdateTagRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                }
+            }
+        },
         "todo-api_internal_dto.UpdateTaskRequest": {
             "type": "object",
+            "required": [
+                "add_tags",
+                "remove_tags"
+            ],
             "properties": {
+                "add_tags": {
+                    "description": "tag names to attach",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "completed": {
                     "type": "boolean"
                 },
@@ -309,10 +3953,248 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 1000
                 },
+                "due_time": {
+                    "description": "\"\" makes the task all-day",
+                    "type": "string"
+                },
+                "exdates": {
+                    "description": "replaces the skipped days",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "description": "0 makes the task top-level",
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "project_id": {
+                    "description": "0 takes the task out of its project",
+                    "type": "integer"
+                },
+                "recurrence": {
+                    "description": "\"\" stops the task recurring",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "remove_tags": {
+                    "description": "tag names to detach",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "title": {
                     "type": "string",
                     "maxLength": 255,
-                    "minLength": 3
+                    "minLength": 1
+                }
+            }
+        },
+        "todo-api_internal_dto.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "description": "[] subscribes to every event",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo-api_internal_models.EventType"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "todo-api_internal_models.EventType": {
+            "type": "string",
+            "enum": [
+                "task.created",
+                "task.updated",
+                "task.completed",
+                "task.deleted",
+                "task.restored",
+                "task.purged"
+            ],
+            "x-enum-comments": {
+                "EventTaskCompleted": "sent along with task.updated",
+                "EventTaskDeleted": "moved to the trash",
+                "EventTaskPurged": "deleted for good",
+                "EventTaskRestored": "brought back from the trash"
+            },
+            "x-enum-varnames": [
+                "EventTaskCreated",
+                "EventTaskUpdated",
+                "EventTaskCompleted",
+                "EventTaskDeleted",
+                "EventTaskRestored",
+                "EventTaskPurged"
+            ]
+        },
+        "todo-api_internal_models.Priority": {
+            "type": "integer",
+            "enum": [
+                0,
+                1,
+                2,
+                3,
+                4
+            ],
+            "x-enum-varnames": [
+                "PriorityNone",
+                "PriorityLow",
+                "PriorityMedium",
+                "PriorityHigh",
+                "PriorityUrgent"
+            ]
+        },
+        "todo-api_internal_models.Tag": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "\"#rrggbb\", empty for the client's default",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "todo-api_internal_models.Task": {
+            "type": "object",
+            "required": [
+                "date",
+                "title"
+            ],
+            "properties": {
+                "blocked": {
+                    "description": "Set when an open task blocks this one, computed on read; never stored.",
+                    "type": "boolean"
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "calendar day, midnight UTC",
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "due_at": {
+                    "description": "DueAt is the moment a task with a due time is due, on its Date in\nTimeZone; both are empty for all-day tasks.",
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "occurrence_date": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "nil for top-level tasks",
+                    "type": "integer"
+                },
+                "priority": {
+                    "$ref": "#/definitions/todo-api_internal_models.Priority"
+                },
+                "project_id": {
+                    "description": "nil for tasks outside any project",
+                    "type": "integer"
+                },
+                "rank": {
+                    "description": "manual order within Date, see pkg/lexorank",
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "Recurrence is an RFC 5545 RRULE whose first occurrence is Date; empty\nfor one-off tasks. ExDates are the days removed from the series.",
+                    "type": "string"
+                },
+                "relevance": {
+                    "description": "Set only by searches (the q filter); never stored.",
+                    "type": "number"
+                },
+                "series_id": {
+                    "description": "SeriesID and OccurrenceDate identify an occurrence of a recurring\ntask: a stored one that was edited separately from its series, or one\nexpanded on read.",
+                    "type": "integer"
+                },
+                "snippet": {
+                    "description": "matched text with \u003cmark\u003e highlights",
+                    "type": "string"
+                },
+                "subtasks": {
+                    "description": "Filled only for tree responses.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo-api_internal_models.Task"
+                    }
+                },
+                "subtasks_completed": {
+                    "type": "integer"
+                },
+                "subtasks_total": {
+                    "description": "Progress over the direct subtasks, counted on read; never stored.",
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/todo-api_internal_models.Tag"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "bumped on every update, exposed as ETag",
+                    "type": "integer"
                 }
             }
         }
@@ -0,0 +1,24 @@
+// Package audit carries who made a request, and which request it was,
+// through its context down to the records that remember changes.
+package audit
+
+import "context"
+
+// Info identifies the origin of a change.
+type Info struct {
+	Actor     string // who asked for the change, empty when unknown
+	RequestID string
+}
+
+type infoKey struct{}
+
+// WithInfo returns a copy of ctx carrying info.
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext returns the info ctx carries, the zero Info if none.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(infoKey{}).(Info)
+	return info
+}
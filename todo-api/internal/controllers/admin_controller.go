@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/services"
+)
+
+type AdminController struct {
+	outbox services.OutboxService
+	logger *zap.Logger
+}
+
+func NewAdminController(outbox services.OutboxService, logger *zap.Logger) *AdminController {
+	return &AdminController{
+		outbox: outbox,
+		logger: logger,
+	}
+}
+
+// OutboxStatus godoc
+// @Summary Show the outbox relay lag
+// @Description Every task change records its events in the outbox in the same transaction; the relay publishes them
+// @Description to the consumers, webhooks among them. Shows how many events wait, how many of them failed last time,
+// @Description and how old the oldest one is.
+// @Tags admin
+// @Produce json
+// @Produce application/problem+json
+// @Success 200 {object} dto.Response "Outbox status retrieved successfully"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/outbox [get]
+func (c *AdminController) OutboxStatus(ctx *gin.Context) {
+	status, err := c.outbox.Status(ctx.Request.Context())
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to get outbox status", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Outbox status retrieved successfully", status))
+}
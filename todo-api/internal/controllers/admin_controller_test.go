@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/services/mock"
+)
+
+func setupAdminController(t *testing.T) (*AdminController, *mock.MockOutboxService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockOutboxService(ctrl)
+	return NewAdminController(mockService, zaptest.NewLogger(t)), mockService
+}
+
+func TestAdminController_OutboxStatus(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupAdminController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/admin/outbox", nil)
+
+		mockService.EXPECT().Status(gomock.Any()).Return(&dto.OutboxStatus{Pending: 3, LagSeconds: 1.5}, nil)
+
+		// Act
+		controller.OutboxStatus(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"pending":3`)
+		assert.Contains(t, recorder.Body.String(), `"lag_seconds":1.5`)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupAdminController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/admin/outbox", nil)
+
+		mockService.EXPECT().Status(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		// Act
+		controller.OutboxStatus(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
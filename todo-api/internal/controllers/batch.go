@@ -0,0 +1,350 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+// CreateTasks godoc
+// @Summary Create tasks in a batch
+// @Description Create several tasks at once, each checked like a single create.
+// @Description An atomic batch (default) creates all of them or none; a best_effort one creates every task that passes its checks.
+// @Description The result lists the outcome of every task in request order: succeeded with the task, failed with a problem, or skipped when an atomic batch failed on another one.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param input body dto.BatchCreateRequest true "Tasks to create and the batch mode"
+// @Param tz query string false "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone of dates and due times, the server default when absent"
+// @Success 200 {object} dto.Response "Batch processed, see data for the outcome of every task"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 422 {object} dto.Problem "Too many tasks for one batch"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/batch/create [post]
+func (c *TaskController) CreateTasks(ctx *gin.Context) {
+	var req dto.BatchCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	loc, ok := c.requestLocation(ctx)
+	if !ok {
+		return
+	}
+
+	serviceReqs := make([]dto.CreateTaskServiceRequest, len(req.Tasks))
+	for i := range req.Tasks {
+		serviceReq, err := convertToServiceCreate(&req.Tasks[i])
+		if err != nil {
+			c.logger.Warn("Invalid date format", zap.Error(err))
+			respondProblem(ctx, invalidDateProblem(fmt.Sprintf("tasks[%d].date", i)))
+			return
+		}
+		serviceReq.Location = loc
+		serviceReqs[i] = serviceReq
+	}
+
+	result, err := c.service.CreateTasks(ctx.Request.Context(), serviceReqs, req.Mode)
+	if err != nil {
+		c.respondError(ctx, "Failed to create tasks", err)
+		return
+	}
+	c.respondBatch(ctx, "create", result)
+}
+
+// UpdateTasks godoc
+// @Summary Update tasks in a batch
+// @Description Apply the same changes to several tasks, named by ids in the body or matched by the filter parameters of the task list, e.g.
+// @Description completed=false&date_from=2026-10-15&date_to=2026-10-15 with changes {"date": "2026-10-16"} moves the open tasks of a day to the next one.
+// @Description Each task is checked like a single update, without versions. An atomic batch (default) updates all of them or none;
+// @Description a best_effort one updates every task that passes its checks. A filter may match at most as many tasks as a batch can hold.
+// @Description A date filter fails the recurring tasks it matches with 422; select a series by id to change it as a whole.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param input body dto.BatchUpdateRequest true "Tasks to update, the batch mode and the changes"
+// @Param force query bool false "Complete the tasks even while blockers are open"
+// @Param completed query bool false "Select tasks by completion status"
+// @Param date_from query string false "Select tasks from this date (format: 2006-01-02)"
+// @Param date_to query string false "Select tasks up to this date (format: 2006-01-02)"
+// @Param priority query string false "Select tasks with these comma-separated priorities"
+// @Param tags query string false "Select tasks with these comma-separated tag names"
+// @Param tag_mode query string false "Match tasks with any (default) or all of the tags" Enums(any, all)
+// @Param project_id query string false "Select tasks of a project, or none for tasks outside any project"
+// @Param parent_id query string false "Select subtasks of a task, or none for top-level tasks"
+// @Param blocked query bool false "Select tasks with (true) or without (false) an open blocker"
+// @Param q query string false "Select tasks by full-text search in title and description"
+// @Param tz query string false "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone the date range is taken in, the server default when absent"
+// @Success 200 {object} dto.Response "Batch processed, see data for the outcome of every task"
+// @Failure 400 {object} dto.Problem "Invalid input data, or tasks selected by neither or both ids and filter"
+// @Failure 422 {object} dto.Problem "No fields to update, or too many tasks for one batch"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/batch/update [post]
+func (c *TaskController) UpdateTasks(ctx *gin.Context) {
+	var req dto.BatchUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	var query dto.UpdateTaskQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Warn("Invalid update parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	changes, err := convertToBatchChanges(&req.Changes)
+	if err != nil {
+		c.logger.Warn("Invalid date format", zap.Error(err))
+		respondProblem(ctx, invalidDateProblem("changes.date"))
+		return
+	}
+	changes.Force = query.Force
+
+	sel, ok := c.batchSelection(ctx, req.BatchSelectRequest)
+	if !ok {
+		return
+	}
+
+	result, err := c.service.UpdateTasks(ctx.Request.Context(), sel, changes)
+	if err != nil {
+		c.respondError(ctx, "Failed to update tasks", err)
+		return
+	}
+	c.respondBatch(ctx, "update", result)
+}
+
+// CompleteTasks godoc
+// @Summary Complete tasks in a batch
+// @Description Mark several tasks completed, named by ids in the body or matched by the filter parameters of the task list,
+// @Description e.g. date_from=2026-10-15&date_to=2026-10-15 for everything on a day. Works like a batch update setting completed.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param input body dto.BatchSelectRequest true "Tasks to complete and the batch mode"
+// @Param force query bool false "Complete the tasks even while blockers are open"
+// @Param completed query bool false "Select tasks by completion status"
+// @Param date_from query string false "Select tasks from this date (format: 2006-01-02)"
+// @Param date_to query string false "Select tasks up to this date (format: 2006-01-02)"
+// @Param priority query string false "Select tasks with these comma-separated priorities"
+// @Param tags query string false "Select tasks with these comma-separated tag names"
+// @Param tag_mode query string false "Match tasks with any (default) or all of the tags" Enums(any, all)
+// @Param project_id query string false "Select tasks of a project, or none for tasks outside any project"
+// @Param parent_id query string false "Select subtasks of a task, or none for top-level tasks"
+// @Param blocked query bool false "Select tasks with (true) or without (false) an open blocker"
+// @Param q query string false "Select tasks by full-text search in title and description"
+// @Param tz query string false "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone the date range is taken in, the server default when absent"
+// @Success 200 {object} dto.Response "Batch processed, see data for the outcome of every task"
+// @Failure 400 {object} dto.Problem "Invalid input data, or tasks selected by neither or both ids and filter"
+// @Failure 422 {object} dto.Problem "Too many tasks for one batch"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/batch/complete [post]
+func (c *TaskController) CompleteTasks(ctx *gin.Context) {
+	var req dto.BatchSelectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	var query dto.UpdateTaskQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Warn("Invalid update parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	sel, ok := c.batchSelection(ctx, req)
+	if !ok {
+		return
+	}
+
+	completed := true
+	changes := dto.BatchChanges{Completed: &completed, Force: query.Force}
+	result, err := c.service.UpdateTasks(ctx.Request.Context(), sel, changes)
+	if err != nil {
+		c.respondError(ctx, "Failed to complete tasks", err)
+		return
+	}
+	c.respondBatch(ctx, "complete", result)
+}
+
+// DeleteTasks godoc
+// @Summary Delete tasks in a batch
+// @Description Move several tasks to the trash, named by ids in the body or matched by the filter parameters of the task list.
+// @Description An atomic batch (default) deletes all of them or none; a best_effort one deletes every task it finds.
+// @Description A date filter fails the recurring tasks it matches with 422; select a series by id to delete it.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param input body dto.BatchSelectRequest true "Tasks to delete and the batch mode"
+// @Param subtasks query string false "Delete subtasks too (cascade) or move them up to the nearest parent that stays (reparent, default)" Enums(cascade, reparent)
+// @Param completed query bool false "Select tasks by completion status"
+// @Param date_from query string false "Select tasks from this date (format: 2006-01-02)"
+// @Param date_to query string false "Select tasks up to this date (format: 2006-01-02)"
+// @Param priority query string false "Select tasks with these comma-separated priorities"
+// @Param tags query string false "Select tasks with these comma-separated tag names"
+// @Param tag_mode query string false "Match tasks with any (default) or all of the tags" Enums(any, all)
+// @Param project_id query string false "Select tasks of a project, or none for tasks outside any project"
+// @Param parent_id query string false "Select subtasks of a task, or none for top-level tasks"
+// @Param blocked query bool false "Select tasks with (true) or without (false) an open blocker"
+// @Param q query string false "Select tasks by full-text search in title and description"
+// @Param tz query string false "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone the date range is taken in, the server default when absent"
+// @Success 200 {object} dto.Response "Batch processed, see data for the outcome of every task"
+// @Failure 400 {object} dto.Problem "Invalid input data, or tasks selected by neither or both ids and filter"
+// @Failure 422 {object} dto.Problem "Too many tasks for one batch"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/batch/delete [post]
+func (c *TaskController) DeleteTasks(ctx *gin.Context) {
+	var req dto.BatchSelectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	var query dto.BatchDeleteQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Warn("Invalid delete parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	sel, ok := c.batchSelection(ctx, req)
+	if !ok {
+		return
+	}
+
+	result, err := c.service.DeleteTasks(ctx.Request.Context(), sel, query.Subtasks == "cascade")
+	if err != nil {
+		c.respondError(ctx, "Failed to delete tasks", err)
+		return
+	}
+	c.respondBatch(ctx, "delete", result)
+}
+
+// batchSelection builds the selection of a batch from the IDs in its body
+// or from the filter parameters of the request, responding with a problem
+// unless exactly one of them is given. A filter must narrow the tasks
+// down: a batch never takes every task implicitly.
+func (c *TaskController) batchSelection(ctx *gin.Context, req dto.BatchSelectRequest) (dto.BatchSelection, bool) {
+	filterReq, filter, ok := c.listFilter(ctx)
+	if !ok {
+		return dto.BatchSelection{}, false
+	}
+	if problem := unparsedFilterProblem(filterReq, filter); problem != nil {
+		c.logger.Warn("Invalid batch filter", zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return dto.BatchSelection{}, false
+	}
+
+	filtered := narrowsTasks(filter)
+	if filtered == (len(req.IDs) > 0) {
+		c.logger.Warn("Invalid batch selection", zap.Int("ids", len(req.IDs)), zap.Bool("filter", filtered))
+		respondProblem(ctx, validationProblem("Select the tasks either by ids or by filter parameters", dto.FieldError{
+			Field:   "ids",
+			Rule:    "selection",
+			Message: "must be set unless filter parameters are, and only then",
+		}))
+		return dto.BatchSelection{}, false
+	}
+
+	sel := dto.BatchSelection{IDs: req.IDs, Mode: req.Mode}
+	if filtered {
+		sel.Filter = &filter
+	}
+	return sel, true
+}
+
+// unparsedFilterProblem reports filter parameters that the task list
+// would silently ignore as malformed. A batch refuses them rather than
+// act on more tasks than meant.
+func unparsedFilterProblem(req *dto.TaskFilterRequest, filter dto.TaskFilter) *dto.Problem {
+	switch {
+	case req.DateFrom != nil && filter.DateFrom == nil:
+		return invalidDateProblem("date_from")
+	case req.DateTo != nil && filter.DateTo == nil:
+		return invalidDateProblem("date_to")
+	case req.Completed != nil && filter.Completed == nil:
+		return validationProblem("Invalid completed filter", dto.FieldError{
+			Field: "completed", Rule: "boolean", Message: "must be true or false",
+		})
+	case req.Blocked != nil && filter.Blocked == nil:
+		return validationProblem("Invalid blocked filter", dto.FieldError{
+			Field: "blocked", Rule: "boolean", Message: "must be true or false",
+		})
+	}
+	return nil
+}
+
+// narrowsTasks tells whether filter leaves out any task.
+func narrowsTasks(filter dto.TaskFilter) bool {
+	return filter.Completed != nil || filter.DateFrom != nil || filter.DateTo != nil ||
+		filter.Query != "" || len(filter.Priorities) > 0 || len(filter.Tags) > 0 ||
+		filter.ProjectID != nil || filter.ParentID != nil || filter.Blocked != nil
+}
+
+// convertToBatchChanges converts the changes of a batch update, failing
+// only on a malformed date.
+func convertToBatchChanges(req *dto.BatchChangesRequest) (dto.BatchChanges, error) {
+	changes := dto.BatchChanges{
+		Completed:  req.Completed,
+		ProjectID:  req.ProjectID,
+		AddTags:    req.AddTags,
+		RemoveTags: req.RemoveTags,
+	}
+
+	if req.DateString != nil {
+		parsedDate, err := time.Parse("2006-01-02", *req.DateString)
+		if err != nil {
+			return changes, err
+		}
+		changes.Date = &parsedDate
+	}
+
+	if req.Priority != nil {
+		priority, _ := models.ParsePriority(*req.Priority) // checked by the oneof binding
+		changes.Priority = &priority
+	}
+	return changes, nil
+}
+
+// respondBatch responds with the outcome of a batch, the error of every
+// failed item as a problem.
+func (c *TaskController) respondBatch(ctx *gin.Context, operation string, result *dto.BatchResult) {
+	for i := range result.Items {
+		if item := &result.Items[i]; item.Err != nil {
+			item.Error = problemFromError(item.Err)
+		}
+	}
+
+	c.logger.Info("Batch processed",
+		zap.String("operation", operation),
+		zap.String("mode", result.Mode),
+		zap.Int("succeeded", result.Succeeded),
+		zap.Int("failed", result.Failed),
+		zap.Int("skipped", result.Skipped),
+	)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse(
+		fmt.Sprintf("Batch processed: %d succeeded, %d failed, %d skipped", result.Succeeded, result.Failed, result.Skipped),
+		result,
+	))
+}
@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+func TestTaskController_CreateTasks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/batch/create", map[string]any{
+			"tasks": []map[string]any{
+				{"title": "First", "date": "2026-10-16", "priority": "high"},
+				{"title": "Second", "date": "2026-10-17"},
+			},
+			"mode": "best_effort",
+		})
+
+		mockService.EXPECT().
+			CreateTasks(gomock.Any(), gomock.Any(), dto.BatchBestEffort).
+			DoAndReturn(func(_ any, reqs []dto.CreateTaskServiceRequest, mode string) (*dto.BatchResult, error) {
+				require.Len(t, reqs, 2)
+				assert.Equal(t, models.PriorityHigh, reqs[0].Priority)
+				assert.True(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC).Equal(reqs[1].Date))
+				return &dto.BatchResult{Mode: mode, Succeeded: 1, Failed: 1, Items: []dto.BatchItem{
+					{Index: 0, ID: 5, Status: dto.BatchSucceeded, Task: &models.Task{Model: gorm.Model{ID: 5}, Title: "First"}},
+					{Index: 1, Status: dto.BatchFailed, Err: &services.Error{Kind: services.ErrValidation, Message: "task date cannot be in the past"}},
+				}}, nil
+			})
+
+		// Act
+		controller.CreateTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var body struct {
+			Data dto.BatchResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.Equal(t, 1, body.Data.Succeeded)
+		assert.Equal(t, 1, body.Data.Failed)
+		require.Len(t, body.Data.Items, 2)
+		assert.Equal(t, uint(5), body.Data.Items[0].Task.ID)
+		require.NotNil(t, body.Data.Items[1].Error)
+		assert.Equal(t, http.StatusUnprocessableEntity, body.Data.Items[1].Error.Status)
+		assert.Equal(t, "task date cannot be in the past", body.Data.Items[1].Error.Detail)
+	})
+
+	t.Run("InvalidDate", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/batch/create", map[string]any{
+			"tasks": []map[string]any{{"title": "First", "date": "2026-10-16"}, {"title": "Second", "date": "tomorrow"}},
+		})
+
+		// Act
+		controller.CreateTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem dto.Problem
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "tasks[1].date", problem.Errors[0].Field)
+	})
+
+	t.Run("NoTasks", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/batch/create", map[string]any{"tasks": []any{}})
+
+		// Act
+		controller.CreateTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestTaskController_UpdateTasks(t *testing.T) {
+	t.Run("ByFilter", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST",
+			"/api/v1/tasks/batch/update?completed=false&date_from=2026-10-15&date_to=2026-10-15",
+			map[string]any{"changes": map[string]any{"date": "2026-10-16"}},
+		)
+
+		day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().
+			UpdateTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, sel dto.BatchSelection, changes dto.BatchChanges) (*dto.BatchResult, error) {
+				assert.Empty(t, sel.IDs)
+				require.NotNil(t, sel.Filter)
+				require.NotNil(t, sel.Filter.Completed)
+				assert.False(t, *sel.Filter.Completed)
+				assert.True(t, day.Equal(*sel.Filter.DateFrom))
+				require.NotNil(t, changes.Date)
+				assert.True(t, day.AddDate(0, 0, 1).Equal(*changes.Date))
+				return &dto.BatchResult{Mode: dto.BatchAtomic, Items: []dto.BatchItem{}}, nil
+			})
+
+		// Act
+		controller.UpdateTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("ByIDs", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/batch/update?force=true", map[string]any{
+			"ids":     []uint{1, 2},
+			"changes": map[string]any{"completed": true, "project_id": 0},
+		})
+
+		zero := uint(0)
+		done := true
+		mockService.EXPECT().
+			UpdateTasks(gomock.Any(),
+				dto.BatchSelection{IDs: []uint{1, 2}},
+				dto.BatchChanges{Completed: &done, ProjectID: &zero, Force: true},
+			).
+			Return(&dto.BatchResult{Mode: dto.BatchAtomic, Succeeded: 2, Items: []dto.BatchItem{}}, nil)
+
+		// Act
+		controller.UpdateTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	for _, tt := range []struct {
+		name string
+		url  string
+		body map[string]any
+	}{
+		{"NeitherIDsNorFilter", "/api/v1/tasks/batch/update", map[string]any{"changes": map[string]any{"completed": true}}},
+		{"BothIDsAndFilter", "/api/v1/tasks/batch/update?completed=false", map[string]any{"ids": []uint{1}, "changes": map[string]any{"completed": true}}},
+		{"MalformedFilter", "/api/v1/tasks/batch/update?date_from=yesterday", map[string]any{"changes": map[string]any{"completed": true}}},
+		{"InvalidMode", "/api/v1/tasks/batch/update", map[string]any{"ids": []uint{1}, "mode": "some", "changes": map[string]any{"completed": true}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			controller, _ := setupTestController(t)
+			ctx, recorder := createTestContext("POST", tt.url, tt.body)
+
+			// Act
+			controller.UpdateTasks(ctx)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+
+	t.Run("NoChanges", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/batch/update", map[string]any{"ids": []uint{1}})
+
+		mockService.EXPECT().
+			UpdateTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrValidation, Message: "no fields to update"})
+
+		// Act
+		controller.UpdateTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+}
+
+func TestTaskController_CompleteTasks(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("POST",
+		"/api/v1/tasks/batch/complete?date_from=2026-10-15&date_to=2026-10-15",
+		map[string]any{"mode": "best_effort"},
+	)
+
+	mockService.EXPECT().
+		UpdateTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, sel dto.BatchSelection, changes dto.BatchChanges) (*dto.BatchResult, error) {
+			assert.Equal(t, dto.BatchBestEffort, sel.Mode)
+			assert.NotNil(t, sel.Filter)
+			require.NotNil(t, changes.Completed)
+			assert.True(t, *changes.Completed)
+			assert.False(t, changes.Force)
+			return &dto.BatchResult{Mode: sel.Mode, Items: []dto.BatchItem{}}, nil
+		})
+
+	// Act
+	controller.CompleteTasks(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestTaskController_DeleteTasks(t *testing.T) {
+	t.Run("Cascade", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/batch/delete?subtasks=cascade",
+			map[string]any{"ids": []uint{3, 4}})
+
+		mockService.EXPECT().
+			DeleteTasks(gomock.Any(), dto.BatchSelection{IDs: []uint{3, 4}}, true).
+			Return(&dto.BatchResult{Mode: dto.BatchAtomic, Succeeded: 2, Items: []dto.BatchItem{
+				{Index: 0, ID: 3, Status: dto.BatchSucceeded},
+				{Index: 1, ID: 4, Status: dto.BatchSucceeded},
+			}}, nil)
+
+		// Act
+		controller.DeleteTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("InvalidSubtasks", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/batch/delete?subtasks=keep",
+			map[string]any{"ids": []uint{3}})
+
+		// Act
+		controller.DeleteTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+)
+
+// ListDependencies godoc
+// @Summary List the blockers of a task
+// @Description Get the tasks that must be completed before this one, open or not.
+// @Tags tasks
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Success 200 {object} dto.Response "Blockers retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/dependencies [get]
+func (c *TaskController) ListDependencies(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	blockers, err := c.service.ListBlockers(ctx.Request.Context(), id)
+	if err != nil {
+		c.respondError(ctx, "Failed to list blockers", err, zap.Uint("task_id", id))
+		return
+	}
+
+	c.logger.Info("Blockers listed successfully", zap.Uint("task_id", id), zap.Int("count", len(blockers)))
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Blockers retrieved successfully", blockers))
+}
+
+// AddDependency godoc
+// @Summary Make a task wait for another
+// @Description The task cannot be completed while the blocker is open. Adding an existing dependency does nothing;
+// @Description one that would make a task wait for itself, directly or through other tasks, is rejected.
+// @Tags tasks
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param blocker_id path int true "ID of the blocking task"
+// @Success 204 "Dependency added"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Task or blocker not found"
+// @Failure 422 {object} dto.Problem "Dependency would create a cycle"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/dependencies/{blocker_id} [put]
+func (c *TaskController) AddDependency(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+	blockerID, ok := c.pathTaskID(ctx, "blocker_id")
+	if !ok {
+		return
+	}
+
+	if err := c.service.AddDependency(ctx.Request.Context(), id, blockerID); err != nil {
+		c.respondError(ctx, "Failed to add dependency", err, zap.Uint("task_id", id), zap.Uint("blocker_id", blockerID))
+		return
+	}
+
+	c.logger.Info("Dependency added successfully", zap.Uint("task_id", id), zap.Uint("blocker_id", blockerID))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// RemoveDependency godoc
+// @Summary Stop a task waiting for another
+// @Tags tasks
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param blocker_id path int true "ID of the blocking task"
+// @Success 204 "Dependency removed"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "No such dependency"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/dependencies/{blocker_id} [delete]
+func (c *TaskController) RemoveDependency(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+	blockerID, ok := c.pathTaskID(ctx, "blocker_id")
+	if !ok {
+		return
+	}
+
+	if err := c.service.RemoveDependency(ctx.Request.Context(), id, blockerID); err != nil {
+		c.respondError(ctx, "Failed to remove dependency", err, zap.Uint("task_id", id), zap.Uint("blocker_id", blockerID))
+		return
+	}
+
+	c.logger.Info("Dependency removed successfully", zap.Uint("task_id", id), zap.Uint("blocker_id", blockerID))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// pathTaskID parses the task ID in the named path parameter, responding
+// with a problem when it is malformed.
+func (c *TaskController) pathTaskID(ctx *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(param), 10, 32)
+	if err != nil {
+		c.logger.Warn("Invalid task ID format",
+			zap.String(param+"_param", ctx.Param(param)),
+			zap.Error(err),
+		)
+		respondProblem(ctx, invalidIDProblem())
+		return 0, false
+	}
+	return uint(id), true
+}
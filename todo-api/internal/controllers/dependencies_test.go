@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+func TestTaskController_ListDependencies(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("GET", "/api/v1/tasks/1/dependencies", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	mockService.EXPECT().
+		ListBlockers(gomock.Any(), uint(1)).
+		Return([]models.Task{{Model: gorm.Model{ID: 2}}}, nil)
+
+	// Act
+	controller.ListDependencies(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data []models.Task `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, uint(2), response.Data[0].ID)
+}
+
+func TestTaskController_AddDependency(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PUT", "/api/v1/tasks/1/dependencies/2", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "blocker_id", Value: "2"}}
+
+		mockService.EXPECT().AddDependency(gomock.Any(), uint(1), uint(2)).Return(nil)
+
+		// Act
+		controller.AddDependency(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PUT", "/api/v1/tasks/1/dependencies/2", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "blocker_id", Value: "2"}}
+
+		mockService.EXPECT().
+			AddDependency(gomock.Any(), uint(1), uint(2)).
+			Return(&services.Error{Kind: services.ErrValidation, Message: "task 1 cannot depend on task 2: dependency cycle: 1 -> 2 -> 1"})
+
+		// Act
+		controller.AddDependency(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+		var problem dto.Problem
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+		assert.Contains(t, problem.Detail, "1 -> 2 -> 1")
+	})
+
+	t.Run("InvalidBlockerID", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("PUT", "/api/v1/tasks/1/dependencies/x", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "blocker_id", Value: "x"}}
+
+		// Act
+		controller.AddDependency(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestTaskController_RemoveDependency(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("DELETE", "/api/v1/tasks/1/dependencies/2", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "blocker_id", Value: "2"}}
+
+	mockService.EXPECT().
+		RemoveDependency(gomock.Any(), uint(1), uint(2)).
+		Return(&services.Error{Kind: services.ErrNotFound, Message: "task 1 does not depend on task 2"})
+
+	// Act
+	controller.RemoveDependency(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestTaskController_CompleteBlockedTask(t *testing.T) {
+	t.Run("Forced", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		completed := true
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1?force=true", dto.UpdateTaskRequest{Completed: &completed})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(1), dto.UpdateTaskServiceRequest{Completed: &completed, Force: true}).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Completed: true, Version: 2}, nil)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		completed := true
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", dto.UpdateTaskRequest{Completed: &completed})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(1), dto.UpdateTaskServiceRequest{Completed: &completed}).
+			Return(nil, &services.Error{Kind: services.ErrConflict, Message: "task 1 is blocked by open tasks 2"})
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+}
+
+func TestTaskController_ListBlockedTasks(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("GET", "/api/v1/tasks?blocked=false", nil)
+
+	unblocked := false
+	mockService.EXPECT().
+		ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Blocked: &unblocked}).
+		Return(&dto.TaskPage{Meta: dto.PageMeta{Limit: 10}}, nil)
+
+	// Act
+	controller.ListTasks(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/services"
+)
+
+func init() {
+	// Report validation errors under the names clients send, not Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(clientFieldName)
+	}
+}
+
+func clientFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// problemFromError builds the problem for an error returned by a service.
+// Details of unexpected errors are not exposed to clients.
+func problemFromError(err error) *dto.Problem {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		problem := dto.NewProblem(http.StatusNotFound, err.Error())
+		problem.Type = dto.ProblemTypeNotFound
+		return problem
+	case errors.Is(err, services.ErrValidation):
+		problem := dto.NewProblem(http.StatusUnprocessableEntity, err.Error())
+		problem.Type = dto.ProblemTypeValidation
+		return problem
+	case errors.Is(err, services.ErrConflict):
+		problem := dto.NewProblem(http.StatusConflict, err.Error())
+		problem.Type = dto.ProblemTypeConflict
+		return problem
+	case errors.Is(err, services.ErrPreconditionFailed):
+		problem := dto.NewProblem(http.StatusPreconditionFailed, err.Error())
+		problem.Type = dto.ProblemTypePreconditionFailed
+		return problem
+	case errors.Is(err, services.ErrHistoryUnavailable):
+		return dto.NewProblem(http.StatusNotImplemented, err.Error())
+	default:
+		return dto.NewProblem(http.StatusInternalServerError, "An unexpected error occurred")
+	}
+}
+
+// validationProblem returns a 400 problem listing the rejected fields.
+func validationProblem(detail string, fieldErrors ...dto.FieldError) *dto.Problem {
+	problem := dto.NewProblem(http.StatusBadRequest, detail)
+	problem.Type = dto.ProblemTypeValidation
+	problem.Errors = fieldErrors
+	return problem
+}
+
+// bindingProblem turns a ShouldBind* error into a 400 problem with one entry
+// per invalid field where the error allows it.
+func bindingProblem(err error) *dto.Problem {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrors := make([]dto.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fieldErrors = append(fieldErrors, dto.FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: ruleMessage(fe),
+			})
+		}
+		return validationProblem("Request validation failed", fieldErrors...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return validationProblem("Request body must be a JSON object")
+		}
+		return validationProblem("Request validation failed", dto.FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be of type %s", jsonTypeName(typeErr.Type)),
+		})
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return validationProblem("Request body is not valid JSON")
+	}
+
+	return validationProblem(err.Error())
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if fe.Param() == "1" && fe.Kind() == reflect.String {
+			return "must not be empty"
+		}
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("failed the %q rule", fe.Tag())
+	}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// respondProblem writes problem as application/problem+json.
+func respondProblem(ctx *gin.Context, problem *dto.Problem) {
+	if problem.Instance == "" && ctx.Request != nil {
+		problem.Instance = ctx.Request.URL.Path
+	}
+	ctx.Header("Content-Type", dto.ProblemContentType)
+	ctx.JSON(problem.Status, problem)
+}
+
+// respondError logs a service error and writes it as a problem with the
+// mapped status. Client errors are logged as warnings, everything else as
+// errors.
+func (c *TaskController) respondError(ctx *gin.Context, msg string, err error, fields ...zap.Field) {
+	respondServiceError(ctx, c.logger, msg, err, fields...)
+}
+
+func respondServiceError(ctx *gin.Context, logger *zap.Logger, msg string, err error, fields ...zap.Field) {
+	problem := problemFromError(err)
+	fields = append(fields, zap.Error(err))
+
+	if problem.Status >= http.StatusInternalServerError {
+		logger.Error(msg, fields...)
+	} else {
+		logger.Warn(msg, fields...)
+	}
+
+	respondProblem(ctx, problem)
+}
+
+func invalidIDProblem() *dto.Problem {
+	return invalidResourceIDProblem("task")
+}
+
+func invalidResourceIDProblem(resource string) *dto.Problem {
+	return validationProblem("Invalid "+resource+" ID format", dto.FieldError{
+		Field:   "id",
+		Rule:    "uint",
+		Message: "must be a positive integer",
+	})
+}
+
+// pathID parses the ID of a resource from path parameter param, responding
+// with 400 when it is not a valid ID.
+func pathID(ctx *gin.Context, logger *zap.Logger, param string, resource string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(param), 10, 32)
+	if err != nil {
+		logger.Warn("Invalid "+resource+" ID format",
+			zap.String(param+"_param", ctx.Param(param)),
+			zap.Error(err),
+		)
+		respondProblem(ctx, invalidResourceIDProblem(resource))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func invalidDateProblem(field string) *dto.Problem {
+	return validationProblem("Invalid date format, expected YYYY-MM-DD", dto.FieldError{
+		Field:   field,
+		Rule:    "date",
+		Message: "must be a date in YYYY-MM-DD format",
+	})
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+func etag(version uint) string {
+	return fmt.Sprintf("%q", strconv.FormatUint(uint64(version), 10))
+}
+
+func setETag(ctx *gin.Context, task *models.Task) {
+	ctx.Header("ETag", etag(task.Version))
+}
+
+// parseIfMatch returns the versions listed in an If-Match header and whether
+// the header is the "*" wildcard. Weak tags never match, as If-Match uses
+// strong comparison.
+func parseIfMatch(header string) (versions []uint, wildcard bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil || strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if version, err := strconv.ParseUint(unquoted, 10, 32); err == nil && version > 0 {
+			versions = append(versions, uint(version))
+		}
+	}
+	return versions, false
+}
+
+// ifMatchVersion resolves the If-Match header of a write on task id into the
+// version the write must be conditioned on, 0 meaning unconditional. It
+// returns a problem when the precondition cannot hold or is required but
+// missing.
+func (c *TaskController) ifMatchVersion(ctx *gin.Context, id uint) (uint, *dto.Problem) {
+	header := ctx.GetHeader("If-Match")
+	if header == "" {
+		if c.requireIfMatch {
+			problem := dto.NewProblem(http.StatusPreconditionRequired, "If-Match header with the task ETag is required")
+			problem.Type = dto.ProblemTypePreconditionRequired
+			return 0, problem
+		}
+		return 0, nil
+	}
+
+	versions, wildcard := parseIfMatch(header)
+	switch {
+	case wildcard:
+		return 0, nil
+	case len(versions) == 1:
+		return versions[0], nil
+	case len(versions) == 0:
+		return 0, preconditionFailedProblem(id)
+	}
+
+	task, err := c.service.GetTaskByID(ctx.Request.Context(), id)
+	if err != nil {
+		return 0, problemFromError(err)
+	}
+	for _, version := range versions {
+		if version == task.Version {
+			return version, nil
+		}
+	}
+	return 0, preconditionFailedProblem(id)
+}
+
+func preconditionFailedProblem(id uint) *dto.Problem {
+	problem := dto.NewProblem(http.StatusPreconditionFailed, fmt.Sprintf("task %d has been modified by someone else", id))
+	problem.Type = dto.ProblemTypePreconditionFailed
+	return problem
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+)
+
+// ListTaskHistory godoc
+// @Summary List the changes to a task
+// @Description Get a page of the history of a task, newest first: every create, update, delete, restore and
+// @Description purge, with the actor (X-Actor header), request ID, the task version after the change and the changed fields
+// @Description before and after it. The history of a task in the trash or purged can be read too.
+// @Tags tasks
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param limit query int false "Limit number of entries (default: 10, capped by the server maximum)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} dto.Response{meta=dto.PageMeta} "Task history retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format or pagination parameters"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Failure 501 {object} dto.Problem "The task history is not available"
+// @Router /api/v1/tasks/{id}/history [get]
+func (c *TaskController) ListTaskHistory(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var filter dto.HistoryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		c.logger.Warn("Invalid history parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	page, err := c.service.ListTaskHistory(ctx.Request.Context(), id, filter)
+	if err != nil {
+		c.respondError(ctx, "Failed to list task history", err, zap.Uint("task_id", id))
+		return
+	}
+
+	c.logger.Debug("Task history listed", zap.Uint("task_id", id), zap.Int("count", len(page.Entries)))
+	ctx.JSON(http.StatusOK, dto.PageResponse("Task history retrieved successfully", page.Entries, page.Meta))
+}
+
+// getTaskAsOf responds with a task as it was at the as_of timestamp. The
+// response has no ETag: the version it shows is not one to update.
+func (c *TaskController) getTaskAsOf(ctx *gin.Context, id uint, spec string) {
+	asOf, err := time.Parse(time.RFC3339, spec)
+	if err != nil {
+		c.logger.Warn("Invalid as_of", zap.String("as_of", spec), zap.Error(err))
+		respondProblem(ctx, validationProblem("Invalid as_of", dto.FieldError{
+			Field:   "as_of",
+			Rule:    "datetime",
+			Message: "must be an RFC 3339 timestamp such as 2026-03-01T09:00:00Z",
+		}))
+		return
+	}
+
+	task, err := c.service.GetTaskAsOf(ctx.Request.Context(), id, asOf)
+	if err != nil {
+		c.respondError(ctx, "Failed to get task", err, zap.Uint("task_id", id), zap.Time("as_of", asOf))
+		return
+	}
+
+	c.logger.Debug("Task rebuilt from history", zap.Uint("task_id", task.ID), zap.Time("as_of", asOf))
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Task retrieved successfully", task))
+}
+
+// RevertTask godoc
+// @Summary Revert a task to an earlier version
+// @Description Set the fields of a task back to their values at an earlier version, given by number or by the history
+// @Description entry that made it. The revert is an ordinary update: it is validated, needs If-Match when updates do,
+// @Description notifies subscribers and is recorded in the history as a new change. The rank and the trash state are
+// @Description not reverted.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the task being reverted"
+// @Param revert body dto.RevertTaskRequest true "Version or history entry to go back to"
+// @Success 200 {object} dto.Response "Task reverted successfully"
+// @Header 200 {string} ETag "New task version"
+// @Failure 400 {object} dto.Problem "Invalid ID format or request data"
+// @Failure 404 {object} dto.Problem "Task, version or history entry not found"
+// @Failure 409 {object} dto.Problem "The old values conflict with the task's blockers or subtasks"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 422 {object} dto.Problem "The old values are no longer valid, or the task already has them"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Failure 501 {object} dto.Problem "The task history is not available"
+// @Router /api/v1/tasks/{id}/revert [post]
+func (c *TaskController) RevertTask(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.RevertTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	version, problem := c.ifMatchVersion(ctx, id)
+	if problem != nil {
+		c.logger.Warn("Revert precondition not met", zap.Uint("task_id", id), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return
+	}
+
+	task, err := c.service.RevertTask(ctx.Request.Context(), id, dto.RevertTaskServiceRequest{
+		ToVersion: req.Version,
+		HistoryID: req.HistoryID,
+		Version:   version,
+	})
+	if err != nil {
+		c.respondError(ctx, "Failed to revert task", err, zap.Uint("task_id", id))
+		return
+	}
+
+	c.logger.Info("Task reverted successfully", zap.Uint("task_id", task.ID), zap.Uint("version", task.Version))
+	setETag(ctx, task)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Task reverted successfully", task))
+}
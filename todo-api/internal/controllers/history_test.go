@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+func TestTaskController_ListTaskHistory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/1/history?limit=1&offset=1", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			ListTaskHistory(gomock.Any(), uint(1), dto.HistoryFilter{Limit: 1, Offset: 1}).
+			Return(&dto.HistoryPage{
+				Entries: []models.TaskHistory{{ID: 7, TaskID: 1, Version: 2, Action: models.HistoryUpdated, Actor: "alice"}},
+				Meta:    dto.PageMeta{Total: 3, Limit: 1, HasMore: true},
+			}, nil)
+
+		// Act
+		controller.ListTaskHistory(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var body struct {
+			Data []models.TaskHistory `json:"data"`
+			Meta dto.PageMeta         `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, "alice", body.Data[0].Actor)
+		assert.True(t, body.Meta.HasMore)
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/1/history?limit=-1", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.ListTaskHistory(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/9/history", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "9"}}
+
+		mockService.EXPECT().
+			ListTaskHistory(gomock.Any(), uint(9), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrNotFound, Message: "task 9 not found"})
+
+		// Act
+		controller.ListTaskHistory(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("HistoryUnavailable", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/1/history", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			ListTaskHistory(gomock.Any(), uint(1), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrHistoryUnavailable, Message: "task history is not available"})
+
+		// Act
+		controller.ListTaskHistory(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNotImplemented, recorder.Code)
+	})
+}
+
+func TestTaskController_GetTaskByID_AsOf(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/1?as_of=2026-03-01T10:00:00%2B02:00", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		asOf := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+		mockService.EXPECT().
+			GetTaskAsOf(gomock.Any(), uint(1), gomock.Cond(func(x any) bool { return x.(time.Time).Equal(asOf) })).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Title: "Original", Version: 1}, nil)
+
+		// Act
+		controller.GetTaskByID(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"title":"Original"`)
+		assert.Empty(t, recorder.Header().Get("ETag"), "a past version is not one to update")
+	})
+
+	t.Run("InvalidTimestamp", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/1?as_of=yesterday", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.GetTaskByID(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"as_of"`)
+	})
+}
+
+func TestTaskController_RevertTask(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/revert", map[string]interface{}{"version": 2})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+		ctx.Request.Header.Set("If-Match", `"5"`)
+
+		target := uint(2)
+		mockService.EXPECT().
+			RevertTask(gomock.Any(), uint(1), dto.RevertTaskServiceRequest{ToVersion: &target, Version: 5}).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Version: 6}, nil)
+
+		// Act
+		controller.RevertTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"6"`, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/revert", map[string]interface{}{"version": 0})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.RevertTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("AlreadyAtVersion", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/revert", map[string]interface{}{"history_id": 7})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			RevertTask(gomock.Any(), uint(1), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrValidation, Message: "task 1 is already at version 3"})
+
+		// Act
+		controller.RevertTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "already at version 3")
+	})
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/services"
+)
+
+// UpdateOccurrence godoc
+// @Summary Update an occurrence of a recurring task
+// @Description Edit only the occurrence on the given day (scope=this, default), or it and every later one (scope=following).
+// @Description Editing the following occurrences ends the series the day before and continues it as a new task, unless the day is the first occurrence.
+// @Description If-Match refers to the recurring task.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "ID of the recurring task"
+// @Param date path string true "Day of the occurrence (format: 2006-01-02)"
+// @Param scope query string false "Occurrences to edit" Enums(this, following)
+// @Param force query bool false "Complete the occurrence even while blockers are open"
+// @Param If-Match header string false "ETag of the recurring task"
+// @Param input body dto.UpdateTaskRequest true "Task update data"
+// @Param tz query string false "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone of dates and due times, the server default when absent"
+// @Success 200 {object} dto.Response "Occurrence updated successfully"
+// @Header 200 {string} ETag "Version of the returned task"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 404 {object} dto.Problem "Task or occurrence not found"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 422 {object} dto.Problem "Task is not recurring or no fields to update"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/occurrences/{date} [patch]
+func (c *TaskController) UpdateOccurrence(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+	day, ok := c.pathDate(ctx, "date")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	var query dto.UpdateTaskQuery
+	var occurrence dto.OccurrenceQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Warn("Invalid update parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+	if err := ctx.ShouldBindQuery(&occurrence); err != nil {
+		c.logger.Warn("Invalid update parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	loc, ok := c.requestLocation(ctx)
+	if !ok {
+		return
+	}
+
+	version, problem := c.ifMatchVersion(ctx, id)
+	if problem != nil {
+		c.logger.Warn("Update precondition not met", zap.Uint("task_id", id), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return
+	}
+
+	serviceReq, err := convertToServiceUpdate(&req)
+	if err != nil {
+		c.logger.Warn("Invalid date format", zap.Error(err))
+		respondProblem(ctx, invalidDateProblem("date"))
+		return
+	}
+	serviceReq.Force = query.Force
+	serviceReq.Version = version
+	serviceReq.Location = loc
+
+	scope := services.ScopeThis
+	if occurrence.Scope != "" {
+		scope = services.OccurrenceScope(occurrence.Scope)
+	}
+
+	task, err := c.service.UpdateOccurrence(ctx.Request.Context(), id, day, serviceReq, scope)
+	if err != nil {
+		c.respondError(ctx, "Failed to update occurrence", err, zap.Uint("task_id", id), zap.Time("date", day))
+		return
+	}
+
+	c.logger.Info("Occurrence updated successfully",
+		zap.Uint("task_id", id),
+		zap.Time("date", day),
+		zap.String("scope", string(scope)),
+	)
+	setETag(ctx, task)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Occurrence updated successfully", task))
+}
+
+// DeleteOccurrence godoc
+// @Summary Delete an occurrence of a recurring task
+// @Description Skip the occurrence on the given day: the day is added to the task's exdates and its stored copy, if any, is deleted.
+// @Tags tasks
+// @Produce application/problem+json
+// @Param id path int true "ID of the recurring task"
+// @Param date path string true "Day of the occurrence (format: 2006-01-02)"
+// @Param If-Match header string false "ETag of the recurring task"
+// @Success 204 "Occurrence deleted successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID or date format"
+// @Failure 404 {object} dto.Problem "Task or occurrence not found"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 422 {object} dto.Problem "Task is not recurring"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/occurrences/{date} [delete]
+func (c *TaskController) DeleteOccurrence(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+	day, ok := c.pathDate(ctx, "date")
+	if !ok {
+		return
+	}
+
+	version, problem := c.ifMatchVersion(ctx, id)
+	if problem != nil {
+		c.logger.Warn("Delete precondition not met", zap.Uint("task_id", id), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return
+	}
+
+	if err := c.service.DeleteOccurrence(ctx.Request.Context(), id, day, version); err != nil {
+		c.respondError(ctx, "Failed to delete occurrence", err, zap.Uint("task_id", id), zap.Time("date", day))
+		return
+	}
+
+	c.logger.Info("Occurrence deleted successfully", zap.Uint("task_id", id), zap.Time("date", day))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// pathDate parses the YYYY-MM-DD date in the named path parameter,
+// responding with a problem when it is malformed.
+func (c *TaskController) pathDate(ctx *gin.Context, param string) (time.Time, bool) {
+	day, err := time.Parse("2006-01-02", ctx.Param(param))
+	if err != nil {
+		c.logger.Warn("Invalid date format", zap.String(param+"_param", ctx.Param(param)), zap.Error(err))
+		respondProblem(ctx, invalidDateProblem(param))
+		return time.Time{}, false
+	}
+	return day, true
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+func TestTaskController_UpdateOccurrence(t *testing.T) {
+	day := time.Date(2030, 1, 9, 0, 0, 0, 0, time.UTC)
+	title := "Retro"
+
+	t.Run("Following", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1/occurrences/2030-01-09?scope=following&force=true",
+			dto.UpdateTaskRequest{Title: &title, ExDates: []string{"2030-01-16"}})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "date", Value: "2030-01-09"}}
+		ctx.Request.Header.Set("If-Match", `"3"`)
+
+		mockService.EXPECT().
+			UpdateOccurrence(gomock.Any(), uint(1), day, dto.UpdateTaskServiceRequest{
+				Title:   &title,
+				ExDates: []time.Time{day.AddDate(0, 0, 7)},
+				Force:   true,
+				Version: 3,
+			}, services.ScopeFollowing).
+			Return(&models.Task{Model: gorm.Model{ID: 9}, Version: 2}, nil)
+
+		// Act
+		controller.UpdateOccurrence(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("InvalidDate", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1/occurrences/09.01.2030", dto.UpdateTaskRequest{Title: &title})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "date", Value: "09.01.2030"}}
+
+		// Act
+		controller.UpdateOccurrence(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1/occurrences/2030-01-09?scope=all", dto.UpdateTaskRequest{Title: &title})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "date", Value: "2030-01-09"}}
+
+		// Act
+		controller.UpdateOccurrence(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("NoOccurrence", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1/occurrences/2030-01-09", dto.UpdateTaskRequest{Title: &title})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "date", Value: "2030-01-09"}}
+
+		mockService.EXPECT().
+			UpdateOccurrence(gomock.Any(), uint(1), day, gomock.Any(), services.ScopeThis).
+			Return(nil, &services.Error{Kind: services.ErrNotFound, Message: "task 1 has no occurrence on 2030-01-09"})
+
+		// Act
+		controller.UpdateOccurrence(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestTaskController_DeleteOccurrence(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("DELETE", "/api/v1/tasks/1/occurrences/2030-01-09", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "date", Value: "2030-01-09"}}
+
+	mockService.EXPECT().
+		DeleteOccurrence(gomock.Any(), uint(1), time.Date(2030, 1, 9, 0, 0, 0, 0, time.UTC), uint(0)).
+		Return(nil)
+
+	// Act
+	controller.DeleteOccurrence(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
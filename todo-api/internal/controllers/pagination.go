@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"todo-api/internal/dto"
+)
+
+// setPageLinks writes an RFC 8288 Link header pointing at the first, next
+// and previous pages of the current request.
+func setPageLinks(ctx *gin.Context, meta dto.PageMeta) {
+	links := []string{pageLink(ctx.Request.URL, "", "first")}
+	if meta.NextCursor != "" {
+		links = append(links, pageLink(ctx.Request.URL, meta.NextCursor, "next"))
+	}
+	if meta.PrevCursor != "" {
+		links = append(links, pageLink(ctx.Request.URL, meta.PrevCursor, "prev"))
+	}
+	ctx.Header("Link", strings.Join(links, ", "))
+}
+
+func pageLink(current *url.URL, cursor string, rel string) string {
+	query := current.Query()
+	query.Del("offset")
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	target := url.URL{Path: current.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf("<%s>; rel=%q", target.String(), rel)
+}
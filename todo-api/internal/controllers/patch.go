@@ -0,0 +1,340 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/pkg/jsonpatch"
+)
+
+// Media types of task patches besides the plain JSON body of UpdateTask.
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// acceptPatch lists the media types PATCH takes, for the Accept-Patch
+// header (RFC 5789).
+var acceptPatch = strings.Join([]string{binding.MIMEJSON, mergePatchContentType, jsonPatchContentType}, ", ")
+
+// ReplaceTask godoc
+// @Summary Replace a task
+// @Description Replace all writable fields of a task with the body, checked by the rules of task creation.
+// @Description Fields left out take their defaults: no description, tags, project, parent, recurrence or due time, priority none, not completed.
+// @Description Only the fields that differ are written, checked like any update; a body equal to the task changes nothing.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the task being replaced"
+// @Param force query bool false "Complete the task even while blockers are open"
+// @Param input body dto.TaskDocument true "The task as it should be"
+// @Param tz query string false "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone of dates and due times, the server default when absent"
+// @Success 200 {object} dto.Response "Task updated successfully"
+// @Header 200 {string} ETag "New task version"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 409 {object} dto.Problem "Task is blocked by open tasks"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 422 {object} dto.Problem "Invalid recurrence, parent or tags"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id} [put]
+func (c *TaskController) ReplaceTask(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var doc dto.TaskDocument
+	if err := ctx.ShouldBindJSON(&doc); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	task, ok := c.currentTask(ctx, id)
+	if !ok {
+		return
+	}
+	c.writeDocument(ctx, task, &doc)
+}
+
+// patchTask applies the patch in the request body to the task as a
+// TaskDocument and writes the result.
+func (c *TaskController) patchTask(ctx *gin.Context, id uint, apply func(doc, patch []byte) ([]byte, error)) {
+	patch, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		c.logger.Warn("Failed to read patch", zap.Error(err))
+		respondProblem(ctx, validationProblem("Request body could not be read"))
+		return
+	}
+
+	task, ok := c.currentTask(ctx, id)
+	if !ok {
+		return
+	}
+
+	current, err := json.Marshal(taskDocument(task))
+	if err != nil {
+		c.respondError(ctx, "Failed to encode task", err, zap.Uint("task_id", id))
+		return
+	}
+	patched, err := apply(current, patch)
+	if err != nil {
+		c.logger.Warn("Patch does not apply", zap.Uint("task_id", id), zap.Error(err))
+		respondProblem(ctx, patchProblem(err))
+		return
+	}
+
+	var doc dto.TaskDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&doc); err == nil {
+		err = binding.Validator.ValidateStruct(&doc)
+	}
+	if err != nil {
+		c.logger.Warn("Patched task is invalid", zap.Uint("task_id", id), zap.Error(err))
+		respondProblem(ctx, patchedDocumentProblem(err))
+		return
+	}
+
+	c.writeDocument(ctx, task, &doc)
+}
+
+// currentTask reads the task a PUT or PATCH rewrites, checking the
+// If-Match header against it.
+func (c *TaskController) currentTask(ctx *gin.Context, id uint) (*models.Task, bool) {
+	version, problem := c.ifMatchVersion(ctx, id)
+	if problem != nil {
+		c.logger.Warn("Update precondition not met", zap.Uint("task_id", id), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return nil, false
+	}
+
+	task, err := c.service.GetTaskByID(ctx.Request.Context(), id)
+	if err != nil {
+		c.respondError(ctx, "Failed to get task", err, zap.Uint("task_id", id))
+		return nil, false
+	}
+	if version != 0 && version != task.Version {
+		c.logger.Warn("Update precondition not met", zap.Uint("task_id", id), zap.Uint("version", task.Version))
+		respondProblem(ctx, preconditionFailedProblem(id))
+		return nil, false
+	}
+	return task, true
+}
+
+// writeDocument makes task look like doc. Only the fields that differ are
+// updated, on the condition that the task is still at the version doc was
+// compared with.
+func (c *TaskController) writeDocument(ctx *gin.Context, task *models.Task, doc *dto.TaskDocument) {
+	var query dto.UpdateTaskQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Warn("Invalid update parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	loc, ok := c.requestLocation(ctx)
+	if !ok {
+		return
+	}
+
+	serviceReq, changed, err := documentUpdate(task, doc)
+	if err != nil {
+		c.logger.Warn("Invalid date format", zap.Error(err))
+		respondProblem(ctx, invalidDateProblem("date"))
+		return
+	}
+	if !changed {
+		c.logger.Debug("Task unchanged", zap.Uint("task_id", task.ID))
+		setETag(ctx, task)
+		ctx.JSON(http.StatusOK, dto.SuccessResponse("Task updated successfully", task))
+		return
+	}
+	serviceReq.Force = query.Force
+	serviceReq.Version = task.Version
+	serviceReq.Location = loc
+
+	updated, err := c.service.UpdateTask(ctx.Request.Context(), task.ID, serviceReq)
+	if err != nil {
+		c.respondError(ctx, "Failed to update task", err, zap.Uint("task_id", task.ID))
+		return
+	}
+
+	c.logger.Info("Task updated successfully", zap.Uint("task_id", updated.ID))
+	setETag(ctx, updated)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Task updated successfully", updated))
+}
+
+// taskDocument returns the writable fields of task as a document. Due
+// times are given in the task's own time zone.
+func taskDocument(task *models.Task) dto.TaskDocument {
+	doc := dto.TaskDocument{
+		CreateTaskRequest: dto.CreateTaskRequest{
+			Title:       task.Title,
+			Description: task.Description,
+			DateString:  task.Date.Format("2006-01-02"),
+			Priority:    task.Priority.String(),
+			Tags:        make([]string, len(task.Tags)),
+			ProjectID:   task.ProjectID,
+			ParentID:    task.ParentID,
+			Recurrence:  task.Recurrence,
+			ExDates:     make([]string, len(task.ExDates)),
+		},
+		Completed: task.Completed,
+	}
+	for i, tag := range task.Tags {
+		doc.Tags[i] = tag.Name
+	}
+	for i, day := range task.ExDates {
+		doc.ExDates[i] = day.Format("2006-01-02")
+	}
+	if task.DueAt != nil {
+		loc, err := time.LoadLocation(task.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		doc.DueTime = task.DueAt.In(loc).Format("15:04")
+	}
+	return doc
+}
+
+// documentUpdate returns the update that makes task look like doc and
+// whether there is anything to update, failing only on a malformed date.
+func documentUpdate(task *models.Task, doc *dto.TaskDocument) (dto.UpdateTaskServiceRequest, bool, error) {
+	var req dto.UpdateTaskServiceRequest
+	current := taskDocument(task)
+	changed := false
+
+	if doc.Title != current.Title {
+		req.Title = &doc.Title
+		changed = true
+	}
+	if doc.Description != current.Description {
+		req.Description = &doc.Description
+		changed = true
+	}
+	if doc.DateString != current.DateString {
+		date, err := time.Parse("2006-01-02", doc.DateString)
+		if err != nil {
+			return req, false, err
+		}
+		req.Date = &date
+		changed = true
+	}
+	if doc.Completed != current.Completed {
+		req.Completed = &doc.Completed
+		changed = true
+	}
+	priority := doc.Priority
+	if priority == "" {
+		priority = models.PriorityNone.String()
+	}
+	if priority != current.Priority {
+		parsed, _ := models.ParsePriority(priority) // checked by the oneof binding
+		req.Priority = &parsed
+		changed = true
+	}
+	for _, name := range doc.Tags {
+		if !slices.Contains(current.Tags, name) && !slices.Contains(req.AddTags, name) {
+			req.AddTags = append(req.AddTags, name)
+			changed = true
+		}
+	}
+	for _, name := range current.Tags {
+		if !slices.Contains(doc.Tags, name) {
+			req.RemoveTags = append(req.RemoveTags, name)
+			changed = true
+		}
+	}
+	if !equalRef(doc.ProjectID, current.ProjectID) {
+		req.ProjectID = refOrZero(doc.ProjectID)
+		changed = true
+	}
+	if !equalRef(doc.ParentID, current.ParentID) {
+		req.ParentID = refOrZero(doc.ParentID)
+		changed = true
+	}
+	if doc.Recurrence != current.Recurrence {
+		req.Recurrence = &doc.Recurrence
+		changed = true
+	}
+	if !sameDays(doc.ExDates, current.ExDates) {
+		req.ExDates = parseDays(append([]string{}, doc.ExDates...)) // empty, not nil, clears them
+		changed = true
+	}
+	if doc.DueTime != current.DueTime {
+		req.DueTime = &doc.DueTime
+		changed = true
+	}
+	return req, changed, nil
+}
+
+func equalRef(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// refOrZero returns the update value of a reference: its ID, or 0 to clear
+// it.
+func refOrZero(ref *uint) *uint {
+	if ref == nil {
+		zero := uint(0)
+		return &zero
+	}
+	return ref
+}
+
+// sameDays tells whether two lists of days hold the same days, in any
+// order.
+func sameDays(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// patchProblem describes a patch that could not be applied: 400 for a
+// malformed patch document, 409 for one that does not fit the task, such as
+// a failed test operation.
+func patchProblem(err error) *dto.Problem {
+	if errors.Is(err, jsonpatch.ErrConflict) {
+		problem := dto.NewProblem(http.StatusConflict, err.Error())
+		problem.Type = dto.ProblemTypeConflict
+		return problem
+	}
+	return validationProblem(fmt.Sprintf("Invalid patch document: %v", err))
+}
+
+// patchedDocumentProblem is bindingProblem for a task that a valid patch
+// made invalid, reported with 422 as RFC 5789 suggests.
+func patchedDocumentProblem(err error) *dto.Problem {
+	problem := bindingProblem(err)
+	problem.Status = http.StatusUnprocessableEntity
+	problem.Title = http.StatusText(http.StatusUnprocessableEntity)
+	return problem
+}
+
+func unsupportedPatchProblem(contentType string) *dto.Problem {
+	return dto.NewProblem(http.StatusUnsupportedMediaType,
+		fmt.Sprintf("Content-Type %q is not supported; use one of %s", contentType, acceptPatch))
+}
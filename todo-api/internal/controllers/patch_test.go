@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+// patchedTask is the task the tests below patch and replace.
+func patchedTask() *models.Task {
+	project := uint(3)
+	due := time.Date(2026, 10, 20, 15, 30, 0, 0, time.UTC)
+	return &models.Task{
+		Model:       gorm.Model{ID: 1},
+		Title:       "Task",
+		Description: "Details",
+		Date:        time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+		Priority:    models.PriorityLow,
+		Tags:        []models.Tag{{Name: "home"}, {Name: "work"}},
+		ProjectID:   &project,
+		DueAt:       &due,
+		TimeZone:    "Europe/Moscow",
+		Version:     2,
+	}
+}
+
+func TestTaskController_UpdateTask_MergePatch(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", map[string]any{
+			"description": nil,
+			"project_id":  nil,
+			"priority":    "high",
+			"tags":        []string{"work", "urgent"},
+		})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+		ctx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+
+		empty, zero, high := "", uint(0), models.PriorityHigh
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(patchedTask(), nil)
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(1), gomock.Any()).
+			DoAndReturn(func(_ any, _ uint, req dto.UpdateTaskServiceRequest) (*models.Task, error) {
+				assert.Nil(t, req.Title)
+				assert.Equal(t, &empty, req.Description)
+				assert.Equal(t, &zero, req.ProjectID)
+				assert.Equal(t, &high, req.Priority)
+				assert.Equal(t, []string{"urgent"}, req.AddTags)
+				assert.Equal(t, []string{"home"}, req.RemoveTags)
+				assert.Nil(t, req.DueTime, "the due time is unchanged")
+				assert.Equal(t, uint(2), req.Version, "the write is conditioned on the patched version")
+				return &models.Task{Model: gorm.Model{ID: 1}, Version: 3}, nil
+			})
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+	})
+
+	for _, tt := range []struct {
+		name  string
+		patch map[string]any
+		field string
+	}{
+		{"RemovedTitle", map[string]any{"title": nil}, "title"},
+		{"InvalidPriority", map[string]any{"priority": "top"}, "priority"},
+		{"UnknownField", map[string]any{"titel": "Typo"}, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			controller, mockService := setupTestController(t)
+			ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", tt.patch)
+			ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+			ctx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+
+			mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(patchedTask(), nil)
+
+			// Act
+			controller.UpdateTask(ctx)
+
+			// Assert
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			if tt.field != "" {
+				var problem dto.Problem
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+				require.Len(t, problem.Errors, 1)
+				assert.Equal(t, tt.field, problem.Errors[0].Field)
+			}
+		})
+	}
+
+	t.Run("StaleIfMatch", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", map[string]any{"title": "Renamed"})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+		ctx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+		ctx.Request.Header.Set("If-Match", `"1"`)
+
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(patchedTask(), nil)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+	})
+}
+
+func TestTaskController_UpdateTask_JSONPatch(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", []map[string]any{
+			{"op": "test", "path": "/completed", "value": false},
+			{"op": "test", "path": "/due_time", "value": "18:30"},
+			{"op": "replace", "path": "/completed", "value": true},
+			{"op": "remove", "path": "/tags/0"},
+			{"op": "replace", "path": "/due_time", "value": ""},
+		})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+		ctx.Request.Header.Set("Content-Type", "application/json-patch+json")
+
+		done, allDay := true, ""
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(patchedTask(), nil)
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(1), dto.UpdateTaskServiceRequest{
+				Completed:  &done,
+				RemoveTags: []string{"home"},
+				DueTime:    &allDay,
+				Version:    2,
+			}).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Version: 3}, nil)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("FailedTest", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", []map[string]any{
+			{"op": "test", "path": "/title", "value": "Other"},
+			{"op": "replace", "path": "/title", "value": "Renamed"},
+		})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+		ctx.Request.Header.Set("Content-Type", "application/json-patch+json")
+
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(patchedTask(), nil)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("MalformedPatch", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", []map[string]any{
+			{"op": "rename", "path": "/title", "value": "Renamed"},
+		})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+		ctx.Request.Header.Set("Content-Type", "application/json-patch+json")
+
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(patchedTask(), nil)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestTaskController_UpdateTask_UnsupportedMediaType(t *testing.T) {
+	// Arrange
+	controller, _ := setupTestController(t)
+	ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", map[string]any{"title": "Renamed"})
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+	ctx.Request.Header.Set("Content-Type", "text/plain")
+
+	// Act
+	controller.UpdateTask(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Accept-Patch"), "application/merge-patch+json")
+}
+
+func TestTaskController_UpdateTask_TitleRules(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", map[string]any{"title": "Go"})
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	title := "Go"
+	mockService.EXPECT().
+		UpdateTask(gomock.Any(), uint(1), gomock.Any()).
+		DoAndReturn(func(_ any, _ uint, req dto.UpdateTaskServiceRequest) (*models.Task, error) {
+			assert.Equal(t, &title, req.Title, "short titles are valid, as on create")
+			return &models.Task{Model: gorm.Model{ID: 1}, Title: title, Version: 3}, nil
+		})
+
+	// Act
+	controller.UpdateTask(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	t.Run("EmptyTitle", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", map[string]any{"title": ""})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestTaskController_ReplaceTask(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PUT", "/api/v1/tasks/1", map[string]any{
+			"title": "Task",
+			"date":  "2026-10-21",
+		})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		empty, zero, none := "", uint(0), models.PriorityNone
+		date := time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(patchedTask(), nil)
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(1), dto.UpdateTaskServiceRequest{
+				Description: &empty,
+				Date:        &date,
+				Priority:    &none,
+				RemoveTags:  []string{"home", "work"},
+				ProjectID:   &zero,
+				DueTime:     &empty,
+				Version:     2,
+			}).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Version: 3}, nil)
+
+		// Act
+		controller.ReplaceTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		current := patchedTask()
+		doc := taskDocument(current)
+		ctx, recorder := createTestContext("PUT", "/api/v1/tasks/1", doc)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current, nil)
+
+		// Act
+		controller.ReplaceTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+		assert.Equal(t, "18:30", doc.DueTime, "due times are in the task's time zone")
+	})
+
+	t.Run("MissingTitle", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("PUT", "/api/v1/tasks/1", map[string]any{"date": "2026-10-21"})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.ReplaceTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
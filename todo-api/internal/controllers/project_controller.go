@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+type ProjectController struct {
+	service services.ProjectService
+	logger  *zap.Logger
+}
+
+func NewProjectController(service services.ProjectService, logger *zap.Logger) *ProjectController {
+	return &ProjectController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateProject godoc
+// @Summary Create a new project
+// @Description Add a project that tasks can be put into with project_id
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param input body dto.CreateProjectRequest true "Project creation data"
+// @Success 201 {object} dto.Response "Project created successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Header 201 {string} Location "URL of the created project"
+// @Router /api/v1/projects [post]
+func (c *ProjectController) CreateProject(ctx *gin.Context) {
+	var req dto.CreateProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	project, err := c.service.CreateProject(ctx.Request.Context(), &models.Project{
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to create project", err)
+		return
+	}
+
+	c.logger.Info("Project created successfully", zap.Uint("project_id", project.ID))
+	ctx.Header("Location", fmt.Sprintf("/api/v1/projects/%d", project.ID))
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse("Project created successfully", project))
+}
+
+// GetProject godoc
+// @Summary Get project by ID
+// @Description Get a project with the counts of its open and completed tasks
+// @Tags projects
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Project ID"
+// @Success 200 {object} dto.Response "Project retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Project not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/projects/{id} [get]
+func (c *ProjectController) GetProject(ctx *gin.Context) {
+	id, ok := c.projectID(ctx)
+	if !ok {
+		return
+	}
+
+	project, err := c.service.GetProject(ctx.Request.Context(), id)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to get project", err, zap.Uint("project_id", id))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Project retrieved successfully", project))
+}
+
+// ListProjects godoc
+// @Summary List projects
+// @Description Get projects ordered by name, with the counts of their open and completed tasks
+// @Tags projects
+// @Produce json
+// @Produce application/problem+json
+// @Param include_archived query bool false "Include archived projects"
+// @Success 200 {object} dto.Response "Projects retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid filter parameters"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/projects [get]
+func (c *ProjectController) ListProjects(ctx *gin.Context) {
+	var req dto.ProjectFilterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warn("Invalid filter parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	projects, err := c.service.ListProjects(ctx.Request.Context(), req.IncludeArchived)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to list projects", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Projects retrieved successfully", projects))
+}
+
+// UpdateProject godoc
+// @Summary Update a project
+// @Description Update the given fields of a project. Archiving hides its tasks from default task listings without deleting them.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Project ID"
+// @Param input body dto.UpdateProjectRequest true "Project update data"
+// @Success 200 {object} dto.Response "Project updated successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 404 {object} dto.Problem "Project not found"
+// @Failure 422 {object} dto.Problem "No fields to update"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/projects/{id} [patch]
+func (c *ProjectController) UpdateProject(ctx *gin.Context) {
+	id, ok := c.projectID(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	project, err := c.service.UpdateProject(ctx.Request.Context(), id, dto.UpdateProjectServiceRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+		Archived:    req.Archived,
+	})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to update project", err, zap.Uint("project_id", id))
+		return
+	}
+
+	c.logger.Info("Project updated successfully", zap.Uint("project_id", project.ID))
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Project updated successfully", project))
+}
+
+// DeleteProject godoc
+// @Summary Delete a project
+// @Description Delete a project. Its tasks are kept outside any project, each getting a new version and a task.updated event;
+// @Description archive the project to hide them instead.
+// @Tags projects
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Project ID"
+// @Success 204 "Project deleted successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Project not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/projects/{id} [delete]
+func (c *ProjectController) DeleteProject(ctx *gin.Context) {
+	id, ok := c.projectID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteProject(ctx.Request.Context(), id); err != nil {
+		respondServiceError(ctx, c.logger, "Failed to delete project", err, zap.Uint("project_id", id))
+		return
+	}
+
+	c.logger.Info("Project deleted successfully", zap.Uint("project_id", id))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// projectID parses the id path parameter, responding with 400 when it is
+// not a valid ID.
+func (c *ProjectController) projectID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		c.logger.Warn("Invalid project ID format",
+			zap.String("id_param", ctx.Param("id")),
+			zap.Error(err),
+		)
+		respondProblem(ctx, invalidResourceIDProblem("project"))
+		return 0, false
+	}
+	return uint(id), true
+}
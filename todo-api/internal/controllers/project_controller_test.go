@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services/mock"
+)
+
+func setupProjectController(t *testing.T) (*ProjectController, *mock.MockProjectService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockProjectService(ctrl)
+	return NewProjectController(mockService, zaptest.NewLogger(t)), mockService
+}
+
+func TestProjectController_ListProjects(t *testing.T) {
+	t.Run("IncludeArchived", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupProjectController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/projects?include_archived=true", nil)
+
+		mockService.EXPECT().
+			ListProjects(gomock.Any(), true).
+			Return([]models.Project{{ID: 1, Name: "Work", OpenTasks: 3, CompletedTasks: 1}}, nil)
+
+		// Act
+		controller.ListProjects(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Data []map[string]any `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, float64(3), response.Data[0]["open_tasks"])
+		assert.Equal(t, float64(1), response.Data[0]["completed_tasks"])
+	})
+}
+
+func TestProjectController_UpdateProject(t *testing.T) {
+	t.Run("Archive", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupProjectController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/projects/2", map[string]any{"archived": true})
+		ctx.Params = gin.Params{{Key: "id", Value: "2"}}
+
+		archived := true
+		mockService.EXPECT().
+			UpdateProject(gomock.Any(), uint(2), dto.UpdateProjectServiceRequest{Archived: &archived}).
+			Return(&models.Project{ID: 2, Archived: true}, nil)
+
+		// Act
+		controller.UpdateProject(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupProjectController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/projects/x", map[string]any{"archived": true})
+		ctx.Params = gin.Params{{Key: "id", Value: "x"}}
+
+		// Act
+		controller.UpdateProject(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Invalid project ID format")
+	})
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/services"
+)
+
+type ReminderController struct {
+	service services.ReminderService
+	logger  *zap.Logger
+}
+
+func NewReminderController(service services.ReminderService, logger *zap.Logger) *ReminderController {
+	return &ReminderController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateReminder godoc
+// @Summary Add a reminder to a task
+// @Description Remind at remind_at, or before_minutes before the task is due; relative reminders follow the due time
+// @Description when it changes. Reminders are sent through the channel's notifier, log by default, and retried with backoff.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param input body dto.CreateReminderRequest true "Reminder data"
+// @Success 201 {object} dto.Response "Reminder created successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 422 {object} dto.Problem "Time in the past, task without due time or channel not configured"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/reminders [post]
+func (c *ReminderController) CreateReminder(ctx *gin.Context) {
+	taskID, ok := pathID(ctx, c.logger, "id", "task")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateReminderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	reminder, err := c.service.CreateReminder(ctx.Request.Context(), taskID, dto.CreateReminderServiceRequest{
+		Channel:       req.Channel,
+		Email:         req.Email,
+		RemindAt:      req.RemindAt,
+		BeforeMinutes: req.BeforeMinutes,
+	})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to create reminder", err, zap.Uint("task_id", taskID))
+		return
+	}
+
+	c.logger.Info("Reminder created successfully", zap.Uint("task_id", taskID), zap.Uint("reminder_id", reminder.ID))
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse("Reminder created successfully", reminder))
+}
+
+// ListReminders godoc
+// @Summary List the reminders of a task
+// @Description Get the task's reminders, soonest first, with their delivery status, attempts and last error.
+// @Tags reminders
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Success 200 {object} dto.Response "Reminders retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/reminders [get]
+func (c *ReminderController) ListReminders(ctx *gin.Context) {
+	taskID, ok := pathID(ctx, c.logger, "id", "task")
+	if !ok {
+		return
+	}
+
+	reminders, err := c.service.ListReminders(ctx.Request.Context(), taskID)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to list reminders", err, zap.Uint("task_id", taskID))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Reminders retrieved successfully", reminders))
+}
+
+// DeleteReminder godoc
+// @Summary Delete a reminder
+// @Tags reminders
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param reminder_id path int true "Reminder ID"
+// @Success 204 "Reminder deleted successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Reminder not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/reminders/{reminder_id} [delete]
+func (c *ReminderController) DeleteReminder(ctx *gin.Context) {
+	taskID, ok := pathID(ctx, c.logger, "id", "task")
+	if !ok {
+		return
+	}
+	id, ok := pathID(ctx, c.logger, "reminder_id", "reminder")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteReminder(ctx.Request.Context(), taskID, id); err != nil {
+		respondServiceError(ctx, c.logger, "Failed to delete reminder", err, zap.Uint("task_id", taskID), zap.Uint("reminder_id", id))
+		return
+	}
+
+	c.logger.Info("Reminder deleted successfully", zap.Uint("task_id", taskID), zap.Uint("reminder_id", id))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
+}
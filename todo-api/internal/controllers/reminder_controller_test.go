@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+	"todo-api/internal/services/mock"
+)
+
+func setupReminderController(t *testing.T) (*ReminderController, *mock.MockReminderService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockReminderService(ctrl)
+	return NewReminderController(mockService, zaptest.NewLogger(t)), mockService
+}
+
+func TestReminderController_CreateReminder(t *testing.T) {
+	before := 30
+
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupReminderController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/reminders",
+			dto.CreateReminderRequest{Channel: models.ChannelEmail, Email: "me@example.com", BeforeMinutes: &before})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			CreateReminder(gomock.Any(), uint(1), dto.CreateReminderServiceRequest{
+				Channel:       models.ChannelEmail,
+				Email:         "me@example.com",
+				BeforeMinutes: &before,
+			}).
+			Return(&models.Reminder{ID: 4, TaskID: 1, Channel: models.ChannelEmail, BeforeMinutes: &before}, nil)
+
+		// Act
+		controller.CreateReminder(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"before_minutes":30`)
+	})
+
+	t.Run("InvalidChannel", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupReminderController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/reminders",
+			dto.CreateReminderRequest{Channel: "sms", BeforeMinutes: &before})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.CreateReminder(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"channel"`)
+	})
+
+	t.Run("NoDueTime", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupReminderController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/reminders", dto.CreateReminderRequest{BeforeMinutes: &before})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			CreateReminder(gomock.Any(), uint(1), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrValidation, Message: "task 1 has no due time to remind before"})
+
+		// Act
+		controller.CreateReminder(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+}
+
+func TestReminderController_ListReminders(t *testing.T) {
+	// Arrange
+	controller, mockService := setupReminderController(t)
+	ctx, recorder := createTestContext("GET", "/api/v1/tasks/1/reminders", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	at := time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC)
+	mockService.EXPECT().
+		ListReminders(gomock.Any(), uint(1)).
+		Return([]models.Reminder{{ID: 4, TaskID: 1, Channel: models.ChannelLog, RemindAt: &at, FireAt: &at, Status: models.ReminderPending}}, nil)
+
+	// Act
+	controller.ListReminders(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":"pending"`)
+}
+
+func TestReminderController_DeleteReminder(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupReminderController(t)
+		ctx, recorder := createTestContext("DELETE", "/api/v1/tasks/1/reminders/4", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "reminder_id", Value: "4"}}
+
+		mockService.EXPECT().DeleteReminder(gomock.Any(), uint(1), uint(4)).Return(nil)
+
+		// Act
+		controller.DeleteReminder(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("InvalidReminderID", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupReminderController(t)
+		ctx, recorder := createTestContext("DELETE", "/api/v1/tasks/1/reminders/abc", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "reminder_id", Value: "abc"}}
+
+		// Act
+		controller.DeleteReminder(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/audit"
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+type SettingsController struct {
+	service services.SettingsService
+	logger  *zap.Logger
+}
+
+func NewSettingsController(service services.SettingsService, logger *zap.Logger) *SettingsController {
+	return &SettingsController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetSettings godoc
+// @Summary Get the settings of the actor
+// @Description Settings belong to the actor named in X-Actor. An actor who has saved none gets the defaults.
+// @Tags settings
+// @Produce json
+// @Produce application/problem+json
+// @Param X-Actor header string true "Who the settings belong to"
+// @Success 200 {object} dto.Response "Settings retrieved successfully"
+// @Failure 422 {object} dto.Problem "No actor named"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/settings [get]
+func (c *SettingsController) GetSettings(ctx *gin.Context) {
+	actor := audit.FromContext(ctx.Request.Context()).Actor
+
+	settings, err := c.service.GetSettings(ctx.Request.Context(), actor)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to get settings", err, zap.String("actor", actor))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Settings retrieved successfully", settings))
+}
+
+// UpdateSettings godoc
+// @Summary Replace the settings of the actor
+// @Description The time zone is used for the dates of task requests that name none in the tz parameter or the
+// @Description Time-Zone header. An empty time zone falls back to the server default.
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param X-Actor header string true "Who the settings belong to"
+// @Param input body dto.UpdateSettingsRequest true "Settings"
+// @Success 200 {object} dto.Response "Settings updated successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 422 {object} dto.Problem "No actor named, or an unknown time zone"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/settings [put]
+func (c *SettingsController) UpdateSettings(ctx *gin.Context) {
+	var req dto.UpdateSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	actor := audit.FromContext(ctx.Request.Context()).Actor
+	settings, err := c.service.UpdateSettings(ctx.Request.Context(), &models.UserSettings{
+		Actor:    actor,
+		TimeZone: req.TimeZone,
+	})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to update settings", err, zap.String("actor", actor))
+		return
+	}
+
+	c.logger.Info("Settings updated successfully", zap.String("actor", actor))
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Settings updated successfully", settings))
+}
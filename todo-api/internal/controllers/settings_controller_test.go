@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"todo-api/internal/audit"
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+	"todo-api/internal/services/mock"
+)
+
+func setupSettingsController(t *testing.T) (*SettingsController, *mock.MockSettingsService) {
+	t.Helper()
+	mockService := mock.NewMockSettingsService(gomock.NewController(t))
+	return NewSettingsController(mockService, zaptest.NewLogger(t)), mockService
+}
+
+func TestSettingsController_UpdateSettings(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupSettingsController(t)
+		ctx, recorder := createTestContext("PUT", "/api/v1/settings", dto.UpdateSettingsRequest{TimeZone: "Asia/Tokyo"})
+		ctx.Request = ctx.Request.WithContext(audit.WithInfo(ctx.Request.Context(), audit.Info{Actor: "alice"}))
+
+		mockService.EXPECT().
+			UpdateSettings(gomock.Any(), &models.UserSettings{Actor: "alice", TimeZone: "Asia/Tokyo"}).
+			Return(&models.UserSettings{Actor: "alice", TimeZone: "Asia/Tokyo"}, nil)
+
+		// Act
+		controller.UpdateSettings(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"time_zone":"Asia/Tokyo"`)
+	})
+
+	t.Run("NoActor", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupSettingsController(t)
+		ctx, recorder := createTestContext("PUT", "/api/v1/settings", dto.UpdateSettingsRequest{TimeZone: "Asia/Tokyo"})
+
+		mockService.EXPECT().
+			UpdateSettings(gomock.Any(), &models.UserSettings{TimeZone: "Asia/Tokyo"}).
+			Return(nil, &services.Error{Kind: services.ErrValidation, Message: "settings belong to an actor, name one in X-Actor"})
+
+		// Act
+		controller.UpdateSettings(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+}
+
+func TestSettingsController_GetSettings(t *testing.T) {
+	// Arrange
+	controller, mockService := setupSettingsController(t)
+	ctx, recorder := createTestContext("GET", "/api/v1/settings", nil)
+	ctx.Request = ctx.Request.WithContext(audit.WithInfo(ctx.Request.Context(), audit.Info{Actor: "alice"}))
+
+	mockService.EXPECT().GetSettings(gomock.Any(), "alice").Return(&models.UserSettings{Actor: "alice"}, nil)
+
+	// Act
+	controller.GetSettings(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"actor":"alice"`)
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/services"
+)
+
+// streamRetry is how long an EventSource waits before reconnecting.
+const streamRetry = 3 * time.Second
+
+// WithStream enables the task streams, which send a heartbeat whenever
+// they have been idle for heartbeat.
+func WithStream(stream services.StreamService, heartbeat time.Duration) Option {
+	return func(c *TaskController) {
+		c.stream = stream
+		c.heartbeat = heartbeat
+	}
+}
+
+// WithStreamOrigins lets browsers open the WebSocket stream from pages of
+// the given origins, such as https://app.example.com, besides the API's
+// own. "*" allows every origin.
+func WithStreamOrigins(origins []string) Option {
+	return func(c *TaskController) {
+		c.origins = make([]string, 0, len(origins))
+		for _, origin := range origins {
+			if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+				c.origins = append(c.origins, origin)
+			}
+		}
+	}
+}
+
+// StreamTasks godoc
+// @Summary Stream task changes
+// @Description Server-sent events for the tasks created, updated, deleted and restored from the trash from now on, whichever instance of the service made the change.
+// @Description Takes the filters of the task list; paging, sort, tree and highlight do not apply. An updated task that does not match the filter comes as task.left, so that a client showing it can drop it.
+// @Description Every event has its ID as id, its type as event and a dto.StreamEvent as data. Reconnecting with Last-Event-ID replays the events missed since; when they are no longer known, a reset event tells the client to reload its tasks.
+// @Tags tasks
+// @Produce text/event-stream
+// @Produce application/problem+json
+// @Param completed query bool false "Filter by completion status"
+// @Param date_from query string false "Filter by start date (format: 2006-01-02)"
+// @Param date_to query string false "Filter by end date (format: 2006-01-02)"
+// @Param priority query string false "Comma-separated priorities to include (none, low, medium, high, urgent)" example(high,urgent)
+// @Param tags query string false "Comma-separated tag names" example(work,urgent)
+// @Param tag_mode query string false "Match tasks with any (default) or all of the tags" Enums(any, all)
+// @Param project_id query string false "Project ID, or none for tasks outside any project"
+// @Param include_archived query bool false "Include tasks of archived projects"
+// @Param parent_id query string false "Parent task ID, or none for top-level tasks"
+// @Param blocked query bool false "Only tasks with (true) or without (false) an open blocker"
+// @Param q query string false "Search in title and description"
+// @Param tz query string false "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone the date range is taken in, the server default when absent"
+// @Param Last-Event-ID header string false "ID of the last event received, to resume after it"
+// @Param last_event_id query string false "Same as Last-Event-ID, for clients that cannot set headers"
+// @Success 200 {object} dto.StreamEvent "Stream of events"
+// @Failure 400 {object} dto.Problem "Invalid filter parameters"
+// @Router /api/v1/tasks/stream [get]
+func (c *TaskController) StreamTasks(ctx *gin.Context) {
+	filter, lastEventID, ok := c.streamRequest(ctx)
+	if !ok {
+		return
+	}
+
+	sub := c.stream.Subscribe(ctx.Request.Context(), filter, lastEventID)
+	defer sub.Close()
+	c.logger.Info("Task stream opened", zap.String("last_event_id", lastEventID), zap.Bool("reset", sub.Reset))
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // keeps nginx from buffering the stream
+	ctx.Status(http.StatusOK)
+	fmt.Fprintf(ctx.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	c.serveStream(ctx, sub,
+		func(message dto.StreamEvent) error {
+			err := writeServerSentEvent(ctx.Writer, message)
+			ctx.Writer.Flush()
+			return err
+		},
+		func() error {
+			_, err := io.WriteString(ctx.Writer, ": heartbeat\n\n")
+			ctx.Writer.Flush()
+			return err
+		},
+		ctx.Request.Context().Done(),
+	)
+}
+
+// StreamTasksWebSocket godoc
+// @Summary Stream task changes over a WebSocket
+// @Description The events of GET /api/v1/tasks/stream as JSON text messages, one dto.StreamEvent each, for clients that prefer a WebSocket.
+// @Description Takes the same filters. Resume with last_event_id; a message of type reset tells the client to reload its tasks. When the server drops the stream it closes the socket with code 1013 (try again later), and the client reconnects with the ID of the last event it got. Messages from the client are ignored.
+// @Tags tasks
+// @Param completed query bool false "Filter by completion status"
+// @Param date_from query string false "Filter by start date (format: 2006-01-02)"
+// @Param date_to query string false "Filter by end date (format: 2006-01-02)"
+// @Param priority query string false "Comma-separated priorities to include (none, low, medium, high, urgent)" example(high,urgent)
+// @Param tags query string false "Comma-separated tag names" example(work,urgent)
+// @Param tag_mode query string false "Match tasks with any (default) or all of the tags" Enums(any, all)
+// @Param project_id query string false "Project ID, or none for tasks outside any project"
+// @Param include_archived query bool false "Include tasks of archived projects"
+// @Param parent_id query string false "Parent task ID, or none for top-level tasks"
+// @Param blocked query bool false "Only tasks with (true) or without (false) an open blocker"
+// @Param q query string false "Search in title and description"
+// @Param tz query string false "IANA time zone the days of date_from and date_to are taken in"
+// @Param last_event_id query string false "ID of the last event received, to resume after it"
+// @Success 101 {object} dto.StreamEvent "Switching to the WebSocket protocol"
+// @Failure 400 {object} dto.Problem "Invalid filter parameters or not a WebSocket handshake"
+// @Failure 403 {string} string "The page's origin is not allowed to open the stream"
+// @Router /api/v1/tasks/stream/ws [get]
+func (c *TaskController) StreamTasksWebSocket(ctx *gin.Context) {
+	filter, lastEventID, ok := c.streamRequest(ctx)
+	if !ok {
+		return
+	}
+
+	upgrader := websocket.Upgrader{CheckOrigin: c.checkOrigin}
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// Upgrade has responded with the error already.
+		c.logger.Warn("WebSocket handshake failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	sub := c.stream.Subscribe(ctx.Request.Context(), filter, lastEventID)
+	defer sub.Close()
+	c.logger.Info("Task WebSocket opened", zap.String("last_event_id", lastEventID), zap.Bool("reset", sub.Reset))
+
+	// Reading answers the client's control frames and notices it leaving.
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	deadline := func() time.Time {
+		return time.Now().Add(c.heartbeat)
+	}
+	dropped := c.serveStream(ctx, sub,
+		func(message dto.StreamEvent) error {
+			_ = conn.SetWriteDeadline(deadline())
+			return conn.WriteJSON(message)
+		},
+		func() error {
+			return conn.WriteControl(websocket.PingMessage, nil, deadline())
+		},
+		gone,
+	)
+	if dropped {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "reconnect with last_event_id"), deadline())
+	}
+}
+
+// checkOrigin accepts WebSocket handshakes without an Origin header, which
+// browsers always send, from the API's own origin and from the allowed
+// ones. Upgrade responds with 403 to the rest.
+func (c *TaskController) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range c.origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamRequest parses the filter and the resume position of a stream,
+// responding with a problem when the filter is invalid.
+func (c *TaskController) streamRequest(ctx *gin.Context) (dto.TaskFilter, string, bool) {
+	_, filter, ok := c.listFilter(ctx)
+	if !ok {
+		return dto.TaskFilter{}, "", false
+	}
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+	return filter, lastEventID, true
+}
+
+// serveStream sends the messages of a subscription until the client is
+// gone or a write fails, pinging it when idle. It reports whether the
+// subscription was dropped.
+func (c *TaskController) serveStream(
+	ctx *gin.Context,
+	sub *services.Subscription,
+	send func(dto.StreamEvent) error,
+	ping func() error,
+	gone <-chan struct{},
+) bool {
+	pending := sub.Replay
+	if sub.Reset {
+		pending = append([]dto.StreamEvent{{Type: dto.StreamReset}}, pending...)
+	}
+	for _, message := range pending {
+		if err := send(message); err != nil {
+			return false
+		}
+	}
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-gone:
+			return false
+		case message, ok := <-sub.Events:
+			if !ok {
+				c.logger.Info("Task stream dropped", zap.String("path", ctx.FullPath()))
+				return true
+			}
+			err = send(message)
+			heartbeat.Reset(c.heartbeat)
+		case <-heartbeat.C:
+			err = ping()
+		}
+		if err != nil {
+			c.logger.Info("Task stream closed", zap.Error(err))
+			return false
+		}
+	}
+}
+
+// writeServerSentEvent writes a message in the text/event-stream format.
+func writeServerSentEvent(w io.Writer, message dto.StreamEvent) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if message.ID != "" {
+		if _, err = fmt.Fprintf(w, "id: %s\n", message.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Type, data)
+	return err
+}
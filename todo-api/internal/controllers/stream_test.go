@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+	"todo-api/internal/services/mock"
+)
+
+func setupStreamController(t *testing.T) (*TaskController, *mock.MockStreamService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStream := mock.NewMockStreamService(ctrl)
+	controller := NewTaskController(mock.NewMockTaskService(ctrl), zaptest.NewLogger(t),
+		WithStream(mockStream, time.Minute))
+	return controller, mockStream
+}
+
+// closedSubscription returns a subscription that delivers messages and is
+// then dropped.
+func closedSubscription(messages ...dto.StreamEvent) *services.Subscription {
+	events := make(chan dto.StreamEvent, len(messages))
+	for _, message := range messages {
+		events <- message
+	}
+	close(events)
+	return &services.Subscription{Events: events}
+}
+
+func TestTaskController_StreamTasks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockStream := setupStreamController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/stream?completed=false&priority=high", nil)
+		ctx.Request.Header.Set("Last-Event-ID", "e1")
+
+		sub := closedSubscription(dto.StreamEvent{ID: "e3", Type: "task.updated", Task: &models.Task{Model: gorm.Model{ID: 7}}})
+		sub.Replay = []dto.StreamEvent{{ID: "e2", Type: "task.created", Task: &models.Task{Model: gorm.Model{ID: 7}}}}
+		mockStream.EXPECT().Subscribe(gomock.Any(), gomock.Any(), "e1").
+			DoAndReturn(func(_ context.Context, filter dto.TaskFilter, _ string) *services.Subscription {
+				assert.False(t, *filter.Completed)
+				assert.Equal(t, []models.Priority{models.PriorityHigh}, filter.Priorities)
+				return sub
+			})
+
+		// Act
+		controller.StreamTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+		body := recorder.Body.String()
+		assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+		assert.Contains(t, body, "id: e2\nevent: task.created\ndata: {\"id\":\"e2\",\"type\":\"task.created\",\"task\":{")
+		assert.Less(t, strings.Index(body, "id: e2"), strings.Index(body, "id: e3"))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		// Arrange
+		controller, mockStream := setupStreamController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/stream?last_event_id=e0", nil)
+
+		sub := closedSubscription()
+		sub.Reset = true
+		mockStream.EXPECT().Subscribe(gomock.Any(), gomock.Any(), "e0").Return(sub)
+
+		// Act
+		controller.StreamTasks(ctx)
+
+		// Assert
+		assert.Contains(t, recorder.Body.String(), "event: reset\ndata: {\"type\":\"reset\"}\n\n")
+		assert.NotContains(t, recorder.Body.String(), "id:")
+	})
+
+	t.Run("InvalidFilter", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupStreamController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/stream?project_id=abc", nil)
+
+		// Act
+		controller.StreamTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"project_id"`)
+	})
+}
+
+func TestTaskController_StreamTasksWebSocket(t *testing.T) {
+	// Arrange
+	controller, mockStream := setupStreamController(t)
+	router := gin.New()
+	router.GET("/api/v1/tasks/stream/ws", controller.StreamTasksWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	sub := closedSubscription(dto.StreamEvent{ID: "e3", Type: dto.StreamTaskLeft, Task: &models.Task{Model: gorm.Model{ID: 7}}})
+	sub.Reset = true
+	mockStream.EXPECT().Subscribe(gomock.Any(), gomock.Any(), "e1").Return(sub)
+
+	// Act
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/tasks/stream/ws?last_event_id=e1", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	var reset, left dto.StreamEvent
+	resetErr := conn.ReadJSON(&reset)
+	leftErr := conn.ReadJSON(&left)
+	_, _, closeErr := conn.ReadMessage()
+
+	// Assert
+	require.NoError(t, resetErr)
+	require.NoError(t, leftErr)
+	assert.Equal(t, dto.StreamReset, reset.Type)
+	assert.Equal(t, "e3", left.ID)
+	assert.Equal(t, uint(7), left.Task.ID)
+	assert.True(t, websocket.IsCloseError(closeErr, websocket.CloseTryAgainLater))
+}
+
+func TestTaskController_StreamTasksWebSocketOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{name: "no origin", origin: "", want: true},
+		{name: "same origin", origin: "http://api.example.com", want: true},
+		{name: "other origin by default", origin: "https://app.example.com", want: false},
+		{name: "allowed origin", allowed: []string{"https://app.example.com/"}, origin: "https://app.example.com", want: true},
+		{name: "other than allowed", allowed: []string{"https://app.example.com"}, origin: "https://evil.example.com", want: false},
+		{name: "any origin", allowed: []string{"*"}, origin: "https://evil.example.com", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			controller := NewTaskController(nil, zaptest.NewLogger(t), WithStreamOrigins(tt.allowed))
+			req := httptest.NewRequest(http.MethodGet, "http://api.example.com/api/v1/tasks/stream/ws", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			// Act
+			ok := controller.checkOrigin(req)
+
+			// Assert
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
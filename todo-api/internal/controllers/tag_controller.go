@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+type TagController struct {
+	service services.TagService
+	logger  *zap.Logger
+}
+
+func NewTagController(service services.TagService, logger *zap.Logger) *TagController {
+	return &TagController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateTag godoc
+// @Summary Create a new tag
+// @Description Add a tag that can be attached to tasks by name. Names are unique.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param input body dto.CreateTagRequest true "Tag creation data"
+// @Success 201 {object} dto.Response "Tag created successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 409 {object} dto.Problem "A tag with this name already exists"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Header 201 {string} Location "URL of the created tag"
+// @Router /api/v1/tags [post]
+func (c *TagController) CreateTag(ctx *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	tag, err := c.service.CreateTag(ctx.Request.Context(), &models.Tag{Name: req.Name, Color: req.Color})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to create tag", err)
+		return
+	}
+
+	c.logger.Info("Tag created successfully", zap.Uint("tag_id", tag.ID))
+	ctx.Header("Location", fmt.Sprintf("/api/v1/tags/%d", tag.ID))
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse("Tag created successfully", tag))
+}
+
+// GetTag godoc
+// @Summary Get tag by ID
+// @Tags tags
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} dto.Response "Tag retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Tag not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tags/{id} [get]
+func (c *TagController) GetTag(ctx *gin.Context) {
+	id, ok := c.tagID(ctx)
+	if !ok {
+		return
+	}
+
+	tag, err := c.service.GetTag(ctx.Request.Context(), id)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to get tag", err, zap.Uint("tag_id", id))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Tag retrieved successfully", tag))
+}
+
+// ListTags godoc
+// @Summary List all tags
+// @Description Get every tag ordered by name
+// @Tags tags
+// @Produce json
+// @Produce application/problem+json
+// @Success 200 {object} dto.Response "Tags retrieved successfully"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tags [get]
+func (c *TagController) ListTags(ctx *gin.Context) {
+	tags, err := c.service.ListTags(ctx.Request.Context())
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to list tags", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Tags retrieved successfully", tags))
+}
+
+// UpdateTag godoc
+// @Summary Update a tag
+// @Description Rename or recolor a tag. Tasks keep the tag under its new name.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Tag ID"
+// @Param input body dto.UpdateTagRequest true "Tag update data"
+// @Success 200 {object} dto.Response "Tag updated successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 404 {object} dto.Problem "Tag not found"
+// @Failure 409 {object} dto.Problem "A tag with this name already exists"
+// @Failure 422 {object} dto.Problem "No fields to update"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tags/{id} [patch]
+func (c *TagController) UpdateTag(ctx *gin.Context) {
+	id, ok := c.tagID(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	tag, err := c.service.UpdateTag(ctx.Request.Context(), id, dto.UpdateTagServiceRequest{
+		Name:  req.Name,
+		Color: req.Color,
+	})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to update tag", err, zap.Uint("tag_id", id))
+		return
+	}
+
+	c.logger.Info("Tag updated successfully", zap.Uint("tag_id", tag.ID))
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Tag updated successfully", tag))
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag
+// @Description Delete a tag and detach it from all tasks, each getting a new version and a task.updated event
+// @Tags tags
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Tag ID"
+// @Success 204 "Tag deleted successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Tag not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tags/{id} [delete]
+func (c *TagController) DeleteTag(ctx *gin.Context) {
+	id, ok := c.tagID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteTag(ctx.Request.Context(), id); err != nil {
+		respondServiceError(ctx, c.logger, "Failed to delete tag", err, zap.Uint("tag_id", id))
+		return
+	}
+
+	c.logger.Info("Tag deleted successfully", zap.Uint("tag_id", id))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// tagID parses the id path parameter, responding with 400 when it is not a
+// valid ID.
+func (c *TagController) tagID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		c.logger.Warn("Invalid tag ID format",
+			zap.String("id_param", ctx.Param("id")),
+			zap.Error(err),
+		)
+		respondProblem(ctx, invalidResourceIDProblem("tag"))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+	"todo-api/internal/services/mock"
+)
+
+func setupTagController(t *testing.T) (*TagController, *mock.MockTagService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockTagService(ctrl)
+	return NewTagController(mockService, zaptest.NewLogger(t)), mockService
+}
+
+func TestTagController_CreateTag(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTagController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tags", dto.CreateTagRequest{Name: "work", Color: "#00ff00"})
+
+		mockService.EXPECT().
+			CreateTag(gomock.Any(), &models.Tag{Name: "work", Color: "#00ff00"}).
+			Return(&models.Tag{ID: 4, Name: "work", Color: "#00ff00"}, nil)
+
+		// Act
+		controller.CreateTag(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, "/api/v1/tags/4", recorder.Header().Get("Location"))
+	})
+
+	t.Run("InvalidColor", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTagController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tags", dto.CreateTagRequest{Name: "work", Color: "green"})
+
+		// Act
+		controller.CreateTag(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"color"`)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTagController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tags", dto.CreateTagRequest{Name: "work"})
+
+		mockService.EXPECT().
+			CreateTag(gomock.Any(), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrConflict, Message: "a tag with this name already exists", Err: gorm.ErrDuplicatedKey})
+
+		// Act
+		controller.CreateTag(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+}
+
+func TestTagController_DeleteTag(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTagController(t)
+		ctx, recorder := createTestContext("DELETE", "/api/v1/tags/4", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "4"}}
+
+		mockService.EXPECT().DeleteTag(gomock.Any(), uint(4)).Return(nil)
+
+		// Act
+		controller.DeleteTag(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTagController(t)
+		ctx, recorder := createTestContext("DELETE", "/api/v1/tags/abc", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		// Act
+		controller.DeleteTag(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Invalid tag ID format")
+	})
+}
@@ -1,28 +1,53 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/zap"
 
 	"todo-api/internal/dto"
+	"todo-api/internal/models"
 	"todo-api/internal/services"
+	"todo-api/pkg/jsonpatch"
 )
 
 type TaskController struct {
-	service services.TaskService
-	logger  *zap.Logger
+	service        services.TaskService
+	logger         *zap.Logger
+	requireIfMatch bool
+	location       *time.Location // default time zone of requests, nil for UTC
+	settings       services.SettingsService
+	stream         services.StreamService
+	heartbeat      time.Duration // idle time after which streams send a heartbeat
+	origins        []string      // other origins browsers may open the WebSocket stream from
 }
 
-func NewTaskController(service services.TaskService, logger *zap.Logger) *TaskController {
-	return &TaskController{
+type Option func(*TaskController)
+
+// WithRequireIfMatch makes writes without an If-Match header fail with 428.
+func WithRequireIfMatch(require bool) Option {
+	return func(c *TaskController) {
+		c.requireIfMatch = require
+	}
+}
+
+func NewTaskController(service services.TaskService, logger *zap.Logger, opts ...Option) *TaskController {
+	c := &TaskController{
 		service: service,
 		logger:  logger,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // CreateTask godoc
@@ -31,55 +56,66 @@ func NewTaskController(service services.TaskService, logger *zap.Logger) *TaskCo
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Produce application/problem+json
 // @Param input body dto.CreateTaskRequest true "Task creation data"
+// @Param tz query string false "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone of dates and due times, the server default when absent"
 // @Success 201 {object} dto.Response "Task created successfully"
-// @Failure 400 {object} dto.Response "Invalid input data"
-// @Failure 500 {object} dto.Response "Internal server error"
-// @Router /tasks/create [post]
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 422 {object} dto.Problem "Task date is in the past or invalid recurrence"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Header 201 {string} Location "URL of the created task"
+// @Header 201 {string} ETag "Task version"
+// @Router /api/v1/tasks [post]
 func (c *TaskController) CreateTask(ctx *gin.Context) {
-	fmt.Println("CreateTask")
-
 	var req dto.CreateTaskRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		c.logger.Error("Invalid request data", zap.Error(err))
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse(err.Error()))
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
 		return
 	}
 
-	parsedDate, err := time.Parse("2006-01-02", req.DateString)
+	serviceReq, err := convertToServiceCreate(&req)
 	if err != nil {
-		c.logger.Error("Invalid date format", zap.Error(err))
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse("Invalid date format, expected YYYY-MM-DD"))
+		c.logger.Warn("Invalid date format", zap.Error(err))
+		respondProblem(ctx, invalidDateProblem("date"))
 		return
 	}
 
-	serviceReq := dto.CreateTaskServiceRequest{
-		Title:       req.Title,
-		Description: req.Description,
-		Date:        parsedDate,
+	var ok bool
+	if serviceReq.Location, ok = c.requestLocation(ctx); !ok {
+		return
 	}
 
 	task, err := c.service.CreateTask(ctx.Request.Context(), serviceReq)
 	if err != nil {
-		c.logger.Error("Failed to create task", zap.Error(err))
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse(err.Error()))
+		c.respondError(ctx, "Failed to create task", err)
 		return
 	}
 
 	c.logger.Info("Task created successfully", zap.Uint("task_id", task.ID))
+	ctx.Header("Location", fmt.Sprintf("/api/v1/tasks/%d", task.ID))
+	setETag(ctx, task)
 	ctx.JSON(http.StatusCreated, dto.SuccessResponse("Task created successfully", task))
 }
 
 // GetTaskByID godoc
 // @Summary Get task by ID
-// @Description Get a single task by its ID
+// @Description Get a single task by its ID.
+// @Description With as_of, the task as it was at that moment, rebuilt from its history; it may be in the trash now.
+// @Description Fields the history does not follow (updated_at, subtask counters, blocked) are as they are now, and there is no ETag.
 // @Tags tasks
 // @Produce json
+// @Produce application/problem+json
 // @Param id path int true "Task ID"
+// @Param as_of query string false "RFC 3339 timestamp to see the task at" example(2026-03-01T09:00:00Z)
 // @Success 200 {object} dto.Response "Task retrieved successfully"
-// @Failure 400 {object} dto.Response "Invalid ID format"
-// @Failure 500 {object} dto.Response "Internal server error"
-// @Router /tasks/get/{id} [get]
+// @Header 200 {string} ETag "Task version, to be sent back in If-Match"
+// @Failure 400 {object} dto.Problem "Invalid ID format or as_of"
+// @Failure 404 {object} dto.Problem "Task not found, or it did not exist or was in the trash at as_of"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Failure 501 {object} dto.Problem "The task history is not available for as_of"
+// @Router /api/v1/tasks/{id} [get]
 func (c *TaskController) GetTaskByID(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
@@ -87,36 +123,63 @@ func (c *TaskController) GetTaskByID(ctx *gin.Context) {
 			zap.String("id_param", ctx.Param("id")),
 			zap.Error(err),
 		)
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse("Invalid task ID format"))
+		respondProblem(ctx, invalidIDProblem())
+		return
+	}
+
+	var req dto.GetTaskRequest
+	if err = ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warn("Invalid task parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+	if req.AsOf != "" {
+		c.getTaskAsOf(ctx, uint(id), req.AsOf)
 		return
 	}
 
 	task, err := c.service.GetTaskByID(ctx.Request.Context(), uint(id))
 	if err != nil {
-		c.logger.Error("Failed to get task",
-			zap.Uint("task_id", uint(id)),
-			zap.Error(err),
-		)
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse(err.Error()))
+		c.respondError(ctx, "Failed to get task", err, zap.Uint("task_id", uint(id)))
 		return
 	}
 
 	c.logger.Debug("Task retrieved", zap.Uint("task_id", task.ID))
+	setETag(ctx, task)
 	ctx.JSON(http.StatusOK, dto.SuccessResponse("Task retrieved successfully", task))
 }
 
 // UpdateTask godoc
 // @Summary Update a task
-// @Description Update an existing task by ID
+// @Description Update the given fields of an existing task by ID. The body depends on the Content-Type:
+// @Description application/json lists the fields to change, as in dto.UpdateTaskRequest;
+// @Description application/merge-patch+json is a JSON Merge Patch (RFC 7396) and application/json-patch+json a JSON Patch (RFC 6902)
+// @Description of the task as a dto.TaskDocument, where null in a merge patch clears a field and test operations must hold.
+// @Description A patched task is checked by the rules of task creation.
 // @Tags tasks
 // @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
 // @Produce json
+// @Produce application/problem+json
 // @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the task being updated"
+// @Param force query bool false "Complete the task even while blockers are open"
 // @Param input body dto.UpdateTaskRequest true "Task update data"
+// @Param tz query string false "IANA time zone of dates and due times, e.g. Europe/Moscow; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone of dates and due times, the server default when absent"
 // @Success 200 {object} dto.Response "Task updated successfully"
-// @Failure 400 {object} dto.Response "Invalid input data or no fields to update"
-// @Failure 500 {object} dto.Response "Internal server error"
-// @Router /tasks/update/{id} [put]
+// @Header 200 {string} ETag "New task version"
+// @Failure 400 {object} dto.Problem "Invalid input data or malformed patch"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 409 {object} dto.Problem "Task is blocked by open tasks, or the patch does not apply to the task"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 415 {object} dto.Problem "Unsupported Content-Type"
+// @Failure 422 {object} dto.Problem "No fields to update, or the patched task is invalid"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Header 415 {string} Accept-Patch "Content types PATCH takes"
+// @Router /api/v1/tasks/{id} [patch]
 func (c *TaskController) UpdateTask(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
@@ -124,59 +187,90 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 			zap.String("id_param", ctx.Param("id")),
 			zap.Error(err),
 		)
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse("Invalid task ID format"))
+		respondProblem(ctx, invalidIDProblem())
+		return
+	}
+
+	switch contentType := ctx.ContentType(); contentType {
+	case mergePatchContentType:
+		c.patchTask(ctx, uint(id), jsonpatch.MergePatch)
+		return
+	case jsonPatchContentType:
+		c.patchTask(ctx, uint(id), jsonpatch.Apply)
+		return
+	case binding.MIMEJSON, "":
+	default:
+		c.logger.Warn("Unsupported patch type", zap.String("content_type", contentType))
+		ctx.Header("Accept-Patch", acceptPatch)
+		respondProblem(ctx, unsupportedPatchProblem(contentType))
 		return
 	}
 
 	var req dto.UpdateTaskRequest
 	if err = ctx.ShouldBindJSON(&req); err != nil {
-		c.logger.Error("Invalid request data", zap.Error(err))
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse(err.Error()))
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
 		return
 	}
 
-	serviceReq := dto.UpdateTaskServiceRequest{
-		Title:       req.Title,
-		Description: req.Description,
-		Completed:   req.Completed,
+	var query dto.UpdateTaskQuery
+	if err = ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Warn("Invalid update parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
 	}
 
-	if req.DateString != nil {
-		parsedDate, err := time.Parse("2006-01-02", *req.DateString)
-		if err != nil {
-			c.logger.Error("Invalid date format", zap.Error(err))
-			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse("Invalid date format, expected YYYY-MM-DD"))
-			return
-		}
-		serviceReq.Date = &parsedDate
+	loc, ok := c.requestLocation(ctx)
+	if !ok {
+		return
+	}
+
+	version, problem := c.ifMatchVersion(ctx, uint(id))
+	if problem != nil {
+		c.logger.Warn("Update precondition not met", zap.Uint("task_id", uint(id)), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return
+	}
+
+	serviceReq, err := convertToServiceUpdate(&req)
+	if err != nil {
+		c.logger.Warn("Invalid date format", zap.Error(err))
+		respondProblem(ctx, invalidDateProblem("date"))
+		return
 	}
+	serviceReq.Force = query.Force
+	serviceReq.Version = version
+	serviceReq.Location = loc
 
 	task, err := c.service.UpdateTask(ctx.Request.Context(), uint(id), serviceReq)
 	if err != nil {
-		if err.Error() == "no fields to update" {
-			c.logger.Warn("No fields to update provided", zap.Uint("task_id", uint(id)))
-			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse("No fields to update"))
-			return
-		}
-		c.logger.Error("Failed to update task", zap.Error(err))
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse(err.Error()))
+		c.respondError(ctx, "Failed to update task", err, zap.Uint("task_id", uint(id)))
 		return
 	}
 
 	c.logger.Info("Task updated successfully", zap.Uint("task_id", task.ID))
+	setETag(ctx, task)
 	ctx.JSON(http.StatusOK, dto.SuccessResponse("Task updated successfully", task))
 }
 
 // DeleteTask godoc
 // @Summary Delete a task
-// @Description Delete a task by ID
+// @Description Delete a task by ID. It goes to the trash, from where it can be restored, unless permanent=true;
+// @Description that also deletes a task already in the trash for good.
 // @Tags tasks
 // @Produce json
+// @Produce application/problem+json
 // @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the task being deleted"
+// @Param subtasks query string false "Delete subtasks too (cascade) or move them up to the task's parent (reparent, default)" Enums(cascade, reparent)
+// @Param permanent query bool false "Delete the task for good instead of moving it to the trash"
 // @Success 204 "Task deleted successfully"
-// @Failure 400 {object} dto.Response "Invalid ID format"
-// @Failure 500 {object} dto.Response "Internal server error"
-// @Router /tasks/delete/{id} [delete]
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id} [delete]
 func (c *TaskController) DeleteTask(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
@@ -184,52 +278,386 @@ func (c *TaskController) DeleteTask(ctx *gin.Context) {
 			zap.String("id_param", ctx.Param("id")),
 			zap.Error(err),
 		)
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse("Invalid task ID format"))
+		respondProblem(ctx, invalidIDProblem())
 		return
 	}
 
-	if err = c.service.DeleteTask(ctx.Request.Context(), uint(id)); err != nil {
-		c.logger.Error("Failed to delete task", zap.Error(err))
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse(err.Error()))
+	var req dto.DeleteTaskRequest
+	if err = ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warn("Invalid delete parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	version, problem := c.ifMatchVersion(ctx, uint(id))
+	if problem != nil {
+		c.logger.Warn("Delete precondition not met", zap.Uint("task_id", uint(id)), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return
+	}
+
+	cascade := req.Subtasks == "cascade"
+	if req.Permanent {
+		err = c.service.PurgeTask(ctx.Request.Context(), uint(id), version, cascade)
+	} else {
+		err = c.service.DeleteTask(ctx.Request.Context(), uint(id), version, cascade)
+	}
+	if err != nil {
+		c.respondError(ctx, "Failed to delete task", err, zap.Uint("task_id", uint(id)))
 		return
 	}
 
-	c.logger.Info("Task deleted successfully", zap.Uint("task_id", uint(id)))
-	ctx.Status(http.StatusOK)
+	c.logger.Info("Task deleted successfully", zap.Uint("task_id", uint(id)), zap.Bool("permanent", req.Permanent))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
 }
 
 // ListTasks godoc
 // @Summary List all tasks
-// @Description Get a list of tasks with optional filtering
+// @Description Get a page of tasks with optional filtering. The default order is date, then the manual order within the day (rank), or relevance when searching; ID always breaks ties.
+// @Description Pages are addressed either by an opaque cursor from meta.next_cursor/meta.prev_cursor or by limit/offset.
+// @Description With both date_from and date_to, recurring tasks are expanded into their occurrences in the range, each with series_id and occurrence_date set.
 // @Tags tasks
 // @Produce json
+// @Produce application/problem+json
 // @Param completed query bool false "Filter by completion status"
 // @Param date_from query string false "Filter by start date (format: 2006-01-02)"
 // @Param date_to query string false "Filter by end date (format: 2006-01-02)"
-// @Param limit query int false "Limit number of results (default: 10)"
-// @Param offset query int false "Offset for pagination"
-// @Success 200 {object} dto.Response "Tasks retrieved successfully"
-// @Failure 400 {object} dto.Response "Invalid filter parameters"
-// @Failure 500 {object} dto.Response "Internal server error"
-// @Router /tasks/list [get]
+// @Param limit query int false "Limit number of results (default: 10, capped by the server maximum)"
+// @Param offset query int false "Offset for pagination, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor from a previous page"
+// @Param sort query string false "Comma-separated sort fields, '-' for descending (date, rank, priority, title, completed, created_at, updated_at, id, relevance)" example(date,-priority,title)
+// @Param priority query string false "Comma-separated priorities to include (none, low, medium, high, urgent)" example(high,urgent)
+// @Param tags query string false "Comma-separated tag names" example(work,urgent)
+// @Param tag_mode query string false "Match tasks with any (default) or all of the tags" Enums(any, all)
+// @Param project_id query string false "Project ID, or none for tasks outside any project"
+// @Param include_archived query bool false "Include tasks of archived projects"
+// @Param parent_id query string false "Parent task ID, or none for top-level tasks"
+// @Param tree query bool false "Nest subtasks under their parents; lists top-level tasks unless parent_id is set"
+// @Param blocked query bool false "Only tasks with (true) or without (false) an open blocker"
+// @Param q query string false "Full-text search in title and description"
+// @Param highlight query bool false "Return a snippet with <mark> highlights for each match (needs q)"
+// @Param tz query string false "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone the date range is taken in, the server default when absent"
+// @Success 200 {object} dto.Response{meta=dto.PageMeta} "Tasks retrieved successfully"
+// @Header 200 {string} Link "RFC 8288 links to the first, next and prev pages"
+// @Failure 400 {object} dto.Problem "Invalid filter parameters"
+// @Failure 422 {object} dto.Problem "Too many recurring tasks or occurrences in the date range to expand"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks [get]
 func (c *TaskController) ListTasks(ctx *gin.Context) {
-	filterReq, err := parseTaskFilter(ctx)
+	c.listPage(ctx, c.service.ListTasks, "Tasks retrieved successfully")
+}
+
+// listPage responds with the page of tasks list returns for the filter,
+// sort and cursor parameters of the request.
+func (c *TaskController) listPage(
+	ctx *gin.Context,
+	list func(context.Context, dto.TaskFilter) (*dto.TaskPage, error),
+	message string,
+) {
+	filterReq, filter, ok := c.listFilter(ctx)
+	if !ok {
+		return
+	}
+
+	var err error
+	if filterReq.Sort != nil {
+		filter.Sort, err = dto.ParseTaskSort(*filterReq.Sort)
+		if err != nil {
+			c.logger.Warn("Invalid sort", zap.String("sort", *filterReq.Sort), zap.Error(err))
+			respondProblem(ctx, validationProblem("Invalid sort", dto.FieldError{
+				Field:   "sort",
+				Rule:    "sort",
+				Message: err.Error() + "; allowed fields are " + strings.Join(dto.TaskSortFields(), ", "),
+			}))
+			return
+		}
+		if slices.ContainsFunc(filter.Sort, func(f dto.SortField) bool { return f.Field == "relevance" }) &&
+			filter.Query == "" {
+			c.logger.Warn("Relevance sort without query", zap.String("sort", *filterReq.Sort))
+			respondProblem(ctx, validationProblem("Invalid sort", dto.FieldError{
+				Field:   "sort",
+				Rule:    "sort",
+				Message: "relevance can only be used together with q",
+			}))
+			return
+		}
+	}
+
+	if filterReq.Cursor != nil {
+		filter.Cursor, err = dto.DecodeCursor(*filterReq.Cursor)
+		if err != nil {
+			c.logger.Warn("Invalid cursor", zap.String("cursor", *filterReq.Cursor))
+			respondProblem(ctx, validationProblem("Invalid cursor", dto.FieldError{
+				Field:   "cursor",
+				Rule:    "cursor",
+				Message: "must be a cursor returned by a previous page",
+			}))
+			return
+		}
+		if !slices.Equal(filter.Cursor.Sort, filter.OrderOrDefault()) {
+			c.logger.Warn("Cursor does not match sort", zap.String("cursor", *filterReq.Cursor))
+			respondProblem(ctx, validationProblem("Invalid cursor", dto.FieldError{
+				Field:   "cursor",
+				Rule:    "sort",
+				Message: "was issued for sort " + dto.SortSpec(filter.Cursor.Sort) + ", not the requested one",
+			}))
+			return
+		}
+	}
+
+	page, err := list(ctx.Request.Context(), filter)
 	if err != nil {
-		c.logger.Error("Invalid filter parameters", zap.Error(err))
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse(err.Error()))
+		c.respondError(ctx, "Failed to list tasks", err)
 		return
 	}
 
-	filter := convertToServiceFilter(filterReq)
-	tasks, err := c.service.ListTasks(ctx.Request.Context(), filter)
+	c.logger.Info("Tasks listed successfully", zap.Int("count", len(page.Tasks)), zap.Int64("total", page.Meta.Total))
+	setPageLinks(ctx, page.Meta)
+	ctx.JSON(http.StatusOK, dto.PageResponse(message, page.Tasks, page.Meta))
+}
+
+// MoveTask godoc
+// @Summary Move a task in the manual order
+// @Description Place a task right after after_id and/or right before before_id, which must be on the target date.
+// @Description Without neighbours the task goes to the end of the day. date moves the task to another day.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the task being moved"
+// @Param input body dto.MoveTaskRequest true "New position"
+// @Success 200 {object} dto.Response "Task moved successfully"
+// @Header 200 {string} ETag "New task version"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 422 {object} dto.Problem "Neighbours are not on the target date or not adjacent"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/move [post]
+func (c *TaskController) MoveTask(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		c.logger.Error("Failed to list tasks", zap.Error(err))
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse(err.Error()))
+		c.logger.Warn("Invalid task ID format",
+			zap.String("id_param", ctx.Param("id")),
+			zap.Error(err),
+		)
+		respondProblem(ctx, invalidIDProblem())
+		return
+	}
+
+	var req dto.MoveTaskRequest
+	if err = ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	version, problem := c.ifMatchVersion(ctx, uint(id))
+	if problem != nil {
+		c.logger.Warn("Move precondition not met", zap.Uint("task_id", uint(id)), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
 		return
 	}
 
-	c.logger.Info("Tasks listed successfully", zap.Int("count", len(tasks)))
-	ctx.JSON(http.StatusOK, dto.SuccessResponse("Tasks retrieved successfully", tasks))
+	serviceReq := dto.MoveTaskServiceRequest{
+		AfterID:  req.AfterID,
+		BeforeID: req.BeforeID,
+		Version:  version,
+	}
+
+	if req.DateString != nil {
+		parsedDate, err := time.Parse("2006-01-02", *req.DateString)
+		if err != nil {
+			c.logger.Warn("Invalid date format", zap.Error(err))
+			respondProblem(ctx, invalidDateProblem("date"))
+			return
+		}
+		serviceReq.Date = &parsedDate
+	}
+
+	task, err := c.service.MoveTask(ctx.Request.Context(), uint(id), serviceReq)
+	if err != nil {
+		c.respondError(ctx, "Failed to move task", err, zap.Uint("task_id", uint(id)))
+		return
+	}
+
+	c.logger.Info("Task moved successfully", zap.Uint("task_id", task.ID), zap.String("rank", task.Rank))
+	setETag(ctx, task)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Task moved successfully", task))
+}
+
+// ListSubtasks godoc
+// @Summary List subtasks of a task
+// @Description Get the direct subtasks of a task in manual order, or its whole subtree with tree=true.
+// @Tags tasks
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param tree query bool false "Nest subtasks of subtasks"
+// @Success 200 {object} dto.Response "Subtasks retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Task not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/subtasks [get]
+func (c *TaskController) ListSubtasks(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		c.logger.Warn("Invalid task ID format",
+			zap.String("id_param", ctx.Param("id")),
+			zap.Error(err),
+		)
+		respondProblem(ctx, invalidIDProblem())
+		return
+	}
+
+	var req dto.SubtasksRequest
+	if err = ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warn("Invalid subtask parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	subtasks, err := c.service.ListSubtasks(ctx.Request.Context(), uint(id), req.Tree)
+	if err != nil {
+		c.respondError(ctx, "Failed to list subtasks", err, zap.Uint("task_id", uint(id)))
+		return
+	}
+
+	c.logger.Info("Subtasks listed successfully", zap.Uint("task_id", uint(id)), zap.Int("count", len(subtasks)))
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Subtasks retrieved successfully", subtasks))
+}
+
+// convertToServiceCreate converts the body of a create, failing only on a
+// malformed date.
+func convertToServiceCreate(req *dto.CreateTaskRequest) (dto.CreateTaskServiceRequest, error) {
+	serviceReq := dto.CreateTaskServiceRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        req.Tags,
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+		Recurrence:  req.Recurrence,
+		ExDates:     parseDays(req.ExDates),
+		DueTime:     req.DueTime,
+	}
+
+	parsedDate, err := time.Parse("2006-01-02", req.DateString)
+	if err != nil {
+		return serviceReq, err
+	}
+	serviceReq.Date = parsedDate
+
+	if req.Priority != "" {
+		serviceReq.Priority, _ = models.ParsePriority(req.Priority) // checked by the oneof binding
+	}
+	return serviceReq, nil
+}
+
+// convertToServiceUpdate converts the body of an update, failing only on a
+// malformed date.
+func convertToServiceUpdate(req *dto.UpdateTaskRequest) (dto.UpdateTaskServiceRequest, error) {
+	serviceReq := dto.UpdateTaskServiceRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Completed:   req.Completed,
+		AddTags:     req.AddTags,
+		RemoveTags:  req.RemoveTags,
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+		Recurrence:  req.Recurrence,
+		ExDates:     parseDays(req.ExDates),
+		DueTime:     req.DueTime,
+	}
+
+	if req.DateString != nil {
+		parsedDate, err := time.Parse("2006-01-02", *req.DateString)
+		if err != nil {
+			return serviceReq, err
+		}
+		serviceReq.Date = &parsedDate
+	}
+
+	if req.Priority != nil {
+		priority, _ := models.ParsePriority(*req.Priority) // checked by the oneof binding
+		serviceReq.Priority = &priority
+	}
+	return serviceReq, nil
+}
+
+// parseDays parses dates already checked by a datetime binding, keeping a
+// nil list nil.
+func parseDays(days []string) []time.Time {
+	if days == nil {
+		return nil
+	}
+	parsed := make([]time.Time, len(days))
+	for i, day := range days {
+		parsed[i], _ = time.Parse("2006-01-02", day)
+	}
+	return parsed
+}
+
+// listFilter parses the filter parameters shared by ListTasks and the task
+// streams, responding with a problem when they are invalid.
+func (c *TaskController) listFilter(ctx *gin.Context) (*dto.TaskFilterRequest, dto.TaskFilter, bool) {
+	filterReq, err := parseTaskFilter(ctx)
+	if err != nil {
+		c.logger.Warn("Invalid filter parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return nil, dto.TaskFilter{}, false
+	}
+
+	filter := convertToServiceFilter(filterReq)
+
+	var ok bool
+	if filter.Location, ok = c.requestLocation(ctx); !ok {
+		return nil, dto.TaskFilter{}, false
+	}
+
+	if filterReq.Priority != nil {
+		filter.Priorities, err = parsePriorities(*filterReq.Priority)
+		if err != nil {
+			c.logger.Warn("Invalid priority filter", zap.String("priority", *filterReq.Priority), zap.Error(err))
+			respondProblem(ctx, validationProblem("Invalid priority filter", dto.FieldError{
+				Field:   "priority",
+				Rule:    "oneof",
+				Message: err.Error() + "; must be a comma-separated list of none, low, medium, high, urgent",
+			}))
+			return nil, dto.TaskFilter{}, false
+		}
+	}
+
+	if filterReq.ProjectID != nil {
+		filter.ProjectID, err = parseIDOrNone(*filterReq.ProjectID)
+		if err != nil {
+			c.logger.Warn("Invalid project filter", zap.String("project_id", *filterReq.ProjectID), zap.Error(err))
+			respondProblem(ctx, validationProblem("Invalid project filter", dto.FieldError{
+				Field:   "project_id",
+				Rule:    "project_id",
+				Message: "must be a project ID or none",
+			}))
+			return nil, dto.TaskFilter{}, false
+		}
+	}
+
+	if filterReq.ParentID != nil {
+		filter.ParentID, err = parseIDOrNone(*filterReq.ParentID)
+		if err != nil {
+			c.logger.Warn("Invalid parent filter", zap.String("parent_id", *filterReq.ParentID), zap.Error(err))
+			respondProblem(ctx, validationProblem("Invalid parent filter", dto.FieldError{
+				Field:   "parent_id",
+				Rule:    "parent_id",
+				Message: "must be a task ID or none",
+			}))
+			return nil, dto.TaskFilter{}, false
+		}
+	}
+
+	return filterReq, filter, true
 }
 
 func parseTaskFilter(ctx *gin.Context) (*dto.TaskFilterRequest, error) {
@@ -240,6 +668,35 @@ func parseTaskFilter(ctx *gin.Context) (*dto.TaskFilterRequest, error) {
 	return &filter, nil
 }
 
+// parsePriorities parses a comma-separated list of priority names.
+func parsePriorities(spec string) ([]models.Priority, error) {
+	var priorities []models.Priority
+	for _, name := range strings.Split(spec, ",") {
+		priority, err := models.ParsePriority(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		priorities = append(priorities, priority)
+	}
+	return priorities, nil
+}
+
+// parseIDOrNone parses a reference filter such as project_id or
+// parent_id: an ID, or "none" for tasks without the reference, returned
+// as 0.
+func parseIDOrNone(spec string) (*uint, error) {
+	if spec == "none" {
+		none := uint(0)
+		return &none, nil
+	}
+	id, err := strconv.ParseUint(spec, 10, 32)
+	if err != nil || id == 0 {
+		return nil, fmt.Errorf("invalid ID %q", spec)
+	}
+	ref := uint(id)
+	return &ref, nil
+}
+
 func convertToServiceFilter(req *dto.TaskFilterRequest) dto.TaskFilter {
 	filter := dto.TaskFilter{
 		Limit:  10,
@@ -272,5 +729,39 @@ func convertToServiceFilter(req *dto.TaskFilterRequest) dto.TaskFilter {
 		filter.Offset = *req.Offset
 	}
 
+	if req.Query != nil {
+		filter.Query = strings.TrimSpace(*req.Query)
+	}
+
+	if req.Highlight != nil {
+		filter.Highlight, _ = strconv.ParseBool(*req.Highlight)
+	}
+
+	if req.Tags != nil {
+		for _, name := range strings.Split(*req.Tags, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				filter.Tags = append(filter.Tags, name)
+			}
+		}
+	}
+
+	if req.TagMode != nil {
+		filter.TagMode = *req.TagMode
+	}
+
+	if req.IncludeArchived != nil {
+		filter.IncludeArchived, _ = strconv.ParseBool(*req.IncludeArchived)
+	}
+
+	if req.Tree != nil {
+		filter.Tree, _ = strconv.ParseBool(*req.Tree)
+	}
+
+	if req.Blocked != nil {
+		if val, err := strconv.ParseBool(*req.Blocked); err == nil {
+			filter.Blocked = &val
+		}
+	}
+
 	return filter
 }
@@ -7,17 +7,20 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap/zaptest"
 	"gorm.io/gorm"
 
 	"todo-api/internal/dto"
 	"todo-api/internal/models"
+	"todo-api/internal/services"
 	"todo-api/internal/services/mock"
 )
 
@@ -92,6 +95,7 @@ func TestTaskController_CreateTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, "/api/v1/tasks/1", recorder.Header().Get("Location"))
 
 		var response dto.Response
 		err = json.Unmarshal(recorder.Body.Bytes(), &response)
@@ -127,12 +131,81 @@ func TestTaskController_CreateTask(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, dto.ProblemTypeValidation, problem.Type)
+	})
+
+	t.Run("FieldValidationErrors", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		reqBody := map[string]any{
+			"description": strings.Repeat("x", 1001),
+			"date":        "2026-01-02",
+		}
+		ctx, recorder := createTestContext("POST", "/tasks/create", reqBody)
+
+		// Act
+		controller.CreateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+
+		assert.Equal(t, dto.ProblemTypeValidation, problem.Type)
+		assert.Equal(t, "/tasks/create", problem.Instance)
+		assert.ElementsMatch(t, []dto.FieldError{
+			{Field: "title", Rule: "required", Message: "is required"},
+			{Field: "description", Rule: "max", Message: "must be at most 1000 characters long"},
+		}, problem.Errors)
+	})
+
+	t.Run("InvalidDate", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		reqBody := dto.CreateTaskRequest{Title: "Test task", DateString: "02.01.2026"}
+		ctx, recorder := createTestContext("POST", "/tasks/create", reqBody)
+
+		// Act
+		controller.CreateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Contains(t, response.Message, "json")
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "date", problem.Errors[0].Field)
+	})
+
+	t.Run("DateInPast", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		reqBody := dto.CreateTaskRequest{
+			Title:      "Test task",
+			DateString: "2020-01-02",
+		}
+
+		ctx, recorder := createTestContext("POST", "/tasks/create", reqBody)
+
+		mockService.EXPECT().
+			CreateTask(gomock.Any(), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrValidation, Message: "task date cannot be in the past"}).
+			Times(1)
+
+		// Act
+		controller.CreateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
 	})
 
 	t.Run("InternalError", func(t *testing.T) {
@@ -159,12 +232,13 @@ func TestTaskController_CreateTask(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, expectedErr.Error(), response.Message)
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, http.StatusInternalServerError, problem.Status)
+		assert.Equal(t, "An unexpected error occurred", problem.Detail)
 	})
 }
 
@@ -180,6 +254,7 @@ func TestTaskController_GetTaskByID(t *testing.T) {
 			Title:       "Test task",
 			Description: "Test description",
 			Completed:   false,
+			Version:     3,
 		}
 
 		mockService.EXPECT().
@@ -198,6 +273,7 @@ func TestTaskController_GetTaskByID(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
 
 		var response dto.Response
 		err := json.Unmarshal(recorder.Body.Bytes(), &response)
@@ -235,12 +311,38 @@ func TestTaskController_GetTaskByID(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "Invalid task ID format", response.Message)
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "Invalid task ID format", problem.Detail)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+
+		mockService.EXPECT().
+			GetTaskByID(gomock.Any(), uint(7)).
+			Return(nil, &services.Error{Kind: services.ErrNotFound, Message: "task 7 not found"}).
+			Times(1)
+
+		ctx, recorder := createTestContext("GET", "/tasks/7", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "7"}}
+
+		// Act
+		controller.GetTaskByID(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "task 7 not found", problem.Detail)
 	})
 
 	t.Run("ServiceError", func(t *testing.T) {
@@ -266,13 +368,13 @@ func TestTaskController_GetTaskByID(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, expectedError.Error(), response.Message)
-		assert.Nil(t, response.Data)
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, http.StatusInternalServerError, problem.Status)
+		assert.Equal(t, "An unexpected error occurred", problem.Detail)
 	})
 }
 
@@ -361,12 +463,12 @@ func TestTaskController_UpdateTask(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "Invalid task ID format", response.Message)
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "Invalid task ID format", problem.Detail)
 	})
 
 	t.Run("InvalidJSON", func(t *testing.T) {
@@ -385,12 +487,12 @@ func TestTaskController_UpdateTask(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Contains(t, response.Message, "json")
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, dto.ProblemTypeValidation, problem.Type)
 	})
 
 	t.Run("NoFieldsToUpdate", func(t *testing.T) {
@@ -414,21 +516,42 @@ func TestTaskController_UpdateTask(t *testing.T) {
 
 		mockService.EXPECT().
 			UpdateTask(gomock.Any(), taskID, expectedServiceReq).
-			Return(nil, errors.New("no fields to update")).
+			Return(nil, &services.Error{Kind: services.ErrValidation, Message: "no fields to update"}).
 			Times(1)
 
 		// Act
 		controller.UpdateTask(ctx)
 
 		// Assert
-		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "No fields to update", response.Message)
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "no fields to update", problem.Detail)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		title := "Updated title"
+		reqBody := dto.UpdateTaskRequest{Title: &title}
+
+		ctx, recorder := createTestContext("PUT", "/tasks/7", reqBody)
+		ctx.Params = gin.Params{{Key: "id", Value: "7"}}
+
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(7), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrNotFound, Message: "task 7 not found"}).
+			Times(1)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
 	})
 
 	t.Run("ServiceError", func(t *testing.T) {
@@ -466,12 +589,92 @@ func TestTaskController_UpdateTask(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, expectedError.Error(), response.Message)
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, http.StatusInternalServerError, problem.Status)
+		assert.Equal(t, "An unexpected error occurred", problem.Detail)
+	})
+}
+
+func TestTaskController_UpdateTaskPreconditions(t *testing.T) {
+	title := "Updated title"
+	reqBody := dto.UpdateTaskRequest{Title: &title}
+
+	t.Run("IfMatchPassedAsVersion", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", reqBody)
+		ctx.Request.Header.Set("If-Match", `"4"`)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(1), dto.UpdateTaskServiceRequest{Title: &title, Version: 4}).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Title: title, Version: 5}, nil)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"5"`, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("StaleVersion", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", reqBody)
+		ctx.Request.Header.Set("If-Match", `"4"`)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			UpdateTask(gomock.Any(), uint(1), gomock.Any()).
+			Return(nil, &services.Error{Kind: services.ErrPreconditionFailed, Message: "task 1 has been modified by someone else"})
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+	})
+
+	t.Run("SeveralTagsNoneCurrent", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", reqBody)
+		ctx.Request.Header.Set("If-Match", `"2", W/"6", "3"`)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			GetTaskByID(gomock.Any(), uint(1)).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Version: 6}, nil)
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+	})
+
+	t.Run("MissingIfMatchInStrictMode", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		controller := NewTaskController(mock.NewMockTaskService(ctrl), zaptest.NewLogger(t), WithRequireIfMatch(true))
+		ctx, recorder := createTestContext("PATCH", "/api/v1/tasks/1", reqBody)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.UpdateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ProblemTypePreconditionRequired, problem.Type)
 	})
 }
 
@@ -488,7 +691,7 @@ func TestTaskController_DeleteTask(t *testing.T) {
 		ctx.Params = params
 
 		mockService.EXPECT().
-			DeleteTask(gomock.Any(), taskID).
+			DeleteTask(gomock.Any(), taskID, uint(0), false).
 			Return(nil).
 			Times(1)
 
@@ -496,7 +699,7 @@ func TestTaskController_DeleteTask(t *testing.T) {
 		controller.DeleteTask(ctx)
 
 		// Assert
-		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
 		assert.Empty(t, recorder.Body.String()) // Проверяем, что тело ответа пустое
 	})
 
@@ -516,12 +719,63 @@ func TestTaskController_DeleteTask(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 
-		var response dto.Response
-		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "Invalid task ID format", response.Message)
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "Invalid task ID format", problem.Detail)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+
+		ctx, recorder := createTestContext("DELETE", "/tasks/7", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "7"}}
+
+		mockService.EXPECT().
+			DeleteTask(gomock.Any(), uint(7), uint(0), false).
+			Return(&services.Error{Kind: services.ErrNotFound, Message: "task 7 not found"}).
+			Times(1)
+
+		// Act
+		controller.DeleteTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("CascadeSubtasks", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+
+		ctx, recorder := createTestContext("DELETE", "/tasks/1?subtasks=cascade", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			DeleteTask(gomock.Any(), uint(1), uint(0), true).
+			Return(nil)
+
+		// Act
+		controller.DeleteTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("InvalidSubtasksMode", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+
+		ctx, recorder := createTestContext("DELETE", "/tasks/1?subtasks=orphan", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.DeleteTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 	})
 
 	t.Run("ServiceError", func(t *testing.T) {
@@ -537,7 +791,7 @@ func TestTaskController_DeleteTask(t *testing.T) {
 		ctx.Params = params
 
 		mockService.EXPECT().
-			DeleteTask(gomock.Any(), taskID).
+			DeleteTask(gomock.Any(), taskID, uint(0), false).
 			Return(expectedError).
 			Times(1)
 
@@ -547,11 +801,411 @@ func TestTaskController_DeleteTask(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 
-		var response dto.Response
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+
+		assert.Equal(t, dto.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, http.StatusInternalServerError, problem.Status)
+		assert.Equal(t, "An unexpected error occurred", problem.Detail)
+	})
+}
+
+func TestTaskController_ListTasks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?completed=true&limit=2&offset=4", nil)
+
+		completed := true
+		meta := dto.PageMeta{Total: 9, Limit: 2, HasMore: true, NextCursor: "bmV4dA", PrevCursor: "cHJldg"}
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Completed: &completed, Limit: 2, Offset: 4}).
+			Return(&dto.TaskPage{
+				Tasks: []models.Task{{Model: gorm.Model{ID: 5}}, {Model: gorm.Model{ID: 6}}},
+				Meta:  meta,
+			}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t,
+			`</api/v1/tasks?completed=true&limit=2>; rel="first", `+
+				`</api/v1/tasks?completed=true&cursor=bmV4dA&limit=2>; rel="next", `+
+				`</api/v1/tasks?completed=true&cursor=cHJldg&limit=2>; rel="prev"`,
+			recorder.Header().Get("Link"))
+
+		var response struct {
+			Data []models.Task `json:"data"`
+			Meta dto.PageMeta  `json:"meta"`
+		}
 		err := json.Unmarshal(recorder.Body.Bytes(), &response)
 		assert.NoError(t, err)
+		assert.Len(t, response.Data, 2)
+		assert.Equal(t, meta, response.Meta)
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		task := models.Task{Model: gorm.Model{ID: 3}, Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+		cursor := dto.CursorAfter(task, dto.DefaultTaskSort())
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?cursor="+cursor.Encode(), nil)
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Cursor: cursor}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?sort=-date,title", nil)
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Sort: []dto.SortField{
+				{Field: "date", Desc: true},
+				{Field: "title"},
+				{Field: "id"},
+			}}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?tags=work,+urgent,&tag_mode=all", nil)
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Tags: []string{"work", "urgent"}, TagMode: dto.TagModeAll}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Project", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?project_id=none&include_archived=true", nil)
+
+		none := uint(0)
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, ProjectID: &none, IncludeArchived: true}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("InvalidProject", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?project_id=work", nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"project_id"`)
+	})
+
+	t.Run("InvalidTagMode", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?tags=work&tag_mode=none", nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"tag_mode"`)
+	})
+
+	t.Run("InvalidSort", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?sort=date,-password", nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "sort", problem.Errors[0].Field)
+		assert.Contains(t, problem.Errors[0].Message, `"-password"`)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?q=+milk+&highlight=true", nil)
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Query: "milk", Highlight: true}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("RelevanceWithoutQuery", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?sort=-relevance", nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "sort", problem.Errors[0].Field)
+	})
+
+	t.Run("PriorityFilter", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?priority=high,urgent", nil)
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{
+				Limit:      10,
+				Priorities: []models.Priority{models.PriorityHigh, models.PriorityUrgent},
+			}).
+			Return(&dto.TaskPage{}, nil)
 
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, expectedError.Error(), response.Message)
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("InvalidPriorityFilter", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?priority=high,asap", nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "priority", problem.Errors[0].Field)
+	})
+
+	t.Run("CursorFromAnotherSort", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		order, err := dto.ParseTaskSort("-title")
+		require.NoError(t, err)
+		cursor := dto.CursorAfter(models.Task{Model: gorm.Model{ID: 3}, Title: "x"}, order)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?cursor="+cursor.Encode(), nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err = json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "cursor", problem.Errors[0].Field)
+	})
+
+	t.Run("TopLevelTree", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?parent_id=none&tree=true", nil)
+
+		top := uint(0)
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, ParentID: &top, Tree: true}).
+			Return(&dto.TaskPage{Meta: dto.PageMeta{Limit: 10}}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("InvalidParentID", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?parent_id=root", nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "parent_id", problem.Errors[0].Field)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?cursor=garbage", nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var problem dto.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "cursor", problem.Errors[0].Field)
+	})
+}
+
+func TestTaskController_ListSubtasks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/1/subtasks?tree=true", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		parentID := uint(1)
+		mockService.EXPECT().
+			ListSubtasks(gomock.Any(), uint(1), true).
+			Return([]models.Task{{Model: gorm.Model{ID: 2}, ParentID: &parentID}}, nil)
+
+		// Act
+		controller.ListSubtasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var response struct {
+			Data []models.Task `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, uint(2), response.Data[0].ID)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks/9/subtasks", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "9"}}
+
+		mockService.EXPECT().
+			ListSubtasks(gomock.Any(), uint(9), false).
+			Return(nil, &services.Error{Kind: services.ErrNotFound, Message: "task 9 not found"})
+
+		// Act
+		controller.ListSubtasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestTaskController_MoveTask(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		afterID := uint(2)
+		date := "2026-01-03"
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/move", dto.MoveTaskRequest{AfterID: &afterID, DateString: &date})
+		ctx.Request.Header.Set("If-Match", `"4"`)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		parsedDate := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().
+			MoveTask(gomock.Any(), uint(1), dto.MoveTaskServiceRequest{AfterID: &afterID, Date: &parsedDate, Version: 4}).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Rank: "m", Version: 5}, nil)
+
+		// Act
+		controller.MoveTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"5"`, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("NeighbourNotOnDate", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		beforeID := uint(9)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/move", dto.MoveTaskRequest{BeforeID: &beforeID})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().
+			MoveTask(gomock.Any(), uint(1), dto.MoveTaskServiceRequest{BeforeID: &beforeID}).
+			Return(nil, &services.Error{Kind: services.ErrValidation, Message: "task 9 to place before is not on the target date"})
+
+		// Act
+		controller.MoveTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+
+	t.Run("InvalidDate", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		date := "tomorrow"
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/move", dto.MoveTaskRequest{DateString: &date})
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		// Act
+		controller.MoveTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 	})
 }
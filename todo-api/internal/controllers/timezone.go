@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/audit"
+	"todo-api/internal/dto"
+	"todo-api/internal/services"
+)
+
+// timeZoneHeader names the header a client sends its IANA time zone in.
+const timeZoneHeader = "Time-Zone"
+
+// WithDefaultTimeZone sets the time zone of requests that do not name one.
+func WithDefaultTimeZone(loc *time.Location) Option {
+	return func(c *TaskController) {
+		if loc != nil {
+			c.location = loc
+		}
+	}
+}
+
+// WithUserTimeZones takes the time zone of requests that do not name one
+// from the settings of their actor, before falling back to the default.
+func WithUserTimeZones(settings services.SettingsService) Option {
+	return func(c *TaskController) {
+		c.settings = settings
+	}
+}
+
+// requestLocation returns the time zone the request's dates are in: the tz
+// query parameter, else the Time-Zone header, else the actor's setting, else
+// the server default. It responds with a problem when the named zone is
+// unknown.
+func (c *TaskController) requestLocation(ctx *gin.Context) (*time.Location, bool) {
+	name := ctx.Query("tz")
+	if name == "" {
+		name = ctx.GetHeader(timeZoneHeader)
+	}
+	if name == "" {
+		return c.actorLocation(ctx)
+	}
+
+	// LoadLocation treats "" and "Local" as the server's zone, which a
+	// client cannot mean.
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		c.logger.Warn("Invalid time zone", zap.String("tz", name), zap.Error(err))
+		respondProblem(ctx, validationProblem("Invalid time zone", dto.FieldError{
+			Field:   "tz",
+			Rule:    "timezone",
+			Message: "must be an IANA time zone such as Europe/Moscow",
+		}))
+		return nil, false
+	}
+	return loc, true
+}
+
+// actorLocation returns the time zone the request's actor has set, or the
+// server default.
+func (c *TaskController) actorLocation(ctx *gin.Context) (*time.Location, bool) {
+	if c.settings == nil {
+		return c.location, true
+	}
+
+	actor := audit.FromContext(ctx.Request.Context()).Actor
+	loc, err := c.settings.Location(ctx.Request.Context(), actor)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to get the actor's time zone", err, zap.String("actor", actor))
+		return nil, false
+	}
+	if loc == nil {
+		return c.location, true
+	}
+	return loc, true
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"todo-api/internal/audit"
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services/mock"
+)
+
+func TestTaskController_TimeZone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	date := time.Date(2030, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("query parameter", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks?tz=Europe/Moscow",
+			dto.CreateTaskRequest{Title: "Call mom", DateString: "2030-03-10", DueTime: "18:30"})
+		ctx.Request.Header.Set("Time-Zone", "Europe/Berlin")
+
+		mockService.EXPECT().
+			CreateTask(gomock.Any(), dto.CreateTaskServiceRequest{Title: "Call mom", Date: date, DueTime: "18:30", Location: moscow}).
+			Return(&models.Task{Title: "Call mom"}, nil)
+
+		// Act
+		controller.CreateTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	})
+
+	t.Run("header", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks?date_from=2030-03-10", nil)
+		ctx.Request.Header.Set("Time-Zone", "Europe/Berlin")
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, DateFrom: &date, Location: berlin}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("server default", func(t *testing.T) {
+		// Arrange
+		mockService := mock.NewMockTaskService(gomock.NewController(t))
+		controller := NewTaskController(mockService, zaptest.NewLogger(t), WithDefaultTimeZone(moscow))
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks", nil)
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Location: moscow}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("actor setting", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockService := mock.NewMockTaskService(ctrl)
+		mockSettings := mock.NewMockSettingsService(ctrl)
+		controller := NewTaskController(mockService, zaptest.NewLogger(t),
+			WithDefaultTimeZone(moscow), WithUserTimeZones(mockSettings))
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks", nil)
+		ctx.Request = ctx.Request.WithContext(audit.WithInfo(ctx.Request.Context(), audit.Info{Actor: "alice"}))
+
+		mockSettings.EXPECT().Location(gomock.Any(), "alice").Return(berlin, nil)
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Location: berlin}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("header over actor setting", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockService := mock.NewMockTaskService(ctrl)
+		controller := NewTaskController(mockService, zaptest.NewLogger(t),
+			WithUserTimeZones(mock.NewMockSettingsService(ctrl)))
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks", nil)
+		ctx.Request = ctx.Request.WithContext(audit.WithInfo(ctx.Request.Context(), audit.Info{Actor: "alice"}))
+		ctx.Request.Header.Set("Time-Zone", "Europe/Moscow")
+
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Location: moscow}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("actor without a setting", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockService := mock.NewMockTaskService(ctrl)
+		mockSettings := mock.NewMockSettingsService(ctrl)
+		controller := NewTaskController(mockService, zaptest.NewLogger(t),
+			WithDefaultTimeZone(moscow), WithUserTimeZones(mockSettings))
+		ctx, recorder := createTestContext("GET", "/api/v1/tasks", nil)
+
+		mockSettings.EXPECT().Location(gomock.Any(), "").Return(nil, nil)
+		mockService.EXPECT().
+			ListTasks(gomock.Any(), dto.TaskFilter{Limit: 10, Location: moscow}).
+			Return(&dto.TaskPage{}, nil)
+
+		// Act
+		controller.ListTasks(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	for _, name := range []string{"Mars/Olympus_Mons", "Local"} {
+		t.Run("unknown zone "+name, func(t *testing.T) {
+			// Arrange
+			controller, _ := setupTestController(t)
+			ctx, recorder := createTestContext("GET", "/api/v1/tasks?tz="+name, nil)
+
+			// Act
+			controller.ListTasks(ctx)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), `"field":"tz"`)
+		})
+	}
+}
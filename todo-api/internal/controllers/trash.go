@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+)
+
+// ListTrash godoc
+// @Summary List deleted tasks
+// @Description Get a page of the tasks in the trash, with the filters, sort and pagination of the task list.
+// @Description Recurring tasks are not expanded and stored occurrences are left out: they are restored along with their series.
+// @Tags tasks
+// @Produce json
+// @Produce application/problem+json
+// @Param completed query bool false "Filter by completion status"
+// @Param date_from query string false "Filter by start date (format: 2006-01-02)"
+// @Param date_to query string false "Filter by end date (format: 2006-01-02)"
+// @Param limit query int false "Limit number of results (default: 10, capped by the server maximum)"
+// @Param offset query int false "Offset for pagination, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor from a previous page"
+// @Param sort query string false "Comma-separated sort fields, '-' for descending" example(-updated_at)
+// @Param priority query string false "Comma-separated priorities to include (none, low, medium, high, urgent)"
+// @Param tags query string false "Comma-separated tag names"
+// @Param tag_mode query string false "Match tasks with any (default) or all of the tags" Enums(any, all)
+// @Param project_id query string false "Project ID, or none for tasks outside any project"
+// @Param include_archived query bool false "Include tasks of archived projects"
+// @Param parent_id query string false "Parent task ID, or none for top-level tasks"
+// @Param q query string false "Full-text search in title and description"
+// @Param tz query string false "IANA time zone the days of date_from and date_to are taken in; overrides the Time-Zone header"
+// @Param Time-Zone header string false "IANA time zone the date range is taken in, the server default when absent"
+// @Success 200 {object} dto.Response{meta=dto.PageMeta} "Deleted tasks retrieved successfully"
+// @Header 200 {string} Link "RFC 8288 links to the first, next and prev pages"
+// @Failure 400 {object} dto.Problem "Invalid filter parameters"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/trash [get]
+func (c *TaskController) ListTrash(ctx *gin.Context) {
+	c.listPage(ctx, c.service.ListTrash, "Deleted tasks retrieved successfully")
+}
+
+// RestoreTask godoc
+// @Summary Restore a deleted task
+// @Description Bring a task back from the trash, along with the occurrences and subtasks deleted with it.
+// @Description A subtask deleted on its own cannot be restored while its parent is in the trash.
+// @Tags tasks
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the deleted task"
+// @Success 200 {object} dto.Response "Task restored successfully"
+// @Header 200 {string} ETag "New task version"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Task is not in the trash"
+// @Failure 409 {object} dto.Problem "Parent task is in the trash"
+// @Failure 412 {object} dto.Problem "Task was modified since it was read"
+// @Failure 428 {object} dto.Problem "If-Match header is required"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/tasks/{id}/restore [post]
+func (c *TaskController) RestoreTask(ctx *gin.Context) {
+	id, ok := c.pathTaskID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	version, problem := c.ifMatchVersion(ctx, id)
+	if problem != nil {
+		c.logger.Warn("Restore precondition not met", zap.Uint("task_id", id), zap.String("detail", problem.Detail))
+		respondProblem(ctx, problem)
+		return
+	}
+
+	task, err := c.service.RestoreTask(ctx.Request.Context(), id, version)
+	if err != nil {
+		c.respondError(ctx, "Failed to restore task", err, zap.Uint("task_id", id))
+		return
+	}
+
+	c.logger.Info("Task restored successfully", zap.Uint("task_id", task.ID))
+	setETag(ctx, task)
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Task restored successfully", task))
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+func TestTaskController_ListTrash(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("GET", "/api/v1/tasks/trash?completed=true&limit=1", nil)
+
+	completed := true
+	mockService.EXPECT().
+		ListTrash(gomock.Any(), dto.TaskFilter{Completed: &completed, Limit: 1}).
+		Return(&dto.TaskPage{
+			Tasks: []models.Task{{Model: gorm.Model{ID: 5}}},
+			Meta:  dto.PageMeta{Total: 2, Limit: 1, HasMore: true, NextCursor: "bmV4dA"},
+		}, nil)
+
+	// Act
+	controller.ListTrash(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Link"),
+		`</api/v1/tasks/trash?completed=true&cursor=bmV4dA&limit=1>; rel="next"`)
+}
+
+func TestTaskController_RestoreTask(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/1/restore", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+		ctx.Request.Header.Set("If-Match", `"4"`)
+
+		mockService.EXPECT().
+			RestoreTask(gomock.Any(), uint(1), uint(4)).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Version: 5}, nil)
+
+		// Act
+		controller.RestoreTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"5"`, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("ParentTrashed", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/2/restore", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().
+			RestoreTask(gomock.Any(), uint(2), uint(0)).
+			Return(nil, &services.Error{Kind: services.ErrConflict, Message: "task 2 cannot be restored"})
+
+		// Act
+		controller.RestoreTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("InvalidTaskIDFormat", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupTestController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/tasks/abc/restore", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		// Act
+		controller.RestoreTask(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestTaskController_DeleteTask_Permanent(t *testing.T) {
+	// Arrange
+	controller, mockService := setupTestController(t)
+	ctx, recorder := createTestContext("DELETE", "/api/v1/tasks/1?permanent=true&subtasks=cascade", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	mockService.EXPECT().
+		PurgeTask(gomock.Any(), uint(1), uint(0), true).
+		Return(nil)
+
+	// Act
+	controller.DeleteTask(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
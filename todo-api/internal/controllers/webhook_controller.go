@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+)
+
+type WebhookController struct {
+	service services.WebhookService
+	logger  *zap.Logger
+}
+
+func NewWebhookController(service services.WebhookService, logger *zap.Logger) *WebhookController {
+	return &WebhookController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Subscribe to task events
+// @Description Have task.created, task.updated, task.completed, task.deleted, task.restored and task.purged events, or only those listed
+// @Description in events, POSTed to url as JSON. Requests carry X-Webhook-Timestamp and X-Webhook-Signature, "sha256=" and the hex
+// @Description HMAC-SHA256 of "<timestamp>.<body>" keyed with secret, and the event ID as Idempotency-Key.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param input body dto.CreateWebhookRequest true "Subscription data"
+// @Success 201 {object} dto.Response "Webhook created successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Header 201 {string} Location "URL of the created webhook"
+// @Router /api/v1/webhooks [post]
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	subscription, err := c.service.CreateSubscription(ctx.Request.Context(), &models.WebhookSubscription{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: req.Active == nil || *req.Active,
+	})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to create webhook", err)
+		return
+	}
+
+	c.logger.Info("Webhook created successfully", zap.Uint("webhook_id", subscription.ID))
+	ctx.Header("Location", fmt.Sprintf("/api/v1/webhooks/%d", subscription.ID))
+	ctx.JSON(http.StatusCreated, dto.SuccessResponse("Webhook created successfully", subscription))
+}
+
+// GetWebhook godoc
+// @Summary Get webhook by ID
+// @Tags webhooks
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} dto.Response "Webhook retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Webhook not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/webhooks/{id} [get]
+func (c *WebhookController) GetWebhook(ctx *gin.Context) {
+	id, ok := pathID(ctx, c.logger, "id", "webhook")
+	if !ok {
+		return
+	}
+
+	subscription, err := c.service.GetSubscription(ctx.Request.Context(), id)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to get webhook", err, zap.Uint("webhook_id", id))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Webhook retrieved successfully", subscription))
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description Get every webhook subscription, oldest first. Secrets are never returned.
+// @Tags webhooks
+// @Produce json
+// @Produce application/problem+json
+// @Success 200 {object} dto.Response "Webhooks retrieved successfully"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/webhooks [get]
+func (c *WebhookController) ListWebhooks(ctx *gin.Context) {
+	subscriptions, err := c.service.ListSubscriptions(ctx.Request.Context())
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to list webhooks", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Webhooks retrieved successfully", subscriptions))
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Update the given fields of a webhook. A paused webhook (active false) gets no new events; its pending
+// @Description deliveries wait until it is resumed.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Webhook ID"
+// @Param input body dto.UpdateWebhookRequest true "Subscription update data"
+// @Success 200 {object} dto.Response "Webhook updated successfully"
+// @Failure 400 {object} dto.Problem "Invalid input data"
+// @Failure 404 {object} dto.Problem "Webhook not found"
+// @Failure 422 {object} dto.Problem "No fields to update"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/webhooks/{id} [patch]
+func (c *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	id, ok := pathID(ctx, c.logger, "id", "webhook")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.logger.Warn("Invalid request data", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	subscription, err := c.service.UpdateSubscription(ctx.Request.Context(), id, dto.UpdateWebhookServiceRequest{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: req.Active,
+	})
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to update webhook", err, zap.Uint("webhook_id", id))
+		return
+	}
+
+	c.logger.Info("Webhook updated successfully", zap.Uint("webhook_id", subscription.ID))
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Webhook updated successfully", subscription))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook subscription together with its delivery log.
+// @Tags webhooks
+// @Produce application/problem+json
+// @Param id path int true "Webhook ID"
+// @Success 204 "Webhook deleted successfully"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Webhook not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	id, ok := pathID(ctx, c.logger, "id", "webhook")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteSubscription(ctx.Request.Context(), id); err != nil {
+		respondServiceError(ctx, c.logger, "Failed to delete webhook", err, zap.Uint("webhook_id", id))
+		return
+	}
+
+	c.logger.Info("Webhook deleted successfully", zap.Uint("webhook_id", id))
+	ctx.Status(http.StatusNoContent)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// ListDeliveries godoc
+// @Summary List the deliveries of a webhook
+// @Description Get the delivery log of a webhook, newest first: every event sent or to be sent, with its payload, status,
+// @Description attempts, last error and last response status. Dead deliveries ran out of attempts.
+// @Tags webhooks
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Only deliveries in this status" Enums(pending, delivered, dead)
+// @Param limit query int false "Number of deliveries (max 100)" default(50)
+// @Success 200 {object} dto.Response "Deliveries retrieved successfully"
+// @Failure 400 {object} dto.Problem "Invalid filter parameters"
+// @Failure 404 {object} dto.Problem "Webhook not found"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (c *WebhookController) ListDeliveries(ctx *gin.Context) {
+	id, ok := pathID(ctx, c.logger, "id", "webhook")
+	if !ok {
+		return
+	}
+
+	var req dto.DeliveryFilterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warn("Invalid filter parameters", zap.Error(err))
+		respondProblem(ctx, bindingProblem(err))
+		return
+	}
+
+	deliveries, err := c.service.ListDeliveries(ctx.Request.Context(), id, models.DeliveryStatus(req.Status), req.Limit)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to list deliveries", err, zap.Uint("webhook_id", id))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SuccessResponse("Deliveries retrieved successfully", deliveries))
+}
+
+// Redeliver godoc
+// @Summary Redeliver an event
+// @Description Send a delivered or dead delivery again as soon as possible, with a fresh set of attempts. The request
+// @Description keeps its Idempotency-Key.
+// @Tags webhooks
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} dto.Response "Redelivery scheduled"
+// @Failure 400 {object} dto.Problem "Invalid ID format"
+// @Failure 404 {object} dto.Problem "Delivery not found"
+// @Failure 409 {object} dto.Problem "Delivery still pending"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (c *WebhookController) Redeliver(ctx *gin.Context) {
+	id, ok := pathID(ctx, c.logger, "id", "webhook")
+	if !ok {
+		return
+	}
+	deliveryID, ok := pathID(ctx, c.logger, "delivery_id", "delivery")
+	if !ok {
+		return
+	}
+
+	delivery, err := c.service.Redeliver(ctx.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondServiceError(ctx, c.logger, "Failed to redeliver", err, zap.Uint("webhook_id", id), zap.Uint("delivery_id", deliveryID))
+		return
+	}
+
+	c.logger.Info("Redelivery scheduled", zap.Uint("webhook_id", id), zap.Uint("delivery_id", deliveryID))
+	ctx.JSON(http.StatusAccepted, dto.SuccessResponse("Redelivery scheduled", delivery))
+}
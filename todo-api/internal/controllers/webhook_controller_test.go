@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services"
+	"todo-api/internal/services/mock"
+)
+
+func setupWebhookController(t *testing.T) (*WebhookController, *mock.MockWebhookService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockWebhookService(ctrl)
+	return NewWebhookController(mockService, zaptest.NewLogger(t)), mockService
+}
+
+func TestWebhookController_CreateWebhook(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupWebhookController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/webhooks", dto.CreateWebhookRequest{
+			URL:    "https://hooks.example.com/tasks",
+			Secret: "0123456789abcdef",
+			Events: []models.EventType{models.EventTaskCompleted},
+		})
+
+		mockService.EXPECT().
+			CreateSubscription(gomock.Any(), &models.WebhookSubscription{
+				URL:    "https://hooks.example.com/tasks",
+				Secret: "0123456789abcdef",
+				Events: models.EventList{models.EventTaskCompleted},
+				Active: true,
+			}).
+			DoAndReturn(func(_ any, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+				subscription.ID = 3
+				return subscription, nil
+			})
+
+		// Act
+		controller.CreateWebhook(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, "/api/v1/webhooks/3", recorder.Header().Get("Location"))
+		assert.NotContains(t, recorder.Body.String(), "0123456789abcdef")
+	})
+
+	for name, tc := range map[string]struct {
+		req   dto.CreateWebhookRequest
+		field string
+	}{
+		"NotHTTP":      {dto.CreateWebhookRequest{URL: "ftp://hooks.example.com", Secret: "0123456789abcdef"}, "url"},
+		"ShortSecret":  {dto.CreateWebhookRequest{URL: "https://hooks.example.com", Secret: "secret"}, "secret"},
+		"UnknownEvent": {dto.CreateWebhookRequest{URL: "https://hooks.example.com", Secret: "0123456789abcdef", Events: []models.EventType{"task.moved"}}, "events[0]"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			controller, _ := setupWebhookController(t)
+			ctx, recorder := createTestContext("POST", "/api/v1/webhooks", tc.req)
+
+			// Act
+			controller.CreateWebhook(ctx)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), `"field":"`+tc.field+`"`)
+		})
+	}
+}
+
+func TestWebhookController_ListDeliveries(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupWebhookController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/webhooks/3/deliveries?status=dead&limit=10", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "3"}}
+
+		mockService.EXPECT().
+			ListDeliveries(gomock.Any(), uint(3), models.DeliveryDead, 10).
+			Return([]models.WebhookDelivery{{ID: 5, SubscriptionID: 3, Status: models.DeliveryDead, Payload: `{"id":"e1"}`}}, nil)
+
+		// Act
+		controller.ListDeliveries(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"payload":{"id":"e1"}`)
+	})
+
+	t.Run("InvalidStatus", func(t *testing.T) {
+		// Arrange
+		controller, _ := setupWebhookController(t)
+		ctx, recorder := createTestContext("GET", "/api/v1/webhooks/3/deliveries?status=failed", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "3"}}
+
+		// Act
+		controller.ListDeliveries(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestWebhookController_Redeliver(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupWebhookController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/webhooks/3/deliveries/5/redeliver", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "3"}, {Key: "delivery_id", Value: "5"}}
+
+		mockService.EXPECT().
+			Redeliver(gomock.Any(), uint(3), uint(5)).
+			Return(&models.WebhookDelivery{ID: 5, SubscriptionID: 3, Status: models.DeliveryPending}, nil)
+
+		// Act
+		controller.Redeliver(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+	})
+
+	t.Run("StillPending", func(t *testing.T) {
+		// Arrange
+		controller, mockService := setupWebhookController(t)
+		ctx, recorder := createTestContext("POST", "/api/v1/webhooks/3/deliveries/5/redeliver", nil)
+		ctx.Params = gin.Params{{Key: "id", Value: "3"}, {Key: "delivery_id", Value: "5"}}
+
+		mockService.EXPECT().
+			Redeliver(gomock.Any(), uint(3), uint(5)).
+			Return(nil, &services.Error{Kind: services.ErrConflict, Message: "delivery 5 is still pending"})
+
+		// Act
+		controller.Redeliver(ctx)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"todo-api/internal/models"
+)
+
+// Batch modes: an atomic batch writes all of its tasks or none of them, a
+// best-effort one writes every task that passes its checks.
+const (
+	BatchAtomic     = "atomic"
+	BatchBestEffort = "best_effort"
+)
+
+// Outcomes of the items of a batch. An item is skipped when an atomic
+// batch fails on another one.
+const (
+	BatchSucceeded = "succeeded"
+	BatchFailed    = "failed"
+	BatchSkipped   = "skipped"
+)
+
+type BatchCreateRequest struct {
+	Tasks []CreateTaskRequest `json:"tasks" binding:"required,min=1,dive"`
+	Mode  string              `json:"mode" binding:"omitempty,oneof=atomic best_effort"` // atomic by default
+}
+
+// BatchSelectRequest names the tasks of a batch by ID. Without IDs the
+// batch takes the tasks matching the filter query parameters of ListTasks.
+type BatchSelectRequest struct {
+	IDs  []uint `json:"ids" binding:"omitempty,dive,min=1"`
+	Mode string `json:"mode" binding:"omitempty,oneof=atomic best_effort"` // atomic by default
+}
+
+type BatchUpdateRequest struct {
+	BatchSelectRequest
+	Changes BatchChangesRequest `json:"changes"`
+}
+
+type BatchDeleteQuery struct {
+	Subtasks string `form:"subtasks" binding:"omitempty,oneof=cascade reparent"`
+}
+
+// BatchChangesRequest holds the fields a batch update can set, the same
+// on every task.
+type BatchChangesRequest struct {
+	DateString *string  `json:"date"` // "2006-01-02"
+	Completed  *bool    `json:"completed"`
+	Priority   *string  `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	ProjectID  *uint    `json:"project_id"`                                    // 0 takes the tasks out of their projects
+	AddTags    []string `json:"add_tags" binding:"omitempty,dive,required"`    // tag names to attach
+	RemoveTags []string `json:"remove_tags" binding:"omitempty,dive,required"` // tag names to detach
+}
+
+type BatchChanges struct {
+	Date       *time.Time
+	Completed  *bool
+	Priority   *models.Priority
+	ProjectID  *uint // 0 takes the tasks out of their projects
+	AddTags    []string
+	RemoveTags []string
+	Force      bool // complete the tasks even while blockers are open
+}
+
+// BatchSelection is the set of tasks a batch works on: the listed IDs or,
+// when there are none, the tasks matching Filter.
+type BatchSelection struct {
+	IDs    []uint
+	Filter *TaskFilter
+	Mode   string // BatchAtomic (default) or BatchBestEffort
+}
+
+// BatchResult reports what a batch did to each of its items.
+type BatchResult struct {
+	Mode      string      `json:"mode"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Skipped   int         `json:"skipped"`
+	Items     []BatchItem `json:"items"`
+}
+
+// BatchItem is the outcome for one task of a batch. Index is the item's
+// position among the request's tasks or IDs, or among the tasks matching
+// the filter.
+type BatchItem struct {
+	Index  int          `json:"index"`
+	ID     uint         `json:"id,omitempty"`
+	Status string       `json:"status"`
+	Err    error        `json:"-"`               // why the item failed
+	Error  *Problem     `json:"error,omitempty"` // Err as shown to clients
+	Task   *models.Task `json:"task,omitempty"`  // the task as written
+}
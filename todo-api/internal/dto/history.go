@@ -0,0 +1,32 @@
+package dto
+
+import "todo-api/internal/models"
+
+// HistoryFilter selects a page of the history of a task, newest first.
+type HistoryFilter struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type HistoryPage struct {
+	Entries []models.TaskHistory
+	Meta    PageMeta
+}
+
+// GetTaskRequest holds the query parameters of a task read.
+type GetTaskRequest struct {
+	AsOf string `form:"as_of"` // RFC 3339 timestamp, empty for the task as it is
+}
+
+// RevertTaskRequest names the version to take a task back to, either by
+// number or by the history entry that made it.
+type RevertTaskRequest struct {
+	Version   *uint `json:"version" binding:"omitempty,min=1"`
+	HistoryID *uint `json:"history_id" binding:"omitempty,min=1"`
+}
+
+type RevertTaskServiceRequest struct {
+	ToVersion *uint
+	HistoryID *uint // the task as this change left it
+	Version   uint  // expected current version, 0 skips the check
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// OutboxStatus tells how far the outbox relay is behind the task changes.
+type OutboxStatus struct {
+	Pending         int64      `json:"pending"`                     // events not published yet
+	Failing         int64      `json:"failing"`                     // pending events whose last attempt failed
+	LagSeconds      float64    `json:"lag_seconds"`                 // age of the oldest pending event, 0 when there is none
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"` // when the oldest pending event was recorded
+	LastPublishedID uint       `json:"last_published_id,omitempty"`
+	LastPublishedAt *time.Time `json:"last_published_at,omitempty"`
+}
@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"todo-api/internal/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset position in a list order: the values of the sort fields
+// of the task it was taken from. A forward cursor selects the tasks after
+// that position, a backward one those before it.
+type Cursor struct {
+	Sort     []SortField
+	Values   []any
+	Backward bool
+}
+
+type cursorWire struct {
+	Sort     string            `json:"s"`
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+func newCursor(task models.Task, sort []SortField, backward bool) *Cursor {
+	values := make([]any, len(sort))
+	for i, f := range sort {
+		values[i] = TaskSortValue(&task, f.Field)
+	}
+	return &Cursor{Sort: sort, Values: values, Backward: backward}
+}
+
+func CursorAfter(task models.Task, sort []SortField) *Cursor {
+	return newCursor(task, sort, false)
+}
+
+func CursorBefore(task models.Task, sort []SortField) *Cursor {
+	return newCursor(task, sort, true)
+}
+
+// Compare orders task against the keyset position of the cursor in the
+// cursor's sort order.
+func (c *Cursor) Compare(task *models.Task) int {
+	for i, f := range c.Sort {
+		v := CompareSortValues(TaskSortValue(task, f.Field), c.Values[i])
+		if f.Desc {
+			v = -v
+		}
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// Encode returns the opaque form of the cursor handed to clients.
+func (c *Cursor) Encode() string {
+	wire := cursorWire{Sort: SortSpec(c.Sort), Backward: c.Backward}
+	for _, v := range c.Values {
+		raw, _ := json.Marshal(v)
+		wire.Values = append(wire.Values, raw)
+	}
+	raw, _ := json.Marshal(wire)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var wire cursorWire
+	if err = json.Unmarshal(raw, &wire); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	sort, err := ParseTaskSort(wire.Sort)
+	if err != nil || len(sort) != len(wire.Values) {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{Sort: sort, Values: make([]any, len(sort)), Backward: wire.Backward}
+	for i, f := range sort {
+		if c.Values[i], err = taskSortColumns[f.Field].decode(wire.Values[i]); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return c, nil
+}
+
+// PageMeta describes a page of a list response.
+type PageMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type TaskPage struct {
+	Tasks []models.Task
+	Meta  PageMeta
+}
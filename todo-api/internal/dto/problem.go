@@ -0,0 +1,44 @@
+package dto
+
+import "net/http"
+
+const (
+	ProblemContentType = "application/problem+json"
+
+	ProblemTypeValidation = "/problems/validation"
+	ProblemTypeNotFound   = "/problems/not-found"
+	ProblemTypeConflict   = "/problems/conflict"
+
+	ProblemTypePreconditionFailed   = "/problems/precondition-failed"
+	ProblemTypePreconditionRequired = "/problems/precondition-required"
+)
+
+// @Summary Problem
+// @Description Ошибка в формате RFC 7807 (application/problem+json)
+// @Tags models
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why a single request field was rejected.
+// Field is the name the client used (JSON key or query parameter).
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// NewProblem returns a problem of type about:blank titled after the status.
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
@@ -0,0 +1,25 @@
+package dto
+
+type CreateProjectRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+	Color       string `json:"color" binding:"omitempty,hexcolor,len=7"` // "#rrggbb"
+}
+
+type UpdateProjectRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+	Color       *string `json:"color" binding:"omitempty,hexcolor,len=7"`
+	Archived    *bool   `json:"archived"`
+}
+
+type UpdateProjectServiceRequest struct {
+	Name        *string
+	Description *string
+	Color       *string
+	Archived    *bool
+}
+
+type ProjectFilterRequest struct {
+	IncludeArchived bool `form:"include_archived"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type CreateReminderRequest struct {
+	Channel       string     `json:"channel" binding:"omitempty,oneof=log webhook email"` // log when empty
+	Email         string     `json:"email" binding:"omitempty,email,max=254"`             // recipient, required by the email channel
+	RemindAt      *time.Time `json:"remind_at"`                                           // RFC 3339 moment
+	BeforeMinutes *int       `json:"before_minutes" binding:"omitempty,min=0,max=43200"`  // minutes before the task's due time
+}
+
+type CreateReminderServiceRequest struct {
+	Channel       string
+	Email         string
+	RemindAt      *time.Time // exactly one of RemindAt and BeforeMinutes is set
+	BeforeMinutes *int
+}
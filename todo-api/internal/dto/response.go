@@ -7,6 +7,7 @@ type Response struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty" swaggertype:"object"`
+	Meta    any    `json:"meta,omitempty" swaggertype:"object"`
 }
 
 func SuccessResponse(msg string, data any) *Response {
@@ -17,9 +18,11 @@ func SuccessResponse(msg string, data any) *Response {
 	}
 }
 
-func ErrorResponse(message string) *Response {
+func PageResponse(msg string, data any, meta PageMeta) *Response {
 	return &Response{
-		Status:  "error",
-		Message: message,
+		Status:  "success",
+		Message: msg,
+		Data:    data,
+		Meta:    meta,
 	}
 }
@@ -0,0 +1,5 @@
+package dto
+
+type UpdateSettingsRequest struct {
+	TimeZone string `json:"time_zone" binding:"max=64"` // IANA name, empty for the server default
+}
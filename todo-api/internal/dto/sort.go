@@ -0,0 +1,147 @@
+package dto
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"todo-api/internal/models"
+)
+
+// SortField is one key of a list order. Field is both the API name and the
+// database column.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+type taskSortColumn struct {
+	value  func(task *models.Task) any
+	decode func(raw json.RawMessage) (any, error)
+}
+
+// taskSortColumns is the allowlist of fields tasks can be sorted by.
+var taskSortColumns = map[string]taskSortColumn{
+	"id":         {value: func(t *models.Task) any { return t.ID }, decode: decodeAs[uint]},
+	"date":       {value: func(t *models.Task) any { return t.Date }, decode: decodeAs[time.Time]},
+	"title":      {value: func(t *models.Task) any { return t.Title }, decode: decodeAs[string]},
+	"completed":  {value: func(t *models.Task) any { return t.Completed }, decode: decodeAs[bool]},
+	"created_at": {value: func(t *models.Task) any { return t.CreatedAt }, decode: decodeAs[time.Time]},
+	"updated_at": {value: func(t *models.Task) any { return t.UpdatedAt }, decode: decodeAs[time.Time]},
+	"priority":   {value: func(t *models.Task) any { return t.Priority }, decode: decodeAs[models.Priority]},
+	"rank":       {value: func(t *models.Task) any { return t.Rank }, decode: decodeAs[string]},
+	"relevance":  {value: func(t *models.Task) any { return t.Relevance }, decode: decodeAs[float64]},
+}
+
+func decodeAs[T any](raw json.RawMessage) (any, error) {
+	var v T
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// DefaultTaskSort is the order used when the client does not ask for one:
+// by day, then in the manual order within the day.
+func DefaultTaskSort() []SortField {
+	return []SortField{{Field: "date"}, {Field: "rank"}, {Field: "id"}}
+}
+
+// SearchTaskSort is the order of search results: best match first.
+func SearchTaskSort() []SortField {
+	return []SortField{{Field: "relevance", Desc: true}, {Field: "id"}}
+}
+
+// ParseTaskSort parses a spec such as "date,-priority,title". Fields must be in the
+// allowlist and appear once; id is appended as the final tie-breaker unless
+// already present. An empty spec yields the default order.
+func ParseTaskSort(spec string) ([]SortField, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultTaskSort(), nil
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+
+		if _, ok := taskSortColumns[field.Field]; !ok {
+			return nil, fmt.Errorf("cannot sort by %q", part)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("field %q is listed more than once", field.Field)
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+
+	if !seen["id"] {
+		fields = append(fields, SortField{Field: "id"})
+	}
+	return fields, nil
+}
+
+// TaskSortFields lists the fields accepted by ParseTaskSort. relevance is
+// only meaningful together with a search query.
+func TaskSortFields() []string {
+	return []string{"date", "rank", "priority", "title", "completed", "created_at", "updated_at", "id", "relevance"}
+}
+
+// SortSpec formats fields back into the canonical "date,-title,id" form.
+func SortSpec(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Field
+		if f.Desc {
+			parts[i] = "-" + f.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// TaskSortValue returns the value of a sortable field of task.
+func TaskSortValue(task *models.Task, field string) any {
+	return taskSortColumns[field].value(task)
+}
+
+// CompareSortValues orders two values of the same sortable field.
+func CompareSortValues(a, b any) int {
+	switch a := a.(type) {
+	case uint:
+		return cmp.Compare(a, b.(uint))
+	case string:
+		return cmp.Compare(a, b.(string))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case models.Priority:
+		return cmp.Compare(a, b.(models.Priority))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case bool:
+		switch {
+		case a == b.(bool):
+			return 0
+		case a:
+			return 1
+		default:
+			return -1
+		}
+	default:
+		panic(fmt.Sprintf("unsupported sort value %T", a))
+	}
+}
+
+// CompareTasks orders a and b by fields.
+func CompareTasks(a, b *models.Task, fields []SortField) int {
+	for _, f := range fields {
+		c := CompareSortValues(TaskSortValue(a, f.Field), TaskSortValue(b, f.Field))
+		if f.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
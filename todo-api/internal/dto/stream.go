@@ -0,0 +1,21 @@
+package dto
+
+import "todo-api/internal/models"
+
+// Types of the messages of a task stream besides the task event types
+// created, updated and deleted.
+const (
+	// StreamTaskLeft is an updated task that does not match the filter of
+	// the stream (any more); a client showing it drops it.
+	StreamTaskLeft = "task.left"
+	// StreamReset tells a resuming client that the events it missed are no
+	// longer known, so it has to reload its tasks.
+	StreamReset = "reset"
+)
+
+// StreamEvent is a message of a task stream.
+type StreamEvent struct {
+	ID   string       `json:"id,omitempty"` // event ID, sent back as Last-Event-ID to resume after it
+	Type string       `json:"type"`
+	Task *models.Task `json:"task,omitempty"` // the task after the change, or as it was before it was deleted
+}
@@ -0,0 +1,16 @@
+package dto
+
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50,excludesall=0x2C"` // no commas, they separate names in filters
+	Color string `json:"color" binding:"omitempty,hexcolor,len=7"`        // "#rrggbb"
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=50,excludesall=0x2C"`
+	Color *string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+type UpdateTagServiceRequest struct {
+	Name  *string
+	Color *string
+}
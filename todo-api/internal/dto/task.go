@@ -2,24 +2,57 @@ package dto
 
 import (
 	"time"
+
+	"todo-api/internal/models"
 )
 
 type CreateTaskRequest struct {
-	Title       string `json:"title" binding:"required,max=255"`
-	Description string `json:"description" binding:"max=1000"`
-	DateString  string `json:"date" binding:"required"` // "2006-01-02"
+	Title       string   `json:"title" binding:"required,max=255"`
+	Description string   `json:"description" binding:"max=1000"`
+	DateString  string   `json:"date" binding:"required"` // "2006-01-02"
+	Priority    string   `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	Tags        []string `json:"tags" binding:"omitempty,dive,required"` // tag names
+	ProjectID   *uint    `json:"project_id" binding:"omitempty,min=1"`
+	ParentID    *uint    `json:"parent_id" binding:"omitempty,min=1"`
+	Recurrence  string   `json:"recurrence" binding:"max=500"`                         // RRULE, e.g. "FREQ=WEEKLY;BYDAY=MO,WE,FR"
+	ExDates     []string `json:"exdates" binding:"omitempty,dive,datetime=2006-01-02"` // days skipped by the recurrence
+	DueTime     string   `json:"due_time" binding:"omitempty,datetime=15:04"`          // "18:30" in the request time zone; none for all-day tasks
 }
 type CreateTaskServiceRequest struct {
 	Title       string
 	Description string
 	Date        time.Time
+	Priority    models.Priority
+	Tags        []string
+	ProjectID   *uint
+	ParentID    *uint
+	Recurrence  string
+	ExDates     []time.Time
+	DueTime     string         // "15:04", empty for an all-day task
+	Location    *time.Location // time zone of the request, UTC when nil
 }
 
 type UpdateTaskRequest struct {
-	Title       *string `json:"title" binding:"omitempty,min=3,max=255"`
-	Description *string `json:"description" binding:"omitempty,max=1000"`
-	DateString  *string `json:"date" binding:"omitempty"` // "2006-01-02"
-	Completed   *bool   `json:"completed"`
+	Title       *string  `json:"title" binding:"omitnil,min=1,max=255"`
+	Description *string  `json:"description" binding:"omitempty,max=1000"`
+	DateString  *string  `json:"date" binding:"omitempty"` // "2006-01-02"
+	Completed   *bool    `json:"completed"`
+	Priority    *string  `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	AddTags     []string `json:"add_tags" binding:"omitempty,dive,required"`           // tag names to attach
+	RemoveTags  []string `json:"remove_tags" binding:"omitempty,dive,required"`        // tag names to detach
+	ProjectID   *uint    `json:"project_id"`                                           // 0 takes the task out of its project
+	ParentID    *uint    `json:"parent_id"`                                            // 0 makes the task top-level
+	Recurrence  *string  `json:"recurrence" binding:"omitempty,max=500"`               // "" stops the task recurring
+	ExDates     []string `json:"exdates" binding:"omitempty,dive,datetime=2006-01-02"` // replaces the skipped days
+	DueTime     *string  `json:"due_time" binding:"omitempty,datetime=15:04"`          // "" makes the task all-day
+}
+
+// TaskDocument is a task as clients write it: the body of a PUT, which
+// replaces every one of these fields, and the document a PATCH patches.
+// It is checked by the rules of CreateTaskRequest.
+type TaskDocument struct {
+	CreateTaskRequest
+	Completed bool `json:"completed"`
 }
 
 type UpdateTaskServiceRequest struct {
@@ -27,20 +60,112 @@ type UpdateTaskServiceRequest struct {
 	Description *string
 	Date        *time.Time
 	Completed   *bool
+	Priority    *models.Priority
+	AddTags     []string
+	RemoveTags  []string
+	ProjectID   *uint // 0 takes the task out of its project
+	ParentID    *uint // 0 makes the task top-level
+	Recurrence  *string
+	ExDates     []time.Time    // nil leaves the skipped days unchanged
+	DueTime     *string        // "15:04", "" makes the task all-day
+	Location    *time.Location // time zone of the request, UTC when nil
+	Force       bool           // complete the task even while blockers are open
+	Version     uint           // expected current version, 0 skips the check
+}
+
+type UpdateTaskQuery struct {
+	Force bool `form:"force"` // complete the task even while blockers are open
+}
+
+// OccurrenceQuery selects which occurrences of a recurring task an edit
+// applies to: only the one on the given day, or it and all later ones.
+type OccurrenceQuery struct {
+	Scope string `form:"scope" binding:"omitempty,oneof=this following"`
 }
 
 type TaskFilterRequest struct {
-	Completed *string `form:"completed"` // "true"/"false"
-	DateFrom  *string `form:"date_from"` // "2006-01-02"
-	DateTo    *string `form:"date_to"`   // "2006-01-02"
-	Limit     *int    `form:"limit"`
-	Offset    *int    `form:"offset"`
+	Completed       *string `form:"completed"` // "true"/"false"
+	DateFrom        *string `form:"date_from"` // "2006-01-02"
+	DateTo          *string `form:"date_to"`   // "2006-01-02"
+	Limit           *int    `form:"limit"`
+	Offset          *int    `form:"offset"`
+	Cursor          *string `form:"cursor"`
+	Sort            *string `form:"sort"` // "date,-title"
+	Query           *string `form:"q" binding:"omitempty,max=200"`
+	Priority        *string `form:"priority"` // "high,urgent"
+	Tags            *string `form:"tags"`     // "work,home"
+	TagMode         *string `form:"tag_mode" binding:"omitempty,oneof=any all"`
+	Highlight       *string `form:"highlight"`        // "true"/"false"
+	ProjectID       *string `form:"project_id"`       // project ID or "none"
+	IncludeArchived *string `form:"include_archived"` // "true"/"false"
+	ParentID        *string `form:"parent_id"`        // parent task ID or "none"
+	Tree            *string `form:"tree"`             // "true"/"false"
+	Blocked         *string `form:"blocked"`          // "true"/"false"
 }
 
+// Tag filter modes: a task matches with any or with all of the listed tags.
+const (
+	TagModeAny = "any"
+	TagModeAll = "all"
+)
+
 type TaskFilter struct {
-	Completed *bool
-	DateFrom  *time.Time
-	DateTo    *time.Time
-	Limit     int
-	Offset    int
+	Completed       *bool
+	DateFrom        *time.Time
+	DateTo          *time.Time
+	Limit           int
+	Offset          int               // ignored when Cursor is set
+	Cursor          *Cursor           // keyset position, takes precedence over Offset
+	Sort            []SortField       // list order, always ending with id; empty means OrderOrDefault
+	Priorities      []models.Priority // any of
+	Tags            []string          // tag names, matched according to TagMode
+	TagMode         string            // TagModeAny (default) or TagModeAll
+	ProjectID       *uint             // tasks of one project, 0 for tasks outside any project
+	IncludeArchived bool              // also tasks of archived projects, otherwise hidden unless ProjectID names one
+	ParentID        *uint             // subtasks of one task, 0 for top-level tasks
+	Tree            bool              // nest all subtasks under each task; lists top-level tasks unless ParentID is set
+	Blocked         *bool             // tasks with (true) or without (false) an open blocker
+	Recurring       *bool             // recurring tasks (true) or tasks without a recurrence rule (false)
+	Trashed         bool              // deleted tasks in the trash instead of live ones
+	Location        *time.Location    // time zone the days of DateFrom and DateTo are taken in, UTC when nil
+	Query           string            // full-text search over title and description
+	Highlight       bool              // fill Task.Snippet for search matches
+}
+
+// OrderOrDefault returns the requested order, or the default one: by
+// relevance when searching, by date otherwise.
+func (f TaskFilter) OrderOrDefault() []SortField {
+	switch {
+	case len(f.Sort) > 0:
+		return f.Sort
+	case f.Query != "":
+		return SearchTaskSort()
+	default:
+		return DefaultTaskSort()
+	}
+}
+
+// MoveTaskRequest places a task in the manual order. AfterID and BeforeID
+// name the tasks it should follow and precede; with neither it goes to the
+// end of the day. Date moves it to another day first.
+type MoveTaskRequest struct {
+	AfterID    *uint   `json:"after_id" binding:"omitempty,min=1"`
+	BeforeID   *uint   `json:"before_id" binding:"omitempty,min=1"`
+	DateString *string `json:"date"` // "2006-01-02"
+}
+
+type MoveTaskServiceRequest struct {
+	AfterID  *uint
+	BeforeID *uint
+	Date     *time.Time
+	Version  uint // expected current version, 0 skips the check
+}
+
+type DeleteTaskRequest struct {
+	Subtasks  string `form:"subtasks" binding:"omitempty,oneof=cascade reparent"`
+	Permanent bool   `form:"permanent"` // delete for good instead of moving to the trash
+}
+
+type SubtasksRequest struct {
+	Tree bool `form:"tree"`
 }
@@ -0,0 +1,29 @@
+package dto
+
+import "todo-api/internal/models"
+
+type CreateWebhookRequest struct {
+	URL    string             `json:"url" binding:"required,http_url,max=2048"`
+	Secret string             `json:"secret" binding:"required,min=16,max=255"`                                                                              // HMAC-SHA256 key for the signature header
+	Events []models.EventType `json:"events" binding:"omitempty,dive,oneof=task.created task.updated task.completed task.deleted task.restored task.purged"` // every event when empty
+	Active *bool              `json:"active"`                                                                                                                // true when omitted
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string            `json:"url" binding:"omitempty,http_url,max=2048"`
+	Secret *string            `json:"secret" binding:"omitempty,min=16,max=255"`
+	Events []models.EventType `json:"events" binding:"omitempty,dive,oneof=task.created task.updated task.completed task.deleted task.restored task.purged"` // [] subscribes to every event
+	Active *bool              `json:"active"`
+}
+
+type UpdateWebhookServiceRequest struct {
+	URL    *string
+	Secret *string
+	Events []models.EventType // nil leaves the events alone
+	Active *bool
+}
+
+type DeliveryFilterRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending delivered dead"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"` // 50 when omitted
+}
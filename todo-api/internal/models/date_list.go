@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateFormat is the layout of the date-only values tasks use.
+const DateFormat = "2006-01-02"
+
+// DateList is a set of calendar days, stored as a comma-separated text
+// column and exposed to clients as an array of "2006-01-02" strings.
+type DateList []time.Time
+
+// Contains reports whether the list holds the day of t.
+func (l DateList) Contains(t time.Time) bool {
+	for _, date := range l {
+		if date.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l DateList) Value() (driver.Value, error) {
+	days := make([]string, len(l))
+	for i, date := range l {
+		days[i] = date.Format(DateFormat)
+	}
+	return strings.Join(days, ","), nil
+}
+
+func (l *DateList) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into DateList", src)
+	}
+
+	*l = nil
+	if s == "" {
+		return nil
+	}
+	for _, day := range strings.Split(s, ",") {
+		date, err := time.Parse(DateFormat, day)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, date)
+	}
+	return nil
+}
+
+func (l DateList) MarshalJSON() ([]byte, error) {
+	days := make([]string, len(l))
+	for i, date := range l {
+		days[i] = date.Format(DateFormat)
+	}
+	return json.Marshal(days)
+}
+
+func (l *DateList) UnmarshalJSON(data []byte) error {
+	var days []string
+	if err := json.Unmarshal(data, &days); err != nil {
+		return err
+	}
+	*l = nil
+	for _, day := range days {
+		date, err := time.Parse(DateFormat, day)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, date)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("day", DaySerializer{})
+}
+
+// DaySerializer stores a time.Time or *time.Time field as the calendar day
+// "2006-01-02" it stands for. Bound as a time, Postgres would send it as a
+// timestamptz and compare it with a DATE column in the session time zone,
+// a day off anywhere but UTC; SQLite would store the text of the whole
+// time. Days are read back as midnight UTC.
+type DaySerializer struct{}
+
+func (DaySerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value := reflect.New(field.FieldType).Elem()
+	if dbValue != nil {
+		day, err := parseDay(dbValue)
+		if err != nil {
+			return fmt.Errorf("cannot scan %s: %w", field.Name, err)
+		}
+		if field.FieldType.Kind() == reflect.Ptr {
+			value.Set(reflect.ValueOf(&day))
+		} else {
+			value.Set(reflect.ValueOf(day))
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(value)
+	return nil
+}
+
+func (DaySerializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch v := fieldValue.(type) {
+	case time.Time:
+		return v.Format(DateFormat), nil
+	case *time.Time:
+		if v == nil {
+			return nil, nil
+		}
+		return v.Format(DateFormat), nil
+	default:
+		return nil, fmt.Errorf("cannot store %T in day field %s", fieldValue, field.Name)
+	}
+}
+
+// parseDay reads a day as a driver returns it: a time, or text starting
+// with the day, as rows written before days were stored as text have it.
+func parseDay(src interface{}) (time.Time, error) {
+	var s string
+	switch v := src.(type) {
+	case time.Time:
+		y, m, d := v.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC), nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return time.Time{}, fmt.Errorf("unexpected day value %T", src)
+	}
+	if len(s) > len(DateFormat) {
+		s = s[:len(DateFormat)]
+	}
+	return time.Parse(DateFormat, s)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EventType names a change to a task that subscribers can be told about.
+type EventType string
+
+const (
+	EventTaskCreated   EventType = "task.created"
+	EventTaskUpdated   EventType = "task.updated"
+	EventTaskCompleted EventType = "task.completed" // sent along with task.updated
+	EventTaskDeleted   EventType = "task.deleted"   // moved to the trash
+	EventTaskRestored  EventType = "task.restored"  // brought back from the trash
+	EventTaskPurged    EventType = "task.purged"    // deleted for good
+)
+
+// EventTypes lists every event type, in the order they are documented.
+var EventTypes = []EventType{EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted, EventTaskRestored, EventTaskPurged}
+
+// Event is a change to a task. Task is the task after the change, or as it
+// was before it was deleted.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Task       Task      `json:"task"`
+	// ProjectArchived tells that the task's project was archived at the
+	// time, which hides the task from streams like from default listings.
+	ProjectArchived bool `json:"project_archived,omitempty"`
+}
+
+// EventList is a set of event types, stored as a comma-separated text
+// column.
+type EventList []EventType
+
+// Contains reports whether the list holds t.
+func (l EventList) Contains(t EventType) bool {
+	return slices.Contains(l, t)
+}
+
+func (l EventList) Value() (driver.Value, error) {
+	types := make([]string, len(l))
+	for i, t := range l {
+		types[i] = string(t)
+	}
+	return strings.Join(types, ","), nil
+}
+
+func (l *EventList) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into EventList", src)
+	}
+
+	*l = nil
+	if s == "" {
+		return nil
+	}
+	for _, t := range strings.Split(s, ",") {
+		*l = append(*l, EventType(t))
+	}
+	return nil
+}
+
+func (l EventList) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]EventType(l))
+}
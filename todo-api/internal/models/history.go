@@ -0,0 +1,220 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HistoryAction is what a change recorded in the task history did.
+type HistoryAction string
+
+const (
+	HistoryCreated  HistoryAction = "created"
+	HistoryUpdated  HistoryAction = "updated"
+	HistoryDeleted  HistoryAction = "deleted" // moved to the trash
+	HistoryRestored HistoryAction = "restored"
+	HistoryPurged   HistoryAction = "purged" // deleted for good; changes nothing
+)
+
+// TaskHistory is one change to a task: who made it, in which request, and
+// the fields it changed.
+type TaskHistory struct {
+	ID        uint          `gorm:"primarykey" json:"id"` // the order changes were made in
+	TaskID    uint          `gorm:"not null" json:"task_id"`
+	Version   uint          `gorm:"not null" json:"version"` // of the task after the change
+	Action    HistoryAction `gorm:"size:16;not null" json:"action"`
+	Actor     string        `gorm:"size:255;not null;default:''" json:"actor,omitempty"`
+	RequestID string        `gorm:"size:128;not null;default:''" json:"request_id,omitempty"`
+	Changes   FieldChanges  `gorm:"type:text;not null" json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+func (TaskHistory) TableName() string {
+	return "task_history"
+}
+
+// FieldChange is the JSON value of a task field before and after a
+// change; null on the side where the task did not exist.
+type FieldChange struct {
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+// FieldChanges maps the JSON names of the fields a change touched to their
+// values, stored as a JSON text column.
+type FieldChanges map[string]FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]FieldChange(c))
+	return string(data), err
+}
+
+func (c *FieldChanges) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into FieldChanges", src)
+	}
+
+	*c = FieldChanges{}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]FieldChange)(c))
+}
+
+// trackedFields are the stored fields of a task the history follows,
+// named as in the task JSON. Tags are followed by name.
+type trackedFields struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Date        time.Time  `json:"date"`
+	Completed   bool       `json:"completed"`
+	Priority    Priority   `json:"priority"`
+	Rank        string     `json:"rank"`
+	Tags        []string   `json:"tags"`
+	ProjectID   *uint      `json:"project_id"`
+	ParentID    *uint      `json:"parent_id"`
+	Recurrence  string     `json:"recurrence"`
+	ExDates     DateList   `json:"exdates"`
+	DueAt       *time.Time `json:"due_at"`
+	TimeZone    string     `json:"time_zone"`
+	Version     uint       `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+func fieldsOf(task *Task) trackedFields {
+	fields := trackedFields{
+		Title:       task.Title,
+		Description: task.Description,
+		Date:        task.Date.UTC(),
+		Completed:   task.Completed,
+		Priority:    task.Priority,
+		Rank:        task.Rank,
+		Tags:        make([]string, len(task.Tags)),
+		ProjectID:   task.ProjectID,
+		ParentID:    task.ParentID,
+		Recurrence:  task.Recurrence,
+		ExDates:     task.ExDates,
+		TimeZone:    task.TimeZone,
+		Version:     task.Version,
+	}
+	for i, tag := range task.Tags {
+		fields.Tags[i] = tag.Name
+	}
+	if task.DueAt != nil {
+		due := task.DueAt.UTC()
+		fields.DueAt = &due
+	}
+	if task.DeletedAt.Valid {
+		deleted := task.DeletedAt.Time.UTC()
+		fields.DeletedAt = &deleted
+	}
+	return fields
+}
+
+// fieldValues returns the JSON value of every tracked field of task, null
+// for all of them when task is nil.
+func fieldValues(task *Task) (map[string]json.RawMessage, error) {
+	var fields any
+	if task != nil {
+		fields = fieldsOf(task)
+	} else {
+		fields = trackedFields{}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]json.RawMessage
+	if err = json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	if task == nil {
+		for name := range values {
+			values[name] = json.RawMessage("null")
+		}
+	}
+	return values, nil
+}
+
+// DiffTasks returns the tracked fields whose values differ between before
+// and after. A nil task stands for one that does not exist.
+func DiffTasks(before, after *Task) (FieldChanges, error) {
+	old, err := fieldValues(before)
+	if err != nil {
+		return nil, err
+	}
+	current, err := fieldValues(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := FieldChanges{}
+	for name, value := range current {
+		if !bytes.Equal(old[name], value) {
+			changes[name] = FieldChange{Before: old[name], After: value}
+		}
+	}
+	return changes, nil
+}
+
+// Revert sets the fields of task the changes touched back to their values
+// before them. Tags come back with their names only.
+func (c FieldChanges) Revert(task *Task) error {
+	values, err := fieldValues(task)
+	if err != nil {
+		return err
+	}
+	for name, change := range c {
+		if _, ok := values[name]; ok {
+			values[name] = change.Before
+		}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	var fields trackedFields
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	task.Title = fields.Title
+	task.Description = fields.Description
+	task.Date = fields.Date
+	task.Completed = fields.Completed
+	task.Priority = fields.Priority
+	task.Rank = fields.Rank
+	task.ProjectID = fields.ProjectID
+	task.ParentID = fields.ParentID
+	task.Recurrence = fields.Recurrence
+	task.ExDates = fields.ExDates
+	task.DueAt = fields.DueAt
+	task.TimeZone = fields.TimeZone
+	task.Version = fields.Version
+	task.DeletedAt = gorm.DeletedAt{}
+	if fields.DeletedAt != nil {
+		task.DeletedAt = gorm.DeletedAt{Time: *fields.DeletedAt, Valid: true}
+	}
+	if _, ok := c["tags"]; ok {
+		task.Tags = make([]Tag, len(fields.Tags))
+		for i, name := range fields.Tags {
+			task.Tags[i] = Tag{Name: name}
+		}
+	}
+	return nil
+}
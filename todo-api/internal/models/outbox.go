@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a task event recorded in the same transaction as the
+// change it describes, kept until the outbox relay has handed it to every
+// consumer.
+type OutboxEvent struct {
+	ID      uint      `gorm:"primarykey" json:"id"` // the order events are published in
+	EventID string    `gorm:"size:64;not null" json:"event_id"`
+	Type    EventType `gorm:"size:32;not null" json:"type"`
+	TaskID  uint      `gorm:"not null" json:"task_id"`
+	Payload RawJSON   `gorm:"type:text;not null" json:"payload"` // the Event
+
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text;not null;default:''" json:"last_error,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"` // nil until every consumer took the event
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Event decodes the event the row holds.
+func (e *OutboxEvent) Event() (Event, error) {
+	var event Event
+	err := json.Unmarshal([]byte(e.Payload), &event)
+	return event, err
+}
+
+// OutboxStats sums up the state of the outbox.
+type OutboxStats struct {
+	Pending         int64      // events not published yet
+	Failing         int64      // pending events whose last attempt failed
+	OldestPendingAt *time.Time // when the oldest pending event was recorded
+	LastPublishedID uint       // the newest published event, 0 if none
+	LastPublishedAt *time.Time
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// Project groups tasks into a list. Archived projects keep their tasks but
+// hide them from default task listings.
+type Project struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"type:text;not null;default:''" json:"description"`
+	Color       string    `gorm:"size:7;not null;default:''" json:"color"` // "#rrggbb", empty for the client's default
+	Archived    bool      `gorm:"not null;default:false" json:"archived"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Counted over live tasks on read; never stored.
+	OpenTasks      int64 `gorm:"->;-:migration" json:"open_tasks"`
+	CompletedTasks int64 `gorm:"->;-:migration" json:"completed_tasks"`
+}
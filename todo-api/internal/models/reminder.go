@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// ReminderStatus is how far the delivery of a reminder got.
+type ReminderStatus string
+
+const (
+	ReminderPending ReminderStatus = "pending" // waiting for its time or its next attempt
+	ReminderSent    ReminderStatus = "sent"
+	ReminderFailed  ReminderStatus = "failed" // every attempt failed
+)
+
+// Channels a reminder can be delivered through.
+const (
+	ChannelLog     = "log"
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+// Reminder notifies about a task at a fixed moment (RemindAt) or some
+// minutes before the task is due (BeforeMinutes). FireAt is when it goes
+// off; the delivery fields record the attempts at sending it.
+type Reminder struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	TaskID        uint       `gorm:"not null;index" json:"task_id"`
+	Channel       string     `gorm:"size:16;not null" json:"channel"`
+	Email         string     `gorm:"size:254;not null;default:''" json:"email,omitempty"` // recipient on the email channel
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+	BeforeMinutes *int       `json:"before_minutes,omitempty"`
+	// FireAt follows the due time of the task for relative reminders and is
+	// nil while the task has none.
+	FireAt *time.Time `json:"fire_at"`
+
+	Status        ReminderStatus `gorm:"size:16;not null;default:pending" json:"status"`
+	Attempts      int            `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt *time.Time     `gorm:"index" json:"next_attempt_at,omitempty"` // nil when nothing is scheduled
+	LastError     string         `gorm:"type:text;not null;default:''" json:"last_error,omitempty"`
+	SentAt        *time.Time     `json:"sent_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Relative reports whether the reminder is set relative to the due time.
+func (r *Reminder) Relative() bool {
+	return r.BeforeMinutes != nil
+}
+
+// Reschedule sets a relative reminder to go off BeforeMinutes before due,
+// or takes it off the schedule when due is nil, starting its delivery over.
+func (r *Reminder) Reschedule(due *time.Time) {
+	r.FireAt = nil
+	if due != nil {
+		fireAt := due.Add(-time.Duration(*r.BeforeMinutes) * time.Minute)
+		r.FireAt = &fireAt
+	}
+	r.NextAttemptAt = r.FireAt
+	r.Status = ReminderPending
+	r.Attempts = 0
+	r.LastError = ""
+	r.SentAt = nil
+}
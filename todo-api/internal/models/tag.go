@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Color     string    `gorm:"size:7;not null;default:''" json:"color"` // "#rrggbb", empty for the client's default
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskTag is a row of the task_tags join table.
+type TaskTag struct {
+	TaskID uint `gorm:"primaryKey"`
+	TagID  uint `gorm:"primaryKey"`
+}
@@ -10,6 +10,46 @@ type Task struct {
 	gorm.Model
 	Title       string    `gorm:"size:255;not null" json:"title" binding:"required"`
 	Description string    `gorm:"type:text" json:"description" binding:"max=1000"`
-	Date        time.Time `gorm:"not null;index" json:"date" binding:"required"`
+	Date        time.Time `gorm:"type:date;not null;index;serializer:day" json:"date" binding:"required"` // calendar day, midnight UTC
 	Completed   bool      `gorm:"default:false" json:"completed"`
+	Version     uint      `gorm:"not null;default:1" json:"version"` // bumped on every update, exposed as ETag
+	Priority    Priority  `gorm:"not null;default:0" json:"priority"`
+	Rank        string    `gorm:"size:255;not null;default:''" json:"rank"` // manual order within Date, see pkg/lexorank
+	Tags        []Tag     `gorm:"many2many:task_tags" json:"tags"`
+	ProjectID   *uint     `gorm:"index" json:"project_id"` // nil for tasks outside any project
+	ParentID    *uint     `gorm:"index" json:"parent_id"`  // nil for top-level tasks
+
+	// Recurrence is an RFC 5545 RRULE whose first occurrence is Date; empty
+	// for one-off tasks. ExDates are the days removed from the series.
+	Recurrence string   `gorm:"type:text;not null;default:''" json:"recurrence,omitempty"`
+	ExDates    DateList `gorm:"type:text;not null;default:''" json:"exdates,omitempty"`
+	// SeriesID and OccurrenceDate identify an occurrence of a recurring
+	// task: a stored one that was edited separately from its series, or one
+	// expanded on read.
+	SeriesID       *uint      `gorm:"index" json:"series_id,omitempty"`
+	OccurrenceDate *time.Time `gorm:"type:date;serializer:day" json:"occurrence_date,omitempty"`
+	// DueAt is the moment a task with a due time is due, on its Date in
+	// TimeZone; both are empty for all-day tasks.
+	DueAt    *time.Time `gorm:"index" json:"due_at"`
+	TimeZone string     `gorm:"size:64;not null;default:''" json:"time_zone,omitempty"`
+
+	// Progress over the direct subtasks, counted on read; never stored.
+	SubtasksTotal     int64 `gorm:"->;-:migration" json:"subtasks_total"`
+	SubtasksCompleted int64 `gorm:"->;-:migration" json:"subtasks_completed"`
+	// Set when an open task blocks this one, computed on read; never stored.
+	Blocked bool `gorm:"->;-:migration" json:"blocked"`
+	// Filled only for tree responses.
+	Subtasks []Task `gorm:"-" json:"subtasks,omitempty"`
+
+	// Set only by searches (the q filter); never stored.
+	Relevance float64 `gorm:"->;-:migration" json:"relevance,omitempty"`
+	Snippet   string  `gorm:"->;-:migration" json:"snippet,omitempty"` // matched text with <mark> highlights
+}
+
+// TaskDependency is a row of the task_dependencies table: the task cannot
+// be completed while the blocker is open.
+type TaskDependency struct {
+	TaskID    uint `gorm:"primaryKey"`
+	BlockerID uint `gorm:"primaryKey;index"`
+	CreatedAt time.Time
 }
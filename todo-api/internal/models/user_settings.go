@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// UserSettings are the preferences of one actor, the X-Actor a request
+// names.
+type UserSettings struct {
+	Actor     string    `gorm:"primaryKey;size:255" json:"actor"`
+	TimeZone  string    `gorm:"size:64;not null;default:''" json:"time_zone"` // IANA name, empty for the server default
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
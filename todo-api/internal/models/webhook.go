@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// WebhookSubscription asks for the events of the listed types to be POSTed
+// to URL, signed with Secret.
+type WebhookSubscription struct {
+	ID     uint      `gorm:"primarykey" json:"id"`
+	URL    string    `gorm:"size:2048;not null" json:"url"`
+	Secret string    `gorm:"size:255;not null" json:"-"`                  // HMAC-SHA256 key, never returned
+	Events EventList `gorm:"type:text;not null;default:''" json:"events"` // empty for every event
+	Active bool      `gorm:"not null" json:"active"`                      // paused subscriptions keep their deliveries pending
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Wants reports whether the subscription is for events of type t.
+func (s *WebhookSubscription) Wants(t EventType) bool {
+	return s.Active && (len(s.Events) == 0 || s.Events.Contains(t))
+}
+
+// DeliveryStatus is how far the delivery of an event to a subscription got.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending" // waiting for its next attempt
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryDead      DeliveryStatus = "dead" // every attempt failed, redelivered only on request
+)
+
+// RawJSON is a JSON document stored as text and embedded as is in
+// responses.
+type RawJSON string
+
+func (j RawJSON) MarshalJSON() ([]byte, error) {
+	if j == "" {
+		return []byte("null"), nil
+	}
+	return []byte(j), nil
+}
+
+// WebhookDelivery is one event sent to one subscription, with the state of
+// the attempts at sending it.
+type WebhookDelivery struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	SubscriptionID uint      `gorm:"not null;index" json:"subscription_id"`
+	EventID        string    `gorm:"size:64;not null" json:"event_id"`
+	EventType      EventType `gorm:"size:32;not null" json:"event_type"`
+	Payload        RawJSON   `gorm:"type:text;not null" json:"payload"` // the request body, an Event
+
+	Status         DeliveryStatus `gorm:"size:16;not null;default:pending" json:"status"`
+	Attempts       int            `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time     `gorm:"index" json:"next_attempt_at,omitempty"` // nil when nothing is scheduled
+	LastError      string         `gorm:"type:text;not null;default:''" json:"last_error,omitempty"`
+	ResponseStatus int            `gorm:"not null;default:0" json:"response_status,omitempty"` // of the last attempt that got a response
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
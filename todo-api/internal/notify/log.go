@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// LogNotifier writes notifications to the server log. It never fails.
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, notification Notification) error {
+	n.logger.Info("Reminder",
+		zap.String("notification_id", notification.ID),
+		zap.Uint("task_id", notification.Task.ID),
+		zap.String("title", notification.Task.Title),
+	)
+	return nil
+}
@@ -0,0 +1,60 @@
+// Package notify delivers reminders through pluggable channels: the log,
+// an outgoing webhook and SMTP e-mail.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"todo-api/internal/models"
+)
+
+// Notification is one reminder about one task.
+type Notification struct {
+	// ID stays the same across attempts at delivering a reminder, so that
+	// receivers can drop the duplicates a retry may cause.
+	ID       string          `json:"id"`
+	Reminder models.Reminder `json:"reminder"`
+	Task     models.Task     `json:"task"`
+}
+
+// New returns the notification for reminder about task.
+func New(reminder models.Reminder, task models.Task) Notification {
+	id := fmt.Sprintf("reminder-%d", reminder.ID)
+	if reminder.FireAt != nil {
+		id = fmt.Sprintf("%s-%d", id, reminder.FireAt.Unix())
+	}
+	return Notification{ID: id, Reminder: reminder, Task: task}
+}
+
+// Notifier delivers notifications through one channel. Notify returns once
+// the notification has been handed over, or with an error worth a retry.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Subject is a one-line summary of the notification.
+func (n Notification) Subject() string {
+	return "Reminder: " + n.Task.Title
+}
+
+// Text is a plain-text description of the task.
+func (n Notification) Text() string {
+	var b strings.Builder
+	b.WriteString(n.Task.Title + "\n")
+	if n.Task.DueAt != nil {
+		due := *n.Task.DueAt
+		if loc, err := time.LoadLocation(n.Task.TimeZone); err == nil {
+			due = due.In(loc)
+		}
+		b.WriteString("Due: " + due.Format("2006-01-02 15:04 MST") + "\n")
+	} else {
+		b.WriteString("Date: " + n.Task.Date.Format(models.DateFormat) + "\n")
+	}
+	if n.Task.Description != "" {
+		b.WriteString("\n" + n.Task.Description + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,212 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/notify"
+)
+
+func notification() notify.Notification {
+	due := time.Date(2030, 3, 10, 15, 30, 0, 0, time.UTC)
+	return notify.New(
+		models.Reminder{ID: 4, TaskID: 1, Channel: models.ChannelEmail, Email: "me@example.com", FireAt: &due},
+		models.Task{Model: gorm.Model{ID: 1}, Title: "Позвонить маме", Date: due.Truncate(24 * time.Hour), DueAt: &due, TimeZone: "Europe/Moscow"},
+	)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("delivered", func(t *testing.T) {
+		// Arrange
+		var got notify.Notification
+		var key string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key = r.Header.Get("Idempotency-Key")
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		// Act
+		err := notify.NewWebhookNotifier(server.URL, server.Client()).Notify(context.Background(), notification())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, notification().ID, key)
+		assert.Equal(t, notification().ID, got.ID)
+		assert.Equal(t, "Позвонить маме", got.Task.Title)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		// Act
+		err := notify.NewWebhookNotifier(server.URL, server.Client()).Notify(context.Background(), notification())
+
+		// Assert
+		assert.EqualError(t, err, "webhook responded with 503 Service Unavailable")
+	})
+}
+
+// smtpStandIn is a local SMTP server that accepts one message per
+// connection and keeps it, rejecting recipients in reject.
+type smtpStandIn struct {
+	listener net.Listener
+	reject   string
+
+	mu       sync.Mutex
+	from, to string
+	data     string
+}
+
+func newSMTPStandIn(t *testing.T, reject string) *smtpStandIn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStandIn{listener: listener, reject: reject}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpStandIn) config() notify.SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return notify.SMTPConfig{Host: addr.IP.String(), Port: addr.Port, From: "todo@example.com"}
+}
+
+func (s *smtpStandIn) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		upper := strings.ToUpper(command)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.Trim(strings.Fields(command[len("MAIL FROM:"):])[0], "<>")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			to := strings.Trim(command[len("RCPT TO:"):], "<>")
+			if to == s.reject {
+				reply("550 No such user")
+				continue
+			}
+			s.mu.Lock()
+			s.to = to
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpStandIn) message() (from, to, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.from, s.to, s.data
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	t.Run("delivered", func(t *testing.T) {
+		// Arrange
+		server := newSMTPStandIn(t, "")
+
+		// Act
+		err := notify.NewSMTPNotifier(server.config()).Notify(context.Background(), notification())
+
+		// Assert
+		require.NoError(t, err)
+		from, to, data := server.message()
+		assert.Equal(t, "todo@example.com", from)
+		assert.Equal(t, "me@example.com", to)
+		assert.Contains(t, data, "To: me@example.com\r\n")
+		assert.Contains(t, data, "Subject: =?utf-8?q?")
+		assert.Contains(t, data, "Message-ID: <"+notification().ID+"@")
+		assert.Contains(t, data, "Due: 2030-03-10 18:30 MSK\r\n")
+	})
+
+	t.Run("recipient rejected", func(t *testing.T) {
+		// Arrange
+		server := newSMTPStandIn(t, "me@example.com")
+
+		// Act
+		err := notify.NewSMTPNotifier(server.config()).Notify(context.Background(), notification())
+
+		// Assert
+		assert.ErrorContains(t, err, "No such user")
+	})
+
+	t.Run("server down", func(t *testing.T) {
+		// Arrange
+		server := newSMTPStandIn(t, "")
+		cfg := server.config()
+		server.listener.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// Act
+		err := notify.NewSMTPNotifier(cfg).Notify(ctx, notification())
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,186 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+func TestTaskRepository_Batch(t *testing.T) {
+	for name, newRepos := range tagRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("create many", func(t *testing.T) {
+				// Arrange
+				repo, tags := newRepos(t)
+				ctx := context.Background()
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "home"}))
+				tasks := []models.Task{
+					{Title: "First", Date: day(0), Tags: []models.Tag{{Name: "work"}, {Name: "home"}}},
+					{Title: "Second", Date: day(1)},
+				}
+
+				// Act
+				err := repo.CreateMany(ctx, tasks)
+
+				// Assert
+				require.NoError(t, err)
+				require.NotZero(t, tasks[0].ID)
+				assert.Equal(t, []string{"home", "work"}, tagNames(tasks[0].Tags))
+				found, err := repo.GetByIDs(ctx, []uint{tasks[1].ID, tasks[0].ID, 999})
+				require.NoError(t, err)
+				assert.Equal(t, []uint{tasks[1].ID, tasks[0].ID}, taskIDs(found), "in the order asked, missing ones left out")
+				assert.Equal(t, uint(1), found[0].Version)
+				assert.Equal(t, []string{"home", "work"}, tagNames(found[1].Tags))
+			})
+
+			t.Run("create many fails as a whole", func(t *testing.T) {
+				// Arrange
+				repo, _ := newRepos(t)
+				ctx := context.Background()
+				tasks := []models.Task{
+					{Title: "Fine", Date: day(0)},
+					{Title: "Tagged", Date: day(0), Tags: []models.Tag{{Name: "missing"}}},
+				}
+
+				// Act
+				err := repo.CreateMany(ctx, tasks)
+
+				// Assert
+				assert.ErrorIs(t, err, repositories.ErrUnknownTag)
+				total, err := repo.Count(ctx, dto.TaskFilter{})
+				require.NoError(t, err)
+				assert.Zero(t, total)
+			})
+
+			t.Run("update many", func(t *testing.T) {
+				// Arrange
+				repo, tags := newRepos(t)
+				ctx := context.Background()
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+				root, child, leaf, sibling := createTree(t, repo)
+				other := &models.Task{Title: "Other", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, other))
+				due := time.Date(2026, 1, 12, 9, 30, 0, 0, time.UTC)
+
+				// Act
+				err := repo.UpdateMany(ctx, []uint{root.ID, other.ID}, map[string]interface{}{
+					"completed":                      true,
+					"date":                           day(2),
+					repositories.CompleteSubtasksKey: true,
+					repositories.TagChangesKey:       repositories.TagChanges{Attach: []string{"work"}},
+					repositories.DueTimesKey:         repositories.DueTimes{other.ID: &due},
+				})
+
+				// Assert
+				require.NoError(t, err)
+				found, err := repo.GetByIDs(ctx, []uint{root.ID, other.ID, child.ID, leaf.ID, sibling.ID})
+				require.NoError(t, err)
+				for _, task := range found[:4] {
+					assert.True(t, task.Completed, "task %d", task.ID)
+				}
+				assert.True(t, day(2).Equal(found[1].Date))
+				assert.Equal(t, []string{"work"}, tagNames(found[1].Tags))
+				require.NotNil(t, found[1].DueAt)
+				assert.True(t, due.Equal(*found[1].DueAt))
+				assert.Nil(t, found[0].DueAt)
+				assert.Equal(t, uint(2), found[2].Version, "the open subtasks are completed along")
+				assert.Equal(t, uint(1), found[4].Version, "the completed subtask is left alone")
+			})
+
+			t.Run("update many fails as a whole", func(t *testing.T) {
+				// Arrange
+				repo, _ := newRepos(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Kept", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, task))
+
+				// Act
+				err := repo.UpdateMany(ctx, []uint{task.ID, 999}, map[string]interface{}{"completed": true})
+
+				// Assert
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				found, err := repo.GetByID(ctx, task.ID)
+				require.NoError(t, err)
+				assert.False(t, found.Completed)
+			})
+
+			t.Run("delete many", func(t *testing.T) {
+				// Arrange
+				repo, _ := newRepos(t)
+				ctx := context.Background()
+				root, child, leaf, sibling := createTree(t, repo)
+				series, _, _ := createSeries(t, repo)
+
+				// Act
+				err := repo.DeleteMany(ctx, []uint{root.ID, child.ID, series.ID}, false)
+
+				// Assert
+				require.NoError(t, err)
+				live, err := repo.List(ctx, dto.TaskFilter{Limit: 10})
+				require.NoError(t, err)
+				assert.ElementsMatch(t, []uint{leaf.ID, sibling.ID}, taskIDs(live), "stored occurrences go with their series")
+				for _, task := range live {
+					assert.Nil(t, task.ParentID, "task %d moves up past every deleted ancestor", task.ID)
+				}
+				trash, err := repo.Count(ctx, dto.TaskFilter{Trashed: true})
+				require.NoError(t, err)
+				assert.Equal(t, int64(3), trash)
+			})
+
+			t.Run("delete many with cascade", func(t *testing.T) {
+				// Arrange
+				repo, _ := newRepos(t)
+				ctx := context.Background()
+				root, child, _, _ := createTree(t, repo)
+				other := &models.Task{Title: "Other", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, other))
+
+				// Act
+				err := repo.DeleteMany(ctx, []uint{child.ID, root.ID}, true)
+
+				// Assert
+				require.NoError(t, err)
+				live, err := repo.List(ctx, dto.TaskFilter{Limit: 10})
+				require.NoError(t, err)
+				assert.Equal(t, []uint{other.ID}, taskIDs(live))
+				assert.ErrorIs(t, repo.DeleteMany(ctx, []uint{other.ID, root.ID}, true), gorm.ErrRecordNotFound)
+			})
+		})
+	}
+}
+
+func TestTaskRepository_BatchEvents(t *testing.T) {
+	for name, newRepos := range outboxRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo, outbox := newRepos(t)
+			ctx := context.Background()
+			done := &models.Task{Title: "Done", Date: day(0), Completed: true}
+			require.NoError(t, repo.Create(ctx, done))
+			open := &models.Task{Title: "Open", Date: day(0)}
+			require.NoError(t, repo.Create(ctx, open))
+			drain(t, outbox)
+
+			// Act
+			require.NoError(t, repo.UpdateMany(ctx, []uint{done.ID, open.ID}, map[string]interface{}{"completed": true}))
+			require.NoError(t, repo.DeleteMany(ctx, []uint{done.ID}, false))
+
+			// Assert
+			assert.Equal(t, []recorded{
+				{TaskID: done.ID, Type: models.EventTaskUpdated},
+				{TaskID: open.ID, Type: models.EventTaskUpdated},
+				{TaskID: open.ID, Type: models.EventTaskCompleted},
+				{TaskID: done.ID, Type: models.EventTaskDeleted},
+			}, recordedEvents(drain(t, outbox)))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+func TestTaskRepository_Dependencies(t *testing.T) {
+	for name, newRepo := range repositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			// createTasks creates n tasks on consecutive days.
+			createTasks := func(t *testing.T, repo repositories.TaskRepository, n int) []*models.Task {
+				tasks := make([]*models.Task, n)
+				for i := range tasks {
+					tasks[i] = &models.Task{Title: "Task", Date: day(i)}
+					require.NoError(t, repo.Create(context.Background(), tasks[i]))
+				}
+				return tasks
+			}
+
+			t.Run("blocked until blockers complete", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				tasks := createTasks(t, repo, 3)
+				require.NoError(t, repo.AddDependency(ctx, tasks[0].ID, tasks[1].ID))
+				require.NoError(t, repo.AddDependency(ctx, tasks[0].ID, tasks[1].ID))
+				require.NoError(t, repo.AddDependency(ctx, tasks[0].ID, tasks[2].ID))
+				require.NoError(t, repo.Update(ctx, tasks[2].ID, 0, map[string]interface{}{"completed": true}))
+				blocked, unblocked := true, false
+
+				// Act
+				before, err := repo.GetByID(ctx, tasks[0].ID)
+				require.NoError(t, err)
+				blockers, err := repo.ListBlockers(ctx, tasks[0].ID)
+				require.NoError(t, err)
+				onlyBlocked, err := repo.List(ctx, dto.TaskFilter{Blocked: &blocked, Limit: 10})
+				require.NoError(t, err)
+				notBlocked, err := repo.List(ctx, dto.TaskFilter{Blocked: &unblocked, Limit: 10})
+				require.NoError(t, err)
+				require.NoError(t, repo.Update(ctx, tasks[1].ID, 0, map[string]interface{}{"completed": true}))
+				after, err := repo.GetByID(ctx, tasks[0].ID)
+				require.NoError(t, err)
+
+				// Assert
+				assert.True(t, before.Blocked)
+				assert.Equal(t, []uint{tasks[1].ID, tasks[2].ID}, taskIDs(blockers))
+				assert.Equal(t, []uint{tasks[0].ID}, taskIDs(onlyBlocked))
+				assert.Equal(t, []uint{tasks[1].ID, tasks[2].ID}, taskIDs(notBlocked))
+				assert.False(t, after.Blocked)
+			})
+
+			t.Run("deleted blocker does not block", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				tasks := createTasks(t, repo, 2)
+				require.NoError(t, repo.AddDependency(ctx, tasks[0].ID, tasks[1].ID))
+				require.NoError(t, repo.Delete(ctx, tasks[1].ID, 0, false))
+
+				// Act
+				found, err := repo.GetByID(ctx, tasks[0].ID)
+				require.NoError(t, err)
+				blockers, err := repo.ListBlockers(ctx, tasks[0].ID)
+				require.NoError(t, err)
+
+				// Assert
+				assert.False(t, found.Blocked)
+				assert.Empty(t, blockers)
+			})
+
+			t.Run("cycles", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				tasks := createTasks(t, repo, 3)
+				require.NoError(t, repo.AddDependency(ctx, tasks[0].ID, tasks[1].ID))
+				require.NoError(t, repo.AddDependency(ctx, tasks[1].ID, tasks[2].ID))
+
+				// Act
+				selfErr := repo.AddDependency(ctx, tasks[0].ID, tasks[0].ID)
+				loopErr := repo.AddDependency(ctx, tasks[2].ID, tasks[0].ID)
+
+				// Assert
+				assert.ErrorIs(t, selfErr, repositories.ErrDependencyCycle)
+				assert.ErrorIs(t, loopErr, repositories.ErrDependencyCycle)
+				assert.EqualError(t, loopErr, "dependency cycle: 3 -> 1 -> 2 -> 3")
+			})
+
+			t.Run("missing", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				tasks := createTasks(t, repo, 2)
+
+				// Act
+				addErr := repo.AddDependency(ctx, tasks[0].ID, 42)
+				removeErr := repo.RemoveDependency(ctx, tasks[0].ID, tasks[1].ID)
+
+				// Assert
+				assert.ErrorIs(t, addErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, removeErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("remove", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				tasks := createTasks(t, repo, 2)
+				require.NoError(t, repo.AddDependency(ctx, tasks[0].ID, tasks[1].ID))
+
+				// Act
+				err := repo.RemoveDependency(ctx, tasks[0].ID, tasks[1].ID)
+				require.NoError(t, err)
+				found, getErr := repo.GetByID(ctx, tasks[0].ID)
+
+				// Assert
+				require.NoError(t, getErr)
+				assert.False(t, found.Blocked)
+			})
+		})
+	}
+}
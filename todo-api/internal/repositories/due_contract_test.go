@@ -0,0 +1,51 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+func TestTaskRepository_DueTime(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	for name, newRepo := range repositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo := newRepo(t)
+			ctx := context.Background()
+			// 23:30 on the 10th and 01:00 on the 11th in Moscow, both on the 10th in UTC.
+			late := time.Date(2026, 1, 10, 23, 30, 0, 0, moscow).UTC()
+			early := time.Date(2026, 1, 11, 1, 0, 0, 0, moscow).UTC()
+			allDay := &models.Task{Title: "Groceries", Date: day(0)}
+			lateCall := &models.Task{Title: "Call", Date: day(0), DueAt: &late, TimeZone: "Europe/Moscow"}
+			earlyRun := &models.Task{Title: "Run", Date: day(1), DueAt: &early, TimeZone: "Europe/Moscow"}
+			for _, task := range []*models.Task{allDay, lateCall, earlyRun} {
+				require.NoError(t, repo.Create(ctx, task))
+			}
+			from := day(0)
+
+			// Act
+			inMoscow, err := repo.List(ctx, dto.TaskFilter{DateFrom: &from, DateTo: &from, Location: moscow, Limit: 10})
+			require.NoError(t, err)
+			inUTC, err := repo.List(ctx, dto.TaskFilter{DateFrom: &from, DateTo: &from, Limit: 10})
+			require.NoError(t, err)
+			found, err := repo.GetByID(ctx, lateCall.ID)
+			require.NoError(t, err)
+
+			// Assert
+			assert.ElementsMatch(t, []uint{allDay.ID, lateCall.ID}, taskIDs(inMoscow))
+			assert.ElementsMatch(t, []uint{allDay.ID, lateCall.ID, earlyRun.ID}, taskIDs(inUTC))
+			require.NotNil(t, found.DueAt)
+			assert.True(t, late.Equal(*found.DueAt))
+			assert.Equal(t, "Europe/Moscow", found.TimeZone)
+		})
+	}
+}
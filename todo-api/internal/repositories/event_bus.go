@@ -0,0 +1,19 @@
+//go:generate mockgen -source=./event_bus.go -destination=./mock/event_bus.go -package=mock
+package repositories
+
+import (
+	"context"
+
+	"todo-api/internal/models"
+)
+
+// EventBus carries published task events to every process serving the
+// API, so that each can push them to the clients streaming from it.
+type EventBus interface {
+	// Publish sends an event to the listeners of every process.
+	Publish(ctx context.Context, event models.Event) error
+	// Listen hands handle the events published from now on, in the order
+	// they were published, until ctx is done or the bus loses events, which
+	// it reports as an error. handle must not block.
+	Listen(ctx context.Context, handle func(models.Event)) error
+}
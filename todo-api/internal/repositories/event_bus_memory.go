@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"todo-api/internal/models"
+)
+
+// eventBusMemory delivers events within the process, for the storage
+// backends that cannot be shared by several processes.
+type eventBusMemory struct {
+	mu        sync.Mutex
+	listeners map[*func(models.Event)]struct{}
+}
+
+func NewEventBusMemory() EventBus {
+	return &eventBusMemory{listeners: make(map[*func(models.Event)]struct{})}
+}
+
+func (b *eventBusMemory) Publish(_ context.Context, event models.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for handle := range b.listeners {
+		(*handle)(event)
+	}
+	return nil
+}
+
+func (b *eventBusMemory) Listen(ctx context.Context, handle func(models.Event)) error {
+	b.mu.Lock()
+	b.listeners[&handle] = struct{}{}
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.listeners, &handle)
+	b.mu.Unlock()
+	return ctx.Err()
+}
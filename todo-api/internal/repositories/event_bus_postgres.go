@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+// taskEventsChannel is the Postgres notification channel of task events.
+const taskEventsChannel = "task_events"
+
+// eventBusPostgres delivers events to every process connected to the
+// database through LISTEN/NOTIFY. A notification carries only the event
+// ID, as payloads are limited to 8000 bytes; listeners read the event
+// from the outbox, which keeps published events for a while.
+type eventBusPostgres struct {
+	db *gorm.DB
+}
+
+func NewEventBusPostgres(db *gorm.DB) EventBus {
+	return &eventBusPostgres{db: db}
+}
+
+func (b *eventBusPostgres) Publish(ctx context.Context, event models.Event) error {
+	return b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", taskEventsChannel, event.ID).Error
+}
+
+// Listen holds a connection of the pool for as long as it listens.
+func (b *eventBusPostgres) Listen(ctx context.Context, handle func(models.Event)) error {
+	sqlDB, err := b.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a connection: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		pgConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("cannot listen on a %T connection", driverConn)
+		}
+		listener := pgConn.Conn()
+		if _, err := listener.Exec(ctx, "LISTEN "+taskEventsChannel); err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+		// The connection goes back to the pool unless it was lost.
+		defer func() {
+			_, _ = listener.Exec(context.Background(), "UNLISTEN "+taskEventsChannel)
+		}()
+
+		for {
+			notification, err := listener.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			event, err := b.load(ctx, notification.Payload)
+			if err != nil {
+				return fmt.Errorf("failed to load event %s: %w", notification.Payload, err)
+			}
+			handle(event)
+		}
+	})
+}
+
+func (b *eventBusPostgres) load(ctx context.Context, eventID string) (models.Event, error) {
+	var row models.OutboxEvent
+	if err := b.db.WithContext(ctx).Where("event_id = ?", eventID).Take(&row).Error; err != nil {
+		return models.Event{}, err
+	}
+	return row.Event()
+}
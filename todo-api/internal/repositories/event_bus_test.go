@@ -0,0 +1,61 @@
+package repositories_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+func TestEventBusMemory(t *testing.T) {
+	// Arrange
+	bus := repositories.NewEventBusMemory()
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan models.Event, 2)
+	stopped := make(chan error)
+	go func() {
+		stopped <- bus.Listen(ctx, func(event models.Event) { received <- event })
+	}()
+	require.Eventually(t, func() bool {
+		require.NoError(t, bus.Publish(context.Background(), models.Event{ID: "e1"}))
+		return len(received) > 0
+	}, time.Second, time.Millisecond)
+
+	// Act
+	require.NoError(t, bus.Publish(context.Background(), models.Event{ID: "e2"}))
+	cancel()
+	err := <-stopped
+	require.NoError(t, bus.Publish(context.Background(), models.Event{ID: "e3"}))
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+	var ids []string
+	for len(received) > 0 {
+		ids = append(ids, (<-received).ID)
+	}
+	assert.Equal(t, []string{"e1", "e2"}, ids, "nothing is delivered once the listener stopped")
+}
+
+func TestEventBusPostgres_Publish(t *testing.T) {
+	// Arrange
+	gormDB, mock := setupMockDB(t)
+	bus := repositories.NewEventBusPostgres(gormDB)
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs("task_events", "e1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := bus.Publish(context.Background(), models.Event{ID: "e1", Type: models.EventTaskCreated})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,77 @@
+//go:generate mockgen -source=./history_repository.go -destination=./mock/history_repository.go -package=mock
+package repositories
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/audit"
+	"todo-api/internal/models"
+)
+
+// HistoryRepository reads the task history that TaskRepository records
+// along with every change it makes to a task: creating, updating, moving
+// to the trash, restoring and purging. The history of a task outlives it,
+// ending with the purge.
+type HistoryRepository interface {
+	// List returns up to limit changes to a task after skipping offset of
+	// them, newest first.
+	List(ctx context.Context, taskID uint, limit int, offset int) ([]models.TaskHistory, error)
+	Count(ctx context.Context, taskID uint) (int64, error)
+	// ListSince returns the changes to a task made after since, oldest
+	// first.
+	ListSince(ctx context.Context, taskID uint, since time.Time) ([]models.TaskHistory, error)
+	// ListFromVersion returns the change that brought a task to version and
+	// the ones after it, oldest first.
+	ListFromVersion(ctx context.Context, taskID uint, version uint) ([]models.TaskHistory, error)
+	// Get returns a change to a task, or gorm.ErrRecordNotFound when the
+	// task has no such change.
+	Get(ctx context.Context, taskID uint, id uint) (*models.TaskHistory, error)
+}
+
+// newHistoryEntries returns the history rows of a change that turned
+// before into after, one for each task in after. A task missing from
+// before did not exist. The actor and request ID come from ctx.
+func newHistoryEntries(
+	ctx context.Context,
+	action models.HistoryAction,
+	before []models.Task,
+	after []models.Task,
+	now time.Time,
+) ([]models.TaskHistory, error) {
+	info := audit.FromContext(ctx)
+	old := make(map[uint]*models.Task, len(before))
+	for i := range before {
+		old[before[i].ID] = &before[i]
+	}
+
+	entries := make([]models.TaskHistory, len(after))
+	for i := range after {
+		changes, err := models.DiffTasks(old[after[i].ID], &after[i])
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = models.TaskHistory{
+			TaskID:    after[i].ID,
+			Version:   after[i].Version,
+			Action:    action,
+			Actor:     info.Actor,
+			RequestID: info.RequestID,
+			Changes:   changes,
+			CreatedAt: now,
+		}
+	}
+	return entries, nil
+}
+
+// markDeleted returns copies of tasks moved to the trash at now.
+func markDeleted(tasks []models.Task, now time.Time) []models.Task {
+	deleted := slices.Clone(tasks)
+	for i := range deleted {
+		deleted[i].DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	}
+	return deleted
+}
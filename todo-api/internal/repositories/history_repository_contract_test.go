@@ -0,0 +1,237 @@
+package repositories_test
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/audit"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// historyRepos are the repositories of one storage a history test needs.
+type historyRepos struct {
+	tasks   repositories.TaskRepository
+	history repositories.HistoryRepository
+	tags    repositories.TagRepository
+}
+
+// historyRepositoryFactories pairs every HistoryRepository implementation
+// with the TaskRepository recording into it.
+func historyRepositoryFactories() map[string]func(t *testing.T) historyRepos {
+	return map[string]func(t *testing.T) historyRepos{
+		"memory": func(t *testing.T) historyRepos {
+			store := repositories.NewMemoryStore(clock.Real())
+			return historyRepos{
+				tasks:   repositories.NewTaskRepositoryMemory(store),
+				history: repositories.NewHistoryRepositoryMemory(store),
+				tags:    repositories.NewTagRepositoryMemory(store),
+			}
+		},
+		"sqlite": func(t *testing.T) historyRepos {
+			db := setupSQLiteDB(t)
+			return historyRepos{
+				tasks:   repositories.NewTaskRepositoryImpl(db),
+				history: repositories.NewHistoryRepositoryImpl(db),
+				tags:    repositories.NewTagRepositoryImpl(db),
+			}
+		},
+	}
+}
+
+type change struct {
+	Action  models.HistoryAction
+	Version uint
+	Fields  []string
+}
+
+func changesOf(entries []models.TaskHistory) []change {
+	list := make([]change, len(entries))
+	for i, entry := range entries {
+		list[i] = change{Action: entry.Action, Version: entry.Version}
+		for field := range entry.Changes {
+			list[i].Fields = append(list[i].Fields, field)
+		}
+		sort.Strings(list[i].Fields)
+	}
+	return list
+}
+
+func TestHistoryRepository_Contract(t *testing.T) {
+	for name, newRepos := range historyRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("every change is recorded", func(t *testing.T) {
+				// Arrange
+				repos := newRepos(t)
+				tasks, history := repos.tasks, repos.history
+				ctx := audit.WithInfo(context.Background(), audit.Info{Actor: "alice", RequestID: "req-1"})
+				task := &models.Task{Title: "Write report", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				require.NoError(t, tasks.Update(ctx, task.ID, 0, map[string]interface{}{"title": "Write the report", "completed": true}))
+				require.NoError(t, tasks.SetRanks(ctx, map[uint]string{task.ID: "n"}))
+				require.NoError(t, tasks.Delete(ctx, task.ID, 0, false))
+				require.NoError(t, tasks.Restore(ctx, task.ID, 0))
+
+				// Act
+				entries, err := history.List(ctx, task.ID, 10, 0)
+				require.NoError(t, err)
+				total, err := history.Count(ctx, task.ID)
+				require.NoError(t, err)
+				page, err := history.List(ctx, task.ID, 2, 1)
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, []change{
+					{Action: models.HistoryRestored, Version: 4, Fields: []string{"deleted_at", "version"}},
+					{Action: models.HistoryDeleted, Version: 3, Fields: []string{"deleted_at"}},
+					{Action: models.HistoryUpdated, Version: 3, Fields: []string{"rank", "version"}},
+					{Action: models.HistoryUpdated, Version: 2, Fields: []string{"completed", "title", "version"}},
+					{Action: models.HistoryCreated, Version: 1, Fields: []string{ // every field that is not null
+						"completed", "date", "description", "exdates", "priority", "rank", "recurrence", "tags",
+						"time_zone", "title", "version",
+					}},
+				}, changesOf(entries))
+				assert.Equal(t, int64(5), total)
+				assert.Equal(t, changesOf(entries[1:3]), changesOf(page))
+
+				updated := entries[3]
+				assert.Equal(t, "alice", updated.Actor)
+				assert.Equal(t, "req-1", updated.RequestID)
+				assert.JSONEq(t, `"Write report"`, string(updated.Changes["title"].Before))
+				assert.JSONEq(t, `"Write the report"`, string(updated.Changes["title"].After))
+				assert.JSONEq(t, `null`, string(entries[4].Changes["title"].Before))
+			})
+
+			t.Run("side effects are recorded", func(t *testing.T) {
+				// Arrange
+				repos := newRepos(t)
+				tasks, history := repos.tasks, repos.history
+				ctx := context.Background()
+				root := &models.Task{Title: "Root", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, root))
+				child := &models.Task{Title: "Child", Date: day(0), ParentID: &root.ID}
+				require.NoError(t, tasks.Create(ctx, child))
+				require.NoError(t, tasks.Update(ctx, root.ID, 0, map[string]interface{}{
+					"completed":                      true,
+					repositories.CompleteSubtasksKey: true,
+				}))
+
+				// Act
+				require.NoError(t, tasks.Delete(ctx, root.ID, 0, false))
+
+				// Assert
+				entries, err := history.List(ctx, child.ID, 10, 0)
+				require.NoError(t, err)
+				assert.Equal(t, []change{
+					{Action: models.HistoryUpdated, Version: 3, Fields: []string{"parent_id", "version"}},
+					{Action: models.HistoryUpdated, Version: 2, Fields: []string{"completed", "version"}},
+				}, changesOf(entries[:2]))
+			})
+
+			t.Run("tags by name", func(t *testing.T) {
+				// Arrange
+				repos := newRepos(t)
+				tasks, history := repos.tasks, repos.history
+				ctx := context.Background()
+				tags := repos.tags
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+				task := &models.Task{Title: "Tagged", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+
+				// Act
+				require.NoError(t, tasks.Update(ctx, task.ID, 0, map[string]interface{}{
+					repositories.TagChangesKey: repositories.TagChanges{Attach: []string{"work"}},
+				}))
+
+				// Assert
+				entries, err := history.List(ctx, task.ID, 1, 0)
+				require.NoError(t, err)
+				require.Len(t, entries, 1)
+				var before, after []string
+				require.NoError(t, json.Unmarshal(entries[0].Changes["tags"].Before, &before))
+				require.NoError(t, json.Unmarshal(entries[0].Changes["tags"].After, &after))
+				assert.Empty(t, before)
+				assert.Equal(t, []string{"work"}, after)
+			})
+
+			t.Run("list since", func(t *testing.T) {
+				// Arrange
+				repos := newRepos(t)
+				tasks, history := repos.tasks, repos.history
+				ctx := context.Background()
+				task := &models.Task{Title: "Draft", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				time.Sleep(10 * time.Millisecond)
+				since := time.Now()
+				time.Sleep(10 * time.Millisecond)
+				require.NoError(t, tasks.Update(ctx, task.ID, 0, map[string]interface{}{"title": "Final"}))
+				require.NoError(t, tasks.Update(ctx, task.ID, 0, map[string]interface{}{"priority": models.PriorityHigh}))
+
+				// Act
+				entries, err := history.ListSince(ctx, task.ID, since)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, []change{
+					{Action: models.HistoryUpdated, Version: 2, Fields: []string{"title", "version"}},
+					{Action: models.HistoryUpdated, Version: 3, Fields: []string{"priority", "version"}},
+				}, changesOf(entries))
+			})
+
+			t.Run("from version", func(t *testing.T) {
+				// Arrange
+				repos := newRepos(t)
+				tasks, history := repos.tasks, repos.history
+				ctx := context.Background()
+				task := &models.Task{Title: "Draft", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				require.NoError(t, tasks.Update(ctx, task.ID, 0, map[string]interface{}{"title": "Final"}))
+				require.NoError(t, tasks.Update(ctx, task.ID, 0, map[string]interface{}{"priority": models.PriorityHigh}))
+
+				// Act
+				entries, err := history.ListFromVersion(ctx, task.ID, 2)
+
+				// Assert
+				require.NoError(t, err)
+				require.Len(t, entries, 2)
+				assert.Equal(t, []uint{2, 3}, []uint{entries[0].Version, entries[1].Version})
+
+				entry, err := history.Get(ctx, task.ID, entries[1].ID)
+				require.NoError(t, err)
+				assert.Equal(t, entries[1].ID, entry.ID)
+				_, err = history.Get(ctx, task.ID+1, entries[1].ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "the change belongs to another task")
+			})
+
+			t.Run("kept after a purge", func(t *testing.T) {
+				// Arrange
+				repos := newRepos(t)
+				tasks, history := repos.tasks, repos.history
+				ctx := context.Background()
+				task := &models.Task{Title: "Gone", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				require.NoError(t, tasks.Delete(ctx, task.ID, 0, false))
+
+				// Act
+				require.NoError(t, tasks.Purge(ctx, task.ID, 0, false))
+
+				// Assert
+				entries, err := history.List(ctx, task.ID, 10, 0)
+				require.NoError(t, err)
+				require.Len(t, entries, 3)
+				assert.Equal(t, []models.HistoryAction{models.HistoryPurged, models.HistoryDeleted, models.HistoryCreated},
+					[]models.HistoryAction{entries[0].Action, entries[1].Action, entries[2].Action})
+				assert.Empty(t, entries[0].Changes)
+				assert.Equal(t, entries[1].Version, entries[0].Version)
+			})
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+type historyRepository struct {
+	db *gorm.DB
+}
+
+func NewHistoryRepositoryImpl(db *gorm.DB) HistoryRepository {
+	return &historyRepository{db: db}
+}
+
+func (r *historyRepository) List(ctx context.Context, taskID uint, limit int, offset int) ([]models.TaskHistory, error) {
+	var entries []models.TaskHistory
+	err := r.db.WithContext(ctx).
+		Where("task_id = ?", taskID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *historyRepository) Count(ctx context.Context, taskID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TaskHistory{}).Where("task_id = ?", taskID).Count(&count).Error
+	return count, err
+}
+
+func (r *historyRepository) ListSince(ctx context.Context, taskID uint, since time.Time) ([]models.TaskHistory, error) {
+	var entries []models.TaskHistory
+	err := r.db.WithContext(ctx).
+		Where("task_id = ? AND created_at > ?", taskID, since).
+		Order("id").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *historyRepository) ListFromVersion(ctx context.Context, taskID uint, version uint) ([]models.TaskHistory, error) {
+	var entries []models.TaskHistory
+	err := r.db.WithContext(ctx).
+		Where("task_id = ? AND version >= ?", taskID, version).
+		Order("id").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *historyRepository) Get(ctx context.Context, taskID uint, id uint) (*models.TaskHistory, error) {
+	var entry models.TaskHistory
+	if err := r.db.WithContext(ctx).Where("task_id = ?", taskID).First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// recordHistory adds the changes that turned before into after to the
+// history. db must be the transaction that made them.
+func recordHistory(db *gorm.DB, action models.HistoryAction, before []models.Task, after []models.Task) error {
+	if len(after) == 0 {
+		return nil
+	}
+	entries, err := newHistoryEntries(db.Statement.Context, action, before, after, db.NowFunc())
+	if err != nil {
+		return err
+	}
+	return db.Create(&entries).Error
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+type historyRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewHistoryRepositoryMemory(store *MemoryStore) HistoryRepository {
+	return &historyRepositoryMemory{MemoryStore: store}
+}
+
+func (r *historyRepositoryMemory) List(_ context.Context, taskID uint, limit int, offset int) ([]models.TaskHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.historyOf(taskID)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID > entries[j].ID
+	})
+	if offset >= len(entries) {
+		return nil, nil
+	}
+	entries = entries[offset:]
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (r *historyRepositoryMemory) Count(_ context.Context, taskID uint) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.historyOf(taskID))), nil
+}
+
+func (r *historyRepositoryMemory) ListSince(_ context.Context, taskID uint, since time.Time) ([]models.TaskHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []models.TaskHistory
+	for _, entry := range r.historyOf(taskID) {
+		if entry.CreatedAt.After(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *historyRepositoryMemory) ListFromVersion(_ context.Context, taskID uint, version uint) ([]models.TaskHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []models.TaskHistory
+	for _, entry := range r.historyOf(taskID) {
+		if entry.Version >= version {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *historyRepositoryMemory) Get(_ context.Context, taskID uint, id uint) (*models.TaskHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.history[id]
+	if !ok || entry.TaskID != taskID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *entry
+	return &copied, nil
+}
+
+// historyOf returns copies of the history of a task, oldest first.
+func (s *MemoryStore) historyOf(taskID uint) []models.TaskHistory {
+	var entries []models.TaskHistory
+	for _, entry := range s.history {
+		if entry.TaskID == taskID {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// recordHistory adds the changes that turned before into after to the
+// history.
+func (s *MemoryStore) recordHistory(ctx context.Context, action models.HistoryAction, before []models.Task, after []models.Task) error {
+	entries, err := newHistoryEntries(ctx, action, before, after, s.clock.Now())
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		entries[i].ID = s.nextHistoryID
+		s.nextHistoryID++
+		s.history[entries[i].ID] = &entries[i]
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/pkg/clock"
+)
+
+// MemoryStore holds the data of the in-memory repositories. Repositories
+// built on the same store see each other's rows, like tables of one
+// database, and share one lock and clock.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	clock clock.Clock
+
+	tasks        map[uint]*models.Task
+	nextTaskID   uint
+	dependencies map[uint]map[uint]bool // task ID -> set of blocker IDs
+
+	tags      map[uint]*models.Tag
+	nextTagID uint
+	taskTags  map[uint]map[uint]bool // task ID -> set of tag IDs
+
+	projects      map[uint]*models.Project
+	nextProjectID uint
+
+	reminders      map[uint]*models.Reminder
+	nextReminderID uint
+
+	subscriptions      map[uint]*models.WebhookSubscription
+	nextSubscriptionID uint
+	deliveries         map[uint]*models.WebhookDelivery
+	nextDeliveryID     uint
+
+	outbox       map[uint]*models.OutboxEvent
+	nextOutboxID uint
+
+	history       map[uint]*models.TaskHistory
+	nextHistoryID uint
+
+	settings map[string]*models.UserSettings // by actor
+}
+
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{
+		clock: clk,
+
+		tasks:        make(map[uint]*models.Task),
+		nextTaskID:   1,
+		dependencies: make(map[uint]map[uint]bool),
+		tags:         make(map[uint]*models.Tag),
+		nextTagID:    1,
+		taskTags:     make(map[uint]map[uint]bool),
+
+		projects:      make(map[uint]*models.Project),
+		nextProjectID: 1,
+
+		reminders:      make(map[uint]*models.Reminder),
+		nextReminderID: 1,
+
+		subscriptions:      make(map[uint]*models.WebhookSubscription),
+		nextSubscriptionID: 1,
+		deliveries:         make(map[uint]*models.WebhookDelivery),
+		nextDeliveryID:     1,
+
+		outbox:       make(map[uint]*models.OutboxEvent),
+		nextOutboxID: 1,
+
+		history:       make(map[uint]*models.TaskHistory),
+		nextHistoryID: 1,
+
+		settings: make(map[string]*models.UserSettings),
+	}
+}
+
+// tagsOf returns copies of the tags attached to a task, ordered by name.
+func (s *MemoryStore) tagsOf(taskID uint) []models.Tag {
+	tags := []models.Tag{}
+	for tagID := range s.taskTags[taskID] {
+		tags = append(tags, *s.tags[tagID])
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
+
+// tagsByName resolves tag names, failing with ErrUnknownTag for any that
+// does not exist.
+func (s *MemoryStore) tagsByName(names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	var missing []string
+	for _, name := range names {
+		tag, ok := s.tagNamed(name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		tags = append(tags, *tag)
+	}
+	if len(missing) > 0 {
+		return nil, unknownTagsError(missing)
+	}
+	return tags, nil
+}
+
+func (s *MemoryStore) tagNamed(name string) (*models.Tag, bool) {
+	for _, tag := range s.tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return nil, false
+}
+
+// checkProject fails with gorm.ErrForeignKeyViolated, as a database would,
+// when a task refers to a project that does not exist.
+func (s *MemoryStore) checkProject(projectID *uint) error {
+	if projectID == nil {
+		return nil
+	}
+	if _, ok := s.projects[*projectID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	return nil
+}
+
+// inArchivedProject reports whether a task belongs to an archived project.
+func (s *MemoryStore) inArchivedProject(task *models.Task) bool {
+	if task.ProjectID == nil {
+		return false
+	}
+	project, ok := s.projects[*task.ProjectID]
+	return ok && project.Archived
+}
+
+// rescheduleReminders moves the relative reminders of a task to its new
+// due time.
+func (s *MemoryStore) rescheduleReminders(taskID uint, due *time.Time) {
+	for id, reminder := range s.reminders {
+		if reminder.TaskID == taskID && reminder.Relative() {
+			rescheduled := *reminder
+			rescheduled.Reschedule(due)
+			rescheduled.UpdatedAt = s.clock.Now()
+			s.reminders[id] = &rescheduled
+		}
+	}
+}
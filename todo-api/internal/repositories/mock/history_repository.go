@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./history_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./history_repository.go -destination=./mock/history_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	models "todo-api/internal/models"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRepository is a mock of HistoryRepository interface.
+type MockHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHistoryRepositoryMockRecorder is the mock recorder for MockHistoryRepository.
+type MockHistoryRepositoryMockRecorder struct {
+	mock *MockHistoryRepository
+}
+
+// NewMockHistoryRepository creates a new mock instance.
+func NewMockHistoryRepository(ctrl *gomock.Controller) *MockHistoryRepository {
+	mock := &MockHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRepository) EXPECT() *MockHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockHistoryRepository) Count(ctx context.Context, taskID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, taskID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockHistoryRepositoryMockRecorder) Count(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockHistoryRepository)(nil).Count), ctx, taskID)
+}
+
+// Get mocks base method.
+func (m *MockHistoryRepository) Get(ctx context.Context, taskID, id uint) (*models.TaskHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, taskID, id)
+	ret0, _ := ret[0].(*models.TaskHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockHistoryRepositoryMockRecorder) Get(ctx, taskID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockHistoryRepository)(nil).Get), ctx, taskID, id)
+}
+
+// List mocks base method.
+func (m *MockHistoryRepository) List(ctx context.Context, taskID uint, limit, offset int) ([]models.TaskHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, taskID, limit, offset)
+	ret0, _ := ret[0].([]models.TaskHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHistoryRepositoryMockRecorder) List(ctx, taskID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHistoryRepository)(nil).List), ctx, taskID, limit, offset)
+}
+
+// ListFromVersion mocks base method.
+func (m *MockHistoryRepository) ListFromVersion(ctx context.Context, taskID, version uint) ([]models.TaskHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFromVersion", ctx, taskID, version)
+	ret0, _ := ret[0].([]models.TaskHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFromVersion indicates an expected call of ListFromVersion.
+func (mr *MockHistoryRepositoryMockRecorder) ListFromVersion(ctx, taskID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFromVersion", reflect.TypeOf((*MockHistoryRepository)(nil).ListFromVersion), ctx, taskID, version)
+}
+
+// ListSince mocks base method.
+func (m *MockHistoryRepository) ListSince(ctx context.Context, taskID uint, since time.Time) ([]models.TaskHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSince", ctx, taskID, since)
+	ret0, _ := ret[0].([]models.TaskHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSince indicates an expected call of ListSince.
+func (mr *MockHistoryRepositoryMockRecorder) ListSince(ctx, taskID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSince", reflect.TypeOf((*MockHistoryRepository)(nil).ListSince), ctx, taskID, since)
+}
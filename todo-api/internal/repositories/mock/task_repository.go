@@ -12,6 +12,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 	dto "todo-api/internal/dto"
 	models "todo-api/internal/models"
 
@@ -42,6 +43,35 @@ func (m *MockTaskRepository) EXPECT() *MockTaskRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockTaskRepository) AddDependency(ctx context.Context, taskID, blockerID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", ctx, taskID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockTaskRepositoryMockRecorder) AddDependency(ctx, taskID, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockTaskRepository)(nil).AddDependency), ctx, taskID, blockerID)
+}
+
+// Count mocks base method.
+func (m *MockTaskRepository) Count(ctx context.Context, filter dto.TaskFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockTaskRepositoryMockRecorder) Count(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockTaskRepository)(nil).Count), ctx, filter)
+}
+
 // Create mocks base method.
 func (m *MockTaskRepository) Create(ctx context.Context, task *models.Task) error {
 	m.ctrl.T.Helper()
@@ -56,18 +86,60 @@ func (mr *MockTaskRepositoryMockRecorder) Create(ctx, task any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskRepository)(nil).Create), ctx, task)
 }
 
+// CreateMany mocks base method.
+func (m *MockTaskRepository) CreateMany(ctx context.Context, tasks []models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMany", ctx, tasks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMany indicates an expected call of CreateMany.
+func (mr *MockTaskRepositoryMockRecorder) CreateMany(ctx, tasks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMany", reflect.TypeOf((*MockTaskRepository)(nil).CreateMany), ctx, tasks)
+}
+
 // Delete mocks base method.
-func (m *MockTaskRepository) Delete(ctx context.Context, id uint) error {
+func (m *MockTaskRepository) Delete(ctx context.Context, id, version uint, cascade bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version, cascade)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTaskRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) Delete(ctx, id, version, cascade any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaskRepository)(nil).Delete), ctx, id, version, cascade)
+}
+
+// DeleteMany mocks base method.
+func (m *MockTaskRepository) DeleteMany(ctx context.Context, ids []uint, cascade bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMany", ctx, ids, cascade)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMany indicates an expected call of DeleteMany.
+func (mr *MockTaskRepositoryMockRecorder) DeleteMany(ctx, ids, cascade any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaskRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMany", reflect.TypeOf((*MockTaskRepository)(nil).DeleteMany), ctx, ids, cascade)
+}
+
+// ExcludeOccurrence mocks base method.
+func (m *MockTaskRepository) ExcludeOccurrence(ctx context.Context, id, version uint, updates map[string]any, occurrenceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExcludeOccurrence", ctx, id, version, updates, occurrenceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExcludeOccurrence indicates an expected call of ExcludeOccurrence.
+func (mr *MockTaskRepositoryMockRecorder) ExcludeOccurrence(ctx, id, version, updates, occurrenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExcludeOccurrence", reflect.TypeOf((*MockTaskRepository)(nil).ExcludeOccurrence), ctx, id, version, updates, occurrenceID)
 }
 
 // GetByID mocks base method.
@@ -85,6 +157,51 @@ func (mr *MockTaskRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTaskRepository)(nil).GetByID), ctx, id)
 }
 
+// GetByIDs mocks base method.
+func (m *MockTaskRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockTaskRepositoryMockRecorder) GetByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockTaskRepository)(nil).GetByIDs), ctx, ids)
+}
+
+// GetTrashed mocks base method.
+func (m *MockTaskRepository) GetTrashed(ctx context.Context, id uint) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrashed", ctx, id)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrashed indicates an expected call of GetTrashed.
+func (mr *MockTaskRepositoryMockRecorder) GetTrashed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrashed", reflect.TypeOf((*MockTaskRepository)(nil).GetTrashed), ctx, id)
+}
+
+// LastRank mocks base method.
+func (m *MockTaskRepository) LastRank(ctx context.Context, date time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastRank", ctx, date)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastRank indicates an expected call of LastRank.
+func (mr *MockTaskRepositoryMockRecorder) LastRank(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastRank", reflect.TypeOf((*MockTaskRepository)(nil).LastRank), ctx, date)
+}
+
 // List mocks base method.
 func (m *MockTaskRepository) List(ctx context.Context, filter dto.TaskFilter) ([]models.Task, error) {
 	m.ctrl.T.Helper()
@@ -100,16 +217,189 @@ func (mr *MockTaskRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTaskRepository)(nil).List), ctx, filter)
 }
 
+// ListBlockers mocks base method.
+func (m *MockTaskRepository) ListBlockers(ctx context.Context, taskID uint) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlockers", ctx, taskID)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlockers indicates an expected call of ListBlockers.
+func (mr *MockTaskRepositoryMockRecorder) ListBlockers(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlockers", reflect.TypeOf((*MockTaskRepository)(nil).ListBlockers), ctx, taskID)
+}
+
+// ListByDate mocks base method.
+func (m *MockTaskRepository) ListByDate(ctx context.Context, date time.Time) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByDate", ctx, date)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByDate indicates an expected call of ListByDate.
+func (mr *MockTaskRepositoryMockRecorder) ListByDate(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDate", reflect.TypeOf((*MockTaskRepository)(nil).ListByDate), ctx, date)
+}
+
+// ListChildren mocks base method.
+func (m *MockTaskRepository) ListChildren(ctx context.Context, parentIDs []uint) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChildren", ctx, parentIDs)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChildren indicates an expected call of ListChildren.
+func (mr *MockTaskRepositoryMockRecorder) ListChildren(ctx, parentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChildren", reflect.TypeOf((*MockTaskRepository)(nil).ListChildren), ctx, parentIDs)
+}
+
+// ListOverrides mocks base method.
+func (m *MockTaskRepository) ListOverrides(ctx context.Context, seriesIDs []uint) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverrides", ctx, seriesIDs)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverrides indicates an expected call of ListOverrides.
+func (mr *MockTaskRepositoryMockRecorder) ListOverrides(ctx, seriesIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverrides", reflect.TypeOf((*MockTaskRepository)(nil).ListOverrides), ctx, seriesIDs)
+}
+
+// Purge mocks base method.
+func (m *MockTaskRepository) Purge(ctx context.Context, id, version uint, cascade bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id, version, cascade)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTaskRepositoryMockRecorder) Purge(ctx, id, version, cascade any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTaskRepository)(nil).Purge), ctx, id, version, cascade)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockTaskRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockTaskRepositoryMockRecorder) PurgeDeleted(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockTaskRepository)(nil).PurgeDeleted), ctx, before)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockTaskRepository) RemoveDependency(ctx context.Context, taskID, blockerID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", ctx, taskID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockTaskRepositoryMockRecorder) RemoveDependency(ctx, taskID, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockTaskRepository)(nil).RemoveDependency), ctx, taskID, blockerID)
+}
+
+// Restore mocks base method.
+func (m *MockTaskRepository) Restore(ctx context.Context, id, version uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTaskRepositoryMockRecorder) Restore(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTaskRepository)(nil).Restore), ctx, id, version)
+}
+
+// SetRanks mocks base method.
+func (m *MockTaskRepository) SetRanks(ctx context.Context, ranks map[uint]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRanks", ctx, ranks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRanks indicates an expected call of SetRanks.
+func (mr *MockTaskRepositoryMockRecorder) SetRanks(ctx, ranks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRanks", reflect.TypeOf((*MockTaskRepository)(nil).SetRanks), ctx, ranks)
+}
+
+// SplitSeries mocks base method.
+func (m *MockTaskRepository) SplitSeries(ctx context.Context, id, version uint, updates map[string]any, next *models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitSeries", ctx, id, version, updates, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SplitSeries indicates an expected call of SplitSeries.
+func (mr *MockTaskRepositoryMockRecorder) SplitSeries(ctx, id, version, updates, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitSeries", reflect.TypeOf((*MockTaskRepository)(nil).SplitSeries), ctx, id, version, updates, next)
+}
+
+// StoreOccurrence mocks base method.
+func (m *MockTaskRepository) StoreOccurrence(ctx context.Context, occurrence *models.Task, updates map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreOccurrence", ctx, occurrence, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreOccurrence indicates an expected call of StoreOccurrence.
+func (mr *MockTaskRepositoryMockRecorder) StoreOccurrence(ctx, occurrence, updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreOccurrence", reflect.TypeOf((*MockTaskRepository)(nil).StoreOccurrence), ctx, occurrence, updates)
+}
+
 // Update mocks base method.
-func (m *MockTaskRepository) Update(ctx context.Context, id uint, updates map[string]any) error {
+func (m *MockTaskRepository) Update(ctx context.Context, id, version uint, updates map[string]any) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, updates)
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, updates)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockTaskRepositoryMockRecorder) Update(ctx, id, updates any) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) Update(ctx, id, version, updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskRepository)(nil).Update), ctx, id, version, updates)
+}
+
+// UpdateMany mocks base method.
+func (m *MockTaskRepository) UpdateMany(ctx context.Context, ids []uint, updates map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMany", ctx, ids, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMany indicates an expected call of UpdateMany.
+func (mr *MockTaskRepositoryMockRecorder) UpdateMany(ctx, ids, updates any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskRepository)(nil).Update), ctx, id, updates)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMany", reflect.TypeOf((*MockTaskRepository)(nil).UpdateMany), ctx, ids, updates)
 }
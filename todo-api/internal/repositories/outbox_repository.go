@@ -0,0 +1,67 @@
+//go:generate mockgen -source=./outbox_repository.go -destination=./mock/outbox_repository.go -package=mock
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"todo-api/internal/models"
+)
+
+// OutboxRepository reads the task events that TaskRepository records in
+// the outbox along with every change, and tracks their publication.
+type OutboxRepository interface {
+	// ListDue returns up to limit events due at now that are the oldest
+	// unpublished event of their task, oldest first. The later events of a
+	// task wait until it is published.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error)
+	// Claim takes a pending event due at now for one attempt the way
+	// ReminderRepository.Claim takes a reminder.
+	Claim(ctx context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error)
+	// Update sets columns of an event.
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	// GetByEventID returns the event with the given event ID, or
+	// gorm.ErrRecordNotFound once it has been deleted.
+	GetByEventID(ctx context.Context, eventID string) (*models.OutboxEvent, error)
+	// ListSince returns up to limit events published at or after since or
+	// not published yet, oldest first.
+	ListSince(ctx context.Context, since time.Time, limit int) ([]models.OutboxEvent, error)
+	// DeletePublished deletes the events published before before and
+	// returns how many there were.
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+	Stats(ctx context.Context) (models.OutboxStats, error)
+}
+
+// newOutboxEvent returns the outbox row of an event of type t about task,
+// due for publication right away.
+func newOutboxEvent(t models.EventType, task models.Task, archived bool, now time.Time) (models.OutboxEvent, error) {
+	event := models.Event{
+		ID:              newEventID(),
+		Type:            t,
+		OccurredAt:      now.UTC(),
+		Task:            task,
+		ProjectArchived: archived,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return models.OutboxEvent{}, err
+	}
+	return models.OutboxEvent{
+		EventID:       event.ID,
+		Type:          t,
+		TaskID:        task.ID,
+		Payload:       models.RawJSON(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// newEventID returns a random 128-bit ID in hex.
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,396 @@
+package repositories_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// outboxRepositoryFactories pairs every OutboxRepository implementation
+// with the TaskRepository recording into it.
+func outboxRepositoryFactories() map[string]func(t *testing.T) (repositories.TaskRepository, repositories.OutboxRepository) {
+	return map[string]func(t *testing.T) (repositories.TaskRepository, repositories.OutboxRepository){
+		"memory": func(t *testing.T) (repositories.TaskRepository, repositories.OutboxRepository) {
+			store := repositories.NewMemoryStore(clock.Real())
+			return repositories.NewTaskRepositoryMemory(store), repositories.NewOutboxRepositoryMemory(store)
+		},
+		"sqlite": func(t *testing.T) (repositories.TaskRepository, repositories.OutboxRepository) {
+			db := setupSQLiteDB(t)
+			return repositories.NewTaskRepositoryImpl(db), repositories.NewOutboxRepositoryImpl(db)
+		},
+	}
+}
+
+// drain publishes every event in the outbox the way the relay does, one
+// head per task at a time, and returns them in the order they were
+// recorded.
+func drain(t *testing.T, outbox repositories.OutboxRepository) []models.OutboxEvent {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().Add(time.Minute)
+
+	var events []models.OutboxEvent
+	for {
+		due, err := outbox.ListDue(ctx, now, 100)
+		require.NoError(t, err)
+		if len(due) == 0 {
+			break
+		}
+		for _, event := range due {
+			claimed, err := outbox.Claim(ctx, event.ID, now, now.Add(time.Minute))
+			require.NoError(t, err)
+			require.True(t, claimed)
+			require.NoError(t, outbox.Update(ctx, event.ID, map[string]interface{}{"published_at": now}))
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events
+}
+
+type recorded struct {
+	TaskID uint
+	Type   models.EventType
+}
+
+func recordedEvents(events []models.OutboxEvent) []recorded {
+	list := make([]recorded, len(events))
+	for i, event := range events {
+		list[i] = recorded{TaskID: event.TaskID, Type: event.Type}
+	}
+	return list
+}
+
+func TestOutboxRepository_Contract(t *testing.T) {
+	for name, newRepos := range outboxRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("task changes record events", func(t *testing.T) {
+				// Arrange
+				repo, outbox := newRepos(t)
+				ctx := context.Background()
+				parent := &models.Task{Title: "Move house", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, parent))
+				child := &models.Task{Title: "Pack books", Date: day(0), ParentID: &parent.ID}
+				require.NoError(t, repo.Create(ctx, child))
+
+				// Act
+				require.NoError(t, repo.Update(ctx, parent.ID, 0, map[string]interface{}{
+					"completed":                      true,
+					repositories.CompleteSubtasksKey: true,
+				}))
+				require.NoError(t, repo.Update(ctx, parent.ID, 0, map[string]interface{}{"completed": true}))
+				require.NoError(t, repo.SetRanks(ctx, map[uint]string{child.ID: "n"}))
+				require.NoError(t, repo.Delete(ctx, parent.ID, 0, false))
+				failed := repo.Update(ctx, child.ID, 1, map[string]interface{}{"title": "Pack all books"})
+				events := drain(t, outbox)
+
+				// Assert
+				assert.ErrorIs(t, failed, repositories.ErrVersionMismatch)
+				assert.Equal(t, []recorded{
+					{parent.ID, models.EventTaskCreated},
+					{child.ID, models.EventTaskCreated},
+					{parent.ID, models.EventTaskUpdated},
+					{child.ID, models.EventTaskUpdated},
+					{parent.ID, models.EventTaskCompleted},
+					{child.ID, models.EventTaskCompleted},
+					{parent.ID, models.EventTaskUpdated},
+					{child.ID, models.EventTaskUpdated},
+					{child.ID, models.EventTaskUpdated},
+					{parent.ID, models.EventTaskDeleted},
+				}, recordedEvents(events))
+
+				completed, err := events[4].Event()
+				require.NoError(t, err)
+				assert.Equal(t, events[4].EventID, completed.ID)
+				assert.Equal(t, models.EventTaskCompleted, completed.Type)
+				assert.True(t, completed.Task.Completed)
+				assert.Equal(t, int64(1), completed.Task.SubtasksCompleted)
+				moved, err := events[8].Event()
+				require.NoError(t, err)
+				assert.Nil(t, moved.Task.ParentID)
+				deleted, err := events[9].Event()
+				require.NoError(t, err)
+				assert.Equal(t, "Move house", deleted.Task.Title)
+				assert.Equal(t, int64(1), deleted.Task.SubtasksTotal)
+			})
+
+			t.Run("cascade and series", func(t *testing.T) {
+				// Arrange
+				repo, outbox := newRepos(t)
+				ctx := context.Background()
+				series := &models.Task{Title: "Standup", Date: day(0), Recurrence: "FREQ=DAILY"}
+				require.NoError(t, repo.Create(ctx, series))
+				occurrence := day(3)
+				override := &models.Task{Title: "Standup, late", Date: occurrence, SeriesID: &series.ID, OccurrenceDate: &occurrence}
+				require.NoError(t, repo.Create(ctx, override))
+				subtask := &models.Task{Title: "Notes", Date: day(0), ParentID: &series.ID}
+				require.NoError(t, repo.Create(ctx, subtask))
+				drain(t, outbox)
+				next := &models.Task{Title: "Standup", Date: day(2), Recurrence: "FREQ=DAILY"}
+
+				// Act
+				require.NoError(t, repo.SplitSeries(ctx, series.ID, 0, map[string]interface{}{"recurrence": "FREQ=DAILY;COUNT=2"}, next))
+				require.NoError(t, repo.Delete(ctx, next.ID, 0, true))
+				require.NoError(t, repo.Delete(ctx, series.ID, 0, true))
+				events := drain(t, outbox)
+
+				// Assert
+				assert.Equal(t, []recorded{
+					{series.ID, models.EventTaskUpdated},
+					{next.ID, models.EventTaskCreated},
+					{override.ID, models.EventTaskUpdated},
+					{next.ID, models.EventTaskDeleted},
+					{override.ID, models.EventTaskDeleted},
+					{series.ID, models.EventTaskDeleted},
+					{subtask.ID, models.EventTaskDeleted},
+				}, recordedEvents(events))
+			})
+
+			t.Run("trash", func(t *testing.T) {
+				// Arrange
+				repo, outbox := newRepos(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Call mom", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, task))
+				require.NoError(t, repo.Delete(ctx, task.ID, 0, false))
+				drain(t, outbox)
+
+				// Act
+				require.NoError(t, repo.Restore(ctx, task.ID, 0))
+				events := drain(t, outbox)
+
+				// Assert
+				assert.Equal(t, []recorded{
+					{task.ID, models.EventTaskRestored},
+				}, recordedEvents(events))
+				restored, err := events[0].Event()
+				require.NoError(t, err)
+				assert.False(t, restored.Task.DeletedAt.Valid)
+			})
+
+			t.Run("purge", func(t *testing.T) {
+				// Arrange
+				repo, outbox := newRepos(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+				task := &models.Task{Title: "Call mom", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, task))
+				require.NoError(t, repo.Delete(ctx, series.ID, 0, false))
+				require.NoError(t, repo.Delete(ctx, task.ID, 0, false))
+				drain(t, outbox)
+
+				// Act
+				require.NoError(t, repo.Purge(ctx, series.ID, 0, false))
+				purged := drain(t, outbox)
+				_, err := repo.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+				require.NoError(t, err)
+				expired := drain(t, outbox)
+
+				// Assert
+				assert.Equal(t, []recorded{
+					{series.ID, models.EventTaskPurged},
+					{second.ID, models.EventTaskPurged},
+					{third.ID, models.EventTaskPurged},
+				}, recordedEvents(purged), "stored occurrences go with their series")
+				assert.Equal(t, []recorded{{task.ID, models.EventTaskPurged}}, recordedEvents(expired))
+				event, err := purged[0].Event()
+				require.NoError(t, err)
+				assert.True(t, event.Task.DeletedAt.Valid, "the task as it was before")
+			})
+
+			t.Run("relay bookkeeping", func(t *testing.T) {
+				// Arrange
+				repo, outbox := newRepos(t)
+				ctx := context.Background()
+				first := &models.Task{Title: "Call mom", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, first))
+				second := &models.Task{Title: "Buy milk", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, second))
+				require.NoError(t, repo.Update(ctx, first.ID, 0, map[string]interface{}{"title": "Call dad"}))
+				now := time.Now().Add(time.Second)
+
+				// Act
+				due, err := outbox.ListDue(ctx, now, 10)
+				require.NoError(t, err)
+				claimed, err := outbox.Claim(ctx, due[0].ID, now, now.Add(time.Minute))
+				require.NoError(t, err)
+				again, err := outbox.Claim(ctx, due[0].ID, now, now.Add(time.Minute))
+				require.NoError(t, err)
+				require.NoError(t, outbox.Update(ctx, due[0].ID, map[string]interface{}{
+					"last_error":      "webhooks: connection refused",
+					"next_attempt_at": now.Add(time.Hour),
+				}))
+				held, err := outbox.ListDue(ctx, now, 10)
+				require.NoError(t, err)
+				require.NoError(t, outbox.Update(ctx, due[1].ID, map[string]interface{}{"published_at": now}))
+				stats, err := outbox.Stats(ctx)
+				require.NoError(t, err)
+				purged, err := outbox.DeletePublished(ctx, now.Add(time.Second))
+				require.NoError(t, err)
+				missingErr := outbox.Update(ctx, 42, map[string]interface{}{"published_at": now})
+
+				// Assert
+				require.Len(t, due, 2)
+				assert.Equal(t, []uint{first.ID, second.ID}, []uint{due[0].TaskID, due[1].TaskID})
+				assert.True(t, claimed)
+				assert.False(t, again)
+				require.Len(t, held, 1, "the update of the first task waits for its creation")
+				assert.Equal(t, second.ID, held[0].TaskID)
+				assert.Equal(t, int64(2), stats.Pending)
+				assert.Equal(t, int64(1), stats.Failing)
+				require.NotNil(t, stats.OldestPendingAt)
+				assert.WithinDuration(t, due[0].CreatedAt, *stats.OldestPendingAt, time.Second)
+				assert.Equal(t, due[1].ID, stats.LastPublishedID)
+				assert.Equal(t, int64(1), purged)
+				assert.ErrorIs(t, missingErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("events since a publication", func(t *testing.T) {
+				// Arrange
+				repo, outbox := newRepos(t)
+				ctx := context.Background()
+				for _, title := range []string{"Call mom", "Buy milk", "Water plants"} {
+					require.NoError(t, repo.Create(ctx, &models.Task{Title: title, Date: day(0)}))
+				}
+				due, err := outbox.ListDue(ctx, time.Now().Add(time.Second), 10)
+				require.NoError(t, err)
+				require.Len(t, due, 3)
+				now := time.Now()
+				require.NoError(t, outbox.Update(ctx, due[0].ID, map[string]interface{}{"published_at": now.Add(-time.Hour)}))
+				require.NoError(t, outbox.Update(ctx, due[1].ID, map[string]interface{}{"published_at": now}))
+
+				// Act
+				found, err := outbox.GetByEventID(ctx, due[1].EventID)
+				require.NoError(t, err)
+				_, missingErr := outbox.GetByEventID(ctx, "gone")
+				since, err := outbox.ListSince(ctx, now.Add(-time.Minute), 10)
+				require.NoError(t, err)
+				limited, err := outbox.ListSince(ctx, now.Add(-time.Minute), 1)
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, due[1].ID, found.ID)
+				require.NotNil(t, found.PublishedAt)
+				assert.WithinDuration(t, now, *found.PublishedAt, time.Second)
+				assert.ErrorIs(t, missingErr, gorm.ErrRecordNotFound)
+				require.Len(t, since, 2)
+				assert.Equal(t, []uint{due[1].ID, due[2].ID}, []uint{since[0].ID, since[1].ID})
+				require.Len(t, limited, 1)
+				assert.Equal(t, due[1].ID, limited[0].ID)
+			})
+		})
+	}
+}
+
+func TestOutboxRepository_ArchivedProjects(t *testing.T) {
+	factories := map[string]func(t *testing.T) (repositories.TaskRepository, repositories.ProjectRepository, repositories.OutboxRepository){
+		"memory": func(t *testing.T) (repositories.TaskRepository, repositories.ProjectRepository, repositories.OutboxRepository) {
+			store := repositories.NewMemoryStore(clock.Real())
+			return repositories.NewTaskRepositoryMemory(store), repositories.NewProjectRepositoryMemory(store),
+				repositories.NewOutboxRepositoryMemory(store)
+		},
+		"sqlite": func(t *testing.T) (repositories.TaskRepository, repositories.ProjectRepository, repositories.OutboxRepository) {
+			db := setupSQLiteDB(t)
+			return repositories.NewTaskRepositoryImpl(db), repositories.NewProjectRepositoryImpl(db),
+				repositories.NewOutboxRepositoryImpl(db)
+		},
+	}
+	for name, newRepos := range factories {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo, projects, outbox := newRepos(t)
+			ctx := context.Background()
+			old := &models.Project{Name: "Old flat"}
+			require.NoError(t, projects.Create(ctx, old))
+			require.NoError(t, projects.Update(ctx, old.ID, map[string]interface{}{"archived": true}))
+			current := &models.Project{Name: "New flat"}
+			require.NoError(t, projects.Create(ctx, current))
+
+			// Act
+			require.NoError(t, repo.Create(ctx, &models.Task{Title: "Return keys", Date: day(0), ProjectID: &old.ID}))
+			require.NoError(t, repo.Create(ctx, &models.Task{Title: "Paint walls", Date: day(0), ProjectID: &current.ID}))
+			require.NoError(t, repo.Create(ctx, &models.Task{Title: "Call mom", Date: day(0)}))
+			events := drain(t, outbox)
+
+			// Assert
+			require.Len(t, events, 3)
+			archived := make([]bool, len(events))
+			for i, row := range events {
+				event, err := row.Event()
+				require.NoError(t, err)
+				archived[i] = event.ProjectArchived
+			}
+			assert.Equal(t, []bool{true, false, false}, archived)
+		})
+	}
+}
+
+func TestOutboxRepository_DetachedTasks(t *testing.T) {
+	type repos struct {
+		tasks    repositories.TaskRepository
+		projects repositories.ProjectRepository
+		tags     repositories.TagRepository
+		outbox   repositories.OutboxRepository
+	}
+	factories := map[string]func(t *testing.T) repos{
+		"memory": func(t *testing.T) repos {
+			store := repositories.NewMemoryStore(clock.Real())
+			return repos{
+				repositories.NewTaskRepositoryMemory(store), repositories.NewProjectRepositoryMemory(store),
+				repositories.NewTagRepositoryMemory(store), repositories.NewOutboxRepositoryMemory(store),
+			}
+		},
+		"sqlite": func(t *testing.T) repos {
+			db := setupSQLiteDB(t)
+			return repos{
+				repositories.NewTaskRepositoryImpl(db), repositories.NewProjectRepositoryImpl(db),
+				repositories.NewTagRepositoryImpl(db), repositories.NewOutboxRepositoryImpl(db),
+			}
+		},
+	}
+	for name, newRepos := range factories {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			r := newRepos(t)
+			ctx := context.Background()
+			project := &models.Project{Name: "Move house"}
+			require.NoError(t, r.projects.Create(ctx, project))
+			tag := &models.Tag{Name: "boxes"}
+			require.NoError(t, r.tags.Create(ctx, tag))
+			pack := &models.Task{Title: "Pack books", Date: day(0), ProjectID: &project.ID, Tags: []models.Tag{{Name: "boxes"}}}
+			require.NoError(t, r.tasks.Create(ctx, pack))
+			trashed := &models.Task{Title: "Order boxes", Date: day(0), ProjectID: &project.ID}
+			require.NoError(t, r.tasks.Create(ctx, trashed))
+			require.NoError(t, r.tasks.Delete(ctx, trashed.ID, 0, false))
+			drain(t, r.outbox)
+
+			// Act
+			require.NoError(t, r.projects.Delete(ctx, project.ID))
+			require.NoError(t, r.tags.Delete(ctx, tag.ID))
+			events := drain(t, r.outbox)
+
+			// Assert
+			assert.Equal(t, []recorded{
+				{pack.ID, models.EventTaskUpdated},
+				{trashed.ID, models.EventTaskUpdated},
+				{pack.ID, models.EventTaskUpdated},
+			}, recordedEvents(events))
+			last, err := events[2].Event()
+			require.NoError(t, err)
+			assert.Nil(t, last.Task.ProjectID)
+			assert.Empty(t, last.Task.Tags)
+			assert.Equal(t, pack.Version+2, last.Task.Version)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepositoryImpl(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	db := r.db.WithContext(ctx)
+	heads := db.Session(&gorm.Session{NewDB: true}).
+		Model(&models.OutboxEvent{}).
+		Select("MIN(id)").
+		Where("published_at IS NULL").
+		Group("task_id")
+	err := db.Where("id IN (?) AND next_attempt_at <= ?", heads, now.UTC()).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ? AND published_at IS NULL AND next_attempt_at <= ?", id, now.UTC()).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": leaseUntil.UTC(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *outboxRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *outboxRepository) GetByEventID(ctx context.Context, eventID string) (*models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Take(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *outboxRepository) ListSince(ctx context.Context, since time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Where("published_at IS NULL OR published_at >= ?", since.UTC()).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("published_at < ?", before.UTC()).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *outboxRepository) Stats(ctx context.Context) (models.OutboxStats, error) {
+	var stats models.OutboxStats
+	db := r.db.WithContext(ctx)
+
+	var pending struct {
+		Pending int64
+		Failing int64
+	}
+	err := db.Model(&models.OutboxEvent{}).
+		Select("COUNT(*) AS pending, COUNT(CASE WHEN last_error <> '' THEN 1 END) AS failing").
+		Where("published_at IS NULL").
+		Scan(&pending).Error
+	if err != nil {
+		return stats, err
+	}
+	stats.Pending, stats.Failing = pending.Pending, pending.Failing
+
+	var oldest models.OutboxEvent
+	err = db.Where("published_at IS NULL").Order("id").Limit(1).Find(&oldest).Error
+	if err != nil {
+		return stats, err
+	}
+	if oldest.ID != 0 {
+		stats.OldestPendingAt = &oldest.CreatedAt
+	}
+
+	var last models.OutboxEvent
+	err = db.Where("published_at IS NOT NULL").Order("published_at DESC, id DESC").Limit(1).Find(&last).Error
+	if err != nil {
+		return stats, err
+	}
+	if last.ID != 0 {
+		stats.LastPublishedID, stats.LastPublishedAt = last.ID, last.PublishedAt
+	}
+	return stats, nil
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+// outboxRepositoryMemory reads the outbox of a MemoryStore, which the
+// in-memory task repository writes under the same lock as the tasks.
+type outboxRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewOutboxRepositoryMemory(store *MemoryStore) OutboxRepository {
+	return &outboxRepositoryMemory{MemoryStore: store}
+}
+
+func (r *outboxRepositoryMemory) ListDue(_ context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	heads := make(map[uint]*models.OutboxEvent)
+	for _, event := range r.outbox {
+		head, ok := heads[event.TaskID]
+		if event.PublishedAt == nil && (!ok || event.ID < head.ID) {
+			heads[event.TaskID] = event
+		}
+	}
+	var events []models.OutboxEvent
+	for _, head := range heads {
+		if !head.NextAttemptAt.After(now) {
+			events = append(events, *head)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	if limit > 0 && limit < len(events) {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *outboxRepositoryMemory) Claim(_ context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.outbox[id]
+	if !ok || event.PublishedAt != nil || event.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	claimed := *event
+	claimed.Attempts++
+	claimed.NextAttemptAt = leaseUntil
+	r.outbox[id] = &claimed
+	return true, nil
+}
+
+func (r *outboxRepositoryMemory) Update(_ context.Context, id uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.outbox[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	updated := *event
+	for column, value := range updates {
+		var ok bool
+		switch column {
+		case "attempts":
+			updated.Attempts, ok = value.(int)
+		case "last_error":
+			updated.LastError, ok = value.(string)
+		case "next_attempt_at":
+			updated.NextAttemptAt, ok = value.(time.Time)
+		case "published_at":
+			updated.PublishedAt, ok = optionalTime(value)
+		default:
+			return fmt.Errorf("unknown outbox event column %q", column)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v for outbox event column %q", value, column)
+		}
+	}
+	r.outbox[id] = &updated
+	return nil
+}
+
+func (r *outboxRepositoryMemory) GetByEventID(_ context.Context, eventID string) (*models.OutboxEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, event := range r.outbox {
+		if event.EventID == eventID {
+			found := *event
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *outboxRepositoryMemory) ListSince(_ context.Context, since time.Time, limit int) ([]models.OutboxEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.OutboxEvent
+	for _, event := range r.outbox {
+		if event.PublishedAt == nil || !event.PublishedAt.Before(since) {
+			events = append(events, *event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	if limit > 0 && limit < len(events) {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *outboxRepositoryMemory) DeletePublished(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, event := range r.outbox {
+		if event.PublishedAt != nil && event.PublishedAt.Before(before) {
+			delete(r.outbox, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *outboxRepositoryMemory) Stats(_ context.Context) (models.OutboxStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var stats models.OutboxStats
+	var oldest, last *models.OutboxEvent
+	for _, event := range r.outbox {
+		if event.PublishedAt == nil {
+			stats.Pending++
+			if event.LastError != "" {
+				stats.Failing++
+			}
+			if oldest == nil || event.ID < oldest.ID {
+				oldest = event
+			}
+			continue
+		}
+		if last == nil || event.PublishedAt.After(*last.PublishedAt) ||
+			event.PublishedAt.Equal(*last.PublishedAt) && event.ID > last.ID {
+			last = event
+		}
+	}
+	if oldest != nil {
+		createdAt := oldest.CreatedAt
+		stats.OldestPendingAt = &createdAt
+	}
+	if last != nil {
+		publishedAt := *last.PublishedAt
+		stats.LastPublishedID, stats.LastPublishedAt = last.ID, &publishedAt
+	}
+	return stats, nil
+}
+
+// record adds an event of type t about each of tasks to the outbox.
+func (s *MemoryStore) record(t models.EventType, tasks ...models.Task) error {
+	now := s.clock.Now()
+	for _, task := range tasks {
+		var archived bool
+		if task.ProjectID != nil {
+			project, ok := s.projects[*task.ProjectID]
+			archived = ok && project.Archived
+		}
+		event, err := newOutboxEvent(t, task, archived, now)
+		if err != nil {
+			return err
+		}
+		event.ID = s.nextOutboxID
+		s.nextOutboxID++
+		s.outbox[event.ID] = &event
+	}
+	return nil
+}
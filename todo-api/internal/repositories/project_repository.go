@@ -0,0 +1,23 @@
+//go:generate mockgen -source=./project_repository.go -destination=./mock/project_repository.go -package=mock
+package repositories
+
+import (
+	"context"
+
+	"todo-api/internal/models"
+)
+
+// ProjectRepository stores projects. Projects it returns carry counts of
+// their open and completed tasks. Like TaskRepository it returns
+// gorm.ErrRecordNotFound for missing projects. Deleting a project takes its
+// tasks out of it rather than deleting them, updating each like
+// TaskRepository.Update would.
+type ProjectRepository interface {
+	Create(ctx context.Context, project *models.Project) error
+	GetByID(ctx context.Context, id uint) (*models.Project, error)
+	// List returns projects ordered by name, archived ones only with
+	// includeArchived.
+	List(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,227 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// projectRepositoryFactories pairs every ProjectRepository implementation
+// with the TaskRepository sharing its storage.
+func projectRepositoryFactories() map[string]func(t *testing.T) (repositories.TaskRepository, repositories.ProjectRepository) {
+	return map[string]func(t *testing.T) (repositories.TaskRepository, repositories.ProjectRepository){
+		"memory": func(t *testing.T) (repositories.TaskRepository, repositories.ProjectRepository) {
+			store := repositories.NewMemoryStore(clock.Real())
+			return repositories.NewTaskRepositoryMemory(store), repositories.NewProjectRepositoryMemory(store)
+		},
+		"sqlite": func(t *testing.T) (repositories.TaskRepository, repositories.ProjectRepository) {
+			db := setupSQLiteDB(t)
+			return repositories.NewTaskRepositoryImpl(db), repositories.NewProjectRepositoryImpl(db)
+		},
+	}
+}
+
+func TestProjectRepository_Contract(t *testing.T) {
+	for name, newRepos := range projectRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("crud", func(t *testing.T) {
+				// Arrange
+				_, projects := newRepos(t)
+				ctx := context.Background()
+				home := &models.Project{Name: "Home", Color: "#00ff00"}
+				require.NoError(t, projects.Create(ctx, home))
+				require.NoError(t, projects.Create(ctx, &models.Project{Name: "Errands"}))
+
+				// Act
+				err := projects.Update(ctx, home.ID, map[string]interface{}{"description": "Chores"})
+				require.NoError(t, err)
+				found, err := projects.GetByID(ctx, home.ID)
+				require.NoError(t, err)
+				all, err := projects.List(ctx, false)
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, "Chores", found.Description)
+				assert.Equal(t, "#00ff00", found.Color)
+				require.Len(t, all, 2)
+				assert.Equal(t, "Errands", all[0].Name)
+				assert.Equal(t, "Home", all[1].Name)
+			})
+
+			t.Run("missing", func(t *testing.T) {
+				// Arrange
+				_, projects := newRepos(t)
+				ctx := context.Background()
+
+				// Act
+				_, getErr := projects.GetByID(ctx, 42)
+				updateErr := projects.Update(ctx, 42, map[string]interface{}{"name": "x"})
+				deleteErr := projects.Delete(ctx, 42)
+
+				// Assert
+				assert.ErrorIs(t, getErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, updateErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, deleteErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("task counts", func(t *testing.T) {
+				// Arrange
+				tasks, projects := newRepos(t)
+				ctx := context.Background()
+				project := &models.Project{Name: "Work"}
+				require.NoError(t, projects.Create(ctx, project))
+				for i, completed := range []bool{false, false, true} {
+					require.NoError(t, tasks.Create(ctx, &models.Task{Title: "Task", Date: day(i), Completed: completed, ProjectID: &project.ID}))
+				}
+				deleted := &models.Task{Title: "Deleted", Date: day(0), ProjectID: &project.ID}
+				require.NoError(t, tasks.Create(ctx, deleted))
+				require.NoError(t, tasks.Delete(ctx, deleted.ID, 0, false))
+				require.NoError(t, tasks.Create(ctx, &models.Task{Title: "Elsewhere", Date: day(0)}))
+
+				// Act
+				found, err := projects.GetByID(ctx, project.ID)
+				require.NoError(t, err)
+				listed, err := projects.List(ctx, false)
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, int64(2), found.OpenTasks)
+				assert.Equal(t, int64(1), found.CompletedTasks)
+				require.Len(t, listed, 1)
+				assert.Equal(t, int64(2), listed[0].OpenTasks)
+				assert.Equal(t, int64(1), listed[0].CompletedTasks)
+			})
+
+			t.Run("filter by project", func(t *testing.T) {
+				// Arrange
+				tasks, projects := newRepos(t)
+				ctx := context.Background()
+				work := &models.Project{Name: "Work"}
+				require.NoError(t, projects.Create(ctx, work))
+				inWork := &models.Task{Title: "Report", Date: day(0), ProjectID: &work.ID}
+				loose := &models.Task{Title: "Loose", Date: day(1)}
+				require.NoError(t, tasks.Create(ctx, inWork))
+				require.NoError(t, tasks.Create(ctx, loose))
+				none := uint(0)
+
+				// Act
+				ofWork, err := tasks.List(ctx, dto.TaskFilter{ProjectID: &work.ID, Limit: 10})
+				require.NoError(t, err)
+				outside, err := tasks.List(ctx, dto.TaskFilter{ProjectID: &none, Limit: 10})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, []uint{inWork.ID}, taskIDs(ofWork))
+				assert.Equal(t, []uint{loose.ID}, taskIDs(outside))
+			})
+
+			t.Run("archived projects hide their tasks", func(t *testing.T) {
+				// Arrange
+				tasks, projects := newRepos(t)
+				ctx := context.Background()
+				old := &models.Project{Name: "Old"}
+				require.NoError(t, projects.Create(ctx, old))
+				archivedTask := &models.Task{Title: "Archived", Date: day(0), ProjectID: &old.ID}
+				loose := &models.Task{Title: "Loose", Date: day(1)}
+				require.NoError(t, tasks.Create(ctx, archivedTask))
+				require.NoError(t, tasks.Create(ctx, loose))
+				require.NoError(t, projects.Update(ctx, old.ID, map[string]interface{}{"archived": true}))
+
+				// Act
+				byDefault, err := tasks.List(ctx, dto.TaskFilter{Limit: 10})
+				require.NoError(t, err)
+				total, err := tasks.Count(ctx, dto.TaskFilter{})
+				require.NoError(t, err)
+				included, err := tasks.List(ctx, dto.TaskFilter{IncludeArchived: true, Limit: 10})
+				require.NoError(t, err)
+				ofProject, err := tasks.List(ctx, dto.TaskFilter{ProjectID: &old.ID, Limit: 10})
+				require.NoError(t, err)
+				active, err := projects.List(ctx, false)
+				require.NoError(t, err)
+				all, err := projects.List(ctx, true)
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, []uint{loose.ID}, taskIDs(byDefault))
+				assert.Equal(t, int64(1), total)
+				assert.Equal(t, []uint{archivedTask.ID, loose.ID}, taskIDs(included))
+				assert.Equal(t, []uint{archivedTask.ID}, taskIDs(ofProject))
+				assert.Empty(t, active)
+				require.Len(t, all, 1)
+				assert.True(t, all[0].Archived)
+			})
+
+			t.Run("move between projects", func(t *testing.T) {
+				// Arrange
+				tasks, projects := newRepos(t)
+				ctx := context.Background()
+				work := &models.Project{Name: "Work"}
+				home := &models.Project{Name: "Home"}
+				require.NoError(t, projects.Create(ctx, work))
+				require.NoError(t, projects.Create(ctx, home))
+				task := &models.Task{Title: "Task", Date: day(0), ProjectID: &work.ID}
+				require.NoError(t, tasks.Create(ctx, task))
+
+				// Act
+				moveErr := tasks.Update(ctx, task.ID, 0, map[string]interface{}{"project_id": home.ID})
+				moved, err := tasks.GetByID(ctx, task.ID)
+				require.NoError(t, err)
+				removeErr := tasks.Update(ctx, task.ID, 0, map[string]interface{}{"project_id": nil})
+				removed, err := tasks.GetByID(ctx, task.ID)
+				require.NoError(t, err)
+
+				// Assert
+				require.NoError(t, moveErr)
+				require.NotNil(t, moved.ProjectID)
+				assert.Equal(t, home.ID, *moved.ProjectID)
+				require.NoError(t, removeErr)
+				assert.Nil(t, removed.ProjectID)
+			})
+
+			t.Run("unknown project", func(t *testing.T) {
+				// Arrange
+				tasks, _ := newRepos(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Task", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				missing := uint(42)
+
+				// Act
+				createErr := tasks.Create(ctx, &models.Task{Title: "Orphan", Date: day(0), ProjectID: &missing})
+				updateErr := tasks.Update(ctx, task.ID, 0, map[string]interface{}{"project_id": missing})
+
+				// Assert
+				assert.ErrorIs(t, createErr, gorm.ErrForeignKeyViolated)
+				assert.ErrorIs(t, updateErr, gorm.ErrForeignKeyViolated)
+			})
+
+			t.Run("delete keeps tasks", func(t *testing.T) {
+				// Arrange
+				tasks, projects := newRepos(t)
+				ctx := context.Background()
+				project := &models.Project{Name: "Gone"}
+				require.NoError(t, projects.Create(ctx, project))
+				task := &models.Task{Title: "Task", Date: day(0), ProjectID: &project.ID}
+				require.NoError(t, tasks.Create(ctx, task))
+
+				// Act
+				err := projects.Delete(ctx, project.ID)
+				require.NoError(t, err)
+				found, err := tasks.GetByID(ctx, task.ID)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Nil(t, found.ProjectID)
+				assert.Equal(t, task.Version+1, found.Version)
+			})
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+// projectColumns selects a project with the counts of its live tasks.
+const projectColumns = "projects.*, " +
+	"(SELECT COUNT(*) FROM tasks WHERE tasks.project_id = projects.id AND tasks.deleted_at IS NULL AND tasks.completed = ?) AS open_tasks, " +
+	"(SELECT COUNT(*) FROM tasks WHERE tasks.project_id = projects.id AND tasks.deleted_at IS NULL AND tasks.completed = ?) AS completed_tasks"
+
+type projectRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectRepositoryImpl(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+func (r *projectRepository) Create(ctx context.Context, project *models.Project) error {
+	return r.db.WithContext(ctx).Create(project).Error
+}
+
+func (r *projectRepository) GetByID(ctx context.Context, id uint) (*models.Project, error) {
+	var project models.Project
+	if err := r.withCounts(ctx).First(&project, id).Error; err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *projectRepository) List(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	var projects []models.Project
+	query := r.withCounts(ctx)
+	if !includeArchived {
+		query = query.Where("archived = ?", false)
+	}
+	err := query.Order("name, id").Find(&projects).Error
+	return projects, err
+}
+
+func (r *projectRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&models.Project{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *projectRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var taskIDs []uint
+		err := tx.Unscoped().Model(&models.Task{}).Where("project_id = ?", id).Order("id").Pluck("id", &taskIDs).Error
+		if err != nil {
+			return err
+		}
+		err = detachTasks(tx, taskIDs, func(tx *gorm.DB) error {
+			return tx.Unscoped().Model(&models.Task{}).Where("id IN ?", taskIDs).Update("project_id", nil).Error
+		})
+		if err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Project{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *projectRepository) withCounts(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&models.Project{}).Select(projectColumns, false, true)
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+// projectRepositoryMemory keeps projects in a MemoryStore, next to their
+// tasks.
+type projectRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewProjectRepositoryMemory(store *MemoryStore) ProjectRepository {
+	return &projectRepositoryMemory{MemoryStore: store}
+}
+
+func (r *projectRepositoryMemory) Create(_ context.Context, project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	project.ID = r.nextProjectID
+	project.CreatedAt = now
+	project.UpdatedAt = now
+	r.nextProjectID++
+
+	stored := *project
+	r.projects[project.ID] = &stored
+	return nil
+}
+
+func (r *projectRepositoryMemory) GetByID(_ context.Context, id uint) (*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, ok := r.projects[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := r.withCounts(project)
+	return &found, nil
+}
+
+func (r *projectRepositoryMemory) List(_ context.Context, includeArchived bool) ([]models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make([]models.Project, 0, len(r.projects))
+	for _, project := range r.projects {
+		if project.Archived && !includeArchived {
+			continue
+		}
+		projects = append(projects, r.withCounts(project))
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+		return projects[i].ID < projects[j].ID
+	})
+	return projects, nil
+}
+
+func (r *projectRepositoryMemory) Update(_ context.Context, id uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	project, ok := r.projects[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	updated := *project
+	for column, value := range updates {
+		var ok bool
+		switch column {
+		case "name":
+			updated.Name, ok = value.(string)
+		case "description":
+			updated.Description, ok = value.(string)
+		case "color":
+			updated.Color, ok = value.(string)
+		case "archived":
+			updated.Archived, ok = value.(bool)
+		default:
+			return fmt.Errorf("unknown project column %q", column)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v for project column %q", value, column)
+		}
+	}
+	updated.UpdatedAt = r.clock.Now()
+	r.projects[id] = &updated
+	return nil
+}
+
+func (r *projectRepositoryMemory) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	var taskIDs []uint
+	for taskID, task := range r.tasks {
+		if task.ProjectID != nil && *task.ProjectID == id {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	err := r.detachTasks(ctx, taskIDs, func(task *models.Task) {
+		task.ProjectID = nil
+	})
+	if err != nil {
+		return err
+	}
+	delete(r.projects, id)
+	return nil
+}
+
+// withCounts returns a copy of project with its live tasks counted.
+func (r *projectRepositoryMemory) withCounts(project *models.Project) models.Project {
+	counted := *project
+	for _, task := range r.tasks {
+		if task.DeletedAt.Valid || task.ProjectID == nil || *task.ProjectID != project.ID {
+			continue
+		}
+		if task.Completed {
+			counted.CompletedTasks++
+		} else {
+			counted.OpenTasks++
+		}
+	}
+	return counted
+}
@@ -0,0 +1,247 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+// createSeries creates a weekly task with stored occurrences on its second
+// and third weeks.
+func createSeries(t *testing.T, repo repositories.TaskRepository) (series, second, third *models.Task) {
+	t.Helper()
+	ctx := context.Background()
+
+	series = &models.Task{
+		Title:      "Standup",
+		Date:       day(0),
+		Recurrence: "FREQ=WEEKLY",
+		ExDates:    models.DateList{day(21)},
+	}
+	require.NoError(t, repo.Create(ctx, series))
+	for _, offset := range []int{7, 14} {
+		occurrence := day(offset)
+		stored := &models.Task{Title: "Standup", Date: occurrence, SeriesID: &series.ID, OccurrenceDate: &occurrence}
+		require.NoError(t, repo.Create(ctx, stored))
+		if offset == 7 {
+			second = stored
+		} else {
+			third = stored
+		}
+	}
+	return series, second, third
+}
+
+func TestTaskRepository_Recurrence(t *testing.T) {
+	for name, newRepo := range repositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("recurring filter and fields", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+				recurring, oneOff := true, false
+
+				// Act
+				found, err := repo.GetByID(ctx, series.ID)
+				require.NoError(t, err)
+				onlySeries, err := repo.List(ctx, dto.TaskFilter{Recurring: &recurring, Limit: 10})
+				require.NoError(t, err)
+				others, err := repo.List(ctx, dto.TaskFilter{Recurring: &oneOff, Limit: 10})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, "FREQ=WEEKLY", found.Recurrence)
+				assert.Equal(t, models.DateList{day(21)}, found.ExDates)
+				assert.Equal(t, []uint{series.ID}, taskIDs(onlySeries))
+				assert.Equal(t, []uint{second.ID, third.ID}, taskIDs(others))
+				require.NotNil(t, others[0].OccurrenceDate)
+				assert.True(t, day(7).Equal(*others[0].OccurrenceDate))
+			})
+
+			t.Run("overrides include deleted", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+				require.NoError(t, repo.Delete(ctx, second.ID, 0, false))
+
+				// Act
+				overrides, err := repo.ListOverrides(ctx, []uint{series.ID})
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, []uint{second.ID, third.ID}, taskIDs(overrides))
+				assert.True(t, overrides[0].DeletedAt.Valid)
+				assert.False(t, overrides[1].DeletedAt.Valid)
+			})
+
+			t.Run("split series", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+				next := &models.Task{Title: "Standup", Date: day(14), Recurrence: "FREQ=WEEKLY", ExDates: models.DateList{day(21)}}
+
+				// Act
+				err := repo.SplitSeries(ctx, series.ID, series.Version, map[string]interface{}{
+					"recurrence": "FREQ=WEEKLY;UNTIL=20260123T000000Z",
+					"ex_dates":   models.DateList(nil),
+				}, next)
+				require.NoError(t, err)
+				found, err := repo.GetByID(ctx, series.ID)
+				require.NoError(t, err)
+				kept, err := repo.ListOverrides(ctx, []uint{series.ID})
+				require.NoError(t, err)
+				moved, err := repo.ListOverrides(ctx, []uint{next.ID})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, "FREQ=WEEKLY;UNTIL=20260123T000000Z", found.Recurrence)
+				assert.Empty(t, found.ExDates)
+				assert.Equal(t, uint(2), found.Version)
+				assert.NotZero(t, next.ID)
+				assert.Equal(t, []uint{second.ID}, taskIDs(kept))
+				assert.Equal(t, []uint{third.ID}, taskIDs(moved))
+			})
+
+			t.Run("stale split changes nothing", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, _, _ := createSeries(t, repo)
+				next := &models.Task{Title: "Standup", Date: day(14), Recurrence: "FREQ=WEEKLY"}
+
+				// Act
+				err := repo.SplitSeries(ctx, series.ID, 9, map[string]interface{}{"recurrence": "FREQ=DAILY"}, next)
+				all, listErr := repo.List(ctx, dto.TaskFilter{Limit: 10})
+
+				// Assert
+				assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+				require.NoError(t, listErr)
+				assert.Len(t, all, 3)
+			})
+
+			t.Run("exclude occurrence", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+
+				// Act
+				err := repo.ExcludeOccurrence(ctx, series.ID, series.Version, map[string]interface{}{
+					"ex_dates": models.DateList{day(7), day(21)},
+				}, second.ID)
+				require.NoError(t, err)
+				found, err := repo.GetByID(ctx, series.ID)
+				require.NoError(t, err)
+				_, getErr := repo.GetByID(ctx, second.ID)
+
+				// Assert
+				assert.Equal(t, models.DateList{day(7), day(21)}, found.ExDates)
+				assert.ErrorIs(t, getErr, gorm.ErrRecordNotFound)
+				_, err = repo.GetByID(ctx, third.ID)
+				assert.NoError(t, err)
+			})
+
+			t.Run("stale exclude changes nothing", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, _ := createSeries(t, repo)
+
+				// Act
+				err := repo.ExcludeOccurrence(ctx, series.ID, 9, map[string]interface{}{
+					"ex_dates": models.DateList{day(7), day(21)},
+				}, second.ID)
+				found, getErr := repo.GetByID(ctx, second.ID)
+
+				// Assert
+				assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+				require.NoError(t, getErr)
+				assert.False(t, found.DeletedAt.Valid)
+			})
+
+			t.Run("store occurrence", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+				date := day(28)
+				occurrence := &models.Task{Title: "Standup", Date: date, SeriesID: &series.ID, OccurrenceDate: &date}
+
+				// Act
+				err := repo.StoreOccurrence(ctx, occurrence, map[string]interface{}{"title": "Retro"})
+				require.NoError(t, err)
+				found, err := repo.GetByID(ctx, occurrence.ID)
+				require.NoError(t, err)
+				overrides, err := repo.ListOverrides(ctx, []uint{series.ID})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, "Retro", found.Title)
+				assert.Equal(t, uint(2), found.Version)
+				assert.Equal(t, []uint{second.ID, third.ID, occurrence.ID}, taskIDs(overrides))
+			})
+
+			t.Run("rejected store occurrence stores nothing", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+				date := day(28)
+				occurrence := &models.Task{Title: "Standup", Date: date, SeriesID: &series.ID, OccurrenceDate: &date}
+
+				// Act
+				err := repo.StoreOccurrence(ctx, occurrence, map[string]interface{}{
+					repositories.TagChangesKey: repositories.TagChanges{Attach: []string{"missing"}},
+				})
+				overrides, listErr := repo.ListOverrides(ctx, []uint{series.ID})
+
+				// Assert
+				assert.ErrorIs(t, err, repositories.ErrUnknownTag)
+				require.NoError(t, listErr)
+				assert.Equal(t, []uint{second.ID, third.ID}, taskIDs(overrides))
+			})
+
+			t.Run("delete removes stored occurrences", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, _ := createSeries(t, repo)
+
+				// Act
+				err := repo.Delete(ctx, series.ID, 0, false)
+				require.NoError(t, err)
+				_, getErr := repo.GetByID(ctx, second.ID)
+
+				// Assert
+				assert.ErrorIs(t, getErr, gorm.ErrRecordNotFound)
+			})
+		})
+	}
+}
+
+func TestDateList(t *testing.T) {
+	// Arrange
+	list := models.DateList{time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	value, err := list.Value()
+	require.NoError(t, err)
+	var scanned models.DateList
+	scanErr := scanned.Scan(value)
+
+	// Assert
+	assert.Equal(t, "2026-03-01,2026-03-08", value)
+	require.NoError(t, scanErr)
+	assert.Equal(t, list, scanned)
+}
@@ -0,0 +1,55 @@
+//go:generate mockgen -source=./reminder_repository.go -destination=./mock/reminder_repository.go -package=mock
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"todo-api/internal/models"
+)
+
+// ReminderRepository stores reminders and the state of their delivery. It
+// returns gorm.ErrRecordNotFound for missing reminders and
+// gorm.ErrForeignKeyViolated for a reminder on a task that does not exist.
+// Relative reminders are rescheduled by TaskRepository.Update whenever the
+// due time of their task changes.
+type ReminderRepository interface {
+	Create(ctx context.Context, reminder *models.Reminder) error
+	// ListByTask returns the reminders of a task, soonest first.
+	ListByTask(ctx context.Context, taskID uint) ([]models.Reminder, error)
+	Delete(ctx context.Context, taskID uint, id uint) error
+	// ListDue returns up to limit pending reminders whose next attempt is
+	// due at now, oldest first, skipping those of deleted and completed
+	// tasks.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error)
+	// Claim takes a due reminder for one attempt: it counts the attempt and
+	// moves the next one to leaseUntil, so that other schedulers skip the
+	// reminder and an attempt cut short by a crash is retried. It reports
+	// false when the reminder is no longer due, e.g. claimed by someone else.
+	Claim(ctx context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error)
+	// Update sets delivery columns of a reminder.
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+}
+
+// scheduleColumns are the columns Reminder.Reschedule changes.
+func scheduleColumns(reminder *models.Reminder) map[string]interface{} {
+	return map[string]interface{}{
+		"fire_at":         reminder.FireAt,
+		"next_attempt_at": reminder.NextAttemptAt,
+		"status":          reminder.Status,
+		"attempts":        reminder.Attempts,
+		"last_error":      reminder.LastError,
+		"sent_at":         reminder.SentAt,
+	}
+}
+
+// dueAtUpdate returns the new due time in a task update map and whether
+// the map changes it.
+func dueAtUpdate(updates map[string]interface{}) (*time.Time, bool) {
+	value, ok := updates["due_at"]
+	if !ok {
+		return nil, false
+	}
+	due, _ := value.(*time.Time)
+	return due, true
+}
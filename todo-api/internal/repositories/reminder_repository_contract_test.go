@@ -0,0 +1,224 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// reminderRepositoryFactories pairs every ReminderRepository implementation
+// with the TaskRepository sharing its storage.
+func reminderRepositoryFactories() map[string]func(t *testing.T) (repositories.TaskRepository, repositories.ReminderRepository) {
+	return map[string]func(t *testing.T) (repositories.TaskRepository, repositories.ReminderRepository){
+		"memory": func(t *testing.T) (repositories.TaskRepository, repositories.ReminderRepository) {
+			store := repositories.NewMemoryStore(clock.Real())
+			return repositories.NewTaskRepositoryMemory(store), repositories.NewReminderRepositoryMemory(store)
+		},
+		"sqlite": func(t *testing.T) (repositories.TaskRepository, repositories.ReminderRepository) {
+			db := setupSQLiteDB(t)
+			return repositories.NewTaskRepositoryImpl(db), repositories.NewReminderRepositoryImpl(db)
+		},
+	}
+}
+
+// at returns a moment on the day of day(0).
+func at(hour, minute int) time.Time {
+	return day(0).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func absoluteReminder(taskID uint, fireAt time.Time) *models.Reminder {
+	return &models.Reminder{
+		TaskID:        taskID,
+		Channel:       models.ChannelLog,
+		RemindAt:      &fireAt,
+		FireAt:        &fireAt,
+		NextAttemptAt: &fireAt,
+		Status:        models.ReminderPending,
+	}
+}
+
+func TestReminderRepository_Contract(t *testing.T) {
+	for name, newRepos := range reminderRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("create, list and delete", func(t *testing.T) {
+				// Arrange
+				tasks, reminders := newRepos(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Dentist", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				late := absoluteReminder(task.ID, at(18, 0))
+				early := absoluteReminder(task.ID, at(9, 0))
+				require.NoError(t, reminders.Create(ctx, late))
+				require.NoError(t, reminders.Create(ctx, early))
+
+				// Act
+				listed, err := reminders.ListByTask(ctx, task.ID)
+				require.NoError(t, err)
+				wrongTaskErr := reminders.Delete(ctx, task.ID+1, late.ID)
+				deleteErr := reminders.Delete(ctx, task.ID, late.ID)
+				remaining, err := reminders.ListByTask(ctx, task.ID)
+				require.NoError(t, err)
+
+				// Assert
+				require.Len(t, listed, 2)
+				assert.Equal(t, early.ID, listed[0].ID)
+				assert.True(t, at(9, 0).Equal(*listed[0].FireAt))
+				assert.Equal(t, models.ReminderPending, listed[0].Status)
+				assert.ErrorIs(t, wrongTaskErr, gorm.ErrRecordNotFound)
+				assert.NoError(t, deleteErr)
+				require.Len(t, remaining, 1)
+				assert.Equal(t, early.ID, remaining[0].ID)
+			})
+
+			t.Run("missing task", func(t *testing.T) {
+				// Arrange
+				_, reminders := newRepos(t)
+
+				// Act
+				err := reminders.Create(context.Background(), absoluteReminder(42, at(9, 0)))
+
+				// Assert
+				assert.ErrorIs(t, err, gorm.ErrForeignKeyViolated)
+			})
+
+			t.Run("due reminders of open tasks", func(t *testing.T) {
+				// Arrange
+				tasks, reminders := newRepos(t)
+				ctx := context.Background()
+				open := &models.Task{Title: "Open", Date: day(0)}
+				completed := &models.Task{Title: "Completed", Date: day(0), Completed: true}
+				deleted := &models.Task{Title: "Deleted", Date: day(0)}
+				for _, task := range []*models.Task{open, completed, deleted} {
+					require.NoError(t, tasks.Create(ctx, task))
+				}
+				require.NoError(t, tasks.Delete(ctx, deleted.ID, 0, false))
+				second := absoluteReminder(open.ID, at(9, 30))
+				first := absoluteReminder(open.ID, at(9, 0))
+				future := absoluteReminder(open.ID, at(11, 0))
+				sent := absoluteReminder(open.ID, at(8, 0))
+				sent.Status = models.ReminderSent
+				for _, reminder := range []*models.Reminder{
+					second, first, future, sent,
+					absoluteReminder(completed.ID, at(9, 0)),
+					absoluteReminder(deleted.ID, at(9, 0)),
+				} {
+					require.NoError(t, reminders.Create(ctx, reminder))
+				}
+
+				// Act
+				due, err := reminders.ListDue(ctx, at(10, 0), 10)
+				require.NoError(t, err)
+				limited, err := reminders.ListDue(ctx, at(10, 0), 1)
+				require.NoError(t, err)
+
+				// Assert
+				require.Len(t, due, 2)
+				assert.Equal(t, []uint{first.ID, second.ID}, []uint{due[0].ID, due[1].ID})
+				require.Len(t, limited, 1)
+				assert.Equal(t, first.ID, limited[0].ID)
+			})
+
+			t.Run("claim", func(t *testing.T) {
+				// Arrange
+				tasks, reminders := newRepos(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Dentist", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				reminder := absoluteReminder(task.ID, at(9, 0))
+				require.NoError(t, reminders.Create(ctx, reminder))
+
+				// Act
+				claimed, err := reminders.Claim(ctx, reminder.ID, at(9, 0), at(9, 1))
+				require.NoError(t, err)
+				again, err := reminders.Claim(ctx, reminder.ID, at(9, 0), at(9, 1))
+				require.NoError(t, err)
+				dueDuringClaim, err := reminders.ListDue(ctx, at(9, 0), 10)
+				require.NoError(t, err)
+				dueAfterClaim, err := reminders.ListDue(ctx, at(9, 1), 10)
+				require.NoError(t, err)
+
+				// Assert
+				assert.True(t, claimed)
+				assert.False(t, again)
+				assert.Empty(t, dueDuringClaim)
+				require.Len(t, dueAfterClaim, 1)
+				assert.Equal(t, 1, dueAfterClaim[0].Attempts)
+			})
+
+			t.Run("update delivery state", func(t *testing.T) {
+				// Arrange
+				tasks, reminders := newRepos(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Dentist", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+				reminder := absoluteReminder(task.ID, at(9, 0))
+				require.NoError(t, reminders.Create(ctx, reminder))
+
+				// Act
+				err := reminders.Update(ctx, reminder.ID, map[string]interface{}{
+					"status":          models.ReminderSent,
+					"sent_at":         at(9, 0),
+					"next_attempt_at": nil,
+				})
+				require.NoError(t, err)
+				listed, err := reminders.ListByTask(ctx, task.ID)
+				require.NoError(t, err)
+				missingErr := reminders.Update(ctx, 42, map[string]interface{}{"last_error": "boom"})
+
+				// Assert
+				require.Len(t, listed, 1)
+				assert.Equal(t, models.ReminderSent, listed[0].Status)
+				require.NotNil(t, listed[0].SentAt)
+				assert.True(t, at(9, 0).Equal(*listed[0].SentAt))
+				assert.Nil(t, listed[0].NextAttemptAt)
+				assert.ErrorIs(t, missingErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("relative reminders follow the due time", func(t *testing.T) {
+				// Arrange
+				tasks, reminders := newRepos(t)
+				ctx := context.Background()
+				due := at(18, 0)
+				task := &models.Task{Title: "Call", Date: day(0), DueAt: &due}
+				require.NoError(t, tasks.Create(ctx, task))
+				thirty := 30
+				relative := &models.Reminder{TaskID: task.ID, Channel: models.ChannelLog, BeforeMinutes: &thirty}
+				relative.Reschedule(&due)
+				relative.Status = models.ReminderSent
+				absolute := absoluteReminder(task.ID, at(9, 0))
+				require.NoError(t, reminders.Create(ctx, relative))
+				require.NoError(t, reminders.Create(ctx, absolute))
+				later := at(20, 0)
+
+				// Act
+				err := tasks.Update(ctx, task.ID, 0, map[string]interface{}{"due_at": &later})
+				require.NoError(t, err)
+				moved, err := reminders.ListByTask(ctx, task.ID)
+				require.NoError(t, err)
+				err = tasks.Update(ctx, task.ID, 0, map[string]interface{}{"due_at": (*time.Time)(nil)})
+				require.NoError(t, err)
+				unscheduled, err := reminders.ListByTask(ctx, task.ID)
+				require.NoError(t, err)
+
+				// Assert
+				require.Len(t, moved, 2)
+				assert.Equal(t, absolute.ID, moved[0].ID)
+				assert.True(t, at(19, 30).Equal(*moved[1].FireAt))
+				assert.True(t, at(19, 30).Equal(*moved[1].NextAttemptAt))
+				assert.Equal(t, models.ReminderPending, moved[1].Status)
+				require.Len(t, unscheduled, 2)
+				assert.Nil(t, unscheduled[1].FireAt)
+				assert.Nil(t, unscheduled[1].NextAttemptAt)
+				assert.True(t, at(9, 0).Equal(*unscheduled[0].FireAt))
+			})
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepositoryImpl(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) Create(ctx context.Context, reminder *models.Reminder) error {
+	return r.db.WithContext(ctx).Create(reminder).Error
+}
+
+func (r *reminderRepository) ListByTask(ctx context.Context, taskID uint) ([]models.Reminder, error) {
+	reminders := []models.Reminder{}
+	err := r.db.WithContext(ctx).
+		Where("task_id = ?", taskID).
+		Order("fire_at IS NULL, fire_at, id").
+		Find(&reminders).Error
+	return reminders, err
+}
+
+func (r *reminderRepository) Delete(ctx context.Context, taskID uint, id uint) error {
+	result := r.db.WithContext(ctx).Where("task_id = ?", taskID).Delete(&models.Reminder{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *reminderRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	err := r.db.WithContext(ctx).
+		Joins("JOIN tasks ON tasks.id = reminders.task_id").
+		Where("reminders.status = ? AND reminders.next_attempt_at <= ?", models.ReminderPending, now.UTC()).
+		Where("tasks.deleted_at IS NULL AND tasks.completed = ?", false).
+		Order("reminders.next_attempt_at, reminders.id").
+		Limit(limit).
+		Find(&reminders).Error
+	return reminders, err
+}
+
+func (r *reminderRepository) Claim(ctx context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, models.ReminderPending, now.UTC()).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": leaseUntil.UTC(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *reminderRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&models.Reminder{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// rescheduleReminders moves the relative reminders of a task to its new
+// due time.
+func rescheduleReminders(db *gorm.DB, taskID uint, due *time.Time) error {
+	var reminders []models.Reminder
+	if err := db.Where("task_id = ? AND before_minutes IS NOT NULL", taskID).Find(&reminders).Error; err != nil {
+		return err
+	}
+	for i := range reminders {
+		reminders[i].Reschedule(due)
+		err := db.Model(&models.Reminder{}).Where("id = ?", reminders[i].ID).Updates(scheduleColumns(&reminders[i])).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+// reminderRepositoryMemory keeps reminders in a MemoryStore, next to the
+// tasks they belong to.
+type reminderRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewReminderRepositoryMemory(store *MemoryStore) ReminderRepository {
+	return &reminderRepositoryMemory{MemoryStore: store}
+}
+
+func (r *reminderRepositoryMemory) Create(_ context.Context, reminder *models.Reminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tasks[reminder.TaskID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+
+	now := r.clock.Now()
+	reminder.ID = r.nextReminderID
+	if reminder.Status == "" {
+		reminder.Status = models.ReminderPending
+	}
+	reminder.CreatedAt = now
+	reminder.UpdatedAt = now
+	r.nextReminderID++
+
+	stored := *reminder
+	r.reminders[reminder.ID] = &stored
+	return nil
+}
+
+func (r *reminderRepositoryMemory) ListByTask(_ context.Context, taskID uint) ([]models.Reminder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reminders := []models.Reminder{}
+	for _, reminder := range r.reminders {
+		if reminder.TaskID == taskID {
+			reminders = append(reminders, *reminder)
+		}
+	}
+	sort.Slice(reminders, func(i, j int) bool {
+		a, b := reminders[i].FireAt, reminders[j].FireAt
+		switch {
+		case a == nil || b == nil:
+			if (a == nil) != (b == nil) {
+				return b == nil
+			}
+		case !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return reminders[i].ID < reminders[j].ID
+	})
+	return reminders, nil
+}
+
+func (r *reminderRepositoryMemory) Delete(_ context.Context, taskID uint, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reminder, ok := r.reminders[id]
+	if !ok || reminder.TaskID != taskID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.reminders, id)
+	return nil
+}
+
+func (r *reminderRepositoryMemory) ListDue(_ context.Context, now time.Time, limit int) ([]models.Reminder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var reminders []models.Reminder
+	for _, reminder := range r.reminders {
+		task, ok := r.tasks[reminder.TaskID]
+		if ok && !task.DeletedAt.Valid && !task.Completed && reminderDue(reminder, now) {
+			reminders = append(reminders, *reminder)
+		}
+	}
+	sort.Slice(reminders, func(i, j int) bool {
+		a, b := *reminders[i].NextAttemptAt, *reminders[j].NextAttemptAt
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return reminders[i].ID < reminders[j].ID
+	})
+	if limit > 0 && limit < len(reminders) {
+		reminders = reminders[:limit]
+	}
+	return reminders, nil
+}
+
+func (r *reminderRepositoryMemory) Claim(_ context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reminder, ok := r.reminders[id]
+	if !ok || !reminderDue(reminder, now) {
+		return false, nil
+	}
+	claimed := *reminder
+	claimed.Attempts++
+	claimed.NextAttemptAt = &leaseUntil
+	claimed.UpdatedAt = r.clock.Now()
+	r.reminders[id] = &claimed
+	return true, nil
+}
+
+func (r *reminderRepositoryMemory) Update(_ context.Context, id uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reminder, ok := r.reminders[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	updated := *reminder
+	for column, value := range updates {
+		var ok bool
+		switch column {
+		case "status":
+			updated.Status, ok = value.(models.ReminderStatus)
+		case "attempts":
+			updated.Attempts, ok = value.(int)
+		case "last_error":
+			updated.LastError, ok = value.(string)
+		case "fire_at":
+			updated.FireAt, ok = optionalTime(value)
+		case "next_attempt_at":
+			updated.NextAttemptAt, ok = optionalTime(value)
+		case "sent_at":
+			updated.SentAt, ok = optionalTime(value)
+		default:
+			return fmt.Errorf("unknown reminder column %q", column)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v for reminder column %q", value, column)
+		}
+	}
+	updated.UpdatedAt = r.clock.Now()
+	r.reminders[id] = &updated
+	return nil
+}
+
+// reminderDue reports whether a pending reminder's next attempt is due at now.
+func reminderDue(reminder *models.Reminder, now time.Time) bool {
+	return reminder.Status == models.ReminderPending &&
+		reminder.NextAttemptAt != nil && !reminder.NextAttemptAt.After(now)
+}
+
+// optionalTime converts the value of a nullable time column, nil, a
+// time.Time or a *time.Time.
+func optionalTime(value interface{}) (*time.Time, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case time.Time:
+		return &v, true
+	case *time.Time:
+		return v, true
+	}
+	return nil, false
+}
@@ -0,0 +1,16 @@
+//go:generate mockgen -source=./settings_repository.go -destination=./mock/settings_repository.go -package=mock
+package repositories
+
+import (
+	"context"
+
+	"todo-api/internal/models"
+)
+
+// SettingsRepository stores the settings of each actor. Get returns
+// gorm.ErrRecordNotFound for an actor that has never saved any.
+type SettingsRepository interface {
+	Get(ctx context.Context, actor string) (*models.UserSettings, error)
+	// Save creates the actor's settings or replaces the stored ones.
+	Save(ctx context.Context, settings *models.UserSettings) error
+}
@@ -0,0 +1,65 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+func settingsRepositoryFactories() map[string]func(t *testing.T) repositories.SettingsRepository {
+	return map[string]func(t *testing.T) repositories.SettingsRepository{
+		"memory": func(t *testing.T) repositories.SettingsRepository {
+			return repositories.NewSettingsRepositoryMemory(repositories.NewMemoryStore(clock.Real()))
+		},
+		"sqlite": func(t *testing.T) repositories.SettingsRepository {
+			return repositories.NewSettingsRepositoryImpl(setupSQLiteDB(t))
+		},
+	}
+}
+
+func TestSettingsRepository_Contract(t *testing.T) {
+	for name, newRepo := range settingsRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("never saved", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+
+				// Act
+				_, err := repo.Get(context.Background(), "alice")
+
+				// Assert
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("save replaces", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				require.NoError(t, repo.Save(ctx, &models.UserSettings{Actor: "alice", TimeZone: "Europe/Moscow"}))
+				require.NoError(t, repo.Save(ctx, &models.UserSettings{Actor: "bob", TimeZone: "Asia/Tokyo"}))
+				first, err := repo.Get(ctx, "alice")
+				require.NoError(t, err)
+
+				// Act
+				err = repo.Save(ctx, &models.UserSettings{Actor: "alice", TimeZone: "Europe/Berlin"})
+				require.NoError(t, err)
+				alice, err := repo.Get(ctx, "alice")
+				require.NoError(t, err)
+				bob, err := repo.Get(ctx, "bob")
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, "Europe/Berlin", alice.TimeZone)
+				assert.True(t, alice.CreatedAt.Equal(first.CreatedAt))
+				assert.Equal(t, "Asia/Tokyo", bob.TimeZone)
+			})
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todo-api/internal/models"
+)
+
+type settingsRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingsRepositoryImpl(db *gorm.DB) SettingsRepository {
+	return &settingsRepository{db: db}
+}
+
+func (r *settingsRepository) Get(ctx context.Context, actor string) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	if err := r.db.WithContext(ctx).Where("actor = ?", actor).First(&settings).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *settingsRepository) Save(ctx context.Context, settings *models.UserSettings) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "actor"}},
+		DoUpdates: clause.AssignmentColumns([]string{"time_zone", "updated_at"}),
+	}).Create(settings).Error
+}
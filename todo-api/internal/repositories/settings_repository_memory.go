@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+type settingsRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewSettingsRepositoryMemory(store *MemoryStore) SettingsRepository {
+	return &settingsRepositoryMemory{MemoryStore: store}
+}
+
+func (r *settingsRepositoryMemory) Get(_ context.Context, actor string) (*models.UserSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings, ok := r.settings[actor]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *settings
+	return &found, nil
+}
+
+func (r *settingsRepositoryMemory) Save(_ context.Context, settings *models.UserSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	settings.CreatedAt = now
+	if stored, ok := r.settings[settings.Actor]; ok {
+		settings.CreatedAt = stored.CreatedAt
+	}
+	settings.UpdatedAt = now
+
+	stored := *settings
+	r.settings[settings.Actor] = &stored
+	return nil
+}
@@ -0,0 +1,156 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+// createTree creates a root task with two subtasks, the first of which has
+// a subtask of its own.
+func createTree(t *testing.T, repo repositories.TaskRepository) (root, child, leaf, sibling *models.Task) {
+	t.Helper()
+	ctx := context.Background()
+
+	root = &models.Task{Title: "Root", Date: day(0)}
+	require.NoError(t, repo.Create(ctx, root))
+	child = &models.Task{Title: "Child", Date: day(0), ParentID: &root.ID}
+	require.NoError(t, repo.Create(ctx, child))
+	leaf = &models.Task{Title: "Leaf", Date: day(0), ParentID: &child.ID}
+	require.NoError(t, repo.Create(ctx, leaf))
+	sibling = &models.Task{Title: "Sibling", Date: day(1), ParentID: &root.ID, Completed: true}
+	require.NoError(t, repo.Create(ctx, sibling))
+	return root, child, leaf, sibling
+}
+
+func TestTaskRepository_Subtasks(t *testing.T) {
+	for name, newRepo := range repositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("progress and parent filter", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, child, leaf, sibling := createTree(t, repo)
+				top := uint(0)
+
+				// Act
+				found, err := repo.GetByID(ctx, root.ID)
+				require.NoError(t, err)
+				topLevel, err := repo.List(ctx, dto.TaskFilter{ParentID: &top, Limit: 10})
+				require.NoError(t, err)
+				ofRoot, err := repo.List(ctx, dto.TaskFilter{ParentID: &root.ID, Limit: 10})
+				require.NoError(t, err)
+				children, err := repo.ListChildren(ctx, []uint{root.ID, child.ID})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, int64(2), found.SubtasksTotal)
+				assert.Equal(t, int64(1), found.SubtasksCompleted)
+				assert.Equal(t, []uint{root.ID}, taskIDs(topLevel))
+				require.Len(t, topLevel, 1)
+				assert.Equal(t, int64(2), topLevel[0].SubtasksTotal)
+				assert.Equal(t, []uint{child.ID, sibling.ID}, taskIDs(ofRoot))
+				assert.ElementsMatch(t, []uint{child.ID, leaf.ID, sibling.ID}, taskIDs(children))
+			})
+
+			t.Run("complete subtasks", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, child, leaf, _ := createTree(t, repo)
+
+				// Act
+				err := repo.Update(ctx, root.ID, 0, map[string]interface{}{
+					"completed":                      true,
+					repositories.CompleteSubtasksKey: true,
+				})
+				require.NoError(t, err)
+				foundChild, err := repo.GetByID(ctx, child.ID)
+				require.NoError(t, err)
+				foundLeaf, err := repo.GetByID(ctx, leaf.ID)
+				require.NoError(t, err)
+
+				// Assert
+				assert.True(t, foundChild.Completed)
+				assert.Equal(t, uint(2), foundChild.Version)
+				assert.True(t, foundLeaf.Completed)
+			})
+
+			t.Run("delete reparents subtasks", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, child, leaf, _ := createTree(t, repo)
+
+				// Act
+				err := repo.Delete(ctx, child.ID, 0, false)
+				require.NoError(t, err)
+				foundLeaf, err := repo.GetByID(ctx, leaf.ID)
+				require.NoError(t, err)
+
+				// Assert
+				require.NotNil(t, foundLeaf.ParentID)
+				assert.Equal(t, root.ID, *foundLeaf.ParentID)
+				assert.Equal(t, uint(2), foundLeaf.Version)
+			})
+
+			t.Run("delete cascades to subtasks", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, child, leaf, sibling := createTree(t, repo)
+				other := &models.Task{Title: "Other", Date: day(2)}
+				require.NoError(t, repo.Create(ctx, other))
+
+				// Act
+				err := repo.Delete(ctx, root.ID, 0, true)
+				require.NoError(t, err)
+				remaining, err := repo.List(ctx, dto.TaskFilter{Limit: 10})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, []uint{other.ID}, taskIDs(remaining))
+				for _, id := range []uint{root.ID, child.ID, leaf.ID, sibling.ID} {
+					_, err = repo.GetByID(ctx, id)
+					assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				}
+			})
+
+			t.Run("stale delete keeps subtasks", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, child, _, _ := createTree(t, repo)
+
+				// Act
+				err := repo.Delete(ctx, root.ID, 7, true)
+				foundChild, getErr := repo.GetByID(ctx, child.ID)
+
+				// Assert
+				assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+				require.NoError(t, getErr)
+				assert.Equal(t, root.ID, *foundChild.ParentID)
+			})
+
+			t.Run("unknown parent", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				missing := uint(42)
+
+				// Act
+				err := repo.Create(ctx, &models.Task{Title: "Orphan", Date: day(0), ParentID: &missing})
+
+				// Assert
+				assert.ErrorIs(t, err, gorm.ErrForeignKeyViolated)
+			})
+		})
+	}
+}
@@ -0,0 +1,21 @@
+//go:generate mockgen -source=./tag_repository.go -destination=./mock/tag_repository.go -package=mock
+package repositories
+
+import (
+	"context"
+
+	"todo-api/internal/models"
+)
+
+// TagRepository stores tags. Like TaskRepository it returns
+// gorm.ErrRecordNotFound for missing tags, and gorm.ErrDuplicatedKey when a
+// name is already taken. Deleting a tag detaches it from every task,
+// updating each like TaskRepository.Update would.
+type TagRepository interface {
+	Create(ctx context.Context, tag *models.Tag) error
+	GetByID(ctx context.Context, id uint) (*models.Tag, error)
+	// List returns every tag ordered by name.
+	List(ctx context.Context) ([]models.Tag, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+}
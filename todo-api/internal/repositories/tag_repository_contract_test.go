@@ -0,0 +1,222 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// tagRepositoryFactories pairs every TagRepository implementation with the
+// TaskRepository sharing its storage.
+func tagRepositoryFactories() map[string]func(t *testing.T) (repositories.TaskRepository, repositories.TagRepository) {
+	return map[string]func(t *testing.T) (repositories.TaskRepository, repositories.TagRepository){
+		"memory": func(t *testing.T) (repositories.TaskRepository, repositories.TagRepository) {
+			store := repositories.NewMemoryStore(clock.Real())
+			return repositories.NewTaskRepositoryMemory(store), repositories.NewTagRepositoryMemory(store)
+		},
+		"sqlite": func(t *testing.T) (repositories.TaskRepository, repositories.TagRepository) {
+			db := setupSQLiteDB(t)
+			return repositories.NewTaskRepositoryImpl(db), repositories.NewTagRepositoryImpl(db)
+		},
+	}
+}
+
+func TestTagRepository_Contract(t *testing.T) {
+	for name, newRepos := range tagRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("crud", func(t *testing.T) {
+				// Arrange
+				_, tags := newRepos(t)
+				ctx := context.Background()
+				work := &models.Tag{Name: "work", Color: "#ff0000"}
+				require.NoError(t, tags.Create(ctx, work))
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "home"}))
+
+				// Act
+				err := tags.Update(ctx, work.ID, map[string]interface{}{"name": "office"})
+				require.NoError(t, err)
+				found, err := tags.GetByID(ctx, work.ID)
+				require.NoError(t, err)
+				all, err := tags.List(ctx)
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, "office", found.Name)
+				assert.Equal(t, "#ff0000", found.Color)
+				require.Len(t, all, 2)
+				assert.Equal(t, "home", all[0].Name)
+				assert.Equal(t, "office", all[1].Name)
+			})
+
+			t.Run("duplicate name", func(t *testing.T) {
+				// Arrange
+				_, tags := newRepos(t)
+				ctx := context.Background()
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+				home := &models.Tag{Name: "home"}
+				require.NoError(t, tags.Create(ctx, home))
+
+				// Act
+				createErr := tags.Create(ctx, &models.Tag{Name: "work"})
+				renameErr := tags.Update(ctx, home.ID, map[string]interface{}{"name": "work"})
+
+				// Assert
+				assert.ErrorIs(t, createErr, gorm.ErrDuplicatedKey)
+				assert.ErrorIs(t, renameErr, gorm.ErrDuplicatedKey)
+			})
+
+			t.Run("missing", func(t *testing.T) {
+				// Arrange
+				_, tags := newRepos(t)
+				ctx := context.Background()
+
+				// Act
+				_, getErr := tags.GetByID(ctx, 42)
+				updateErr := tags.Update(ctx, 42, map[string]interface{}{"name": "x"})
+				deleteErr := tags.Delete(ctx, 42)
+
+				// Assert
+				assert.ErrorIs(t, getErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, updateErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, deleteErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("create task with tags", func(t *testing.T) {
+				// Arrange
+				tasks, tags := newRepos(t)
+				ctx := context.Background()
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "home"}))
+				task := &models.Task{Title: "Both", Date: day(0), Tags: []models.Tag{{Name: "work"}, {Name: "home"}}}
+
+				// Act
+				err := tasks.Create(ctx, task)
+				require.NoError(t, err)
+				found, err := tasks.GetByID(ctx, task.ID)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, []string{"home", "work"}, tagNames(found.Tags))
+			})
+
+			t.Run("unknown tag", func(t *testing.T) {
+				// Arrange
+				tasks, tags := newRepos(t)
+				ctx := context.Background()
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+				task := &models.Task{Title: "Plain", Date: day(0)}
+				require.NoError(t, tasks.Create(ctx, task))
+
+				// Act
+				createErr := tasks.Create(ctx, &models.Task{Title: "Typo", Date: day(0), Tags: []models.Tag{{Name: "wrok"}}})
+				updateErr := tasks.Update(ctx, task.ID, 0, map[string]interface{}{
+					repositories.TagChangesKey: repositories.TagChanges{Attach: []string{"work", "wrok"}},
+				})
+				found, err := tasks.GetByID(ctx, task.ID)
+
+				// Assert
+				assert.ErrorIs(t, createErr, repositories.ErrUnknownTag)
+				assert.ErrorContains(t, createErr, "wrok")
+				assert.ErrorIs(t, updateErr, repositories.ErrUnknownTag)
+				require.NoError(t, err)
+				assert.Empty(t, found.Tags, "a failed update must not attach anything")
+				assert.Equal(t, uint(1), found.Version)
+			})
+
+			t.Run("attach and detach", func(t *testing.T) {
+				// Arrange
+				tasks, tags := newRepos(t)
+				ctx := context.Background()
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+				require.NoError(t, tags.Create(ctx, &models.Tag{Name: "home"}))
+				task := &models.Task{Title: "Task", Date: day(0), Tags: []models.Tag{{Name: "work"}}}
+				require.NoError(t, tasks.Create(ctx, task))
+
+				// Act
+				err := tasks.Update(ctx, task.ID, task.Version, map[string]interface{}{
+					"title": "Renamed",
+					repositories.TagChangesKey: repositories.TagChanges{
+						Attach: []string{"home", "work"},
+						Detach: []string{"work"},
+					},
+				})
+				require.NoError(t, err)
+				found, err := tasks.GetByID(ctx, task.ID)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, "Renamed", found.Title)
+				assert.Equal(t, []string{"home"}, tagNames(found.Tags))
+				assert.Equal(t, task.Version+1, found.Version)
+			})
+
+			t.Run("filter by tags", func(t *testing.T) {
+				// Arrange
+				tasks, tags := newRepos(t)
+				ctx := context.Background()
+				for _, name := range []string{"work", "home", "urgent"} {
+					require.NoError(t, tags.Create(ctx, &models.Tag{Name: name}))
+				}
+				both := &models.Task{Title: "Both", Date: day(0), Tags: []models.Tag{{Name: "work"}, {Name: "urgent"}}}
+				work := &models.Task{Title: "Work", Date: day(1), Tags: []models.Tag{{Name: "work"}}}
+				home := &models.Task{Title: "Home", Date: day(2), Tags: []models.Tag{{Name: "home"}}}
+				for _, task := range []*models.Task{both, work, home, {Title: "None", Date: day(3)}} {
+					require.NoError(t, tasks.Create(ctx, task))
+				}
+
+				// Act
+				anyOf, err := tasks.List(ctx, dto.TaskFilter{Tags: []string{"urgent", "home"}, Limit: 10})
+				require.NoError(t, err)
+				anyTotal, err := tasks.Count(ctx, dto.TaskFilter{Tags: []string{"urgent", "home"}})
+				require.NoError(t, err)
+				allOf, err := tasks.List(ctx, dto.TaskFilter{Tags: []string{"work", "urgent"}, TagMode: dto.TagModeAll, Limit: 10})
+				require.NoError(t, err)
+				allTotal, err := tasks.Count(ctx, dto.TaskFilter{Tags: []string{"work", "urgent"}, TagMode: dto.TagModeAll})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, []uint{both.ID, home.ID}, taskIDs(anyOf))
+				assert.Equal(t, int64(2), anyTotal)
+				assert.Equal(t, []uint{both.ID}, taskIDs(allOf))
+				assert.Equal(t, int64(1), allTotal)
+				assert.Equal(t, []string{"urgent", "work"}, tagNames(allOf[0].Tags), "tasks come back with all their tags")
+			})
+
+			t.Run("delete detaches", func(t *testing.T) {
+				// Arrange
+				tasks, tags := newRepos(t)
+				ctx := context.Background()
+				work := &models.Tag{Name: "work"}
+				require.NoError(t, tags.Create(ctx, work))
+				task := &models.Task{Title: "Task", Date: day(0), Tags: []models.Tag{{Name: "work"}}}
+				require.NoError(t, tasks.Create(ctx, task))
+
+				// Act
+				err := tags.Delete(ctx, work.ID)
+				require.NoError(t, err)
+				found, err := tasks.GetByID(ctx, task.ID)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Empty(t, found.Tags)
+				assert.Equal(t, task.Version+1, found.Version)
+			})
+		})
+	}
+}
+
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepositoryImpl(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id uint) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).First(&tag, id).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) List(ctx context.Context) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&models.Tag{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var taskIDs []uint
+		err := tx.Model(&models.TaskTag{}).Where("tag_id = ?", id).Order("task_id").Pluck("task_id", &taskIDs).Error
+		if err != nil {
+			return err
+		}
+		err = detachTasks(tx, taskIDs, func(tx *gorm.DB) error {
+			return tx.Where("tag_id = ?", id).Delete(&models.TaskTag{}).Error
+		})
+		if err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+// tagRepositoryMemory keeps tags in a MemoryStore, next to the tasks they
+// are attached to.
+type tagRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewTagRepositoryMemory(store *MemoryStore) TagRepository {
+	return &tagRepositoryMemory{MemoryStore: store}
+}
+
+func (r *tagRepositoryMemory) Create(_ context.Context, tag *models.Tag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, taken := r.tagNamed(tag.Name); taken {
+		return gorm.ErrDuplicatedKey
+	}
+
+	now := r.clock.Now()
+	tag.ID = r.nextTagID
+	tag.CreatedAt = now
+	tag.UpdatedAt = now
+	r.nextTagID++
+
+	stored := *tag
+	r.tags[tag.ID] = &stored
+	return nil
+}
+
+func (r *tagRepositoryMemory) GetByID(_ context.Context, id uint) (*models.Tag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tag, ok := r.tags[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *tag
+	return &found, nil
+}
+
+func (r *tagRepositoryMemory) List(_ context.Context) ([]models.Tag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tags := make([]models.Tag, 0, len(r.tags))
+	for _, tag := range r.tags {
+		tags = append(tags, *tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+func (r *tagRepositoryMemory) Update(_ context.Context, id uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tag, ok := r.tags[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	updated := *tag
+	for column, value := range updates {
+		var ok bool
+		switch column {
+		case "name":
+			updated.Name, ok = value.(string)
+			if other, taken := r.tagNamed(updated.Name); taken && other.ID != id {
+				return gorm.ErrDuplicatedKey
+			}
+		case "color":
+			updated.Color, ok = value.(string)
+		default:
+			return fmt.Errorf("unknown tag column %q", column)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v for tag column %q", value, column)
+		}
+	}
+	updated.UpdatedAt = r.clock.Now()
+	r.tags[id] = &updated
+	return nil
+}
+
+func (r *tagRepositoryMemory) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tags[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	var taskIDs []uint
+	for taskID, tagIDs := range r.taskTags {
+		if tagIDs[id] {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	err := r.detachTasks(ctx, taskIDs, func(task *models.Task) {
+		delete(r.taskTags[task.ID], id)
+	})
+	if err != nil {
+		return err
+	}
+	delete(r.tags, id)
+	return nil
+}
@@ -0,0 +1,72 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+func TestTaskMatches_AgreesWithList(t *testing.T) {
+	for name, newRepos := range tagRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo, tags := newRepos(t)
+			ctx := context.Background()
+			require.NoError(t, tags.Create(ctx, &models.Tag{Name: "work"}))
+			require.NoError(t, tags.Create(ctx, &models.Tag{Name: "home"}))
+			report := &models.Task{Title: "Write report", Description: "quarterly numbers", Date: day(1), Priority: models.PriorityHigh}
+			require.NoError(t, repo.Create(ctx, report))
+			require.NoError(t, repo.Update(ctx, report.ID, 0, map[string]interface{}{
+				repositories.TagChangesKey: repositories.TagChanges{Attach: []string{"work", "home"}},
+			}))
+			chart := &models.Task{Title: "Draw chart", Date: day(2), ParentID: &report.ID}
+			require.NoError(t, repo.Create(ctx, chart))
+			require.NoError(t, repo.Update(ctx, chart.ID, 0, map[string]interface{}{
+				repositories.TagChangesKey: repositories.TagChanges{Attach: []string{"work"}},
+			}))
+			require.NoError(t, repo.AddDependency(ctx, chart.ID, report.ID))
+			milk := &models.Task{Title: "Buy milk", Date: day(3), Completed: true}
+			require.NoError(t, repo.Create(ctx, milk))
+			all, err := repo.List(ctx, dto.TaskFilter{Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+
+			yes, no, top := true, false, uint(0)
+			from, to := day(2), day(3)
+			filters := map[string]dto.TaskFilter{
+				"completed":  {Completed: &no},
+				"date range": {DateFrom: &from, DateTo: &to},
+				"priority":   {Priorities: []models.Priority{models.PriorityHigh}},
+				"any tag":    {Tags: []string{"home", "work"}},
+				"all tags":   {Tags: []string{"home", "work"}, TagMode: dto.TagModeAll},
+				"top level":  {ParentID: &top},
+				"subtasks":   {ParentID: &report.ID},
+				"outside":    {ProjectID: &top},
+				"blocked":    {Blocked: &yes},
+				"query":      {Query: "QUARTERLY report"},
+			}
+
+			for filterName, filter := range filters {
+				// Act
+				filter.Limit = 10
+				listed, err := repo.List(ctx, filter)
+				require.NoError(t, err)
+				var matched []models.Task
+				for _, task := range all {
+					if repositories.TaskMatches(&task, filter, false) {
+						matched = append(matched, task)
+					}
+				}
+
+				// Assert
+				assert.ElementsMatch(t, taskIDs(listed), taskIDs(matched), filterName)
+			}
+		})
+	}
+}
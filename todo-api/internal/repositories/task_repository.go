@@ -3,15 +3,336 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"todo-api/internal/dto"
 	"todo-api/internal/models"
 )
 
+var (
+	// ErrVersionMismatch is returned by conditional writes when the task
+	// exists but its version differs from the expected one.
+	ErrVersionMismatch = errors.New("task version mismatch")
+	// ErrUnknownTag is returned when a task refers to a tag name that does
+	// not exist.
+	ErrUnknownTag = errors.New("unknown tag")
+	// ErrDependencyCycle is returned when a new dependency would make a task
+	// wait, directly or through other tasks, for itself.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrParentTrashed is returned when a task cannot be restored because
+	// its parent is still in the trash.
+	ErrParentTrashed = errors.New("parent task is in the trash")
+)
+
+// TagChangesKey is the key of a TagChanges value in an Update map. It is
+// applied in the same transaction as the column updates.
+const TagChangesKey = "tags"
+
+// TagChanges attaches and detaches tags by name. Attaching an unknown tag
+// fails with ErrUnknownTag; detaching one that is not attached is a no-op.
+type TagChanges struct {
+	Attach []string
+	Detach []string
+}
+
+// CompleteSubtasksKey, set to true in an Update map, also completes every
+// open descendant of the task in the same transaction.
+const CompleteSubtasksKey = "complete_subtasks"
+
+// DueTimesKey is the key of a DueTimes value in an UpdateMany map: the due
+// times that differ from task to task, set along with the shared updates.
+const DueTimesKey = "due_times"
+
+// DueTimes maps task IDs to their new due_at, nil for all-day.
+type DueTimes map[uint]*time.Time
+
+// TaskRepository stores tasks. Missing and soft-deleted tasks fail with
+// gorm.ErrRecordNotFound, and a version of 0 matches any. Every write
+// records its events in the outbox and its changes, with the audit.Info of
+// ctx, in the task history, in the same transaction; dependency changes
+// record nothing.
 type TaskRepository interface {
+	// Create attaches the tags in task.Tags by name and records
+	// task.created. A missing project or parent fails with
+	// gorm.ErrForeignKeyViolated.
 	Create(ctx context.Context, task *models.Task) error
+	// GetByID returns a live task with its tags by name, the progress of
+	// its direct subtasks, and Blocked set when a live open task blocks it.
 	GetByID(ctx context.Context, id uint) (*models.Task, error)
-	Update(ctx context.Context, id uint, updates map[string]interface{}) error
-	Delete(ctx context.Context, id uint) error
+	// Update applies updates when the stored version equals version and
+	// increments it. It records task.updated for each task it changes and
+	// task.completed for each it completes.
+	Update(ctx context.Context, id uint, version uint, updates map[string]interface{}) error
+	// Delete moves a task to the trash when its version matches, with its
+	// subtree when cascade is set; otherwise its children move up to its
+	// parent. A recurring task takes its stored occurrences along. It
+	// records task.deleted with each task as it was.
+	Delete(ctx context.Context, id uint, version uint, cascade bool) error
+	// List orders tasks by filter.OrderOrDefault, or by the order of the
+	// cursor; a backward cursor returns them nearest to it first. With
+	// filter.Trashed it lists the trash, without stored occurrences.
 	List(ctx context.Context, filter dto.TaskFilter) ([]models.Task, error)
+	// Count counts the tasks List would select, ignoring pagination.
+	Count(ctx context.Context, filter dto.TaskFilter) (int64, error)
+	// ListByDate returns the live tasks on date in manual order (rank, id).
+	ListByDate(ctx context.Context, date time.Time) ([]models.Task, error)
+	// LastRank returns the greatest rank on date, "" for an empty day.
+	LastRank(ctx context.Context, date time.Time) (string, error)
+	// SetRanks rewrites the ranks of several tasks, bumping their versions.
+	SetRanks(ctx context.Context, ranks map[uint]string) error
+	// ListChildren returns the live direct subtasks of the given tasks in
+	// the default order.
+	ListChildren(ctx context.Context, parentIDs []uint) ([]models.Task, error)
+	// AddDependency makes blockerID block taskID, both live tasks. An
+	// existing dependency is a no-op; one closing a loop fails with
+	// ErrDependencyCycle naming the loop.
+	AddDependency(ctx context.Context, taskID uint, blockerID uint) error
+	// RemoveDependency fails with gorm.ErrRecordNotFound when there is no
+	// such dependency.
+	RemoveDependency(ctx context.Context, taskID uint, blockerID uint) error
+	// ListBlockers returns the live tasks blocking taskID, open or not, in
+	// the default order.
+	ListBlockers(ctx context.Context, taskID uint) ([]models.Task, error)
+	// ListOverrides returns the stored occurrences of the given recurring
+	// tasks, deleted ones included, by occurrence date and without tags or
+	// computed fields.
+	ListOverrides(ctx context.Context, seriesIDs []uint) ([]models.Task, error)
+	// SplitSeries ends a recurring task early and continues it as next: in
+	// one transaction it updates task id as Update would, creates next and
+	// moves the stored occurrences from next.Date on over to it.
+	SplitSeries(ctx context.Context, id uint, version uint, updates map[string]interface{}, next *models.Task) error
+	// ExcludeOccurrence updates task id as Update would and, unless
+	// occurrenceID is 0, deletes that stored occurrence as Delete would, in
+	// one transaction.
+	ExcludeOccurrence(ctx context.Context, id uint, version uint, updates map[string]interface{}, occurrenceID uint) error
+	// StoreOccurrence creates occurrence as Create would and applies updates
+	// to it as Update would, in one transaction.
+	StoreOccurrence(ctx context.Context, occurrence *models.Task, updates map[string]interface{}) error
+	// GetTrashed returns a task in the trash the way GetByID returns a live
+	// one.
+	GetTrashed(ctx context.Context, id uint) (*models.Task, error)
+	// Restore brings a task back from the trash when its version matches,
+	// with the subtasks and the stored occurrences not excluded from its
+	// series deleted along with it, and records task.restored for each. A
+	// task not in the trash is not found; one whose parent is fails with
+	// ErrParentTrashed.
+	Restore(ctx context.Context, id uint, version uint) error
+	// Purge deletes a task in the trash for good when its version matches,
+	// with its trashed subtree when cascade is set; subtasks left behind
+	// become top-level. It records task.purged for each task purged, stored
+	// occurrences included.
+	Purge(ctx context.Context, id uint, version uint, cascade bool) error
+	// PurgeDeleted purges, as Purge would, every task deleted before before
+	// and returns how many there were.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	// GetByIDs returns the live tasks among ids, loaded like GetByID, in the
+	// order of ids.
+	GetByIDs(ctx context.Context, ids []uint) ([]models.Task, error)
+	// CreateMany is Create for a batch: one transaction with one statement
+	// per step for all the tasks, failing as a whole.
+	CreateMany(ctx context.Context, tasks []models.Task) error
+	// UpdateMany applies the same updates to the tasks with the distinct
+	// ids, like CreateMany and without checking versions. Only the
+	// DueTimesKey entries, and the reminders following them, differ by task.
+	UpdateMany(ctx context.Context, ids []uint, updates map[string]interface{}) error
+	// DeleteMany deletes the tasks with the distinct ids, like CreateMany and
+	// without checking versions. Each child that stays moves up to its
+	// nearest ancestor that is not deleted.
+	DeleteMany(ctx context.Context, ids []uint, cascade bool) error
+}
+
+// dayBounds returns the moments the date range of a filter starts and ends
+// in its time zone, the end exclusive, nil for an open side. Tasks with a
+// due time are matched by it; all-day tasks by their date alone.
+func dayBounds(filter dto.TaskFilter) (from, to *time.Time) {
+	loc := filter.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if filter.DateFrom != nil {
+		y, m, d := filter.DateFrom.Date()
+		start := time.Date(y, m, d, 0, 0, 0, 0, loc).UTC()
+		from = &start
+	}
+	if filter.DateTo != nil {
+		y, m, d := filter.DateTo.Date()
+		end := time.Date(y, m, d+1, 0, 0, 0, 0, loc).UTC()
+		to = &end
+	}
+	return from, to
+}
+
+// TaskMatches reports whether a task, loaded with its tags and computed
+// fields, passes filter the way List would select it; archived tells
+// whether its project is archived. Search uses the substring rules of
+// the non-Postgres backends, and recurring tasks are matched by their
+// first date, not their occurrences.
+func TaskMatches(task *models.Task, filter dto.TaskFilter, archived bool) bool {
+	if !matchesTaskFilter(task, filter) || !matchesProjectFilter(task, filter, archived) {
+		return false
+	}
+	attached := make(map[string]bool, len(task.Tags))
+	for _, tag := range task.Tags {
+		attached[tag.Name] = true
+	}
+	if !matchesTagFilter(attached, filter) {
+		return false
+	}
+	if filter.Blocked != nil && task.Blocked != *filter.Blocked {
+		return false
+	}
+	if terms := searchTerms(filter.Query); len(terms) > 0 {
+		if _, ok := searchRelevance(task, terms); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesTaskFilter(task *models.Task, filter dto.TaskFilter) bool {
+	if filter.Completed != nil && task.Completed != *filter.Completed {
+		return false
+	}
+	if task.DueAt != nil {
+		from, to := dayBounds(filter)
+		if from != nil && task.DueAt.Before(*from) || to != nil && !task.DueAt.Before(*to) {
+			return false
+		}
+	} else {
+		if filter.DateFrom != nil && task.Date.Before(*filter.DateFrom) {
+			return false
+		}
+		if filter.DateTo != nil && task.Date.After(*filter.DateTo) {
+			return false
+		}
+	}
+	if len(filter.Priorities) > 0 && !slices.Contains(filter.Priorities, task.Priority) {
+		return false
+	}
+	if filter.Recurring != nil && (task.Recurrence != "") != *filter.Recurring {
+		return false
+	}
+	if filter.ParentID != nil {
+		if *filter.ParentID == 0 {
+			return task.ParentID == nil
+		}
+		return task.ParentID != nil && *task.ParentID == *filter.ParentID
+	}
+	return true
+}
+
+// matchesTagFilter reports whether the names of the tags attached to a
+// task satisfy the tags filter in its mode.
+func matchesTagFilter(attached map[string]bool, filter dto.TaskFilter) bool {
+	if len(filter.Tags) == 0 {
+		return true
+	}
+	for _, name := range filter.Tags {
+		if attached[name] && filter.TagMode != dto.TagModeAll {
+			return true
+		}
+		if !attached[name] && filter.TagMode == dto.TagModeAll {
+			return false
+		}
+	}
+	return filter.TagMode == dto.TagModeAll
+}
+
+// matchesProjectFilter reports whether a task belongs to the filter's
+// project, or, without one, whether it is outside archived projects unless
+// those are included.
+func matchesProjectFilter(task *models.Task, filter dto.TaskFilter, archived bool) bool {
+	switch {
+	case filter.ProjectID == nil:
+		return filter.IncludeArchived || !archived
+	case *filter.ProjectID == 0:
+		return task.ProjectID == nil
+	default:
+		return task.ProjectID != nil && *task.ProjectID == *filter.ProjectID
+	}
+}
+
+func unknownTagsError(names []string) error {
+	return fmt.Errorf("%w: %s", ErrUnknownTag, strings.Join(names, ", "))
+}
+
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+// adoptingParents groups the children that stay when deleted go by the
+// parent they move up to: their nearest ancestor that is not deleted, 0
+// for none.
+func adoptingParents(deleted []models.Task, children []models.Task) map[uint][]uint {
+	parents := make(map[uint]*uint, len(deleted))
+	for _, task := range deleted {
+		parents[task.ID] = task.ParentID
+	}
+	groups := make(map[uint][]uint)
+	for _, child := range children {
+		parent := child.ParentID
+		for parent != nil {
+			grandparent, gone := parents[*parent]
+			if !gone {
+				break
+			}
+			parent = grandparent
+		}
+		adopter := uint(0)
+		if parent != nil {
+			adopter = *parent
+		}
+		groups[adopter] = append(groups[adopter], child.ID)
+	}
+	return groups
+}
+
+// dependencyCycle returns the error for adding a dependency of taskID on
+// blockerID when path leads from blockerID back to taskID.
+func dependencyCycle(taskID uint, path []uint) error {
+	steps := make([]string, 0, len(path)+1)
+	steps = append(steps, strconv.FormatUint(uint64(taskID), 10))
+	for _, id := range path {
+		steps = append(steps, strconv.FormatUint(uint64(id), 10))
+	}
+	return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(steps, " -> "))
+}
+
+// dependencyPath returns the shortest chain of blockers leading from one
+// task to another, both included, or nil when there is none.
+func dependencyPath(edges []models.TaskDependency, from, to uint) []uint {
+	blockers := make(map[uint][]uint)
+	for _, edge := range edges {
+		blockers[edge.TaskID] = append(blockers[edge.TaskID], edge.BlockerID)
+	}
+
+	previous := map[uint]uint{from: from}
+	for queue := []uint{from}; len(queue) > 0; queue = queue[1:] {
+		id := queue[0]
+		if id == to {
+			var path []uint
+			for ; id != from; id = previous[id] {
+				path = append(path, id)
+			}
+			path = append(path, from)
+			slices.Reverse(path)
+			return path
+		}
+		for _, blocker := range blockers[id] {
+			if _, seen := previous[blocker]; !seen {
+				previous[blocker] = id
+				queue = append(queue, blocker)
+			}
+		}
+	}
+	return nil
 }
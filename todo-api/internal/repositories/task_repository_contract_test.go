@@ -0,0 +1,365 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/migrations"
+	"todo-api/pkg/clock"
+	"todo-api/pkg/database"
+	"todo-api/pkg/migrator"
+)
+
+// setupSQLiteDB returns a fresh in-memory SQLite database with all
+// migrations applied.
+func setupSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	return setupSQLiteDBAt(t, clock.Real())
+}
+
+// setupSQLiteDBAt is setupSQLiteDB with the time taken from clk.
+func setupSQLiteDBAt(t *testing.T, clk clock.Clock) *gorm.DB {
+	t.Helper()
+
+	db, err := database.ConnectSQLite(":memory:", clk)
+	require.NoError(t, err)
+
+	source, err := migrations.Source("sqlite")
+	require.NoError(t, err)
+	m, err := migrator.New(db, source)
+	require.NoError(t, err)
+	_, err = m.Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+// repositoryFactories lists every TaskRepository implementation that must
+// behave the same way. The gorm implementation is exercised on SQLite.
+func repositoryFactories() map[string]func(t *testing.T) repositories.TaskRepository {
+	return map[string]func(t *testing.T) repositories.TaskRepository{
+		"memory": func(t *testing.T) repositories.TaskRepository {
+			return repositories.NewTaskRepositoryMemory(repositories.NewMemoryStore(clock.Real()))
+		},
+		"sqlite": func(t *testing.T) repositories.TaskRepository {
+			return repositories.NewTaskRepositoryImpl(setupSQLiteDB(t))
+		},
+	}
+}
+
+func day(offset int) time.Time {
+	return time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC).AddDate(0, 0, offset)
+}
+
+func TestTaskRepository_Contract(t *testing.T) {
+	for name, newRepo := range repositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("create and get", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Write report", Description: "Q1", Date: day(0)}
+
+				// Act
+				err := repo.Create(ctx, task)
+				require.NoError(t, err)
+				found, err := repo.GetByID(ctx, task.ID)
+
+				// Assert
+				require.NoError(t, err)
+				assert.NotZero(t, task.ID)
+				assert.Equal(t, "Write report", found.Title)
+				assert.Equal(t, "Q1", found.Description)
+				assert.True(t, day(0).Equal(found.Date))
+				assert.False(t, found.Completed)
+			})
+
+			t.Run("get missing", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+
+				// Act
+				_, err := repo.GetByID(context.Background(), 42)
+
+				// Assert
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("update", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Old", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, task))
+				newDate := day(1)
+
+				// Act
+				err := repo.Update(ctx, task.ID, 0, map[string]interface{}{
+					"title":     "New",
+					"completed": true,
+					"date":      &newDate,
+				})
+				require.NoError(t, err)
+				found, err := repo.GetByID(ctx, task.ID)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, "New", found.Title)
+				assert.True(t, found.Completed)
+				assert.True(t, newDate.Equal(found.Date))
+			})
+
+			t.Run("conditional writes", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Shared", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, task))
+				require.Equal(t, uint(1), task.Version)
+
+				// Act
+				firstErr := repo.Update(ctx, task.ID, 1, map[string]interface{}{"title": "Mine"})
+				staleErr := repo.Update(ctx, task.ID, 1, map[string]interface{}{"title": "Theirs"})
+				staleDeleteErr := repo.Delete(ctx, task.ID, 1, false)
+				found, getErr := repo.GetByID(ctx, task.ID)
+
+				// Assert
+				assert.NoError(t, firstErr)
+				assert.ErrorIs(t, staleErr, repositories.ErrVersionMismatch)
+				assert.ErrorIs(t, staleDeleteErr, repositories.ErrVersionMismatch)
+				require.NoError(t, getErr)
+				assert.Equal(t, "Mine", found.Title)
+				assert.Equal(t, uint(2), found.Version)
+				assert.NoError(t, repo.Delete(ctx, task.ID, 2, false))
+			})
+
+			t.Run("update and delete missing", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+
+				// Act
+				updateErr := repo.Update(ctx, 42, 0, map[string]interface{}{"title": "New"})
+				deleteErr := repo.Delete(ctx, 42, 0, false)
+
+				// Assert
+				assert.ErrorIs(t, updateErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, deleteErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("delete is soft", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				task := &models.Task{Title: "Gone", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, task))
+
+				// Act
+				err := repo.Delete(ctx, task.ID, 0, false)
+				require.NoError(t, err)
+				_, getErr := repo.GetByID(ctx, task.ID)
+				tasks, listErr := repo.List(ctx, dto.TaskFilter{Limit: 10})
+
+				// Assert
+				assert.ErrorIs(t, getErr, gorm.ErrRecordNotFound)
+				assert.NoError(t, listErr)
+				assert.Empty(t, tasks)
+			})
+
+			t.Run("list with filters", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				for i, completed := range []bool{false, true, true, false} {
+					task := &models.Task{Title: "Task", Date: day(i), Completed: completed}
+					require.NoError(t, repo.Create(ctx, task))
+				}
+				completed := true
+				from, to := day(1), day(3)
+
+				// Act
+				tasks, err := repo.List(ctx, dto.TaskFilter{
+					Completed: &completed,
+					DateFrom:  &from,
+					DateTo:    &to,
+					Limit:     1,
+					Offset:    1,
+				})
+
+				// Assert
+				require.NoError(t, err)
+				require.Len(t, tasks, 1)
+				assert.True(t, day(2).Equal(tasks[0].Date))
+			})
+
+			t.Run("keyset pagination", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				// Inserted out of order; list order is (date, id).
+				for _, offset := range []int{2, 0, 1, 0, 2} {
+					require.NoError(t, repo.Create(ctx, &models.Task{Title: "Task", Date: day(offset)}))
+				}
+				all, err := repo.List(ctx, dto.TaskFilter{Limit: 10})
+				require.NoError(t, err)
+				require.Len(t, all, 5)
+				assert.Equal(t, []uint{2, 4, 3, 1, 5}, taskIDs(all))
+
+				// Act
+				next, nextErr := repo.List(ctx, dto.TaskFilter{Limit: 2, Cursor: dto.CursorAfter(all[1], dto.DefaultTaskSort())})
+				prev, prevErr := repo.List(ctx, dto.TaskFilter{Limit: 2, Cursor: dto.CursorBefore(all[3], dto.DefaultTaskSort())})
+				total, countErr := repo.Count(ctx, dto.TaskFilter{Limit: 2, Cursor: dto.CursorAfter(all[1], dto.DefaultTaskSort())})
+
+				// Assert
+				require.NoError(t, nextErr)
+				require.NoError(t, prevErr)
+				require.NoError(t, countErr)
+				assert.Equal(t, []uint{3, 1}, taskIDs(next))
+				assert.Equal(t, []uint{3, 4}, taskIDs(prev))
+				assert.Equal(t, int64(5), total)
+			})
+
+			t.Run("sorted keyset pagination", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				for _, task := range []models.Task{
+					{Title: "b", Date: day(0)},
+					{Title: "a", Date: day(0)},
+					{Title: "b", Date: day(1)},
+					{Title: "a", Date: day(1)},
+				} {
+					require.NoError(t, repo.Create(ctx, &task))
+				}
+				order, err := dto.ParseTaskSort("title,-date")
+				require.NoError(t, err)
+
+				// Act
+				all, allErr := repo.List(ctx, dto.TaskFilter{Limit: 10, Sort: order})
+				next, nextErr := repo.List(ctx, dto.TaskFilter{Limit: 2, Cursor: dto.CursorAfter(all[0], order)})
+				prev, prevErr := repo.List(ctx, dto.TaskFilter{Limit: 2, Cursor: dto.CursorBefore(all[3], order)})
+
+				// Assert
+				require.NoError(t, allErr)
+				require.NoError(t, nextErr)
+				require.NoError(t, prevErr)
+				assert.Equal(t, []uint{4, 2, 3, 1}, taskIDs(all))
+				assert.Equal(t, []uint{2, 3}, taskIDs(next))
+				assert.Equal(t, []uint{3, 2}, taskIDs(prev))
+			})
+
+			t.Run("priority filter and sort", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				for _, priority := range []models.Priority{models.PriorityLow, models.PriorityUrgent, models.PriorityNone, models.PriorityHigh} {
+					require.NoError(t, repo.Create(ctx, &models.Task{Title: "Task", Date: day(0), Priority: priority}))
+				}
+				order, err := dto.ParseTaskSort("-priority")
+				require.NoError(t, err)
+
+				// Act
+				tasks, err := repo.List(ctx, dto.TaskFilter{
+					Limit:      10,
+					Sort:       order,
+					Priorities: []models.Priority{models.PriorityLow, models.PriorityHigh, models.PriorityUrgent},
+				})
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, []uint{2, 4, 1}, taskIDs(tasks))
+				assert.Equal(t, models.PriorityUrgent, tasks[0].Priority)
+			})
+
+			t.Run("manual order within a day", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				for _, rank := range []string{"r", "", "i", "r"} {
+					require.NoError(t, repo.Create(ctx, &models.Task{Title: "Task", Date: day(0), Rank: rank}))
+				}
+				require.NoError(t, repo.Create(ctx, &models.Task{Title: "Other day", Date: day(1), Rank: "z"}))
+
+				// Act
+				last, lastErr := repo.LastRank(ctx, day(0))
+				empty, emptyErr := repo.LastRank(ctx, day(2))
+				setErr := repo.SetRanks(ctx, map[uint]string{2: "1", 4: "s"})
+				tasks, listErr := repo.ListByDate(ctx, day(0))
+
+				// Assert
+				require.NoError(t, lastErr)
+				require.NoError(t, emptyErr)
+				require.NoError(t, setErr)
+				require.NoError(t, listErr)
+				assert.Equal(t, "r", last)
+				assert.Equal(t, "", empty)
+				assert.Equal(t, []uint{2, 3, 1, 4}, taskIDs(tasks))
+				assert.Equal(t, uint(2), tasks[0].Version)
+				assert.Equal(t, uint(1), tasks[1].Version)
+			})
+
+			t.Run("search", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				for _, task := range []models.Task{
+					{Title: "Buy milk", Description: "and bread", Date: day(0)},
+					{Title: "Bake bread", Description: "for dinner", Date: day(1)},
+					{Title: "Call mom", Date: day(2)},
+				} {
+					require.NoError(t, repo.Create(ctx, &task))
+				}
+				filter := dto.TaskFilter{Query: "BREAD", Highlight: true, Limit: 1}
+
+				// Act
+				first, firstErr := repo.List(ctx, filter)
+				require.NoError(t, firstErr)
+				require.Len(t, first, 1)
+				filter.Cursor = dto.CursorAfter(first[0], dto.SearchTaskSort())
+				second, secondErr := repo.List(ctx, filter)
+				total, countErr := repo.Count(ctx, filter)
+
+				// Assert
+				require.NoError(t, secondErr)
+				require.NoError(t, countErr)
+				assert.Equal(t, uint(2), first[0].ID)
+				assert.Equal(t, "Bake <mark>bread</mark> for dinner", first[0].Snippet)
+				assert.Equal(t, []uint{1}, taskIDs(second))
+				assert.Greater(t, first[0].Relevance, second[0].Relevance)
+				assert.Equal(t, int64(2), total)
+			})
+
+			t.Run("search ignores the case of any letter", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				report := &models.Task{Title: "Отчёт за квартал", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, report))
+				require.NoError(t, repo.Create(ctx, &models.Task{Title: "Позвонить маме", Date: day(0)}))
+
+				// Act
+				tasks, err := repo.List(ctx, dto.TaskFilter{Query: "отчёт", Limit: 10})
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, []uint{report.ID}, taskIDs(tasks))
+			})
+		})
+	}
+}
+
+func taskIDs(tasks []models.Task) []uint {
+	ids := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
@@ -2,63 +2,1400 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"todo-api/internal/dto"
 	"todo-api/internal/models"
 )
 
+// subtreeQuery selects the IDs of the live descendants of a task.
+const subtreeQuery = "WITH RECURSIVE subtree(id) AS (" +
+	"SELECT id FROM tasks WHERE parent_id = ? AND deleted_at IS NULL " +
+	"UNION ALL " +
+	"SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id WHERE tasks.deleted_at IS NULL" +
+	") SELECT id FROM subtree"
+
+// subtreesQuery selects the IDs of the live descendants of several tasks.
+const subtreesQuery = "WITH RECURSIVE subtree(id) AS (" +
+	"SELECT id FROM tasks WHERE parent_id IN ? AND deleted_at IS NULL " +
+	"UNION ALL " +
+	"SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id WHERE tasks.deleted_at IS NULL" +
+	") SELECT id FROM subtree"
+
+// trashedSubtreeQuery selects the IDs of the descendants of a task that
+// are in the trash.
+const trashedSubtreeQuery = "WITH RECURSIVE subtree(id) AS (" +
+	"SELECT id FROM tasks WHERE parent_id = ? AND deleted_at IS NOT NULL " +
+	"UNION ALL " +
+	"SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id WHERE tasks.deleted_at IS NOT NULL" +
+	") SELECT id FROM subtree"
+
+// deletedWithQuery selects the IDs of the descendants of a task in the
+// trash that were deleted along with it, which are all marked at the same
+// time.
+const deletedWithQuery = "WITH RECURSIVE subtree(id, deleted_at) AS (" +
+	"SELECT id, deleted_at FROM tasks WHERE id = ? " +
+	"UNION ALL " +
+	"SELECT tasks.id, tasks.deleted_at FROM tasks JOIN subtree " +
+	"ON tasks.parent_id = subtree.id AND tasks.deleted_at = subtree.deleted_at" +
+	") SELECT id FROM subtree WHERE id <> ?"
+
+// reachableDependenciesQuery selects every dependency reachable from a task
+// by following blockers, soft-deleted tasks included so that restoring one
+// cannot close a loop.
+const reachableDependenciesQuery = "WITH RECURSIVE reachable(task_id, blocker_id) AS (" +
+	"SELECT task_id, blocker_id FROM task_dependencies WHERE task_id = ? " +
+	"UNION " +
+	"SELECT task_dependencies.task_id, task_dependencies.blocker_id FROM task_dependencies " +
+	"JOIN reachable ON task_dependencies.task_id = reachable.blocker_id" +
+	") SELECT task_id, blocker_id FROM reachable"
+
+// createBatchSize is how many tasks CreateMany inserts per statement.
+const createBatchSize = 100
+
+// dependencyLockKey is the Postgres advisory lock serialising dependency
+// inserts, so that two concurrent ones cannot each close half of a loop.
+const dependencyLockKey = 7140
+
 type taskRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	postgres bool // full-text search uses tsvector instead of LIKE
 }
 
 func NewTaskRepositoryImpl(db *gorm.DB) TaskRepository {
-	return &taskRepository{db: db}
+	return &taskRepository{db: db, postgres: db.Dialector.Name() == "postgres"}
 }
 
 func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
-	return r.db.WithContext(ctx).Create(task).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createTask(tx, task); err != nil {
+			return err
+		}
+		if err := recordEvents(tx, models.EventTaskCreated, *task); err != nil {
+			return err
+		}
+		return recordHistory(tx, models.HistoryCreated, nil, []models.Task{*task})
+	})
+}
+
+// createTask inserts a task and attaches its tags, which must run in a
+// transaction when there are any.
+func createTask(db *gorm.DB, task *models.Task) error {
+	if task.Version == 0 {
+		task.Version = 1
+	}
+	if len(task.Tags) == 0 {
+		task.Tags = []models.Tag{}
+		return db.Create(task).Error
+	}
+
+	tags, err := findTags(db, tagNames(task.Tags))
+	if err != nil {
+		return err
+	}
+	task.Tags = tags
+	// Tags.* only writes the task_tags rows, never the tags themselves.
+	return db.Omit("Tags.*").Create(task).Error
 }
 
 func (r *taskRepository) GetByID(ctx context.Context, id uint) (*models.Task, error) {
 	var task models.Task
-	err := r.db.WithContext(ctx).First(&task, id).Error
-	if err != nil {
+	db := r.db.WithContext(ctx)
+	if err := db.Preload("Tags", orderTagsByName).First(&task, id).Error; err != nil {
 		return nil, err
 	}
-	return &task, nil
+	tasks := []models.Task{task}
+	if err := fillComputed(db, tasks); err != nil {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+func (r *taskRepository) Update(ctx context.Context, id uint, version uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.updateTask(tx, id, version, updates)
+	})
+}
+
+// updateTask is Update within the transaction tx.
+func (r *taskRepository) updateTask(tx *gorm.DB, id uint, version uint, updates map[string]interface{}) error {
+	changes, hasTags := updates[TagChangesKey].(TagChanges)
+	completeSubtasks, _ := updates[CompleteSubtasksKey].(bool)
+	due, dueChanged := dueAtUpdate(updates)
+
+	completing := false
+	if completed, _ := updates["completed"].(bool); completed {
+		var stored []bool
+		if err := tx.Model(&models.Task{}).Where("id = ?", id).Pluck("completed", &stored).Error; err != nil {
+			return err
+		}
+		completing = len(stored) == 1 && !stored[0]
+	}
+	var subtasks []uint
+	if completeSubtasks {
+		err := tx.Model(&models.Task{}).
+			Where("id IN (?) AND completed = ?", gorm.Expr(subtreeQuery, id), false).
+			Order("id").
+			Pluck("id", &subtasks).Error
+		if err != nil {
+			return err
+		}
+	}
+	before, err := loadTasks(tx, append([]uint{id}, subtasks...))
+	if err != nil {
+		return err
+	}
+
+	if err := r.update(tx, id, version, updates); err != nil {
+		return err
+	}
+	if len(subtasks) > 0 {
+		err := tx.Model(&models.Task{}).
+			Where("id IN ?", subtasks).
+			Updates(map[string]interface{}{"completed": true, "version": gorm.Expr("version + 1")}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if dueChanged {
+		if err := rescheduleReminders(tx, id, due); err != nil {
+			return err
+		}
+	}
+	if hasTags {
+		if err := changeTags(tx, []uint{id}, changes); err != nil {
+			return err
+		}
+	}
+
+	tasks, err := loadTasks(tx, append([]uint{id}, subtasks...))
+	if err != nil {
+		return err
+	}
+	if err = recordEvents(tx, models.EventTaskUpdated, tasks...); err != nil {
+		return err
+	}
+	if err = recordHistory(tx, models.HistoryUpdated, before, tasks); err != nil {
+		return err
+	}
+	if !completing {
+		tasks = tasks[1:]
+	}
+	return recordEvents(tx, models.EventTaskCompleted, tasks...)
+}
+
+func (r *taskRepository) update(db *gorm.DB, id uint, version uint, updates map[string]interface{}) error {
+	query := db.Model(&models.Task{}).Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Updates(columnUpdates(updates))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missOrMismatch(db, id)
+	}
+	return nil
+}
+
+// columnUpdates returns the column values of an Update map, leaving out
+// the special keys, with the version bumped.
+func columnUpdates(updates map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(updates)+1)
+	for column, value := range updates {
+		switch column {
+		case TagChangesKey, CompleteSubtasksKey, DueTimesKey:
+		case "date", "occurrence_date":
+			values[column] = dayArg(value)
+		default:
+			values[column] = value
+		}
+	}
+	values["version"] = gorm.Expr("version + 1")
+	return values
 }
 
-func (r *taskRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
-	return r.db.WithContext(ctx).
-		Model(&models.Task{}).
-		Where("id = ?", id).
-		Updates(updates).Error
+func (r *taskRepository) Delete(ctx context.Context, id uint, version uint, cascade bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.deleteTask(tx, id, version, cascade)
+	})
 }
 
-func (r *taskRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Task{}, id).Error
+// deleteTask is Delete within the transaction tx.
+func (r *taskRepository) deleteTask(tx *gorm.DB, id uint, version uint, cascade bool) error {
+	// Every task deleted here is marked at the same time, which is how
+	// Restore tells the subtasks deleted along with a task.
+	now := tx.NowFunc()
+	deleting := tx.Session(&gorm.Session{NowFunc: func() time.Time { return now }})
+
+	// The deleted events carry the tasks as they were.
+	ids := []uint{id}
+	if cascade {
+		var subtree []uint
+		if err := tx.Raw(subtreeQuery+" ORDER BY id", id).Scan(&subtree).Error; err != nil {
+			return err
+		}
+		ids = append(ids, subtree...)
+	}
+	var overrides []uint
+	if err := tx.Model(&models.Task{}).Where("series_id = ?", id).Order("id").Pluck("id", &overrides).Error; err != nil {
+		return err
+	}
+	deleted, err := loadTasks(tx, append(ids, overrides...))
+	if err != nil {
+		return err
+	}
+	if len(deleted) == 0 || deleted[0].ID != id {
+		return gorm.ErrRecordNotFound
+	}
+
+	var moved []uint
+	var movedBefore []models.Task
+	if len(ids) > 1 {
+		if err = deleting.Where("id IN ?", ids[1:]).Delete(&models.Task{}).Error; err != nil {
+			return err
+		}
+	} else if !cascade {
+		if err = tx.Model(&models.Task{}).Where("parent_id = ?", id).Order("id").Pluck("id", &moved).Error; err != nil {
+			return err
+		}
+		if movedBefore, err = loadTasks(tx, moved); err != nil {
+			return err
+		}
+		err = tx.Model(&models.Task{}).Where("parent_id = ?", id).
+			Updates(map[string]interface{}{"parent_id": deleted[0].ParentID, "version": gorm.Expr("version + 1")}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = deleting.Where("series_id = ?", id).Delete(&models.Task{}).Error; err != nil {
+		return err
+	}
+
+	query := deleting
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+	result := query.Delete(&models.Task{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missOrMismatch(tx, id)
+	}
+
+	updated, err := loadTasks(tx, moved)
+	if err != nil {
+		return err
+	}
+	if err = recordEvents(tx, models.EventTaskUpdated, updated...); err != nil {
+		return err
+	}
+	if err = recordEvents(tx, models.EventTaskDeleted, deleted...); err != nil {
+		return err
+	}
+	if err = recordHistory(tx, models.HistoryUpdated, movedBefore, updated); err != nil {
+		return err
+	}
+	return recordHistory(tx, models.HistoryDeleted, deleted, markDeleted(deleted, now))
+}
+
+func (r *taskRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Task, error) {
+	return loadTasks(r.db.WithContext(ctx), ids)
+}
+
+func (r *taskRepository) CreateMany(ctx context.Context, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var names []string
+		for i := range tasks {
+			names = append(names, tagNames(tasks[i].Tags)...)
+		}
+		slices.Sort(names)
+		names = slices.Compact(names)
+		var tags []models.Tag
+		if len(names) > 0 {
+			var err error
+			if tags, err = findTags(tx, names); err != nil {
+				return err
+			}
+		}
+
+		for i := range tasks {
+			if tasks[i].Version == 0 {
+				tasks[i].Version = 1
+			}
+			wanted := tagNames(tasks[i].Tags)
+			tasks[i].Tags = []models.Tag{}
+			for _, tag := range tags {
+				if slices.Contains(wanted, tag.Name) {
+					tasks[i].Tags = append(tasks[i].Tags, tag)
+				}
+			}
+		}
+		// Tags.* only writes the task_tags rows, never the tags themselves.
+		if err := tx.Omit("Tags.*").CreateInBatches(&tasks, createBatchSize).Error; err != nil {
+			return err
+		}
+
+		if err := recordEvents(tx, models.EventTaskCreated, tasks...); err != nil {
+			return err
+		}
+		return recordHistory(tx, models.HistoryCreated, nil, tasks)
+	})
+}
+
+func (r *taskRepository) UpdateMany(ctx context.Context, ids []uint, updates map[string]interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	changes, hasTags := updates[TagChangesKey].(TagChanges)
+	completeSubtasks, _ := updates[CompleteSubtasksKey].(bool)
+	completed, _ := updates["completed"].(bool)
+	dueTimes, _ := updates[DueTimesKey].(DueTimes)
+	due, dueChanged := dueAtUpdate(updates)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := loadTasks(tx, ids)
+		if err != nil {
+			return err
+		}
+		if len(before) != len(ids) {
+			return gorm.ErrRecordNotFound
+		}
+		var subtasks []uint
+		if completeSubtasks {
+			err := tx.Model(&models.Task{}).
+				Where("id IN (?) AND id NOT IN ? AND completed = ?", gorm.Expr(subtreesQuery, ids), ids, false).
+				Order("id").
+				Pluck("id", &subtasks).Error
+			if err != nil {
+				return err
+			}
+		}
+		subtasksBefore, err := loadTasks(tx, subtasks)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Model(&models.Task{}).Where("id IN ?", ids).Updates(columnUpdates(updates)).Error; err != nil {
+			return err
+		}
+		if len(subtasks) > 0 {
+			err := tx.Model(&models.Task{}).
+				Where("id IN ?", subtasks).
+				Updates(map[string]interface{}{"completed": true, "version": gorm.Expr("version + 1")}).Error
+			if err != nil {
+				return err
+			}
+		}
+		// Due times differ from task to task: one statement each.
+		for _, id := range ids {
+			taskDue, ok := dueTimes[id]
+			if !ok {
+				if !dueChanged {
+					continue
+				}
+				taskDue = due
+			} else if err := tx.Model(&models.Task{}).Where("id = ?", id).Update("due_at", taskDue).Error; err != nil {
+				return err
+			}
+			if err := rescheduleReminders(tx, id, taskDue); err != nil {
+				return err
+			}
+		}
+		if hasTags {
+			if err := changeTags(tx, ids, changes); err != nil {
+				return err
+			}
+		}
+
+		tasks, err := loadTasks(tx, append(slices.Clone(ids), subtasks...))
+		if err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskUpdated, tasks...); err != nil {
+			return err
+		}
+		if err = recordHistory(tx, models.HistoryUpdated, append(before, subtasksBefore...), tasks); err != nil {
+			return err
+		}
+		var completing []models.Task
+		for i := range tasks {
+			if i >= len(ids) || completed && !before[i].Completed {
+				completing = append(completing, tasks[i])
+			}
+		}
+		return recordEvents(tx, models.EventTaskCompleted, completing...)
+	})
+}
+
+func (r *taskRepository) DeleteMany(ctx context.Context, ids []uint, cascade bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The deleted events carry the tasks as they were.
+		deleting := slices.Clone(ids)
+		if cascade {
+			var subtree []uint
+			if err := tx.Raw(subtreesQuery+" ORDER BY id", ids).Scan(&subtree).Error; err != nil {
+				return err
+			}
+			for _, id := range subtree {
+				if !slices.Contains(deleting, id) {
+					deleting = append(deleting, id)
+				}
+			}
+		}
+		var overrides []uint
+		err := tx.Model(&models.Task{}).
+			Where("series_id IN ? AND id NOT IN ?", deleting, deleting).
+			Order("id").
+			Pluck("id", &overrides).Error
+		if err != nil {
+			return err
+		}
+		deleted, err := loadTasks(tx, append(slices.Clone(deleting), overrides...))
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if i >= len(deleted) || deleted[i].ID != id {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		var moved []uint
+		var movedBefore []models.Task
+		if !cascade {
+			var children []models.Task
+			err := tx.Select("id", "parent_id").
+				Where("parent_id IN ? AND id NOT IN ?", deleting, deleting).
+				Order("id").
+				Find(&children).Error
+			if err != nil {
+				return err
+			}
+			for _, child := range children {
+				moved = append(moved, child.ID)
+			}
+			if movedBefore, err = loadTasks(tx, moved); err != nil {
+				return err
+			}
+			groups := adoptingParents(deleted, children)
+			for _, parent := range slices.Sorted(maps.Keys(groups)) {
+				var parentID *uint
+				if parent != 0 {
+					parentID = &parent
+				}
+				err := tx.Model(&models.Task{}).Where("id IN ?", groups[parent]).
+					Updates(map[string]interface{}{"parent_id": parentID, "version": gorm.Expr("version + 1")}).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		now := tx.NowFunc()
+		err = tx.Session(&gorm.Session{NowFunc: func() time.Time { return now }}).
+			Where("id IN ?", append(deleting, overrides...)).
+			Delete(&models.Task{}).Error
+		if err != nil {
+			return err
+		}
+
+		updated, err := loadTasks(tx, moved)
+		if err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskUpdated, updated...); err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskDeleted, deleted...); err != nil {
+			return err
+		}
+		if err = recordHistory(tx, models.HistoryUpdated, movedBefore, updated); err != nil {
+			return err
+		}
+		return recordHistory(tx, models.HistoryDeleted, deleted, markDeleted(deleted, now))
+	})
+}
+
+// missOrMismatch explains why a conditional write touched no rows.
+func missOrMismatch(db *gorm.DB, id uint) error {
+	var count int64
+	if err := db.Model(&models.Task{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrVersionMismatch
 }
 
 func (r *taskRepository) List(ctx context.Context, filter dto.TaskFilter) ([]models.Task, error) {
 	var tasks []models.Task
-	query := r.db.WithContext(ctx).Model(&models.Task{})
+	query := r.applyTaskFilter(r.db.WithContext(ctx).Model(&models.Task{}), filter)
+
+	terms := searchTerms(filter.Query)
+	if len(terms) > 0 {
+		relevance, args := r.relevanceExpr(filter.Query, terms)
+		columns := "tasks.*, " + relevance + " AS relevance"
+		if filter.Highlight && r.postgres {
+			columns += ", ts_headline('russian', title || ' ' || coalesce(description, ''), " + pgSearchQuery +
+				", 'StartSel=" + highlightStart + ", StopSel=" + highlightStop + "') AS snippet"
+			args = append(args, filter.Query, filter.Query)
+		}
+		query = query.Select(columns, args...)
+	}
+
+	order := filter.OrderOrDefault()
+	backward := false
+	if filter.Cursor != nil {
+		// A cursor carries the order it was issued for.
+		order, backward = filter.Cursor.Sort, filter.Cursor.Backward
+		condition, args := r.keysetCondition(filter, terms)
+		query = query.Where(condition, args...)
+	} else if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	columns := make([]string, len(order))
+	for i, f := range order {
+		columns[i] = f.Field
+		if f.Desc != backward {
+			columns[i] += " DESC"
+		}
+	}
+
+	query = query.Preload("Tags", orderTagsByName)
+	if err := query.Order(strings.Join(columns, ", ")).Limit(filter.Limit).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := fillComputed(r.db.WithContext(ctx), tasks); err != nil {
+		return nil, err
+	}
+
+	if filter.Highlight && !r.postgres && len(terms) > 0 {
+		for i := range tasks {
+			tasks[i].Snippet = searchSnippet(&tasks[i], terms)
+		}
+	}
+	return tasks, nil
+}
+
+// keysetCondition selects the rows past the filter's cursor in its sort
+// order, expanded as "a > ? OR (a = ? AND b > ?) OR ..." so mixed directions
+// work everywhere. Field names come from the sort allowlist and are safe to
+// inline.
+func (r *taskRepository) keysetCondition(filter dto.TaskFilter, terms []string) (string, []interface{}) {
+	cursor := filter.Cursor
+	column := func(field string) (string, []interface{}) {
+		if field == "relevance" {
+			return r.relevanceExpr(filter.Query, terms)
+		}
+		return field, nil
+	}
+
+	value := func(j int) interface{} {
+		if cursor.Sort[j].Field == "date" {
+			return dayArg(cursor.Values[j])
+		}
+		return cursor.Values[j]
+	}
+
+	var disjuncts []string
+	var args []interface{}
+	for i, f := range cursor.Sort {
+		op := ">"
+		if f.Desc != cursor.Backward {
+			op = "<"
+		}
+
+		var parts []string
+		for j := 0; j <= i; j++ {
+			expr, exprArgs := column(cursor.Sort[j].Field)
+			if j < i {
+				parts = append(parts, expr+" = ?")
+			} else {
+				parts = append(parts, expr+" "+op+" ?")
+			}
+			args = append(append(args, exprArgs...), value(j))
+		}
+
+		if i == 0 {
+			disjuncts = append(disjuncts, parts[0])
+		} else {
+			disjuncts = append(disjuncts, "("+strings.Join(parts, " AND ")+")")
+		}
+	}
+	return strings.Join(disjuncts, " OR "), args
+}
+
+// relevanceExpr is the SQL expression ranking a row against a search query.
+func (r *taskRepository) relevanceExpr(query string, terms []string) (string, []interface{}) {
+	if r.postgres {
+		return "ts_rank(search, " + pgSearchQuery + ")", []interface{}{query, query}
+	}
+	return likeRelevance(terms)
+}
+
+func (r *taskRepository) Count(ctx context.Context, filter dto.TaskFilter) (int64, error) {
+	var total int64
+	err := r.applyTaskFilter(r.db.WithContext(ctx).Model(&models.Task{}), filter).Count(&total).Error
+	return total, err
+}
+
+func (r *taskRepository) ListByDate(ctx context.Context, date time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	db := r.db.WithContext(ctx)
+	if err := db.Preload("Tags", orderTagsByName).Where("date = ?", dayArg(date)).Order("rank, id").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, fillComputed(db, tasks)
+}
+
+func (r *taskRepository) LastRank(ctx context.Context, date time.Time) (string, error) {
+	var rank string
+	err := r.db.WithContext(ctx).Model(&models.Task{}).
+		Where("date = ?", dayArg(date)).
+		Select("COALESCE(MAX(rank), '')").
+		Scan(&rank).Error
+	return rank, err
+}
+
+func (r *taskRepository) SetRanks(ctx context.Context, ranks map[uint]string) error {
+	ids := make([]uint, 0, len(ranks))
+	for id := range ranks {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := loadTasks(tx, ids)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			result := tx.Model(&models.Task{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"rank":    ranks[id],
+				"version": gorm.Expr("version + 1"),
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		tasks, err := loadTasks(tx, ids)
+		if err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskUpdated, tasks...); err != nil {
+			return err
+		}
+		return recordHistory(tx, models.HistoryUpdated, before, tasks)
+	})
+}
+
+func (r *taskRepository) ListChildren(ctx context.Context, parentIDs []uint) ([]models.Task, error) {
+	var tasks []models.Task
+	db := r.db.WithContext(ctx)
+	err := db.Preload("Tags", orderTagsByName).
+		Where("parent_id IN ?", parentIDs).
+		Order("date, rank, id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, fillComputed(db, tasks)
+}
+
+func (r *taskRepository) ListOverrides(ctx context.Context, seriesIDs []uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("series_id IN ?", seriesIDs).
+		Order("occurrence_date, id").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *taskRepository) SplitSeries(ctx context.Context, id uint, version uint, updates map[string]interface{}, next *models.Task) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var moving []uint
+		err := tx.Model(&models.Task{}).
+			Where("series_id = ? AND occurrence_date >= ?", id, dayArg(next.Date)).
+			Order("id").
+			Pluck("id", &moving).Error
+		if err != nil {
+			return err
+		}
+		before, err := loadTasks(tx, append([]uint{id}, moving...))
+		if err != nil {
+			return err
+		}
+
+		if err := r.update(tx, id, version, updates); err != nil {
+			return err
+		}
+		if err := createTask(tx, next); err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&models.Task{}).
+			Where("series_id = ? AND occurrence_date >= ?", id, dayArg(next.Date)).
+			Updates(map[string]interface{}{"series_id": next.ID, "version": gorm.Expr("version + 1")}).Error
+		if err != nil {
+			return err
+		}
+
+		var moved []uint
+		if err = tx.Model(&models.Task{}).Where("series_id = ?", next.ID).Order("id").Pluck("id", &moved).Error; err != nil {
+			return err
+		}
+		series, err := loadTasks(tx, []uint{id})
+		if err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskUpdated, series...); err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskCreated, *next); err != nil {
+			return err
+		}
+		overrides, err := loadTasks(tx, moved)
+		if err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskUpdated, overrides...); err != nil {
+			return err
+		}
+		if err = recordHistory(tx, models.HistoryUpdated, before, append(series, overrides...)); err != nil {
+			return err
+		}
+		return recordHistory(tx, models.HistoryCreated, nil, []models.Task{*next})
+	})
+}
+
+func (r *taskRepository) ExcludeOccurrence(ctx context.Context, id uint, version uint, updates map[string]interface{}, occurrenceID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.updateTask(tx, id, version, updates); err != nil {
+			return err
+		}
+		if occurrenceID == 0 {
+			return nil
+		}
+		return r.deleteTask(tx, occurrenceID, 0, false)
+	})
+}
+
+func (r *taskRepository) StoreOccurrence(ctx context.Context, occurrence *models.Task, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createTask(tx, occurrence); err != nil {
+			return err
+		}
+		if err := recordEvents(tx, models.EventTaskCreated, *occurrence); err != nil {
+			return err
+		}
+		if err := recordHistory(tx, models.HistoryCreated, nil, []models.Task{*occurrence}); err != nil {
+			return err
+		}
+		return r.updateTask(tx, occurrence.ID, 0, updates)
+	})
+}
+
+func (r *taskRepository) GetTrashed(ctx context.Context, id uint) (*models.Task, error) {
+	var task models.Task
+	db := r.db.WithContext(ctx)
+	if err := trashed(db).Preload("Tags", orderTagsByName).First(&task, id).Error; err != nil {
+		return nil, err
+	}
+	tasks := []models.Task{task}
+	if err := fillComputed(db, tasks); err != nil {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+func (r *taskRepository) Restore(ctx context.Context, id uint, version uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := trashed(tx).First(&task, id).Error; err != nil {
+			return err
+		}
+		if task.ParentID != nil {
+			var count int64
+			if err := tx.Model(&models.Task{}).Where("id = ?", *task.ParentID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return fmt.Errorf("%w: restore task %d first", ErrParentTrashed, *task.ParentID)
+			}
+		}
+
+		var overrides []models.Task
+		if err := tx.Unscoped().Where("series_id = ? AND deleted_at IS NOT NULL", id).Order("id").Find(&overrides).Error; err != nil {
+			return err
+		}
+		var subtree []uint
+		if err := tx.Raw(deletedWithQuery+" ORDER BY id", id, id).Scan(&subtree).Error; err != nil {
+			return err
+		}
+		ids := append([]uint{id}, subtree...)
+		for _, override := range overrides {
+			// Excluded days were deleted on their own, not with the series.
+			if !task.ExDates.Contains(*override.OccurrenceDate) {
+				ids = append(ids, override.ID)
+			}
+		}
+
+		before, err := loadTasks(tx.Unscoped().Session(&gorm.Session{}), ids)
+		if err != nil {
+			return err
+		}
+
+		restore := map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}
+		query := trashed(tx).Model(&models.Task{}).Where("id = ?", id)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+		result := query.Updates(restore)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionMismatch
+		}
+		if len(ids) > 1 {
+			if err := tx.Unscoped().Model(&models.Task{}).Where("id IN ?", ids[1:]).Updates(restore).Error; err != nil {
+				return err
+			}
+		}
+
+		restored, err := loadTasks(tx, ids)
+		if err != nil {
+			return err
+		}
+		if err = recordEvents(tx, models.EventTaskRestored, restored...); err != nil {
+			return err
+		}
+		return recordHistory(tx, models.HistoryRestored, before, restored)
+	})
+}
+
+func (r *taskRepository) Purge(ctx context.Context, id uint, version uint, cascade bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []uint{id}
+		if cascade {
+			var subtree []uint
+			if err := tx.Raw(trashedSubtreeQuery+" ORDER BY id", id).Scan(&subtree).Error; err != nil {
+				return err
+			}
+			ids = append(ids, subtree...)
+		}
+		purged, err := loadPurged(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) > 1 {
+			if err = tx.Unscoped().Where("id IN ?", ids[1:]).Delete(&models.Task{}).Error; err != nil {
+				return err
+			}
+		}
+
+		query := trashed(tx)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+		// Stored occurrences go along by ON DELETE CASCADE.
+		result := query.Delete(&models.Task{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := trashed(tx).Model(&models.Task{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			return ErrVersionMismatch
+		}
+		return recordPurged(tx, purged)
+	})
+}
+
+func (r *taskRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := trashed(tx).Model(&models.Task{}).Where("deleted_at < ?", before).Order("id").Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		purged, err := loadPurged(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Task{})
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+		return recordPurged(tx, purged)
+	})
+	return count, err
+}
+
+// loadPurged returns the tasks with ids, followed by the stored occurrences
+// that go with them by ON DELETE CASCADE, as they are before a purge.
+func loadPurged(tx *gorm.DB, ids []uint) ([]models.Task, error) {
+	var occurrences []uint
+	if err := tx.Unscoped().Model(&models.Task{}).Where("series_id IN ?", ids).Order("id").Pluck("id", &occurrences).Error; err != nil {
+		return nil, err
+	}
+	return loadTasks(tx.Unscoped().Session(&gorm.Session{}), append(slices.Clone(ids), occurrences...))
+}
+
+// recordPurged records task.purged and a history entry for each of the
+// purged tasks. Their history is kept: task_history does not reference
+// tasks, so that it outlives them.
+func recordPurged(tx *gorm.DB, purged []models.Task) error {
+	if err := recordEvents(tx, models.EventTaskPurged, purged...); err != nil {
+		return err
+	}
+	return recordHistory(tx, models.HistoryPurged, purged, purged)
+}
+
+// trashed starts a query over the tasks in the trash: deleted ones other
+// than stored occurrences.
+func trashed(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("tasks.deleted_at IS NOT NULL AND tasks.series_id IS NULL")
+}
+
+func (r *taskRepository) AddDependency(ctx context.Context, taskID uint, blockerID uint) error {
+	if taskID == blockerID {
+		return dependencyCycle(taskID, []uint{taskID})
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if r.postgres {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", dependencyLockKey).Error; err != nil {
+				return err
+			}
+		}
+
+		var count int64
+		if err := tx.Model(&models.Task{}).Where("id IN ?", []uint{taskID, blockerID}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count < 2 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var edges []models.TaskDependency
+		if err := tx.Raw(reachableDependenciesQuery, blockerID).Scan(&edges).Error; err != nil {
+			return err
+		}
+		if path := dependencyPath(edges, blockerID, taskID); path != nil {
+			return dependencyCycle(taskID, path)
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.TaskDependency{TaskID: taskID, BlockerID: blockerID}).Error
+	})
+}
+
+func (r *taskRepository) RemoveDependency(ctx context.Context, taskID uint, blockerID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("task_id = ? AND blocker_id = ?", taskID, blockerID).
+		Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *taskRepository) ListBlockers(ctx context.Context, taskID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	db := r.db.WithContext(ctx)
+	blockers := db.Session(&gorm.Session{NewDB: true}).
+		Model(&models.TaskDependency{}).
+		Select("blocker_id").
+		Where("task_id = ?", taskID)
+	err := db.Preload("Tags", orderTagsByName).
+		Where("id IN (?)", blockers).
+		Order("date, rank, id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, fillComputed(db, tasks)
+}
+
+func (r *taskRepository) applyTaskFilter(query *gorm.DB, filter dto.TaskFilter) *gorm.DB {
+	if filter.Trashed {
+		query = trashed(query)
+	}
 
 	if filter.Completed != nil {
 		query = query.Where("completed = ?", *filter.Completed)
 	}
 
 	if filter.DateFrom != nil || filter.DateTo != nil {
-		if filter.DateFrom != nil && filter.DateTo != nil {
-			query = query.Where("date BETWEEN ? AND ?", *filter.DateFrom, *filter.DateTo)
-		} else if filter.DateFrom != nil {
-			query = query.Where("date >= ?", *filter.DateFrom)
+		allDay, timed := []string{"tasks.due_at IS NULL"}, []string{"tasks.due_at IS NOT NULL"}
+		var allDayArgs, timedArgs []interface{}
+		from, to := dayBounds(filter)
+		if from != nil {
+			allDay, allDayArgs = append(allDay, "tasks.date >= ?"), append(allDayArgs, dayArg(*filter.DateFrom))
+			timed, timedArgs = append(timed, "tasks.due_at >= ?"), append(timedArgs, *from)
+		}
+		if to != nil {
+			allDay, allDayArgs = append(allDay, "tasks.date <= ?"), append(allDayArgs, dayArg(*filter.DateTo))
+			timed, timedArgs = append(timed, "tasks.due_at < ?"), append(timedArgs, *to)
+		}
+		query = query.Where("("+strings.Join(allDay, " AND ")+") OR ("+strings.Join(timed, " AND ")+")",
+			append(allDayArgs, timedArgs...)...)
+	}
+
+	if len(filter.Priorities) > 0 {
+		query = query.Where("priority IN ?", filter.Priorities)
+	}
+
+	if filter.Recurring != nil {
+		if *filter.Recurring {
+			query = query.Where("tasks.recurrence <> ''")
 		} else {
-			query = query.Where("date <= ?", *filter.DateTo)
+			query = query.Where("tasks.recurrence = ''")
 		}
 	}
 
-	err := query.Limit(filter.Limit).Offset(filter.Offset).Find(&tasks).Error
-	return tasks, err
+	if names := slices.Compact(slices.Sorted(slices.Values(filter.Tags))); len(names) > 0 {
+		tagged := query.Session(&gorm.Session{NewDB: true}).
+			Table("task_tags").
+			Select("task_tags.task_id").
+			Joins("JOIN tags ON tags.id = task_tags.tag_id").
+			Where("tags.name IN ?", names)
+		if filter.TagMode == dto.TagModeAll {
+			tagged = tagged.Group("task_tags.task_id").Having("COUNT(*) = ?", len(names))
+		}
+		query = query.Where("tasks.id IN (?)", tagged)
+	}
+
+	switch {
+	case filter.ProjectID == nil:
+		if !filter.IncludeArchived {
+			active := query.Session(&gorm.Session{NewDB: true}).
+				Model(&models.Project{}).
+				Select("id").
+				Where("archived = ?", false)
+			query = query.Where("tasks.project_id IS NULL OR tasks.project_id IN (?)", active)
+		}
+	case *filter.ProjectID == 0:
+		query = query.Where("tasks.project_id IS NULL")
+	default:
+		query = query.Where("tasks.project_id = ?", *filter.ProjectID)
+	}
+
+	if filter.ParentID != nil {
+		if *filter.ParentID == 0 {
+			query = query.Where("tasks.parent_id IS NULL")
+		} else {
+			query = query.Where("tasks.parent_id = ?", *filter.ParentID)
+		}
+	}
+
+	if filter.Blocked != nil {
+		blocked := openBlockers(query).Select("task_dependencies.task_id")
+		if *filter.Blocked {
+			query = query.Where("tasks.id IN (?)", blocked)
+		} else {
+			query = query.Where("tasks.id NOT IN (?)", blocked)
+		}
+	}
+
+	if terms := searchTerms(filter.Query); len(terms) > 0 {
+		if r.postgres {
+			query = query.Where("search @@ "+pgSearchQuery, filter.Query, filter.Query)
+		} else {
+			condition, args := likeMatch(terms)
+			query = query.Where(condition, args...)
+		}
+	}
+
+	return query
+}
+
+// dayArg binds a date as the calendar day it stands for, the way
+// models.DaySerializer stores task dates, rather than as a timestamp.
+func dayArg(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(models.DateFormat)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(models.DateFormat)
+	default:
+		return value
+	}
+}
+
+// loadTasks returns the live tasks with ids in the order of ids, complete
+// with tags and computed fields.
+func loadTasks(db *gorm.DB, ids []uint) ([]models.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var found []models.Task
+	if err := db.Preload("Tags", orderTagsByName).Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	if err := fillComputed(db, found); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Task, len(found))
+	for _, task := range found {
+		byID[task.ID] = task
+	}
+	tasks := make([]models.Task, 0, len(found))
+	for _, id := range ids {
+		if task, ok := byID[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// detachTasks takes the tasks with ids, live or in the trash, off a project
+// or tag about to be deleted, so that the foreign keys have nothing left to
+// change behind the tasks' backs: detach makes the change, and each task
+// gets a new version, task.updated and a history entry like any update.
+func detachTasks(tx *gorm.DB, ids []uint, detach func(tx *gorm.DB) error) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	before, err := loadTasks(tx.Unscoped().Session(&gorm.Session{}), ids)
+	if err != nil {
+		return err
+	}
+	if err = detach(tx); err != nil {
+		return err
+	}
+	err = tx.Unscoped().Model(&models.Task{}).Where("id IN ?", ids).Update("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return err
+	}
+
+	after, err := loadTasks(tx.Unscoped().Session(&gorm.Session{}), ids)
+	if err != nil {
+		return err
+	}
+	if err = recordEvents(tx, models.EventTaskUpdated, after...); err != nil {
+		return err
+	}
+	return recordHistory(tx, models.HistoryUpdated, before, after)
+}
+
+// recordEvents adds an event of type t about each of tasks to the outbox.
+// db must be the transaction that changed them.
+func recordEvents(db *gorm.DB, t models.EventType, tasks ...models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	archived, err := archivedProjects(db, tasks)
+	if err != nil {
+		return err
+	}
+	now := db.NowFunc()
+	events := make([]models.OutboxEvent, len(tasks))
+	for i, task := range tasks {
+		inArchived := task.ProjectID != nil && archived[*task.ProjectID]
+		if events[i], err = newOutboxEvent(t, task, inArchived, now); err != nil {
+			return err
+		}
+	}
+	return db.Create(&events).Error
+}
+
+// archivedProjects returns the set of the archived projects tasks are in.
+func archivedProjects(db *gorm.DB, tasks []models.Task) (map[uint]bool, error) {
+	var ids []uint
+	for _, task := range tasks {
+		if task.ProjectID != nil {
+			ids = append(ids, *task.ProjectID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var archived []uint
+	err := db.Model(&models.Project{}).Where("id IN ? AND archived = ?", ids, true).Pluck("id", &archived).Error
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[uint]bool, len(archived))
+	for _, id := range archived {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// fillComputed sets the fields of tasks that are derived from other rows.
+func fillComputed(db *gorm.DB, tasks []models.Task) error {
+	if err := fillProgress(db, tasks); err != nil {
+		return err
+	}
+	return fillBlocked(db, tasks)
+}
+
+// fillProgress sets the subtask counters of tasks from their live direct
+// subtasks.
+func fillProgress(db *gorm.DB, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	var rows []subtaskProgress
+	err := db.Model(&models.Task{}).
+		Select("parent_id, COUNT(*) AS total, COUNT(CASE WHEN completed THEN 1 END) AS completed").
+		Where("parent_id IN ?", ids).
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	progress := make(map[uint]subtaskProgress, len(rows))
+	for _, row := range rows {
+		progress[row.ParentID] = row
+	}
+	for i := range tasks {
+		tasks[i].SubtasksTotal = progress[tasks[i].ID].Total
+		tasks[i].SubtasksCompleted = progress[tasks[i].ID].Completed
+	}
+	return nil
+}
+
+// fillBlocked sets Blocked on the tasks that have a live open blocker.
+func fillBlocked(db *gorm.DB, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	var blocked []uint
+	err := openBlockers(db).
+		Distinct("task_dependencies.task_id").
+		Where("task_dependencies.task_id IN ?", ids).
+		Scan(&blocked).Error
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Blocked = slices.Contains(blocked, tasks[i].ID)
+	}
+	return nil
+}
+
+// openBlockers starts a query over the dependencies whose blocker is live
+// and not completed.
+func openBlockers(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("task_dependencies").
+		Joins("JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id").
+		Where("blockers.completed = ? AND blockers.deleted_at IS NULL", false)
+}
+
+type subtaskProgress struct {
+	ParentID  uint
+	Total     int64
+	Completed int64
+}
+
+// orderTagsByName is the Preload condition giving tasks their tags in name
+// order, which is also what the in-memory repository returns.
+func orderTagsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name")
+}
+
+// findTags resolves tag names, failing with ErrUnknownTag for any that does
+// not exist.
+func findTags(db *gorm.DB, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	if err := db.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		found[tag.Name] = true
+	}
+	var missing []string
+	for _, name := range names {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, unknownTagsError(missing)
+	}
+	return tags, nil
+}
+
+// changeTags applies the same tag changes to every task in taskIDs.
+func changeTags(db *gorm.DB, taskIDs []uint, changes TagChanges) error {
+	if len(changes.Attach) > 0 {
+		tags, err := findTags(db, changes.Attach)
+		if err != nil {
+			return err
+		}
+		rows := make([]models.TaskTag, 0, len(taskIDs)*len(tags))
+		for _, taskID := range taskIDs {
+			for _, tag := range tags {
+				rows = append(rows, models.TaskTag{TaskID: taskID, TagID: tag.ID})
+			}
+		}
+		if err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(changes.Detach) > 0 {
+		detached := db.Session(&gorm.Session{NewDB: true}).Model(&models.Tag{}).Select("id").Where("name IN ?", changes.Detach)
+		return db.Where("task_id IN ? AND tag_id IN (?)", taskIDs, detached).Delete(&models.TaskTag{}).Error
+	}
+	return nil
 }
@@ -0,0 +1,1202 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+// taskRepositoryMemory keeps tasks in process memory. It mirrors the
+// behaviour of the gorm implementation, including soft deletes and
+// gorm.ErrRecordNotFound for missing rows, so it can stand in for it in
+// development and tests.
+type taskRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewTaskRepositoryMemory(store *MemoryStore) TaskRepository {
+	return &taskRepositoryMemory{MemoryStore: store}
+}
+
+func (r *taskRepositoryMemory) Create(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.create(task); err != nil {
+		return err
+	}
+	if err := r.record(models.EventTaskCreated, *task); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryCreated, nil, []models.Task{*task})
+}
+
+func (r *taskRepositoryMemory) create(task *models.Task) error {
+	if err := r.checkReferences(task); err != nil {
+		return err
+	}
+
+	var tags []models.Tag
+	if len(task.Tags) > 0 {
+		var err error
+		if tags, err = r.tagsByName(tagNames(task.Tags)); err != nil {
+			return err
+		}
+	}
+
+	now := r.clock.Now()
+	task.ID = r.nextTaskID
+	if task.Version == 0 {
+		task.Version = 1
+	}
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	r.nextTaskID++
+
+	stored := *task
+	stored.Tags = nil
+	r.tasks[task.ID] = &stored
+
+	r.taskTags[task.ID] = make(map[uint]bool)
+	for _, tag := range tags {
+		r.taskTags[task.ID][tag.ID] = true
+	}
+	task.Tags = r.tagsOf(task.ID)
+	r.countSubtasks(task)
+	return nil
+}
+
+func (r *taskRepositoryMemory) GetByID(_ context.Context, id uint) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, ok := r.live(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := r.hydrated(task)
+	return &found, nil
+}
+
+func (r *taskRepositoryMemory) Update(ctx context.Context, id uint, version uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.updateTask(ctx, id, version, updates)
+}
+
+// updateTask is Update with the lock held.
+func (r *taskRepositoryMemory) updateTask(ctx context.Context, id uint, version uint, updates map[string]interface{}) error {
+	completing := false
+	if completed, _ := updates["completed"].(bool); completed {
+		task, ok := r.live(id)
+		completing = ok && !task.Completed
+	}
+	changing := []uint{id}
+	if complete, _ := updates[CompleteSubtasksKey].(bool); complete {
+		for _, descendant := range r.subtree(id) {
+			changing = append(changing, descendant.ID)
+		}
+	}
+	before := r.liveHydrated(changing)
+	subtasks, err := r.update(id, version, updates)
+	if err != nil {
+		return err
+	}
+
+	tasks := r.hydratedByID(append([]uint{id}, subtasks...))
+	if err = r.record(models.EventTaskUpdated, tasks...); err != nil {
+		return err
+	}
+	if err = r.recordHistory(ctx, models.HistoryUpdated, before, tasks); err != nil {
+		return err
+	}
+	if !completing {
+		tasks = tasks[1:]
+	}
+	return r.record(models.EventTaskCompleted, tasks...)
+}
+
+// update applies updates to the task with id and returns the IDs of the
+// subtasks it completed along.
+func (r *taskRepositoryMemory) update(id uint, version uint, updates map[string]interface{}) ([]uint, error) {
+	task, err := r.writable(id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, _ := updates[TagChangesKey].(TagChanges)
+	attach, err := r.tagsByName(changes.Attach)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *task
+	if err = applyTaskUpdates(&updated, updates); err != nil {
+		return nil, err
+	}
+	if err = r.checkReferences(&updated); err != nil {
+		return nil, err
+	}
+	updated.Version++
+	updated.UpdatedAt = r.clock.Now()
+	r.tasks[id] = &updated
+
+	var subtasks []uint
+	if complete, _ := updates[CompleteSubtasksKey].(bool); complete {
+		for _, descendant := range r.subtree(id) {
+			if !descendant.Completed {
+				completed := *descendant
+				completed.Completed = true
+				completed.Version++
+				completed.UpdatedAt = updated.UpdatedAt
+				r.tasks[descendant.ID] = &completed
+				subtasks = append(subtasks, descendant.ID)
+			}
+		}
+	}
+	slices.Sort(subtasks)
+
+	if due, ok := dueAtUpdate(updates); ok {
+		r.rescheduleReminders(id, due)
+	}
+
+	for _, tag := range attach {
+		r.taskTags[id][tag.ID] = true
+	}
+	for _, name := range changes.Detach {
+		if tag, ok := r.tagNamed(name); ok {
+			delete(r.taskTags[id], tag.ID)
+		}
+	}
+	return subtasks, nil
+}
+
+func (r *taskRepositoryMemory) Delete(ctx context.Context, id uint, version uint, cascade bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.deleteTask(ctx, id, version, cascade)
+}
+
+// deleteTask is Delete with the lock held.
+func (r *taskRepositoryMemory) deleteTask(ctx context.Context, id uint, version uint, cascade bool) error {
+	task, err := r.writable(id, version)
+	if err != nil {
+		return err
+	}
+
+	// The deleted events carry the tasks as they were.
+	deleted := []*models.Task{task}
+	if cascade {
+		deleted = append(deleted, sortedByID(r.subtree(id))...)
+	}
+	var overrides []*models.Task
+	for _, stored := range r.tasks {
+		if !stored.DeletedAt.Valid && stored.SeriesID != nil && *stored.SeriesID == id {
+			overrides = append(overrides, stored)
+		}
+	}
+	deleted = append(deleted, sortedByID(overrides)...)
+	events := make([]models.Task, len(deleted))
+	for i, stored := range deleted {
+		events[i] = r.hydrated(stored)
+	}
+
+	var moved []uint
+	var movedBefore []models.Task
+	now := r.clock.Now()
+	if !cascade {
+		for _, child := range r.children(id) {
+			movedBefore = append(movedBefore, r.hydrated(child))
+			updated := *child
+			updated.ParentID = task.ParentID
+			updated.Version++
+			updated.UpdatedAt = now
+			r.tasks[child.ID] = &updated
+			moved = append(moved, child.ID)
+		}
+		slices.Sort(moved)
+	}
+	for _, stored := range deleted {
+		stored.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	}
+
+	updated := r.hydratedByID(moved)
+	if err = r.record(models.EventTaskUpdated, updated...); err != nil {
+		return err
+	}
+	if err = r.record(models.EventTaskDeleted, events...); err != nil {
+		return err
+	}
+	if err = r.recordHistory(ctx, models.HistoryUpdated, movedBefore, updated); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryDeleted, events, markDeleted(events, now))
+}
+
+func (r *taskRepositoryMemory) GetByIDs(_ context.Context, ids []uint) ([]models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.liveHydrated(ids), nil
+}
+
+func (r *taskRepositoryMemory) CreateMany(ctx context.Context, tasks []models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Check every task first: a failed batch leaves nothing behind.
+	for i := range tasks {
+		if err := r.checkReferences(&tasks[i]); err != nil {
+			return err
+		}
+		if _, err := r.tagsByName(tagNames(tasks[i].Tags)); err != nil {
+			return err
+		}
+	}
+	for i := range tasks {
+		if err := r.create(&tasks[i]); err != nil {
+			return err
+		}
+	}
+	if err := r.record(models.EventTaskCreated, tasks...); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryCreated, nil, tasks)
+}
+
+func (r *taskRepositoryMemory) UpdateMany(ctx context.Context, ids []uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := r.liveHydrated(ids)
+	if len(before) != len(ids) {
+		return gorm.ErrRecordNotFound
+	}
+	changes, _ := updates[TagChangesKey].(TagChanges)
+	if _, err := r.tagsByName(changes.Attach); err != nil {
+		return err
+	}
+	dueTimes, _ := updates[DueTimesKey].(DueTimes)
+	updated := make([]models.Task, len(before))
+	for i := range before {
+		updated[i] = *r.tasks[ids[i]]
+		if err := applyTaskUpdates(&updated[i], updates); err != nil {
+			return err
+		}
+		if due, ok := dueTimes[ids[i]]; ok {
+			updated[i].DueAt = due
+		}
+		if err := r.checkReferences(&updated[i]); err != nil {
+			return err
+		}
+	}
+
+	var subtasks []uint
+	if complete, _ := updates[CompleteSubtasksKey].(bool); complete {
+		for _, id := range ids {
+			for _, descendant := range r.subtree(id) {
+				if !descendant.Completed && !slices.Contains(ids, descendant.ID) && !slices.Contains(subtasks, descendant.ID) {
+					subtasks = append(subtasks, descendant.ID)
+				}
+			}
+		}
+		slices.Sort(subtasks)
+	}
+	subtasksBefore := r.hydratedByID(subtasks)
+
+	now := r.clock.Now()
+	for i := range updated {
+		updated[i].Version++
+		updated[i].UpdatedAt = now
+		r.tasks[ids[i]] = &updated[i]
+	}
+	for _, id := range subtasks {
+		completed := *r.tasks[id]
+		completed.Completed = true
+		completed.Version++
+		completed.UpdatedAt = now
+		r.tasks[id] = &completed
+	}
+	due, dueChanged := dueAtUpdate(updates)
+	for _, id := range ids {
+		if taskDue, ok := dueTimes[id]; ok {
+			r.rescheduleReminders(id, taskDue)
+		} else if dueChanged {
+			r.rescheduleReminders(id, due)
+		}
+		for _, name := range changes.Attach {
+			tag, _ := r.tagNamed(name)
+			r.taskTags[id][tag.ID] = true
+		}
+		for _, name := range changes.Detach {
+			if tag, ok := r.tagNamed(name); ok {
+				delete(r.taskTags[id], tag.ID)
+			}
+		}
+	}
+
+	tasks := r.hydratedByID(append(slices.Clone(ids), subtasks...))
+	if err := r.record(models.EventTaskUpdated, tasks...); err != nil {
+		return err
+	}
+	if err := r.recordHistory(ctx, models.HistoryUpdated, append(before, subtasksBefore...), tasks); err != nil {
+		return err
+	}
+	completed, _ := updates["completed"].(bool)
+	var completing []models.Task
+	for i := range tasks {
+		if i >= len(ids) || completed && !before[i].Completed {
+			completing = append(completing, tasks[i])
+		}
+	}
+	return r.record(models.EventTaskCompleted, completing...)
+}
+
+func (r *taskRepositoryMemory) DeleteMany(ctx context.Context, ids []uint, cascade bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The deleted events carry the tasks as they were.
+	deleted := r.liveHydrated(ids)
+	if len(deleted) != len(ids) {
+		return gorm.ErrRecordNotFound
+	}
+	deleting := slices.Clone(ids)
+	if cascade {
+		var subtree []*models.Task
+		for _, id := range ids {
+			subtree = append(subtree, r.subtree(id)...)
+		}
+		for _, task := range sortedByID(subtree) {
+			if !slices.Contains(deleting, task.ID) {
+				deleting = append(deleting, task.ID)
+			}
+		}
+	}
+	var overrides []*models.Task
+	for _, stored := range r.tasks {
+		if !stored.DeletedAt.Valid && stored.SeriesID != nil &&
+			slices.Contains(deleting, *stored.SeriesID) && !slices.Contains(deleting, stored.ID) {
+			overrides = append(overrides, stored)
+		}
+	}
+	deleted = r.hydratedByID(deleting)
+	for _, stored := range sortedByID(overrides) {
+		deleted = append(deleted, r.hydrated(stored))
+	}
+
+	var moved []uint
+	var movedBefore []models.Task
+	now := r.clock.Now()
+	if !cascade {
+		var children []models.Task
+		for _, id := range deleting {
+			for _, child := range r.children(id) {
+				if !slices.Contains(deleting, child.ID) {
+					children = append(children, *child)
+				}
+			}
+		}
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].ID < children[j].ID
+		})
+		for parent, group := range adoptingParents(deleted, children) {
+			for _, id := range group {
+				movedBefore = append(movedBefore, r.hydrated(r.tasks[id]))
+				updated := *r.tasks[id]
+				updated.ParentID = nil
+				if parent != 0 {
+					updated.ParentID = &parent
+				}
+				updated.Version++
+				updated.UpdatedAt = now
+				r.tasks[id] = &updated
+				moved = append(moved, id)
+			}
+		}
+		slices.Sort(moved)
+	}
+	for _, task := range deleted {
+		r.tasks[task.ID].DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	}
+
+	updated := r.hydratedByID(moved)
+	if err := r.record(models.EventTaskUpdated, updated...); err != nil {
+		return err
+	}
+	if err := r.record(models.EventTaskDeleted, deleted...); err != nil {
+		return err
+	}
+	if err := r.recordHistory(ctx, models.HistoryUpdated, movedBefore, updated); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryDeleted, deleted, markDeleted(deleted, now))
+}
+
+func (r *taskRepositoryMemory) List(_ context.Context, filter dto.TaskFilter) ([]models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order := filter.OrderOrDefault()
+	if filter.Cursor != nil {
+		order = filter.Cursor.Sort
+	}
+
+	tasks := r.matching(filter)
+	sort.Slice(tasks, func(i, j int) bool {
+		return dto.CompareTasks(&tasks[i], &tasks[j], order) < 0
+	})
+
+	if filter.Cursor == nil {
+		return paginate(tasks, filter.Limit, filter.Offset), nil
+	}
+
+	var page []models.Task
+	if filter.Cursor.Backward {
+		for i := len(tasks) - 1; i >= 0; i-- {
+			if filter.Cursor.Compare(&tasks[i]) < 0 {
+				page = append(page, tasks[i])
+			}
+		}
+	} else {
+		for i := range tasks {
+			if filter.Cursor.Compare(&tasks[i]) > 0 {
+				page = append(page, tasks[i])
+			}
+		}
+	}
+	return paginate(page, filter.Limit, 0), nil
+}
+
+func (r *taskRepositoryMemory) Count(_ context.Context, filter dto.TaskFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.matching(filter))), nil
+}
+
+func (r *taskRepositoryMemory) ListByDate(_ context.Context, date time.Time) ([]models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tasks := r.onDate(date)
+	order := []dto.SortField{{Field: "rank"}, {Field: "id"}}
+	sort.Slice(tasks, func(i, j int) bool {
+		return dto.CompareTasks(&tasks[i], &tasks[j], order) < 0
+	})
+	return tasks, nil
+}
+
+func (r *taskRepositoryMemory) LastRank(_ context.Context, date time.Time) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var last string
+	for _, task := range r.onDate(date) {
+		last = max(last, task.Rank)
+	}
+	return last, nil
+}
+
+func (r *taskRepositoryMemory) SetRanks(ctx context.Context, ranks map[uint]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id := range ranks {
+		if _, ok := r.live(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+	}
+	now := r.clock.Now()
+	ids := make([]uint, 0, len(ranks))
+	var before []models.Task
+	for id, rank := range ranks {
+		before = append(before, r.hydrated(r.tasks[id]))
+		updated := *r.tasks[id]
+		updated.Rank = rank
+		updated.Version++
+		updated.UpdatedAt = now
+		r.tasks[id] = &updated
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	tasks := r.hydratedByID(ids)
+	if err := r.record(models.EventTaskUpdated, tasks...); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryUpdated, before, tasks)
+}
+
+func (r *taskRepositoryMemory) ListChildren(_ context.Context, parentIDs []uint) ([]models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tasks []models.Task
+	for _, parentID := range parentIDs {
+		for _, child := range r.children(parentID) {
+			tasks = append(tasks, r.hydrated(child))
+		}
+	}
+	order := dto.DefaultTaskSort()
+	sort.Slice(tasks, func(i, j int) bool {
+		return dto.CompareTasks(&tasks[i], &tasks[j], order) < 0
+	})
+	return tasks, nil
+}
+
+func (r *taskRepositoryMemory) ListOverrides(_ context.Context, seriesIDs []uint) ([]models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tasks []models.Task
+	for _, task := range r.tasks {
+		if task.SeriesID != nil && slices.Contains(seriesIDs, *task.SeriesID) {
+			tasks = append(tasks, *task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].OccurrenceDate.Before(*tasks[j].OccurrenceDate)
+	})
+	return tasks, nil
+}
+
+func (r *taskRepositoryMemory) SplitSeries(ctx context.Context, id uint, version uint, updates map[string]interface{}, next *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Validate next before the update so that a failure leaves no trace.
+	if err := r.checkReferences(next); err != nil {
+		return err
+	}
+	if _, err := r.tagsByName(tagNames(next.Tags)); err != nil {
+		return err
+	}
+	before := r.liveHydrated([]uint{id})
+	if _, err := r.update(id, version, updates); err != nil {
+		return err
+	}
+	if err := r.create(next); err != nil {
+		return err
+	}
+
+	var moved []uint
+	for taskID, task := range r.tasks {
+		if task.SeriesID != nil && *task.SeriesID == id && !task.OccurrenceDate.Before(next.Date) {
+			updated := *task
+			updated.SeriesID = &next.ID
+			updated.Version++
+			updated.UpdatedAt = next.CreatedAt
+			r.tasks[taskID] = &updated
+			if !task.DeletedAt.Valid {
+				moved = append(moved, taskID)
+				before = append(before, r.hydrated(task))
+			}
+		}
+	}
+	slices.Sort(moved)
+
+	series, overrides := r.hydratedByID([]uint{id}), r.hydratedByID(moved)
+	if err := r.record(models.EventTaskUpdated, series...); err != nil {
+		return err
+	}
+	if err := r.record(models.EventTaskCreated, *next); err != nil {
+		return err
+	}
+	if err := r.record(models.EventTaskUpdated, overrides...); err != nil {
+		return err
+	}
+	if err := r.recordHistory(ctx, models.HistoryUpdated, before, append(series, overrides...)); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryCreated, nil, []models.Task{*next})
+}
+
+func (r *taskRepositoryMemory) ExcludeOccurrence(ctx context.Context, id uint, version uint, updates map[string]interface{}, occurrenceID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Check the occurrence before the update so that a failure leaves no
+	// trace.
+	if occurrenceID != 0 {
+		if _, ok := r.live(occurrenceID); !ok {
+			return gorm.ErrRecordNotFound
+		}
+	}
+	if err := r.updateTask(ctx, id, version, updates); err != nil {
+		return err
+	}
+	if occurrenceID == 0 {
+		return nil
+	}
+	return r.deleteTask(ctx, occurrenceID, 0, false)
+}
+
+func (r *taskRepositoryMemory) StoreOccurrence(ctx context.Context, occurrence *models.Task, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Check the occurrence as updated before creating it so that a failure
+	// leaves no trace.
+	updated := *occurrence
+	if err := applyTaskUpdates(&updated, updates); err != nil {
+		return err
+	}
+	for _, task := range []*models.Task{occurrence, &updated} {
+		if err := r.checkReferences(task); err != nil {
+			return err
+		}
+	}
+	changes, _ := updates[TagChangesKey].(TagChanges)
+	if _, err := r.tagsByName(append(tagNames(occurrence.Tags), changes.Attach...)); err != nil {
+		return err
+	}
+
+	if err := r.create(occurrence); err != nil {
+		return err
+	}
+	if err := r.record(models.EventTaskCreated, *occurrence); err != nil {
+		return err
+	}
+	if err := r.recordHistory(ctx, models.HistoryCreated, nil, []models.Task{*occurrence}); err != nil {
+		return err
+	}
+	return r.updateTask(ctx, occurrence.ID, 0, updates)
+}
+
+func (r *taskRepositoryMemory) GetTrashed(_ context.Context, id uint) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, ok := r.tasks[id]
+	if !ok || !inTrash(task) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := r.hydrated(task)
+	return &found, nil
+}
+
+func (r *taskRepositoryMemory) Restore(ctx context.Context, id uint, version uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, err := r.trashedWritable(id, version)
+	if err != nil {
+		return err
+	}
+	if task.ParentID != nil {
+		if _, ok := r.live(*task.ParentID); !ok {
+			return fmt.Errorf("%w: restore task %d first", ErrParentTrashed, *task.ParentID)
+		}
+	}
+
+	ids := []uint{id}
+	for _, descendant := range sortedByID(r.deletedWith(task)) {
+		ids = append(ids, descendant.ID)
+	}
+	var overrides []*models.Task
+	for _, stored := range r.tasks {
+		// Excluded days were deleted on their own, not with the series.
+		if stored.DeletedAt.Valid && stored.SeriesID != nil && *stored.SeriesID == id &&
+			!task.ExDates.Contains(*stored.OccurrenceDate) {
+			overrides = append(overrides, stored)
+		}
+	}
+	for _, override := range sortedByID(overrides) {
+		ids = append(ids, override.ID)
+	}
+
+	before := r.hydratedByID(ids)
+	now := r.clock.Now()
+	for _, restoredID := range ids {
+		restored := *r.tasks[restoredID]
+		restored.DeletedAt = gorm.DeletedAt{}
+		restored.Version++
+		restored.UpdatedAt = now
+		r.tasks[restoredID] = &restored
+	}
+	restored := r.hydratedByID(ids)
+	if err := r.record(models.EventTaskRestored, restored...); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryRestored, before, restored)
+}
+
+func (r *taskRepositoryMemory) Purge(ctx context.Context, id uint, version uint, cascade bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.trashedWritable(id, version); err != nil {
+		return err
+	}
+	ids := []uint{id}
+	if cascade {
+		for _, descendant := range sortedByID(r.trashedSubtree(id)) {
+			ids = append(ids, descendant.ID)
+		}
+	}
+	return r.purgeAll(ctx, ids)
+}
+
+func (r *taskRepositoryMemory) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []*models.Task
+	for _, task := range r.tasks {
+		if inTrash(task) && task.DeletedAt.Time.Before(before) {
+			expired = append(expired, task)
+		}
+	}
+	ids := make([]uint, len(expired))
+	for i, task := range sortedByID(expired) {
+		ids[i] = task.ID
+	}
+	return int64(len(ids)), r.purgeAll(ctx, ids)
+}
+
+// purgeAll purges the tasks with ids and records task.purged and a history
+// entry for each of them and their stored occurrences.
+func (r *taskRepositoryMemory) purgeAll(ctx context.Context, ids []uint) error {
+	var occurrences []*models.Task
+	for _, task := range r.tasks {
+		if task.SeriesID != nil && slices.Contains(ids, *task.SeriesID) {
+			occurrences = append(occurrences, task)
+		}
+	}
+	all := slices.Clone(ids)
+	for _, occurrence := range sortedByID(occurrences) {
+		all = append(all, occurrence.ID)
+	}
+
+	purged := r.hydratedByID(all)
+	for _, id := range ids {
+		r.purge(id)
+	}
+	if err := r.record(models.EventTaskPurged, purged...); err != nil {
+		return err
+	}
+	return r.recordHistory(ctx, models.HistoryPurged, purged, purged)
+}
+
+// purge removes a task for good along with its stored occurrences and the
+// rows referring to it, as the foreign keys of the database would. The
+// history of the task is kept.
+func (r *taskRepositoryMemory) purge(id uint) {
+	delete(r.tasks, id)
+	delete(r.taskTags, id)
+	delete(r.dependencies, id)
+	for _, blockers := range r.dependencies {
+		delete(blockers, id)
+	}
+	for reminderID, reminder := range r.reminders {
+		if reminder.TaskID == id {
+			delete(r.reminders, reminderID)
+		}
+	}
+	for taskID, task := range r.tasks {
+		if task.SeriesID != nil && *task.SeriesID == id {
+			r.purge(taskID)
+		} else if task.ParentID != nil && *task.ParentID == id {
+			orphan := *task
+			orphan.ParentID = nil
+			r.tasks[taskID] = &orphan
+		}
+	}
+}
+
+// trashedSubtree returns the descendants of a task that are in the trash.
+func (r *taskRepositoryMemory) trashedSubtree(id uint) []*models.Task {
+	var descendants []*models.Task
+	for level := []uint{id}; len(level) > 0; {
+		var next []uint
+		for _, task := range r.tasks {
+			if task.DeletedAt.Valid && task.ParentID != nil && slices.Contains(level, *task.ParentID) {
+				descendants = append(descendants, task)
+				next = append(next, task.ID)
+			}
+		}
+		level = next
+	}
+	return descendants
+}
+
+// deletedWith returns the descendants of a task in the trash that were
+// deleted along with it, which are all marked at the same time.
+func (r *taskRepositoryMemory) deletedWith(task *models.Task) []*models.Task {
+	var descendants []*models.Task
+	for level := []uint{task.ID}; len(level) > 0; {
+		var next []uint
+		for _, stored := range r.tasks {
+			if stored.DeletedAt.Valid && stored.DeletedAt.Time.Equal(task.DeletedAt.Time) &&
+				stored.ParentID != nil && slices.Contains(level, *stored.ParentID) {
+				descendants = append(descendants, stored)
+				next = append(next, stored.ID)
+			}
+		}
+		level = next
+	}
+	return descendants
+}
+
+// trashedWritable is writable for a task in the trash.
+func (r *taskRepositoryMemory) trashedWritable(id uint, version uint) (*models.Task, error) {
+	task, ok := r.tasks[id]
+	if !ok || !inTrash(task) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if version != 0 && task.Version != version {
+		return nil, ErrVersionMismatch
+	}
+	return task, nil
+}
+
+// inTrash reports whether a stored task is in the trash: deleted and not a
+// stored occurrence.
+func inTrash(task *models.Task) bool {
+	return task.DeletedAt.Valid && task.SeriesID == nil
+}
+
+func (r *taskRepositoryMemory) AddDependency(_ context.Context, taskID uint, blockerID uint) error {
+	if taskID == blockerID {
+		return dependencyCycle(taskID, []uint{taskID})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, taskOK := r.live(taskID)
+	_, blockerOK := r.live(blockerID)
+	if !taskOK || !blockerOK {
+		return gorm.ErrRecordNotFound
+	}
+
+	var edges []models.TaskDependency
+	for id, blockers := range r.dependencies {
+		for blocker := range blockers {
+			edges = append(edges, models.TaskDependency{TaskID: id, BlockerID: blocker})
+		}
+	}
+	if path := dependencyPath(edges, blockerID, taskID); path != nil {
+		return dependencyCycle(taskID, path)
+	}
+
+	if r.dependencies[taskID] == nil {
+		r.dependencies[taskID] = make(map[uint]bool)
+	}
+	r.dependencies[taskID][blockerID] = true
+	return nil
+}
+
+func (r *taskRepositoryMemory) RemoveDependency(_ context.Context, taskID uint, blockerID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.dependencies[taskID][blockerID] {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.dependencies[taskID], blockerID)
+	return nil
+}
+
+func (r *taskRepositoryMemory) ListBlockers(_ context.Context, taskID uint) ([]models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tasks []models.Task
+	for blockerID := range r.dependencies[taskID] {
+		if blocker, ok := r.live(blockerID); ok {
+			tasks = append(tasks, r.hydrated(blocker))
+		}
+	}
+	order := dto.DefaultTaskSort()
+	sort.Slice(tasks, func(i, j int) bool {
+		return dto.CompareTasks(&tasks[i], &tasks[j], order) < 0
+	})
+	return tasks, nil
+}
+
+// isBlocked reports whether a live open task blocks a task.
+func (r *taskRepositoryMemory) isBlocked(taskID uint) bool {
+	for blockerID := range r.dependencies[taskID] {
+		if blocker, ok := r.live(blockerID); ok && !blocker.Completed {
+			return true
+		}
+	}
+	return false
+}
+
+// children returns the live direct subtasks of a task, unordered.
+func (r *taskRepositoryMemory) children(parentID uint) []*models.Task {
+	var children []*models.Task
+	for _, task := range r.tasks {
+		if !task.DeletedAt.Valid && task.ParentID != nil && *task.ParentID == parentID {
+			children = append(children, task)
+		}
+	}
+	return children
+}
+
+// subtree returns the live descendants of a task.
+func (r *taskRepositoryMemory) subtree(id uint) []*models.Task {
+	var descendants []*models.Task
+	for level := r.children(id); len(level) > 0; {
+		descendants = append(descendants, level...)
+		var next []*models.Task
+		for _, task := range level {
+			next = append(next, r.children(task.ID)...)
+		}
+		level = next
+	}
+	return descendants
+}
+
+// hydrated returns a copy of a stored task with its tags, subtask counters
+// and blocked flag filled in.
+func (r *taskRepositoryMemory) hydrated(task *models.Task) models.Task {
+	found := *task
+	found.Tags = r.tagsOf(task.ID)
+	r.countSubtasks(&found)
+	found.Blocked = r.isBlocked(task.ID)
+	return found
+}
+
+// liveHydrated returns hydrated copies of the live tasks among ids.
+func (r *taskRepositoryMemory) liveHydrated(ids []uint) []models.Task {
+	var tasks []models.Task
+	for _, id := range ids {
+		if task, ok := r.live(id); ok {
+			tasks = append(tasks, r.hydrated(task))
+		}
+	}
+	return tasks
+}
+
+// hydratedByID returns hydrated copies of the stored tasks with ids.
+func (r *taskRepositoryMemory) hydratedByID(ids []uint) []models.Task {
+	tasks := make([]models.Task, len(ids))
+	for i, id := range ids {
+		tasks[i] = r.hydrated(r.tasks[id])
+	}
+	return tasks
+}
+
+// detachTasks takes the stored tasks with ids, live or in the trash, off a
+// project or tag about to be deleted: detach makes the change, and each
+// task gets a new version, task.updated and a history entry like any
+// update.
+func (s *MemoryStore) detachTasks(ctx context.Context, ids []uint, detach func(task *models.Task)) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	slices.Sort(ids)
+	tasks := &taskRepositoryMemory{MemoryStore: s}
+	before := tasks.hydratedByID(ids)
+	now := s.clock.Now()
+	for _, id := range ids {
+		updated := *s.tasks[id]
+		detach(&updated)
+		updated.Version++
+		updated.UpdatedAt = now
+		s.tasks[id] = &updated
+	}
+
+	after := tasks.hydratedByID(ids)
+	if err := s.record(models.EventTaskUpdated, after...); err != nil {
+		return err
+	}
+	return s.recordHistory(ctx, models.HistoryUpdated, before, after)
+}
+
+func sortedByID(tasks []*models.Task) []*models.Task {
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+func (r *taskRepositoryMemory) countSubtasks(task *models.Task) {
+	task.SubtasksTotal, task.SubtasksCompleted = 0, 0
+	for _, child := range r.children(task.ID) {
+		task.SubtasksTotal++
+		if child.Completed {
+			task.SubtasksCompleted++
+		}
+	}
+}
+
+// checkReferences fails with gorm.ErrForeignKeyViolated, as a database
+// would, when a task refers to a project, parent or series that does not
+// exist.
+func (r *taskRepositoryMemory) checkReferences(task *models.Task) error {
+	if err := r.checkProject(task.ProjectID); err != nil {
+		return err
+	}
+	for _, ref := range []*uint{task.ParentID, task.SeriesID} {
+		if ref == nil {
+			continue
+		}
+		if _, ok := r.tasks[*ref]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	return nil
+}
+
+func (r *taskRepositoryMemory) onDate(date time.Time) []models.Task {
+	var tasks []models.Task
+	for _, task := range r.tasks {
+		if !task.DeletedAt.Valid && task.Date.Equal(date) {
+			tasks = append(tasks, r.hydrated(task))
+		}
+	}
+	return tasks
+}
+
+// matching returns copies of the live tasks, or with filter.Trashed those
+// in the trash, that pass filter, unordered, with search relevance and
+// snippets filled in.
+func (r *taskRepositoryMemory) matching(filter dto.TaskFilter) []models.Task {
+	terms := searchTerms(filter.Query)
+
+	var tasks []models.Task
+	for _, task := range r.tasks {
+		if task.DeletedAt.Valid != filter.Trashed || filter.Trashed && task.SeriesID != nil ||
+			!matchesTaskFilter(task, filter) || !matchesTagFilter(r.attachedTags(task.ID), filter) ||
+			!matchesProjectFilter(task, filter, r.inArchivedProject(task)) {
+			continue
+		}
+		if filter.Blocked != nil && r.isBlocked(task.ID) != *filter.Blocked {
+			continue
+		}
+
+		found := r.hydrated(task)
+		if len(terms) > 0 {
+			relevance, ok := searchRelevance(task, terms)
+			if !ok {
+				continue
+			}
+			found.Relevance = relevance
+			if filter.Highlight {
+				found.Snippet = searchSnippet(task, terms)
+			}
+		}
+		tasks = append(tasks, found)
+	}
+	return tasks
+}
+
+func (r *taskRepositoryMemory) live(id uint) (*models.Task, bool) {
+	task, ok := r.tasks[id]
+	if !ok || task.DeletedAt.Valid {
+		return nil, false
+	}
+	return task, true
+}
+
+// writable returns the live task id if its version matches, 0 matching any.
+func (r *taskRepositoryMemory) writable(id uint, version uint) (*models.Task, error) {
+	task, ok := r.live(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if version != 0 && task.Version != version {
+		return nil, ErrVersionMismatch
+	}
+	return task, nil
+}
+
+// attachedTags returns the set of names of the tags attached to a task.
+func (r *taskRepositoryMemory) attachedTags(taskID uint) map[string]bool {
+	attached := make(map[string]bool)
+	for tagID := range r.taskTags[taskID] {
+		attached[r.tags[tagID].Name] = true
+	}
+	return attached
+}
+
+func paginate(tasks []models.Task, limit, offset int) []models.Task {
+	if offset >= len(tasks) {
+		return nil
+	}
+	tasks = tasks[offset:]
+	if limit > 0 && limit < len(tasks) {
+		tasks = tasks[:limit]
+	}
+	return tasks
+}
+
+// applyTaskUpdates sets the columns of an update map on task, accepting the
+// same value types gorm would.
+func applyTaskUpdates(task *models.Task, updates map[string]interface{}) error {
+	for column, value := range updates {
+		var ok bool
+		switch column {
+		case TagChangesKey, CompleteSubtasksKey, DueTimesKey:
+			continue
+		case "title":
+			task.Title, ok = value.(string)
+		case "description":
+			task.Description, ok = value.(string)
+		case "completed":
+			task.Completed, ok = value.(bool)
+		case "priority":
+			task.Priority, ok = value.(models.Priority)
+		case "rank":
+			task.Rank, ok = value.(string)
+		case "recurrence":
+			task.Recurrence, ok = value.(string)
+		case "ex_dates":
+			task.ExDates, ok = value.(models.DateList)
+		case "due_at":
+			switch v := value.(type) {
+			case *time.Time:
+				task.DueAt, ok = v, true
+			case nil:
+				task.DueAt, ok = nil, true
+			}
+		case "time_zone":
+			task.TimeZone, ok = value.(string)
+		case "project_id":
+			task.ProjectID, ok = optionalID(value)
+		case "parent_id":
+			task.ParentID, ok = optionalID(value)
+		case "date":
+			switch v := value.(type) {
+			case time.Time:
+				task.Date, ok = v, true
+			case *time.Time:
+				if v != nil {
+					task.Date, ok = *v, true
+				}
+			}
+		default:
+			return fmt.Errorf("unknown task column %q", column)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v for task column %q", value, column)
+		}
+	}
+	return nil
+}
+
+// optionalID converts the value of a nullable ID column, nil or uint.
+func optionalID(value interface{}) (*uint, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case uint:
+		return &v, true
+	case *uint:
+		return v, true
+	}
+	return nil, false
+}
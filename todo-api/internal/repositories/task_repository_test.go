@@ -2,7 +2,10 @@ package repositories_test
 
 import (
 	"context"
+	"database/sql/driver"
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +34,63 @@ func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	return gormDB, mock
 }
 
+// expectComputedFields expects the subtask progress and blocker queries for
+// tasks ids and reports no subtasks and no blockers.
+func expectComputedFields(mock sqlmock.Sqlmock, ids ...driver.Value) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT parent_id, COUNT(*) AS total, COUNT(CASE WHEN completed THEN 1 END) AS completed FROM "tasks" ` +
+		`WHERE parent_id IN (` + placeholders(1, len(ids)) + `) AND "tasks"."deleted_at" IS NULL GROUP BY "parent_id"`)).
+		WithArgs(ids...).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id", "total", "completed"}))
+	mock.ExpectQuery(regexp.QuoteMeta(blockedQuery(placeholders(2, len(ids))))).
+		WithArgs(append([]driver.Value{false}, ids...)...).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id"}))
+}
+
+// placeholders returns n comma-separated Postgres placeholders starting at
+// $first.
+func placeholders(first, n int) string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = fmt.Sprintf("$%d", first+i)
+	}
+	return strings.Join(list, ",")
+}
+
+func blockedQuery(list string) string {
+	return `SELECT DISTINCT task_dependencies.task_id FROM "task_dependencies" JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id ` +
+		`WHERE (blockers.completed = $1 AND blockers.deleted_at IS NULL) AND task_dependencies.task_id IN (` + list + `)`
+}
+
+// expectLoadTasks expects a task to be loaded by ID along with its tags and
+// computed fields, as it is for the events of a change.
+func expectLoadTasks(mock sqlmock.Sqlmock, id uint, completed bool) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tasks" WHERE id IN ($1) AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "completed"}).AddRow(id, "Test Task", completed))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "task_tags" WHERE "task_tags"."task_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_id"}))
+	expectComputedFields(mock, id)
+}
+
+// expectOutboxEvents expects events of types to be recorded about the task
+// with id, one insert each.
+func expectOutboxEvents(mock sqlmock.Sqlmock, id uint, types ...models.EventType) {
+	for i, t := range types {
+		mock.ExpectQuery(`INSERT INTO "outbox_events" (.+) VALUES (.+) RETURNING "id"`).
+			WithArgs(sqlmock.AnyArg(), t, id, sqlmock.AnyArg(), 0, sqlmock.AnyArg(), "", nil, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+	}
+}
+
+// expectHistory expects an entry about the task with id to be added to
+// its history.
+func expectHistory(mock sqlmock.Sqlmock, id uint, action models.HistoryAction) {
+	mock.ExpectQuery(`INSERT INTO "task_history" (.+) VALUES (.+) RETURNING "id"`).
+		WithArgs(id, sqlmock.AnyArg(), action, "", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
 func TestTaskRepository_Create(t *testing.T) {
 	// Arrange
 	gormDB, mock := setupMockDB(t)
@@ -52,10 +112,23 @@ func TestTaskRepository_Create(t *testing.T) {
 			nil,
 			task.Title,
 			task.Description,
-			task.Date,
+			task.Date.Format("2006-01-02"), // a day, not a timestamp
 			task.Completed,
+			uint(1), // version
+			task.Priority,
+			task.Rank,
+			nil, // project_id
+			nil, // parent_id
+			"",  // recurrence
+			"",  // ex_dates
+			nil, // series_id
+			nil, // occurrence_date
+			nil, // due_at
+			"",  // time_zone
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectOutboxEvents(mock, 1, models.EventTaskCreated)
+	expectHistory(mock, 1, models.HistoryCreated)
 	mock.ExpectCommit()
 
 	// Act
@@ -102,6 +175,15 @@ func TestTaskRepository_GetByID(t *testing.T) {
 	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
 		WithArgs(taskID, 1).
 		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "task_tags" WHERE "task_tags"."task_id" = $1`)).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT parent_id, COUNT(*) AS total, COUNT(CASE WHEN completed THEN 1 END) AS completed FROM "tasks" WHERE parent_id IN ($1) AND "tasks"."deleted_at" IS NULL GROUP BY "parent_id"`)).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id", "total", "completed"}).AddRow(taskID, 3, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(blockedQuery("$2"))).
+		WithArgs(false, taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id"}).AddRow(taskID))
 
 	// Act
 	task, err := repo.GetByID(context.Background(), taskID)
@@ -113,6 +195,9 @@ func TestTaskRepository_GetByID(t *testing.T) {
 	assert.Equal(t, "Test Task", task.Title)
 	assert.Equal(t, "Test Description", task.Description)
 	assert.Equal(t, false, task.Completed)
+	assert.Equal(t, int64(3), task.SubtasksTotal)
+	assert.Equal(t, int64(1), task.SubtasksCompleted)
+	assert.True(t, task.Blocked)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -129,7 +214,11 @@ func TestTaskRepository_Update(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tasks" SET "completed"=$1,"title"=$2,"updated_at"=$3 WHERE id = $4 AND "tasks"."deleted_at" IS NULL`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "completed" FROM "tasks" WHERE id = $1 AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"completed"}).AddRow(false))
+	expectLoadTasks(mock, taskID, false)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tasks" SET "completed"=$1,"title"=$2,"version"=version + 1,"updated_at"=$3 WHERE id = $4 AND "tasks"."deleted_at" IS NULL`)).
 		WithArgs(
 			updates["completed"],
 			updates["title"],
@@ -137,16 +226,46 @@ func TestTaskRepository_Update(t *testing.T) {
 			taskID,
 		).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLoadTasks(mock, taskID, true)
+	expectOutboxEvents(mock, taskID, models.EventTaskUpdated)
+	expectHistory(mock, taskID, models.HistoryUpdated)
+	expectOutboxEvents(mock, taskID, models.EventTaskCompleted)
 	mock.ExpectCommit()
 
 	// Act
-	err := repo.Update(context.Background(), taskID, updates)
+	err := repo.Update(context.Background(), taskID, 0, updates)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTaskRepository_UpdateVersionMismatch(t *testing.T) {
+	// Arrange
+	gormDB, mock := setupMockDB(t)
+	repo := repositories.NewTaskRepositoryImpl(gormDB)
+
+	taskID := uint(1)
+	updates := map[string]interface{}{"title": "Updated Title"}
+
+	mock.ExpectBegin()
+	expectLoadTasks(mock, taskID, false)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tasks" SET "title"=$1,"version"=version + 1,"updated_at"=$2 WHERE id = $3 AND version = $4 AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs(updates["title"], sqlmock.AnyArg(), taskID, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "tasks" WHERE id = $1 AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.Update(context.Background(), taskID, 2, updates)
+
+	// Assert
+	assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTaskRepository_Delete(t *testing.T) {
 	// Arrange
 	gormDB, mock := setupMockDB(t)
@@ -155,16 +274,31 @@ func TestTaskRepository_Delete(t *testing.T) {
 	taskID := uint(1)
 
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "tasks" WHERE series_id = $1 AND "tasks"."deleted_at" IS NULL ORDER BY id`)).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	expectLoadTasks(mock, taskID, false)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "tasks" WHERE parent_id = $1 AND "tasks"."deleted_at" IS NULL ORDER BY id`)).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tasks" SET "parent_id"=$1,"version"=version + 1,"updated_at"=$2 WHERE parent_id = $3 AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs(nil, sqlmock.AnyArg(), taskID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tasks" SET "deleted_at"=$1 WHERE series_id = $2 AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), taskID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tasks" SET "deleted_at"=$1 WHERE "tasks"."id" = $2 AND "tasks"."deleted_at" IS NULL`)).
 		WithArgs(
 			sqlmock.AnyArg(), // deleted_at
 			taskID,
 		).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectOutboxEvents(mock, taskID, models.EventTaskDeleted)
+	expectHistory(mock, taskID, models.HistoryDeleted)
 	mock.ExpectCommit()
 
 	// Act
-	err := repo.Delete(context.Background(), taskID)
+	err := repo.Delete(context.Background(), taskID, 0, false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -218,16 +352,27 @@ func TestTaskRepository_List(t *testing.T) {
 				expectedTasks[0].Completed,
 			)
 
-		expectedSQL := `SELECT * FROM "tasks" WHERE completed = $1 AND (date BETWEEN $2 AND $3) AND "tasks"."deleted_at" IS NULL LIMIT $4`
+		expectedSQL := `SELECT * FROM "tasks" WHERE completed = $1 ` +
+			`AND ((tasks.due_at IS NULL AND tasks.date >= $2 AND tasks.date <= $3) ` +
+			`OR (tasks.due_at IS NOT NULL AND tasks.due_at >= $4 AND tasks.due_at < $5)) ` +
+			`AND (tasks.project_id IS NULL OR tasks.project_id IN (SELECT "id" FROM "projects" WHERE archived = $6)) ` +
+			`AND "tasks"."deleted_at" IS NULL ORDER BY date, rank, id LIMIT $7`
 
 		mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
 			WithArgs(
 				*filter.Completed,
-				*filter.DateFrom,
-				*filter.DateTo,
+				filter.DateFrom.Format("2006-01-02"),
+				filter.DateTo.Format("2006-01-02"),
+				sqlmock.AnyArg(), // start of date_from
+				sqlmock.AnyArg(), // end of date_to
+				false,
 				filter.Limit,
 			).
 			WillReturnRows(rows)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "task_tags" WHERE "task_tags"."task_id" = $1`)).
+			WithArgs(expectedTasks[0].ID).
+			WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_id"}))
+		expectComputedFields(mock, expectedTasks[0].ID)
 
 		// Act
 		tasks, err := repo.List(context.Background(), filter)
@@ -258,14 +403,23 @@ func TestTaskRepository_List(t *testing.T) {
 			AddRow(1, "Task 1").
 			AddRow(2, "Task 2")
 
-		expectedSQL := `SELECT * FROM "tasks" WHERE date >= $1 AND "tasks"."deleted_at" IS NULL LIMIT $2`
+		expectedSQL := `SELECT * FROM "tasks" ` +
+			`WHERE ((tasks.due_at IS NULL AND tasks.date >= $1) OR (tasks.due_at IS NOT NULL AND tasks.due_at >= $2)) ` +
+			`AND (tasks.project_id IS NULL OR tasks.project_id IN (SELECT "id" FROM "projects" WHERE archived = $3)) ` +
+			`AND "tasks"."deleted_at" IS NULL ORDER BY date, rank, id LIMIT $4`
 
 		mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
 			WithArgs(
-				*filter.DateFrom,
+				filter.DateFrom.Format("2006-01-02"),
+				sqlmock.AnyArg(),
+				false,
 				filter.Limit,
 			).
 			WillReturnRows(rows)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "task_tags" WHERE "task_tags"."task_id" IN ($1,$2)`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_id"}))
+		expectComputedFields(mock, 1, 2)
 
 		// Act
 		tasks, err := repo.List(context.Background(), filter)
@@ -275,4 +429,133 @@ func TestTaskRepository_List(t *testing.T) {
 		assert.Len(t, tasks, 2)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("with cursor", func(t *testing.T) {
+		// Arrange
+		gormDB, mock := setupMockDB(t)
+		repo := repositories.NewTaskRepositoryImpl(gormDB)
+
+		completed := false
+		date := time.Now()
+		day := date.Format("2006-01-02")
+		cursor := dto.CursorBefore(models.Task{Model: gorm.Model{ID: 7}, Date: date, Rank: "i"}, dto.DefaultTaskSort())
+		filter := dto.TaskFilter{
+			Completed: &completed,
+			Cursor:    cursor,
+			Limit:     3,
+			Offset:    20,
+		}
+
+		expectedSQL := `SELECT * FROM "tasks" WHERE completed = $1 ` +
+			`AND (tasks.project_id IS NULL OR tasks.project_id IN (SELECT "id" FROM "projects" WHERE archived = $2)) ` +
+			`AND (date < $3 OR (date = $4 AND rank < $5) OR (date = $6 AND rank = $7 AND id < $8)) ` +
+			`AND "tasks"."deleted_at" IS NULL ORDER BY date DESC, rank DESC, id DESC LIMIT $9`
+
+		mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+			WithArgs(completed, false, day, day, "i", day, "i", uint(7), filter.Limit).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "task_tags" WHERE "task_tags"."task_id" = $1`)).
+			WithArgs(6).
+			WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_id"}))
+		expectComputedFields(mock, 6)
+
+		// Act
+		tasks, err := repo.List(context.Background(), filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("with search on postgres", func(t *testing.T) {
+		// Arrange
+		gormDB, mock := setupMockDB(t)
+		repo := repositories.NewTaskRepositoryImpl(gormDB)
+
+		filter := dto.TaskFilter{Query: "молоко", Limit: 5}
+
+		expectedSQL := `SELECT tasks.*, ts_rank(search, (websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $2))) AS relevance FROM "tasks" ` +
+			`WHERE (tasks.project_id IS NULL OR tasks.project_id IN (SELECT "id" FROM "projects" WHERE archived = $3)) ` +
+			`AND search @@ (websearch_to_tsquery('russian', $4) || websearch_to_tsquery('english', $5)) AND "tasks"."deleted_at" IS NULL ` +
+			`ORDER BY relevance DESC, id LIMIT $6`
+
+		mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+			WithArgs(filter.Query, filter.Query, false, filter.Query, filter.Query, filter.Limit).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "relevance"}).AddRow(4, "Купить молоко", 0.6))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "task_tags" WHERE "task_tags"."task_id" = $1`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_id"}))
+		expectComputedFields(mock, 4)
+
+		// Act
+		tasks, err := repo.List(context.Background(), filter)
+
+		// Assert
+		assert.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, 0.6, tasks[0].Relevance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("with all tags", func(t *testing.T) {
+		// Arrange
+		gormDB, mock := setupMockDB(t)
+		repo := repositories.NewTaskRepositoryImpl(gormDB)
+
+		filter := dto.TaskFilter{Tags: []string{"work", "home"}, TagMode: dto.TagModeAll, Limit: 5}
+
+		expectedSQL := `SELECT * FROM "tasks" WHERE tasks.id IN (SELECT task_tags.task_id FROM "task_tags" JOIN tags ON tags.id = task_tags.tag_id ` +
+			`WHERE tags.name IN ($1,$2) GROUP BY "task_tags"."task_id" HAVING COUNT(*) = $3) ` +
+			`AND (tasks.project_id IS NULL OR tasks.project_id IN (SELECT "id" FROM "projects" WHERE archived = $4)) ` +
+			`AND "tasks"."deleted_at" IS NULL ORDER BY date, rank, id LIMIT $5`
+
+		mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+			WithArgs("home", "work", 2, false, filter.Limit).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "task_tags" WHERE "task_tags"."task_id" = $1`)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_id"}).AddRow(3, 1).AddRow(3, 2))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE "tags"."id" IN ($1,$2) ORDER BY tags.name`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "home").AddRow(1, "work"))
+		expectComputedFields(mock, 3)
+
+		// Act
+		tasks, err := repo.List(context.Background(), filter)
+
+		// Assert
+		assert.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Len(t, tasks[0].Tags, 2)
+		assert.Equal(t, "home", tasks[0].Tags[0].Name)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTaskRepository_Count(t *testing.T) {
+	// Arrange
+	gormDB, mock := setupMockDB(t)
+	repo := repositories.NewTaskRepositoryImpl(gormDB)
+
+	completed := true
+	filter := dto.TaskFilter{
+		Completed: &completed,
+		Cursor:    dto.CursorAfter(models.Task{Model: gorm.Model{ID: 7}, Date: time.Now()}, dto.DefaultTaskSort()),
+		Limit:     3,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "tasks" WHERE completed = $1 `+
+		`AND (tasks.project_id IS NULL OR tasks.project_id IN (SELECT "id" FROM "projects" WHERE archived = $2)) `+
+		`AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs(completed, false).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	// Act
+	total, err := repo.Count(context.Background(), filter)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"strings"
+	"unicode"
+
+	"todo-api/internal/models"
+)
+
+const (
+	// pgSearchQuery parses the q filter with both text-search configurations
+	// used by the tasks.search column.
+	pgSearchQuery = "(websearch_to_tsquery('russian', ?) || websearch_to_tsquery('english', ?))"
+
+	highlightStart = "<mark>"
+	highlightStop  = "</mark>"
+)
+
+// The Postgres search uses the tsvector column; other databases and the
+// in-memory repository fall back to substring matching: every term must
+// occur in the title or the description, and relevance adds 2 for each
+// term found in the title and 1 for each found in the description. The
+// terms are lower-cased, and so are the columns they are compared with: LIKE
+// alone ignores the case of ASCII letters only.
+
+// searchTerms splits a search query into lower-cased words.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// likePattern turns a search term into a LIKE pattern matching it anywhere.
+func likePattern(term string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term) + "%"
+}
+
+// likeMatch is the SQL counterpart of searchRelevance's match rule.
+func likeMatch(terms []string) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, term := range terms {
+		parts = append(parts, `(lower(title) LIKE ? ESCAPE '\' OR lower(description) LIKE ? ESCAPE '\')`)
+		args = append(args, likePattern(term), likePattern(term))
+	}
+	return strings.Join(parts, " AND "), args
+}
+
+// likeRelevance is the SQL counterpart of searchRelevance's score.
+func likeRelevance(terms []string) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, term := range terms {
+		parts = append(parts,
+			`(CASE WHEN lower(title) LIKE ? ESCAPE '\' THEN 2 ELSE 0 END)`,
+			`(CASE WHEN lower(description) LIKE ? ESCAPE '\' THEN 1 ELSE 0 END)`)
+		args = append(args, likePattern(term), likePattern(term))
+	}
+	return "(" + strings.Join(parts, " + ") + ")", args
+}
+
+// searchRelevance reports whether task matches all terms and how well.
+func searchRelevance(task *models.Task, terms []string) (float64, bool) {
+	title := strings.ToLower(task.Title)
+	description := strings.ToLower(task.Description)
+
+	var relevance float64
+	for _, term := range terms {
+		inTitle := strings.Contains(title, term)
+		inDescription := strings.Contains(description, term)
+		if !inTitle && !inDescription {
+			return 0, false
+		}
+		if inTitle {
+			relevance += 2
+		}
+		if inDescription {
+			relevance++
+		}
+	}
+	return relevance, true
+}
+
+// searchSnippet returns the task text with every occurrence of terms
+// wrapped in <mark> tags, like ts_headline does on Postgres.
+func searchSnippet(task *models.Task, terms []string) string {
+	text := []rune(strings.TrimSpace(task.Title + " " + task.Description))
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(text))
+	for _, term := range terms {
+		needle := []rune(term)
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) == term {
+				for j := i; j < i+len(needle); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range text {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(highlightStart)
+		}
+		b.WriteRune(r)
+		if marked[i] && (i == len(text)-1 || !marked[i+1]) {
+			b.WriteString(highlightStop)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,256 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+func TestTaskRepository_Trash(t *testing.T) {
+	for name, newRepo := range repositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("list and count the trash", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, _, _ := createSeries(t, repo)
+				kept := &models.Task{Title: "Kept", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, kept))
+				done := &models.Task{Title: "Done", Date: day(1), Completed: true}
+				require.NoError(t, repo.Create(ctx, done))
+				open := &models.Task{Title: "Open", Date: day(2)}
+				require.NoError(t, repo.Create(ctx, open))
+				for _, id := range []uint{series.ID, done.ID, open.ID} {
+					require.NoError(t, repo.Delete(ctx, id, 0, false))
+				}
+				completed := true
+
+				// Act
+				trash, err := repo.List(ctx, dto.TaskFilter{Trashed: true, Limit: 10})
+				require.NoError(t, err)
+				total, err := repo.Count(ctx, dto.TaskFilter{Trashed: true})
+				require.NoError(t, err)
+				filtered, err := repo.List(ctx, dto.TaskFilter{Trashed: true, Completed: &completed, Limit: 10})
+				require.NoError(t, err)
+				page, err := repo.List(ctx, dto.TaskFilter{Trashed: true, Limit: 1, Offset: 1})
+				require.NoError(t, err)
+				live, err := repo.List(ctx, dto.TaskFilter{Limit: 10})
+				require.NoError(t, err)
+
+				// Assert
+				assert.Equal(t, []uint{series.ID, done.ID, open.ID}, taskIDs(trash), "stored occurrences are left out")
+				assert.Equal(t, int64(3), total)
+				assert.True(t, trash[0].DeletedAt.Valid)
+				assert.Equal(t, []uint{done.ID}, taskIDs(filtered))
+				assert.Equal(t, []uint{done.ID}, taskIDs(page))
+				assert.Equal(t, []uint{kept.ID}, taskIDs(live))
+			})
+
+			t.Run("restore brings back the series", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, third := createSeries(t, repo)
+				require.NoError(t, repo.Delete(ctx, second.ID, 0, false)) // deleted on its own first
+				require.NoError(t, repo.Update(ctx, series.ID, 0, map[string]interface{}{
+					"ex_dates": models.DateList{day(7), day(21)},
+				}))
+				require.NoError(t, repo.Delete(ctx, series.ID, 0, false))
+				deleted, err := repo.List(ctx, dto.TaskFilter{Trashed: true, Limit: 10})
+				require.NoError(t, err)
+				require.Len(t, deleted, 1)
+
+				// Act
+				err = repo.Restore(ctx, series.ID, deleted[0].Version)
+
+				// Assert
+				require.NoError(t, err)
+				found, err := repo.GetByID(ctx, series.ID)
+				require.NoError(t, err)
+				assert.False(t, found.DeletedAt.Valid)
+				assert.Equal(t, deleted[0].Version+1, found.Version)
+				_, err = repo.GetByID(ctx, third.ID)
+				assert.NoError(t, err)
+				_, err = repo.GetByID(ctx, second.ID)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "excluded occurrences stay deleted")
+				trash, err := repo.Count(ctx, dto.TaskFilter{Trashed: true})
+				require.NoError(t, err)
+				assert.Zero(t, trash)
+			})
+
+			t.Run("restore errors", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, _, _, sibling := createTree(t, repo)
+				require.NoError(t, repo.Delete(ctx, sibling.ID, 0, false))
+				require.NoError(t, repo.Delete(ctx, root.ID, 0, true))
+
+				// Act
+				liveErr := repo.Restore(ctx, 999, 0)
+				parentErr := repo.Restore(ctx, sibling.ID, 0)
+				versionErr := repo.Restore(ctx, root.ID, root.Version+1)
+				err := repo.Restore(ctx, root.ID, 0)
+				require.NoError(t, err)
+				siblingErr := repo.Restore(ctx, sibling.ID, 0)
+				againErr := repo.Restore(ctx, root.ID, 0)
+
+				// Assert
+				assert.ErrorIs(t, liveErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, parentErr, repositories.ErrParentTrashed)
+				assert.ErrorIs(t, versionErr, repositories.ErrVersionMismatch)
+				assert.NoError(t, siblingErr)
+				assert.ErrorIs(t, againErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("restore brings back the subtasks deleted with it", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, child, leaf, sibling := createTree(t, repo)
+				require.NoError(t, repo.Delete(ctx, sibling.ID, 0, false)) // deleted on its own first
+				require.NoError(t, repo.Delete(ctx, root.ID, 0, true))
+
+				// Act
+				err := repo.Restore(ctx, root.ID, 0)
+
+				// Assert
+				require.NoError(t, err)
+				for _, task := range []*models.Task{child, leaf} {
+					found, err := repo.GetByID(ctx, task.ID)
+					require.NoError(t, err)
+					assert.Equal(t, task.Version+1, found.Version)
+				}
+				trash, err := repo.List(ctx, dto.TaskFilter{Trashed: true, Limit: 10})
+				require.NoError(t, err)
+				assert.Equal(t, []uint{sibling.ID}, taskIDs(trash))
+			})
+
+			t.Run("purge", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				root, child, leaf, sibling := createTree(t, repo)
+				blocker := &models.Task{Title: "Blocker", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, blocker))
+				require.NoError(t, repo.AddDependency(ctx, blocker.ID, child.ID))
+				require.NoError(t, repo.Delete(ctx, sibling.ID, 0, false))
+				require.NoError(t, repo.Delete(ctx, root.ID, 0, true))
+
+				// Act
+				liveErr := repo.Purge(ctx, blocker.ID, 0, false)
+				versionErr := repo.Purge(ctx, root.ID, root.Version+1, true)
+				err := repo.Purge(ctx, child.ID, 0, true)
+
+				// Assert
+				assert.ErrorIs(t, liveErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, versionErr, repositories.ErrVersionMismatch)
+				require.NoError(t, err)
+				trash, err := repo.List(ctx, dto.TaskFilter{Trashed: true, Limit: 10})
+				require.NoError(t, err)
+				assert.Equal(t, []uint{root.ID, sibling.ID}, taskIDs(trash), "the leaf went with its parent")
+				assert.NotContains(t, taskIDs(trash), leaf.ID)
+				blockers, err := repo.ListBlockers(ctx, blocker.ID)
+				require.NoError(t, err)
+				assert.Empty(t, blockers)
+				require.NoError(t, repo.Restore(ctx, root.ID, 0))
+				require.NoError(t, repo.Restore(ctx, sibling.ID, 0))
+			})
+
+			t.Run("purge deleted before", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				series, second, _ := createSeries(t, repo)
+				parent := &models.Task{Title: "Parent", Date: day(0)}
+				require.NoError(t, repo.Create(ctx, parent))
+				child := &models.Task{Title: "Child", Date: day(0), ParentID: &parent.ID}
+				require.NoError(t, repo.Create(ctx, child))
+				require.NoError(t, repo.Delete(ctx, series.ID, 0, false))
+				require.NoError(t, repo.Delete(ctx, parent.ID, 0, true))
+
+				// Act
+				none, err := repo.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+				require.NoError(t, err)
+				purged, err := repo.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+				require.NoError(t, err)
+
+				// Assert
+				assert.Zero(t, none)
+				assert.Equal(t, int64(3), purged)
+				overrides, err := repo.ListOverrides(ctx, []uint{series.ID})
+				require.NoError(t, err)
+				assert.Empty(t, overrides, "occurrence %d goes with its series", second.ID)
+				trash, err := repo.Count(ctx, dto.TaskFilter{Trashed: true})
+				require.NoError(t, err)
+				assert.Zero(t, trash)
+			})
+		})
+	}
+}
+
+func TestTaskRepository_DeletionTime(t *testing.T) {
+	type repos struct {
+		tasks   repositories.TaskRepository
+		outbox  repositories.OutboxRepository
+		history repositories.HistoryRepository
+	}
+	factories := map[string]func(t *testing.T, clk clock.Clock) repos{
+		"memory": func(t *testing.T, clk clock.Clock) repos {
+			store := repositories.NewMemoryStore(clk)
+			return repos{
+				repositories.NewTaskRepositoryMemory(store), repositories.NewOutboxRepositoryMemory(store),
+				repositories.NewHistoryRepositoryMemory(store),
+			}
+		},
+		"sqlite": func(t *testing.T, clk clock.Clock) repos {
+			db := setupSQLiteDBAt(t, clk)
+			return repos{
+				repositories.NewTaskRepositoryImpl(db), repositories.NewOutboxRepositoryImpl(db),
+				repositories.NewHistoryRepositoryImpl(db),
+			}
+		},
+	}
+	for name, newRepos := range factories {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+			deleted := created.Add(time.Hour)
+			clk := clock.NewFake(created)
+			r := newRepos(t, clk)
+			ctx := context.Background()
+			root, _, leaf, _ := createTree(t, r.tasks)
+			drain(t, r.outbox)
+			clk.Set(deleted)
+
+			// Act
+			err := r.tasks.Delete(ctx, root.ID, 0, true)
+
+			// Assert
+			require.NoError(t, err)
+			found, err := r.tasks.GetTrashed(ctx, root.ID)
+			require.NoError(t, err)
+			assert.True(t, created.Equal(found.CreatedAt))
+			assert.True(t, deleted.Equal(found.DeletedAt.Time))
+			events := drain(t, r.outbox)
+			require.Len(t, events, 4)
+			for _, event := range events {
+				assert.True(t, deleted.Equal(event.CreatedAt))
+			}
+			entries, err := r.history.List(ctx, leaf.ID, 1, 0)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, models.HistoryDeleted, entries[0].Action)
+			assert.True(t, deleted.Equal(entries[0].CreatedAt))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+//go:generate mockgen -source=./webhook_repository.go -destination=./mock/webhook_repository.go -package=mock
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"todo-api/internal/models"
+)
+
+// WebhookRepository stores webhook subscriptions and the deliveries of
+// events to them. It returns gorm.ErrRecordNotFound for missing
+// subscriptions and deliveries. Deleting a subscription deletes its
+// deliveries.
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	// ListSubscriptions returns every subscription, oldest first.
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id uint, updates map[string]interface{}) error
+	DeleteSubscription(ctx context.Context, id uint) error
+
+	// CreateDeliveries stores the deliveries of one event, setting their IDs.
+	// An event already queued for a subscription is not queued again.
+	CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, subscriptionID uint, id uint) (*models.WebhookDelivery, error)
+	// ListDeliveries returns up to limit deliveries to a subscription, newest
+	// first, only those in status unless it is empty.
+	ListDeliveries(ctx context.Context, subscriptionID uint, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error)
+	// ListDueDeliveries returns up to limit pending deliveries to active
+	// subscriptions whose next attempt is due at now, oldest first.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// ClaimDelivery takes a due delivery for one attempt the way
+	// ReminderRepository.Claim takes a reminder.
+	ClaimDelivery(ctx context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error)
+	// UpdateDelivery sets delivery columns of a delivery.
+	UpdateDelivery(ctx context.Context, id uint, updates map[string]interface{}) error
+}
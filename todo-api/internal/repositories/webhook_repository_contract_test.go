@@ -0,0 +1,212 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+func webhookRepositoryFactories() map[string]func(t *testing.T) repositories.WebhookRepository {
+	return map[string]func(t *testing.T) repositories.WebhookRepository{
+		"memory": func(t *testing.T) repositories.WebhookRepository {
+			return repositories.NewWebhookRepositoryMemory(repositories.NewMemoryStore(clock.Real()))
+		},
+		"sqlite": func(t *testing.T) repositories.WebhookRepository {
+			return repositories.NewWebhookRepositoryImpl(setupSQLiteDB(t))
+		},
+	}
+}
+
+func subscription(events ...models.EventType) *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		URL:    "https://hooks.example.com/tasks",
+		Secret: "0123456789abcdef",
+		Events: events,
+		Active: true,
+	}
+}
+
+func delivery(subscriptionID uint, eventID string, nextAttemptAt time.Time) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      models.EventTaskCreated,
+		Payload:        models.RawJSON(`{"id":"` + eventID + `"}`),
+		Status:         models.DeliveryPending,
+		NextAttemptAt:  &nextAttemptAt,
+	}
+}
+
+func TestWebhookRepository_Contract(t *testing.T) {
+	for name, newRepo := range webhookRepositoryFactories() {
+		t.Run(name, func(t *testing.T) {
+			t.Run("subscriptions", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				all := subscription()
+				paused := subscription(models.EventTaskCreated, models.EventTaskDeleted)
+				paused.Active = false
+				require.NoError(t, repo.CreateSubscription(ctx, all))
+				require.NoError(t, repo.CreateSubscription(ctx, paused))
+
+				// Act
+				listed, err := repo.ListSubscriptions(ctx)
+				require.NoError(t, err)
+				err = repo.UpdateSubscription(ctx, paused.ID, map[string]interface{}{
+					"active": true,
+					"events": models.EventList{models.EventTaskCompleted},
+				})
+				require.NoError(t, err)
+				updated, err := repo.GetSubscription(ctx, paused.ID)
+				require.NoError(t, err)
+				deleteErr := repo.DeleteSubscription(ctx, all.ID)
+				_, getErr := repo.GetSubscription(ctx, all.ID)
+				missingErr := repo.UpdateSubscription(ctx, 42, map[string]interface{}{"active": false})
+
+				// Assert
+				require.Len(t, listed, 2)
+				assert.Equal(t, all.ID, listed[0].ID)
+				assert.Empty(t, listed[0].Events)
+				assert.Equal(t, models.EventList{models.EventTaskCreated, models.EventTaskDeleted}, listed[1].Events)
+				assert.False(t, listed[1].Active)
+				assert.Equal(t, "0123456789abcdef", listed[1].Secret)
+				assert.True(t, updated.Active)
+				assert.Equal(t, models.EventList{models.EventTaskCompleted}, updated.Events)
+				assert.NoError(t, deleteErr)
+				assert.ErrorIs(t, getErr, gorm.ErrRecordNotFound)
+				assert.ErrorIs(t, missingErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("delivery log", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				sub, other := subscription(), subscription()
+				require.NoError(t, repo.CreateSubscription(ctx, sub))
+				require.NoError(t, repo.CreateSubscription(ctx, other))
+				deliveries := []models.WebhookDelivery{
+					delivery(sub.ID, "e1", at(9, 0)),
+					delivery(sub.ID, "e2", at(9, 0)),
+					delivery(sub.ID, "e3", at(9, 0)),
+					delivery(other.ID, "e1", at(9, 0)),
+				}
+				deliveries[1].Status = models.DeliveryDead
+				require.NoError(t, repo.CreateDeliveries(ctx, deliveries))
+
+				// Act
+				newest, err := repo.ListDeliveries(ctx, sub.ID, "", 2)
+				require.NoError(t, err)
+				dead, err := repo.ListDeliveries(ctx, sub.ID, models.DeliveryDead, 10)
+				require.NoError(t, err)
+				found, err := repo.GetDelivery(ctx, sub.ID, deliveries[0].ID)
+				require.NoError(t, err)
+				_, wrongSubscriptionErr := repo.GetDelivery(ctx, other.ID, deliveries[0].ID)
+
+				// Assert
+				assert.NotZero(t, deliveries[0].ID)
+				require.Len(t, newest, 2)
+				assert.Equal(t, []string{"e3", "e2"}, []string{newest[0].EventID, newest[1].EventID})
+				require.Len(t, dead, 1)
+				assert.Equal(t, "e2", dead[0].EventID)
+				assert.Equal(t, models.RawJSON(`{"id":"e1"}`), found.Payload)
+				assert.ErrorIs(t, wrongSubscriptionErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("due deliveries of active subscriptions", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				active, paused := subscription(), subscription()
+				paused.Active = false
+				require.NoError(t, repo.CreateSubscription(ctx, active))
+				require.NoError(t, repo.CreateSubscription(ctx, paused))
+				deliveries := []models.WebhookDelivery{
+					delivery(active.ID, "second", at(9, 30)),
+					delivery(active.ID, "first", at(9, 0)),
+					delivery(active.ID, "future", at(11, 0)),
+					delivery(active.ID, "delivered", at(8, 0)),
+					delivery(paused.ID, "paused", at(9, 0)),
+				}
+				deliveries[3].Status = models.DeliveryDelivered
+				require.NoError(t, repo.CreateDeliveries(ctx, deliveries))
+
+				// Act
+				due, err := repo.ListDueDeliveries(ctx, at(10, 0), 10)
+				require.NoError(t, err)
+				limited, err := repo.ListDueDeliveries(ctx, at(10, 0), 1)
+				require.NoError(t, err)
+
+				// Assert
+				require.Len(t, due, 2)
+				assert.Equal(t, []string{"first", "second"}, []string{due[0].EventID, due[1].EventID})
+				require.Len(t, limited, 1)
+				assert.Equal(t, "first", limited[0].EventID)
+			})
+
+			t.Run("claim and record an attempt", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				sub := subscription()
+				require.NoError(t, repo.CreateSubscription(ctx, sub))
+				deliveries := []models.WebhookDelivery{delivery(sub.ID, "e1", at(9, 0))}
+				require.NoError(t, repo.CreateDeliveries(ctx, deliveries))
+				id := deliveries[0].ID
+
+				// Act
+				claimed, err := repo.ClaimDelivery(ctx, id, at(9, 0), at(9, 1))
+				require.NoError(t, err)
+				again, err := repo.ClaimDelivery(ctx, id, at(9, 0), at(9, 1))
+				require.NoError(t, err)
+				err = repo.UpdateDelivery(ctx, id, map[string]interface{}{
+					"status":          models.DeliveryDead,
+					"next_attempt_at": nil,
+					"last_error":      "503 Service Unavailable",
+					"response_status": 503,
+				})
+				require.NoError(t, err)
+				recorded, err := repo.GetDelivery(ctx, sub.ID, id)
+				require.NoError(t, err)
+				missingErr := repo.UpdateDelivery(ctx, 42, map[string]interface{}{"last_error": "boom"})
+
+				// Assert
+				assert.True(t, claimed)
+				assert.False(t, again)
+				assert.Equal(t, 1, recorded.Attempts)
+				assert.Equal(t, models.DeliveryDead, recorded.Status)
+				assert.Nil(t, recorded.NextAttemptAt)
+				assert.Equal(t, 503, recorded.ResponseStatus)
+				assert.ErrorIs(t, missingErr, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("deleting a subscription deletes its deliveries", func(t *testing.T) {
+				// Arrange
+				repo := newRepo(t)
+				ctx := context.Background()
+				sub := subscription()
+				require.NoError(t, repo.CreateSubscription(ctx, sub))
+				require.NoError(t, repo.CreateDeliveries(ctx, []models.WebhookDelivery{delivery(sub.ID, "e1", at(9, 0))}))
+
+				// Act
+				require.NoError(t, repo.DeleteSubscription(ctx, sub.ID))
+				due, err := repo.ListDueDeliveries(ctx, at(10, 0), 10)
+				require.NoError(t, err)
+				deliveries, err := repo.ListDeliveries(ctx, sub.ID, "", 10)
+				require.NoError(t, err)
+
+				// Assert
+				assert.Empty(t, due)
+				assert.Empty(t, deliveries)
+			})
+		})
+	}
+}
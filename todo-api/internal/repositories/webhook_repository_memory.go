@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+)
+
+// webhookRepositoryMemory keeps webhook subscriptions and deliveries in a
+// MemoryStore.
+type webhookRepositoryMemory struct {
+	*MemoryStore
+}
+
+func NewWebhookRepositoryMemory(store *MemoryStore) WebhookRepository {
+	return &webhookRepositoryMemory{MemoryStore: store}
+}
+
+func (r *webhookRepositoryMemory) CreateSubscription(_ context.Context, subscription *models.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	subscription.ID = r.nextSubscriptionID
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	r.nextSubscriptionID++
+
+	stored := *subscription
+	stored.Events = slices.Clone(subscription.Events)
+	r.subscriptions[subscription.ID] = &stored
+	return nil
+}
+
+func (r *webhookRepositoryMemory) GetSubscription(_ context.Context, id uint) (*models.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *subscription
+	found.Events = slices.Clone(subscription.Events)
+	return &found, nil
+}
+
+func (r *webhookRepositoryMemory) ListSubscriptions(_ context.Context) ([]models.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := []models.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		listed := *subscription
+		listed.Events = slices.Clone(subscription.Events)
+		subscriptions = append(subscriptions, listed)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions, nil
+}
+
+func (r *webhookRepositoryMemory) UpdateSubscription(_ context.Context, id uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	updated := *subscription
+	for column, value := range updates {
+		var ok bool
+		switch column {
+		case "url":
+			updated.URL, ok = value.(string)
+		case "secret":
+			updated.Secret, ok = value.(string)
+		case "events":
+			var events models.EventList
+			events, ok = value.(models.EventList)
+			updated.Events = slices.Clone(events)
+		case "active":
+			updated.Active, ok = value.(bool)
+		default:
+			return fmt.Errorf("unknown webhook subscription column %q", column)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v for webhook subscription column %q", value, column)
+		}
+	}
+	updated.UpdatedAt = r.clock.Now()
+	r.subscriptions[id] = &updated
+	return nil
+}
+
+func (r *webhookRepositoryMemory) DeleteSubscription(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.subscriptions, id)
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *webhookRepositoryMemory) CreateDeliveries(_ context.Context, deliveries []models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		if _, ok := r.subscriptions[delivery.SubscriptionID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+
+	now := r.clock.Now()
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if r.hasDelivery(delivery.SubscriptionID, delivery.EventID) {
+			continue
+		}
+		delivery.ID = r.nextDeliveryID
+		if delivery.Status == "" {
+			delivery.Status = models.DeliveryPending
+		}
+		delivery.CreatedAt = now
+		delivery.UpdatedAt = now
+		r.nextDeliveryID++
+
+		stored := *delivery
+		r.deliveries[delivery.ID] = &stored
+	}
+	return nil
+}
+
+func (r *webhookRepositoryMemory) GetDelivery(_ context.Context, subscriptionID uint, id uint) (*models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok || delivery.SubscriptionID != subscriptionID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *delivery
+	return &found, nil
+}
+
+func (r *webhookRepositoryMemory) ListDeliveries(_ context.Context, subscriptionID uint, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID > deliveries[j].ID
+	})
+	if limit > 0 && limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepositoryMemory) ListDueDeliveries(_ context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if r.subscriptions[delivery.SubscriptionID].Active && deliveryDue(delivery, now) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		a, b := *deliveries[i].NextAttemptAt, *deliveries[j].NextAttemptAt
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	if limit > 0 && limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepositoryMemory) ClaimDelivery(_ context.Context, id uint, now time.Time, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok || !deliveryDue(delivery, now) {
+		return false, nil
+	}
+	claimed := *delivery
+	claimed.Attempts++
+	claimed.NextAttemptAt = &leaseUntil
+	claimed.UpdatedAt = r.clock.Now()
+	r.deliveries[id] = &claimed
+	return true, nil
+}
+
+func (r *webhookRepositoryMemory) UpdateDelivery(_ context.Context, id uint, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	updated := *delivery
+	for column, value := range updates {
+		var ok bool
+		switch column {
+		case "status":
+			updated.Status, ok = value.(models.DeliveryStatus)
+		case "attempts":
+			updated.Attempts, ok = value.(int)
+		case "last_error":
+			updated.LastError, ok = value.(string)
+		case "response_status":
+			updated.ResponseStatus, ok = value.(int)
+		case "next_attempt_at":
+			updated.NextAttemptAt, ok = optionalTime(value)
+		case "delivered_at":
+			updated.DeliveredAt, ok = optionalTime(value)
+		default:
+			return fmt.Errorf("unknown webhook delivery column %q", column)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v for webhook delivery column %q", value, column)
+		}
+	}
+	updated.UpdatedAt = r.clock.Now()
+	r.deliveries[id] = &updated
+	return nil
+}
+
+// hasDelivery reports whether an event is queued for a subscription
+// already.
+func (r *webhookRepositoryMemory) hasDelivery(subscriptionID uint, eventID string) bool {
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// deliveryDue reports whether a pending delivery's next attempt is due at
+// now.
+func deliveryDue(delivery *models.WebhookDelivery, now time.Time) bool {
+	return delivery.Status == models.DeliveryPending &&
+		delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now)
+}
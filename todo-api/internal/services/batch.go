@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/lexorank"
+)
+
+// A batch checks each of its items on its own first, the way the single
+// task endpoints would, and then writes all items that passed with one
+// set-based repository call. An atomic batch writes nothing when any item
+// fails; the others are reported as skipped.
+
+func (s *TaskServiceImpl) CreateTasks(ctx context.Context, reqs []dto.CreateTaskServiceRequest, mode string) (*dto.BatchResult, error) {
+	mode = batchMode(mode)
+	if len(reqs) > s.maxBatch {
+		return nil, newError(ErrValidation, "a batch can hold at most %d tasks", s.maxBatch)
+	}
+
+	items := make([]dto.BatchItem, len(reqs))
+	tasks := make([]models.Task, 0, len(reqs))
+	var pending []int
+	lastRanks := make(map[string]string) // last rank of each day, tasks of the batch included
+	for i, req := range reqs {
+		items[i].Index = i
+		task, err := s.newTask(ctx, req)
+		if err == nil {
+			err = s.rankLast(ctx, task, lastRanks)
+		}
+		if err != nil {
+			if !isServiceError(err) {
+				return nil, err
+			}
+			fail(items, []int{i}, err)
+			continue
+		}
+		tasks = append(tasks, *task)
+		pending = append(pending, i)
+	}
+	if !proceed(mode, items, pending) {
+		return batchResult(mode, items), nil
+	}
+
+	err := s.repo.CreateMany(ctx, tasks)
+	if err != nil && mode == dto.BatchBestEffort {
+		// The set-based insert fails as a whole; inserting the tasks one by
+		// one tells which of them broke it. Each is built anew from its
+		// request, since the failed insert may have changed the models.
+		lastRanks = make(map[string]string)
+		for _, i := range pending {
+			task, err := s.newTask(ctx, reqs[i])
+			if err == nil {
+				err = s.rankLast(ctx, task, lastRanks)
+			}
+			if err == nil {
+				if err = s.repo.Create(ctx, task); err != nil {
+					err = translateRepoError(err, 0, "failed to create task")
+				}
+			}
+			if err != nil {
+				if !isServiceError(err) {
+					return nil, err
+				}
+				fail(items, []int{i}, err)
+				continue
+			}
+			succeed(items, []int{i}, []models.Task{*task})
+		}
+		return batchResult(mode, items), nil
+	}
+	if err != nil {
+		if err = translateRepoError(err, 0, "failed to create tasks"); !isServiceError(err) {
+			return nil, err
+		}
+		fail(items, pending, err)
+		return batchResult(mode, items), nil
+	}
+
+	succeed(items, pending, tasks)
+	return batchResult(mode, items), nil
+}
+
+func (s *TaskServiceImpl) UpdateTasks(ctx context.Context, sel dto.BatchSelection, changes dto.BatchChanges) (*dto.BatchResult, error) {
+	mode := batchMode(sel.Mode)
+	updates := make(map[string]interface{})
+	if changes.Date != nil {
+		updates["date"] = *changes.Date
+	}
+	completing := false
+	if changes.Completed != nil {
+		updates["completed"] = *changes.Completed
+		completing = *changes.Completed
+		if completing && s.completion == CompletionCascade {
+			updates[repositories.CompleteSubtasksKey] = true
+		}
+	}
+	if changes.Priority != nil {
+		updates["priority"] = *changes.Priority
+	}
+	if changes.ProjectID != nil {
+		if *changes.ProjectID == 0 {
+			updates["project_id"] = nil
+		} else {
+			updates["project_id"] = *changes.ProjectID
+		}
+	}
+	if len(changes.AddTags) > 0 || len(changes.RemoveTags) > 0 {
+		updates[repositories.TagChangesKey] = repositories.TagChanges{Attach: changes.AddTags, Detach: changes.RemoveTags}
+	}
+	if len(updates) == 0 {
+		return nil, newError(ErrValidation, "no fields to update")
+	}
+
+	items, tasks, err := s.selectTasks(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	dueTimes := make(repositories.DueTimes)
+	var pending []int
+	for i, task := range tasks {
+		if task == nil {
+			continue
+		}
+		if completing {
+			if err := s.checkCompletion(task, changes.Force); err != nil {
+				fail(items, []int{i}, err)
+				continue
+			}
+		}
+		if changes.Date != nil && task.DueAt != nil {
+			dueTimes[task.ID] = dueOn(task, *changes.Date)
+		}
+		pending = append(pending, i)
+	}
+	if !proceed(mode, items, pending) {
+		return batchResult(mode, items), nil
+	}
+	if len(dueTimes) > 0 {
+		updates[repositories.DueTimesKey] = dueTimes
+	}
+
+	ids := itemIDs(items, pending)
+	if err = s.repo.UpdateMany(ctx, ids, updates); err != nil {
+		if err = translateBatchError(err, "failed to update tasks"); !isServiceError(err) {
+			return nil, err
+		}
+		fail(items, pending, err)
+		return batchResult(mode, items), nil
+	}
+
+	updated, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	succeed(items, pending, updated)
+	return batchResult(mode, items), nil
+}
+
+func (s *TaskServiceImpl) DeleteTasks(ctx context.Context, sel dto.BatchSelection, cascade bool) (*dto.BatchResult, error) {
+	mode := batchMode(sel.Mode)
+	items, tasks, err := s.selectTasks(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []int
+	for i, task := range tasks {
+		if task != nil {
+			pending = append(pending, i)
+		}
+	}
+	if !proceed(mode, items, pending) {
+		return batchResult(mode, items), nil
+	}
+
+	if err = s.repo.DeleteMany(ctx, itemIDs(items, pending), cascade); err != nil {
+		if err = translateBatchError(err, "failed to delete tasks"); !isServiceError(err) {
+			return nil, err
+		}
+		fail(items, pending, err)
+		return batchResult(mode, items), nil
+	}
+
+	succeed(items, pending, nil)
+	return batchResult(mode, items), nil
+}
+
+// selectTasks loads the tasks of sel and makes an item for each: the
+// listed IDs, duplicates dropped, or the tasks matching the filter in
+// list order. tasks holds the task of each item, nil for IDs that are not
+// live tasks and for recurring tasks matched by a date filter, whose items
+// have already failed.
+func (s *TaskServiceImpl) selectTasks(ctx context.Context, sel dto.BatchSelection) ([]dto.BatchItem, []*models.Task, error) {
+	var ids []uint
+	var found []models.Task
+	if sel.Filter != nil {
+		filter := *sel.Filter
+		// One task more than a batch can hold tells that the filter
+		// matches too many.
+		filter.Limit = s.maxBatch + 1
+		filter.Offset = 0
+		filter.Cursor = nil
+		filter.Sort = filter.OrderOrDefault()
+		filter.Highlight = false
+		filter.Tree = false
+		filter.Trashed = false
+
+		var err error
+		if found, err = s.repo.List(ctx, filter); err != nil {
+			return nil, nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		if len(found) > s.maxBatch {
+			return nil, nil, newError(ErrValidation, "the filter matches more than %d tasks; narrow it down", s.maxBatch)
+		}
+		for _, task := range found {
+			ids = append(ids, task.ID)
+		}
+	} else {
+		seen := make(map[uint]bool, len(sel.IDs))
+		for _, id := range sel.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, nil, newError(ErrValidation, "select the tasks by ids or by a filter")
+		}
+		if len(ids) > s.maxBatch {
+			return nil, nil, newError(ErrValidation, "a batch can hold at most %d tasks", s.maxBatch)
+		}
+
+		var err error
+		if found, err = s.repo.GetByIDs(ctx, ids); err != nil {
+			return nil, nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+	}
+
+	// A date filter matches a recurring task by its occurrences, which the
+	// task list shows one by one; a batch cannot change them that way.
+	dated := sel.Filter != nil && (sel.Filter.DateFrom != nil || sel.Filter.DateTo != nil)
+	byID := make(map[uint]*models.Task, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+	items := make([]dto.BatchItem, len(ids))
+	tasks := make([]*models.Task, len(ids))
+	for i, id := range ids {
+		items[i] = dto.BatchItem{Index: i, ID: id}
+		switch tasks[i] = byID[id]; {
+		case tasks[i] == nil:
+			fail(items, []int{i}, notFoundError(id))
+		case dated && tasks[i].Recurrence != "":
+			fail(items, []int{i}, newError(ErrValidation,
+				"task %d is recurring and cannot be selected by date; select it by id to change the whole series", id))
+			tasks[i] = nil
+		}
+	}
+	return items, tasks, nil
+}
+
+// rankLast ranks task last on its day, after the tasks of the batch placed
+// there before it, which lastRanks keeps track of.
+func (s *TaskServiceImpl) rankLast(ctx context.Context, task *models.Task, lastRanks map[string]string) error {
+	day := task.Date.Format(time.DateOnly)
+	last, ok := lastRanks[day]
+	if !ok {
+		var err error
+		if last, err = s.repo.LastRank(ctx, task.Date); err != nil {
+			return fmt.Errorf("failed to rank task: %w", err)
+		}
+	}
+	rank, err := lexorank.Between(last, "")
+	if err != nil {
+		return fmt.Errorf("failed to rank task: %w", err)
+	}
+	task.Rank = rank
+	lastRanks[day] = rank
+	return nil
+}
+
+// checkCompletion does the checks of UpdateTask for completing task, on
+// the task as loaded: no open blockers unless force is set, and no open
+// subtasks under the block policy.
+func (s *TaskServiceImpl) checkCompletion(task *models.Task, force bool) error {
+	if !force && task.Blocked {
+		return newError(ErrConflict, "task %d is blocked by open tasks; complete them first or pass force=true", task.ID)
+	}
+	if s.completion == CompletionBlock {
+		if open := task.SubtasksTotal - task.SubtasksCompleted; open > 0 {
+			return newError(ErrConflict, "task %d has %d open subtasks", task.ID, open)
+		}
+	}
+	return nil
+}
+
+func batchMode(mode string) string {
+	if mode == "" {
+		return dto.BatchAtomic
+	}
+	return mode
+}
+
+// proceed tells whether the pending items of a batch are to be written.
+// In an atomic batch with a failed item they are skipped instead.
+func proceed(mode string, items []dto.BatchItem, pending []int) bool {
+	if mode == dto.BatchAtomic && len(pending) < len(items) {
+		for _, i := range pending {
+			items[i].Status = dto.BatchSkipped
+		}
+		return false
+	}
+	return len(pending) > 0
+}
+
+func fail(items []dto.BatchItem, indexes []int, err error) {
+	for _, i := range indexes {
+		items[i].Status = dto.BatchFailed
+		items[i].Err = err
+	}
+}
+
+// succeed marks the items at indexes written, with their tasks as written
+// when tasks is not nil.
+func succeed(items []dto.BatchItem, indexes []int, tasks []models.Task) {
+	byID := make(map[uint]*models.Task, len(tasks))
+	for i := range tasks {
+		byID[tasks[i].ID] = &tasks[i]
+	}
+	for j, i := range indexes {
+		items[i].Status = dto.BatchSucceeded
+		if items[i].ID == 0 && j < len(tasks) {
+			// Created tasks get their IDs from the write.
+			items[i].ID = tasks[j].ID
+		}
+		items[i].Task = byID[items[i].ID]
+	}
+}
+
+func itemIDs(items []dto.BatchItem, indexes []int) []uint {
+	ids := make([]uint, len(indexes))
+	for j, i := range indexes {
+		ids[j] = items[i].ID
+	}
+	return ids
+}
+
+func batchResult(mode string, items []dto.BatchItem) *dto.BatchResult {
+	result := &dto.BatchResult{Mode: mode, Items: items}
+	for _, item := range items {
+		switch item.Status {
+		case dto.BatchSucceeded:
+			result.Succeeded++
+		case dto.BatchFailed:
+			result.Failed++
+		case dto.BatchSkipped:
+			result.Skipped++
+		}
+	}
+	return result
+}
+
+// isServiceError tells whether err is an *Error, meant for clients,
+// rather than an unexpected failure.
+func isServiceError(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
@@ -0,0 +1,364 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+// newBatchService returns a service over a fresh memory store whose
+// clock reads today.
+func newBatchService(t *testing.T, today time.Time, opts ...services.Option) (*services.TaskServiceImpl, repositories.TaskRepository) {
+	t.Helper()
+	repo := repositories.NewTaskRepositoryMemory(repositories.NewMemoryStore(clock.Real()))
+	opts = append([]services.Option{services.WithClock(clock.NewFake(today))}, opts...)
+	return services.NewTaskServiceImpl(repo, opts...), repo
+}
+
+func batchStatuses(result *dto.BatchResult) []string {
+	statuses := make([]string, len(result.Items))
+	for i, item := range result.Items {
+		statuses[i] = item.Status
+	}
+	return statuses
+}
+
+func TestTaskService_CreateTasks(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	reqs := []dto.CreateTaskServiceRequest{
+		{Title: "First", Date: today},
+		{Title: "Too late", Date: today.AddDate(0, 0, -1)},
+		{Title: "Second", Date: today, DueTime: "18:30"},
+	}
+
+	t.Run("atomic", func(t *testing.T) {
+		// Arrange
+		service, repo := newBatchService(t, today)
+
+		// Act
+		result, err := service.CreateTasks(ctx, reqs, "")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, dto.BatchAtomic, result.Mode)
+		assert.Equal(t, []string{dto.BatchSkipped, dto.BatchFailed, dto.BatchSkipped}, batchStatuses(result))
+		assert.Equal(t, 0, result.Succeeded)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, 2, result.Skipped)
+		assert.ErrorIs(t, result.Items[1].Err, services.ErrValidation)
+
+		tasks, err := repo.ListByDate(ctx, today)
+		require.NoError(t, err)
+		assert.Empty(t, tasks, "an atomic batch writes nothing when a task fails")
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		// Arrange
+		service, repo := newBatchService(t, today)
+
+		// Act
+		result, err := service.CreateTasks(ctx, reqs, dto.BatchBestEffort)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{dto.BatchSucceeded, dto.BatchFailed, dto.BatchSucceeded}, batchStatuses(result))
+		assert.Equal(t, 2, result.Succeeded)
+		require.NotNil(t, result.Items[2].Task)
+		assert.Equal(t, result.Items[2].Task.ID, result.Items[2].ID)
+		assert.NotNil(t, result.Items[2].Task.DueAt)
+
+		tasks, err := repo.ListByDate(ctx, today)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "First", tasks[0].Title)
+		assert.Equal(t, "Second", tasks[1].Title, "tasks of a batch are ranked after each other")
+	})
+
+	t.Run("best effort attributes a failed write", func(t *testing.T) {
+		// Arrange
+		service, _ := newBatchService(t, today)
+		reqs := []dto.CreateTaskServiceRequest{
+			{Title: "Tagged", Date: today, Tags: []string{"nowhere"}},
+			{Title: "Plain", Date: today},
+		}
+
+		// Act
+		result, err := service.CreateTasks(ctx, reqs, dto.BatchBestEffort)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{dto.BatchFailed, dto.BatchSucceeded}, batchStatuses(result))
+		assert.ErrorIs(t, result.Items[0].Err, services.ErrValidation)
+	})
+
+	t.Run("best effort retries with fresh tasks", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithClock(clock.NewFake(today)))
+		reqs := []dto.CreateTaskServiceRequest{{Title: "First", Date: today}, {Title: "Second", Date: today}}
+
+		mockRepo.EXPECT().LastRank(gomock.Any(), gomock.Any()).Return("", nil).Times(2)
+		mockRepo.EXPECT().CreateMany(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tasks []models.Task) error {
+			// A failed insert can leave the tasks changed.
+			for i := range tasks {
+				tasks[i].ID, tasks[i].Version, tasks[i].Rank = uint(i+1), 1, "zzz"
+			}
+			return repositories.ErrUnknownTag
+		})
+		var created []models.Task
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task *models.Task) error {
+			created = append(created, *task)
+			task.ID = uint(len(created) + 10)
+			return nil
+		}).Times(2)
+
+		// Act
+		result, err := service.CreateTasks(ctx, reqs, dto.BatchBestEffort)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{dto.BatchSucceeded, dto.BatchSucceeded}, batchStatuses(result))
+		require.Len(t, created, 2)
+		for _, task := range created {
+			assert.Zero(t, task.ID)
+			assert.Zero(t, task.Version)
+			assert.NotEqual(t, "zzz", task.Rank)
+		}
+		assert.Less(t, created[0].Rank, created[1].Rank)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		// Arrange
+		service, _ := newBatchService(t, today, services.WithMaxBatch(2))
+
+		// Act
+		_, err := service.CreateTasks(ctx, reqs, "")
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+	})
+}
+
+func TestTaskService_UpdateTasks(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	ctx := context.Background()
+
+	// seed stores tasks directly, since yesterday is in the past for the
+	// service.
+	seed := func(t *testing.T, repo repositories.TaskRepository, tasks ...models.Task) []uint {
+		t.Helper()
+		require.NoError(t, repo.CreateMany(ctx, tasks))
+		ids := make([]uint, len(tasks))
+		for i := range tasks {
+			ids[i] = tasks[i].ID
+		}
+		return ids
+	}
+
+	t.Run("moves the open tasks of a day", func(t *testing.T) {
+		// Arrange
+		service, repo := newBatchService(t, today)
+		due := time.Date(2026, 10, 15, 15, 30, 0, 0, time.UTC)
+		ids := seed(t, repo,
+			models.Task{Title: "Open", Date: yesterday, DueAt: &due, TimeZone: "Europe/Moscow"},
+			models.Task{Title: "Done", Date: yesterday, Completed: true},
+			models.Task{Title: "Open too", Date: yesterday},
+		)
+		open := false
+		filter := dto.TaskFilter{Completed: &open, DateFrom: &yesterday, DateTo: &yesterday}
+
+		// Act
+		result, err := service.UpdateTasks(ctx, dto.BatchSelection{Filter: &filter}, dto.BatchChanges{Date: &today})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, []uint{ids[0], ids[2]}, []uint{result.Items[0].ID, result.Items[1].ID})
+
+		moved, err := repo.GetByID(ctx, ids[0])
+		require.NoError(t, err)
+		assert.True(t, today.Equal(moved.Date))
+		require.NotNil(t, moved.DueAt)
+		assert.True(t, due.AddDate(0, 0, 1).Equal(*moved.DueAt), "the due time moves along with the date")
+		done, err := repo.GetByID(ctx, ids[1])
+		require.NoError(t, err)
+		assert.True(t, yesterday.Equal(done.Date))
+	})
+
+	t.Run("recurring tasks selected by date fail", func(t *testing.T) {
+		// Arrange
+		service, repo := newBatchService(t, today)
+		ids := seed(t, repo,
+			models.Task{Title: "Standup", Date: yesterday, Recurrence: "FREQ=DAILY"},
+			models.Task{Title: "Report", Date: yesterday},
+		)
+		filter := dto.TaskFilter{DateFrom: &yesterday, DateTo: &today}
+		high := models.PriorityHigh
+
+		// Act
+		result, err := service.UpdateTasks(ctx, dto.BatchSelection{Filter: &filter, Mode: dto.BatchBestEffort},
+			dto.BatchChanges{Priority: &high})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{dto.BatchFailed, dto.BatchSucceeded}, batchStatuses(result))
+		assert.Equal(t, ids, []uint{result.Items[0].ID, result.Items[1].ID})
+		assert.ErrorIs(t, result.Items[0].Err, services.ErrValidation)
+		series, err := repo.GetByID(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, models.PriorityNone, series.Priority)
+	})
+
+	t.Run("unknown IDs", func(t *testing.T) {
+		for _, tt := range []struct {
+			name     string
+			mode     string
+			statuses []string
+			priority models.Priority
+		}{
+			{"atomic", dto.BatchAtomic, []string{dto.BatchSkipped, dto.BatchFailed}, models.PriorityNone},
+			{"best effort", dto.BatchBestEffort, []string{dto.BatchSucceeded, dto.BatchFailed}, models.PriorityHigh},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				service, repo := newBatchService(t, today)
+				ids := seed(t, repo, models.Task{Title: "Task", Date: today})
+				high := models.PriorityHigh
+				sel := dto.BatchSelection{IDs: []uint{ids[0], 99, ids[0]}, Mode: tt.mode}
+
+				// Act
+				result, err := service.UpdateTasks(ctx, sel, dto.BatchChanges{Priority: &high})
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, tt.statuses, batchStatuses(result), "duplicate IDs are dropped")
+				assert.ErrorIs(t, result.Items[1].Err, services.ErrNotFound)
+
+				task, err := repo.GetByID(ctx, ids[0])
+				require.NoError(t, err)
+				assert.Equal(t, tt.priority, task.Priority)
+			})
+		}
+	})
+
+	t.Run("completing a blocked task", func(t *testing.T) {
+		// Arrange
+		service, repo := newBatchService(t, today)
+		ids := seed(t, repo, models.Task{Title: "Blocker", Date: today}, models.Task{Title: "Blocked", Date: today})
+		require.NoError(t, service.AddDependency(ctx, ids[1], ids[0]))
+		done := true
+		sel := dto.BatchSelection{IDs: []uint{ids[1]}}
+
+		// Act
+		refused, err := service.UpdateTasks(ctx, sel, dto.BatchChanges{Completed: &done})
+		require.NoError(t, err)
+		forced, err := service.UpdateTasks(ctx, sel, dto.BatchChanges{Completed: &done, Force: true})
+		require.NoError(t, err)
+
+		// Assert
+		assert.ErrorIs(t, refused.Items[0].Err, services.ErrConflict)
+		assert.Equal(t, 1, forced.Succeeded)
+		assert.True(t, forced.Items[0].Task.Completed)
+	})
+
+	t.Run("filter matches too many", func(t *testing.T) {
+		// Arrange
+		service, repo := newBatchService(t, today, services.WithMaxBatch(1))
+		seed(t, repo, models.Task{Title: "One", Date: today}, models.Task{Title: "Two", Date: today})
+		done := true
+		filter := dto.TaskFilter{DateFrom: &today, DateTo: &today}
+
+		// Act
+		_, err := service.UpdateTasks(ctx, dto.BatchSelection{Filter: &filter}, dto.BatchChanges{Completed: &done})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		// Arrange
+		service, _ := newBatchService(t, today)
+
+		// Act
+		_, err := service.UpdateTasks(ctx, dto.BatchSelection{IDs: []uint{1}}, dto.BatchChanges{})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+	})
+}
+
+func TestTaskService_UpdateTasks_WriteFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockTaskRepository(ctrl)
+	service := services.NewTaskServiceImpl(mockRepo)
+
+	tasks := []models.Task{{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}}
+	mockRepo.EXPECT().GetByIDs(gomock.Any(), []uint{1, 2}).Return(tasks, nil)
+	mockRepo.EXPECT().UpdateMany(gomock.Any(), []uint{1, 2}, gomock.Any()).Return(repositories.ErrUnknownTag)
+	sel := dto.BatchSelection{IDs: []uint{1, 2}, Mode: dto.BatchBestEffort}
+
+	// Act
+	result, err := service.UpdateTasks(context.Background(), sel, dto.BatchChanges{AddTags: []string{"nowhere"}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Failed)
+	assert.ErrorIs(t, result.Items[0].Err, services.ErrValidation)
+	assert.ErrorIs(t, result.Items[1].Err, services.ErrValidation)
+}
+
+func TestTaskService_DeleteTasks(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name    string
+		cascade bool
+	}{
+		{"reparent", false},
+		{"cascade", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service, repo := newBatchService(t, today)
+			parent, err := service.CreateTask(ctx, dto.CreateTaskServiceRequest{Title: "Parent", Date: today})
+			require.NoError(t, err)
+			child, err := service.CreateTask(ctx, dto.CreateTaskServiceRequest{Title: "Child", Date: today, ParentID: &parent.ID})
+			require.NoError(t, err)
+			other, err := service.CreateTask(ctx, dto.CreateTaskServiceRequest{Title: "Other", Date: today})
+			require.NoError(t, err)
+
+			// Act
+			result, err := service.DeleteTasks(ctx, dto.BatchSelection{IDs: []uint{parent.ID, other.ID}}, tt.cascade)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, []string{dto.BatchSucceeded, dto.BatchSucceeded}, batchStatuses(result))
+			_, err = repo.GetByID(ctx, parent.ID)
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			kept, err := repo.GetByID(ctx, child.ID)
+			if tt.cascade {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Nil(t, kept.ParentID)
+		})
+	}
+}
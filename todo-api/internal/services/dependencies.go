@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+func (s *TaskServiceImpl) ListBlockers(ctx context.Context, id uint) ([]models.Task, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+
+	blockers, err := s.repo.ListBlockers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blockers: %w", err)
+	}
+	if blockers == nil {
+		blockers = []models.Task{}
+	}
+	return blockers, nil
+}
+
+func (s *TaskServiceImpl) AddDependency(ctx context.Context, id uint, blockerID uint) error {
+	if id == blockerID {
+		return newError(ErrValidation, "task %d cannot depend on itself", id)
+	}
+	if _, err := s.repo.GetByID(ctx, blockerID); err != nil {
+		return translateRepoError(err, blockerID, "failed to get blocker")
+	}
+
+	err := s.repo.AddDependency(ctx, id, blockerID)
+	if errors.Is(err, repositories.ErrDependencyCycle) {
+		return &Error{
+			Kind:    ErrValidation,
+			Message: fmt.Sprintf("task %d cannot depend on task %d: %v", id, blockerID, err),
+			Err:     err,
+		}
+	}
+	if err != nil {
+		return translateRepoError(err, id, "failed to add dependency")
+	}
+	return nil
+}
+
+func (s *TaskServiceImpl) RemoveDependency(ctx context.Context, id uint, blockerID uint) error {
+	err := s.repo.RemoveDependency(ctx, id, blockerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("task %d does not depend on task %d", id, blockerID), Err: err}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	return nil
+}
+
+// checkBlockers refuses to complete task id while any of its blockers is
+// open.
+func (s *TaskServiceImpl) checkBlockers(ctx context.Context, id uint) error {
+	blockers, err := s.repo.ListBlockers(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to list blockers: %w", err)
+	}
+
+	var open []string
+	for _, blocker := range blockers {
+		if !blocker.Completed {
+			open = append(open, strconv.FormatUint(uint64(blocker.ID), 10))
+		}
+	}
+	if len(open) > 0 {
+		return newError(ErrConflict, "task %d is blocked by open tasks %s; complete them first or pass force=true",
+			id, strings.Join(open, ", "))
+	}
+	return nil
+}
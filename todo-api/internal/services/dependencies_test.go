@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+)
+
+func TestTaskService_AddDependency(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&models.Task{Model: gorm.Model{ID: 3}}, nil)
+		mockRepo.EXPECT().
+			AddDependency(gomock.Any(), uint(1), uint(3)).
+			Return(fmt.Errorf("%w: 1 -> 3 -> 2 -> 1", repositories.ErrDependencyCycle))
+
+		// Act
+		err := service.AddDependency(context.Background(), 1, 3)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.EqualError(t, err, "task 1 cannot depend on task 3: dependency cycle: 1 -> 3 -> 2 -> 1")
+	})
+
+	t.Run("itself", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		service := services.NewTaskServiceImpl(mock.NewMockTaskRepository(ctrl))
+
+		// Act
+		err := service.AddDependency(context.Background(), 2, 2)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+	})
+
+	t.Run("missing blocker", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		err := service.AddDependency(context.Background(), 1, 9)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "task 9 not found")
+	})
+}
+
+func TestTaskService_RemoveDependency(t *testing.T) {
+	t.Run("no such dependency", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().RemoveDependency(gomock.Any(), uint(1), uint(2)).Return(gorm.ErrRecordNotFound)
+
+		// Act
+		err := service.RemoveDependency(context.Background(), 1, 2)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "task 1 does not depend on task 2")
+	})
+}
+
+func TestTaskService_CompleteBlockedTask(t *testing.T) {
+	completed := true
+	blockers := []models.Task{
+		{Model: gorm.Model{ID: 2}, Completed: true},
+		{Model: gorm.Model{ID: 3}},
+		{Model: gorm.Model{ID: 5}},
+	}
+
+	t.Run("refused", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().ListBlockers(gomock.Any(), uint(1)).Return(blockers, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{Completed: &completed})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrConflict)
+		assert.Contains(t, err.Error(), "task 1 is blocked by open tasks 3, 5")
+		assert.Nil(t, task)
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"completed": true}).
+			Return(nil)
+		mockRepo.EXPECT().
+			GetByID(gomock.Any(), uint(1)).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Completed: true, Blocked: true}, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{Completed: &completed, Force: true})
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, task.Completed)
+	})
+}
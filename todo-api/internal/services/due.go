@@ -0,0 +1,84 @@
+package services
+
+import (
+	"time"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+// dueTimeLayout is the format of due times: a time of day in the request's
+// time zone.
+const dueTimeLayout = "15:04"
+
+func locationOrUTC(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// today returns the current day in loc the way task dates hold days: as
+// midnight UTC.
+func (s *TaskServiceImpl) today(loc *time.Location) time.Time {
+	y, m, d := s.clock.Now().In(locationOrUTC(loc)).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// dueAt returns the moment a task on date is due at dueTime in loc.
+func dueAt(date time.Time, dueTime string, loc *time.Location) (time.Time, error) {
+	clock, err := time.Parse(dueTimeLayout, dueTime)
+	if err != nil {
+		return time.Time{}, newError(ErrValidation, "due time must be HH:MM")
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d, clock.Hour(), clock.Minute(), 0, 0, loc).UTC(), nil
+}
+
+// dueOn returns the due time of task moved to day, at the same time of day
+// in the task's time zone, or nil for an all-day task.
+func dueOn(task *models.Task, day time.Time) *time.Time {
+	if task.DueAt == nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(task.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := task.DueAt.In(loc)
+	y, m, d := day.Date()
+	due := time.Date(y, m, d, local.Hour(), local.Minute(), 0, 0, loc).UTC()
+	return &due
+}
+
+// moveToDay places task on day, shifting its due time along.
+func moveToDay(task *models.Task, day time.Time) {
+	task.DueAt = dueOn(task, day)
+	task.Date = day
+}
+
+// updateDue adds the due time changes of req to the updates of task: a new
+// due time, or the current one moved along with the date.
+func updateDue(task *models.Task, req dto.UpdateTaskServiceRequest, updates map[string]interface{}) error {
+	date := task.Date
+	if req.Date != nil {
+		date = *req.Date
+	}
+
+	switch {
+	case req.DueTime != nil && *req.DueTime == "":
+		updates["due_at"] = (*time.Time)(nil)
+		updates["time_zone"] = ""
+	case req.DueTime != nil:
+		loc := locationOrUTC(req.Location)
+		due, err := dueAt(date, *req.DueTime, loc)
+		if err != nil {
+			return err
+		}
+		updates["due_at"] = &due
+		updates["time_zone"] = loc.String()
+	case task.DueAt != nil && !date.Equal(task.Date):
+		updates["due_at"] = dueOn(task, date)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+func TestTaskService_CreateTaskInTimeZone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	// Half past midnight on March 10 in Moscow, still March 9 in UTC.
+	now := clock.NewFake(time.Date(2026, 3, 10, 0, 30, 0, 0, moscow))
+	march9 := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	march10 := march9.AddDate(0, 0, 1)
+
+	t.Run("today in the request zone", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithClock(now))
+
+		mockRepo.EXPECT().LastRank(gomock.Any(), march10).Return("", nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		// Act
+		task, err := service.CreateTask(context.Background(), dto.CreateTaskServiceRequest{
+			Title:    "Call mom",
+			Date:     march10,
+			DueTime:  "18:30",
+			Location: moscow,
+		})
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, task.DueAt)
+		assert.Equal(t, time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC), *task.DueAt)
+		assert.Equal(t, "Europe/Moscow", task.TimeZone)
+	})
+
+	t.Run("yesterday in the request zone", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		service := services.NewTaskServiceImpl(mock.NewMockTaskRepository(ctrl), services.WithClock(now))
+
+		// Act
+		task, err := service.CreateTask(context.Background(), dto.CreateTaskServiceRequest{
+			Title:    "Call mom",
+			Date:     march9,
+			Location: moscow,
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Nil(t, task)
+	})
+
+	t.Run("today in UTC", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithClock(now))
+
+		mockRepo.EXPECT().LastRank(gomock.Any(), march9).Return("", nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		// Act
+		task, err := service.CreateTask(context.Background(), dto.CreateTaskServiceRequest{Title: "Call mom", Date: march9})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Nil(t, task.DueAt)
+		assert.Empty(t, task.TimeZone)
+	})
+}
+
+func TestTaskService_UpdateDueTime(t *testing.T) {
+	date := time.Date(2030, 3, 10, 0, 0, 0, 0, time.UTC)
+	due := time.Date(2030, 3, 10, 15, 30, 0, 0, time.UTC) // 18:30 in Moscow
+	timed := func() *models.Task {
+		return &models.Task{Model: gorm.Model{ID: 1}, Title: "Call mom", Date: date, DueAt: &due, TimeZone: "Europe/Moscow"}
+	}
+
+	t.Run("date change keeps the time of day", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+		// Moscow has no DST, so the UTC time of day stays put too.
+		nextWeek := date.AddDate(0, 0, 7)
+		nextDue := due.AddDate(0, 0, 7)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(timed(), nil)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"date": &nextWeek, "due_at": &nextDue}).
+			Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(timed(), nil)
+
+		// Act
+		_, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{Date: &nextWeek})
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("new due time in the request zone", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		dueTime := "09:00"
+		nextDue := time.Date(2030, 3, 10, 8, 0, 0, 0, time.UTC)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(timed(), nil)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"due_at": &nextDue, "time_zone": "Europe/Berlin"}).
+			Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(timed(), nil)
+
+		// Act
+		_, err = service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{DueTime: &dueTime, Location: berlin})
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("empty due time makes the task all-day", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+		allDay := ""
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(timed(), nil)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"due_at": (*time.Time)(nil), "time_zone": ""}).
+			Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(&models.Task{Model: gorm.Model{ID: 1}, Date: date}, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{DueTime: &allDay})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Nil(t, task.DueAt)
+	})
+}
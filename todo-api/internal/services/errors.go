@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/repositories"
+)
+
+// Error kinds returned by the services. Callers match them with errors.Is;
+// the concrete error is an *Error carrying a message safe to show to clients.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	// ErrPreconditionFailed means the task changed since the client read it.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrHistoryUnavailable means the service was built without a history
+	// repository to read the task history from; see WithHistory.
+	ErrHistoryUnavailable = errors.New("history unavailable")
+)
+
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFoundError(id uint) *Error {
+	return newError(ErrNotFound, "task %d not found", id)
+}
+
+// translateRepoError maps repository errors onto service error kinds and
+// wraps anything it does not recognise with msg.
+func translateRepoError(err error, id uint, msg string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		e := notFoundError(id)
+		e.Err = err
+		return e
+	case errors.Is(err, repositories.ErrVersionMismatch):
+		return &Error{Kind: ErrPreconditionFailed, Message: fmt.Sprintf("task %d has been modified by someone else", id), Err: err}
+	case errors.Is(err, repositories.ErrUnknownTag):
+		return &Error{Kind: ErrValidation, Message: err.Error(), Err: err}
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		// project_id and parent_id are the references a task write can break.
+		return &Error{Kind: ErrValidation, Message: "referenced project or parent task does not exist", Err: err}
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return &Error{Kind: ErrConflict, Message: "task conflicts with existing data", Err: err}
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
+// translateBatchError is translateRepoError for a write to the tasks of a
+// batch, which fails as a whole when any of them is gone by the time it
+// runs.
+func translateBatchError(err error, msg string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Kind: ErrConflict, Message: "tasks of the batch changed while it ran; try again", Err: err}
+	}
+	return translateRepoError(err, 0, msg)
+}
+
+// translateTagError is translateRepoError for tags.
+func translateTagError(err error, id uint, msg string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("tag %d not found", id), Err: err}
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return &Error{Kind: ErrConflict, Message: "a tag with this name already exists", Err: err}
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
+// translateProjectError is translateRepoError for projects.
+func translateProjectError(err error, id uint, msg string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("project %d not found", id), Err: err}
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// translateReminderError is translateRepoError for reminders of task taskID.
+func translateReminderError(err error, taskID uint, id uint, msg string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("reminder %d of task %d not found", id, taskID), Err: err}
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		e := notFoundError(taskID)
+		e.Err = err
+		return e
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
+// translateWebhookError is translateRepoError for webhook subscriptions.
+func translateWebhookError(err error, id uint, msg string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("webhook %d not found", id), Err: err}
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// translateDeliveryError is translateRepoError for deliveries to webhook
+// subscriptionID.
+func translateDeliveryError(err error, subscriptionID uint, id uint, msg string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("delivery %d of webhook %d not found", id, subscriptionID), Err: err}
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+func (s *TaskServiceImpl) ListTaskHistory(ctx context.Context, id uint, filter dto.HistoryFilter) (*dto.HistoryPage, error) {
+	if err := s.checkHistory(); err != nil {
+		return nil, err
+	}
+	total, err := s.history.Count(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count task history: %w", err)
+	}
+	// A task with no history has never existed, unless it dates from
+	// before the history was kept.
+	if total == 0 {
+		if _, err = s.getWithTrashed(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > s.maxLimit {
+		filter.Limit = s.maxLimit
+	}
+	filter.Offset = max(filter.Offset, 0)
+
+	entries, err := s.history.List(ctx, id, filter.Limit+1, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task history: %w", err)
+	}
+
+	hasMore := len(entries) > filter.Limit
+	if hasMore {
+		entries = entries[:filter.Limit]
+	}
+	if entries == nil {
+		entries = []models.TaskHistory{}
+	}
+	return &dto.HistoryPage{
+		Entries: entries,
+		Meta:    dto.PageMeta{Total: total, Limit: filter.Limit, HasMore: hasMore},
+	}, nil
+}
+
+// GetTaskAsOf rebuilds a task as it was at asOf by taking back, newest
+// first, the changes made to it since. Fields the history does not follow,
+// such as updated_at and the subtask counters, are as they are now.
+func (s *TaskServiceImpl) GetTaskAsOf(ctx context.Context, id uint, asOf time.Time) (*models.Task, error) {
+	if err := s.checkHistory(); err != nil {
+		return nil, err
+	}
+	task, err := s.getWithTrashed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	missing := newError(ErrNotFound, "task %d did not exist at %s", id, asOf.Format(time.RFC3339))
+	if task.CreatedAt.After(asOf) {
+		return nil, missing
+	}
+
+	changes, err := s.history.ListSince(ctx, id, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task history: %w", err)
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		if changes[i].Action == models.HistoryCreated {
+			return nil, missing
+		}
+		if err = changes[i].Changes.Revert(task); err != nil {
+			return nil, fmt.Errorf("failed to revert change %d: %w", changes[i].ID, err)
+		}
+	}
+
+	if task.DeletedAt.Valid && !task.DeletedAt.Time.After(asOf) {
+		return nil, newError(ErrNotFound, "task %d was in the trash at %s", id, asOf.Format(time.RFC3339))
+	}
+	task.DeletedAt = gorm.DeletedAt{}
+	return task, nil
+}
+
+// checkHistory fails with ErrHistoryUnavailable when there is no history
+// to read.
+func (s *TaskServiceImpl) checkHistory() error {
+	if s.history == nil {
+		return newError(ErrHistoryUnavailable, "task history is not available")
+	}
+	return nil
+}
+
+// getWithTrashed returns a task whether it is live or in the trash.
+func (s *TaskServiceImpl) getWithTrashed(ctx context.Context, id uint) (*models.Task, error) {
+	task, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		task, err = s.repo.GetTrashed(ctx, id)
+	}
+	if err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+	return task, nil
+}
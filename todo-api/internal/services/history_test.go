@@ -0,0 +1,200 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+)
+
+func change(before, after string) models.FieldChange {
+	return models.FieldChange{Before: json.RawMessage(before), After: json.RawMessage(after)}
+}
+
+func TestTaskService_ListTaskHistory(t *testing.T) {
+	t.Run("pages newest first", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		mockHistory := mock.NewMockHistoryRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory), services.WithMaxLimit(2))
+
+		mockHistory.EXPECT().Count(gomock.Any(), uint(1)).Return(int64(4), nil)
+		mockHistory.EXPECT().List(gomock.Any(), uint(1), 3, 1).
+			Return([]models.TaskHistory{{ID: 4}, {ID: 3}, {ID: 2}}, nil)
+
+		// Act
+		page, err := service.ListTaskHistory(context.Background(), 1, dto.HistoryFilter{Limit: 5, Offset: 1})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, page.Entries, 2, "the limit is capped")
+		assert.Equal(t, dto.PageMeta{Total: 4, Limit: 2, HasMore: true}, page.Meta)
+	})
+
+	t.Run("task in the trash", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		mockHistory := mock.NewMockHistoryRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory))
+
+		mockHistory.EXPECT().Count(gomock.Any(), uint(1)).Return(int64(0), nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.EXPECT().GetTrashed(gomock.Any(), uint(1)).Return(&models.Task{Model: gorm.Model{ID: 1}}, nil)
+		mockHistory.EXPECT().List(gomock.Any(), uint(1), 11, 0).Return(nil, nil)
+
+		// Act
+		page, err := service.ListTaskHistory(context.Background(), 1, dto.HistoryFilter{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotNil(t, page.Entries, "an empty page is a list, not null")
+		assert.Equal(t, 10, page.Meta.Limit)
+	})
+
+	t.Run("purged task", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		mockHistory := mock.NewMockHistoryRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory))
+
+		mockHistory.EXPECT().Count(gomock.Any(), uint(1)).Return(int64(2), nil)
+		mockHistory.EXPECT().List(gomock.Any(), uint(1), 11, 0).
+			Return([]models.TaskHistory{{ID: 2, Action: models.HistoryPurged}, {ID: 1, Action: models.HistoryCreated}}, nil)
+
+		// Act
+		page, err := service.ListTaskHistory(context.Background(), 1, dto.HistoryFilter{})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, page.Entries, 2)
+		assert.Equal(t, models.HistoryPurged, page.Entries[0].Action)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		mockHistory := mock.NewMockHistoryRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory))
+
+		mockHistory.EXPECT().Count(gomock.Any(), uint(1)).Return(int64(0), nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.EXPECT().GetTrashed(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		_, err := service.ListTaskHistory(context.Background(), 1, dto.HistoryFilter{})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+}
+
+func TestTaskService_GetTaskAsOf(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	deleted := created.Add(2 * time.Hour)
+	deletedJSON, _ := json.Marshal(deleted)
+
+	// A task created at 9:00, renamed at 10:00 and moved to the trash at 11:00.
+	trashed := func() *models.Task {
+		return &models.Task{
+			Model:   gorm.Model{ID: 1, CreatedAt: created, DeletedAt: gorm.DeletedAt{Time: deleted, Valid: true}},
+			Title:   "Renamed",
+			Version: 3,
+		}
+	}
+	renamed := models.TaskHistory{ID: 2, Version: 2, Action: models.HistoryUpdated, Changes: models.FieldChanges{
+		"title":   change(`"Original"`, `"Renamed"`),
+		"version": change(`1`, `2`),
+	}}
+	trashedEntry := models.TaskHistory{ID: 3, Version: 3, Action: models.HistoryDeleted, Changes: models.FieldChanges{
+		"deleted_at": change(`null`, string(deletedJSON)),
+		"version":    change(`2`, `3`),
+	}}
+
+	tests := []struct {
+		name    string
+		asOf    time.Time
+		since   []models.TaskHistory
+		title   string
+		version uint
+		message string
+	}{
+		{"before the rename", created.Add(30 * time.Minute), []models.TaskHistory{renamed, trashedEntry}, "Original", 1, ""},
+		{"after the rename", created.Add(90 * time.Minute), []models.TaskHistory{trashedEntry}, "Renamed", 2, ""},
+		{"in the trash", deleted.Add(time.Minute), nil, "", 0, "task 1 was in the trash at 2026-03-01T11:01:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockTaskRepository(ctrl)
+			mockHistory := mock.NewMockHistoryRepository(ctrl)
+			service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory))
+
+			mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+			mockRepo.EXPECT().GetTrashed(gomock.Any(), uint(1)).Return(trashed(), nil)
+			mockHistory.EXPECT().ListSince(gomock.Any(), uint(1), tt.asOf).Return(tt.since, nil)
+
+			// Act
+			task, err := service.GetTaskAsOf(context.Background(), 1, tt.asOf)
+
+			// Assert
+			if tt.message != "" {
+				assert.ErrorIs(t, err, services.ErrNotFound)
+				assert.EqualError(t, err, tt.message)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.title, task.Title)
+			assert.Equal(t, tt.version, task.Version)
+			assert.False(t, task.DeletedAt.Valid)
+		})
+	}
+
+	t.Run("before it was created", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mock.NewMockHistoryRepository(ctrl)))
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(trashed(), nil)
+
+		// Act
+		_, err := service.GetTaskAsOf(context.Background(), 1, created.Add(-time.Minute))
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "task 1 did not exist at 2026-03-01T08:59:00Z")
+	})
+}
+
+func TestTaskService_HistoryUnavailable(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	service := services.NewTaskServiceImpl(mock.NewMockTaskRepository(ctrl))
+	ctx := context.Background()
+	version := uint(1)
+
+	// Act
+	_, listErr := service.ListTaskHistory(ctx, 1, dto.HistoryFilter{})
+	_, asOfErr := service.GetTaskAsOf(ctx, 1, time.Now())
+	_, revertErr := service.RevertTask(ctx, 1, dto.RevertTaskServiceRequest{ToVersion: &version})
+
+	// Assert
+	assert.ErrorIs(t, listErr, services.ErrHistoryUnavailable)
+	assert.ErrorIs(t, asOfErr, services.ErrHistoryUnavailable)
+	assert.ErrorIs(t, revertErr, services.ErrHistoryUnavailable)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./reminder_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./reminder_service.go -destination=./mock/reminder_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	dto "todo-api/internal/dto"
+	models "todo-api/internal/models"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReminderService is a mock of ReminderService interface.
+type MockReminderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReminderServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockReminderServiceMockRecorder is the mock recorder for MockReminderService.
+type MockReminderServiceMockRecorder struct {
+	mock *MockReminderService
+}
+
+// NewMockReminderService creates a new mock instance.
+func NewMockReminderService(ctrl *gomock.Controller) *MockReminderService {
+	mock := &MockReminderService{ctrl: ctrl}
+	mock.recorder = &MockReminderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReminderService) EXPECT() *MockReminderServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateReminder mocks base method.
+func (m *MockReminderService) CreateReminder(ctx context.Context, taskID uint, req dto.CreateReminderServiceRequest) (*models.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReminder", ctx, taskID, req)
+	ret0, _ := ret[0].(*models.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReminder indicates an expected call of CreateReminder.
+func (mr *MockReminderServiceMockRecorder) CreateReminder(ctx, taskID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReminder", reflect.TypeOf((*MockReminderService)(nil).CreateReminder), ctx, taskID, req)
+}
+
+// DeleteReminder mocks base method.
+func (m *MockReminderService) DeleteReminder(ctx context.Context, taskID, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReminder", ctx, taskID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReminder indicates an expected call of DeleteReminder.
+func (mr *MockReminderServiceMockRecorder) DeleteReminder(ctx, taskID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReminder", reflect.TypeOf((*MockReminderService)(nil).DeleteReminder), ctx, taskID, id)
+}
+
+// ListReminders mocks base method.
+func (m *MockReminderService) ListReminders(ctx context.Context, taskID uint) ([]models.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReminders", ctx, taskID)
+	ret0, _ := ret[0].([]models.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReminders indicates an expected call of ListReminders.
+func (mr *MockReminderServiceMockRecorder) ListReminders(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReminders", reflect.TypeOf((*MockReminderService)(nil).ListReminders), ctx, taskID)
+}
@@ -12,8 +12,10 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 	dto "todo-api/internal/dto"
 	models "todo-api/internal/models"
+	services "todo-api/internal/services"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +44,20 @@ func (m *MockTaskService) EXPECT() *MockTaskServiceMockRecorder {
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockTaskService) AddDependency(ctx context.Context, id, blockerID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", ctx, id, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockTaskServiceMockRecorder) AddDependency(ctx, id, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockTaskService)(nil).AddDependency), ctx, id, blockerID)
+}
+
 // CreateTask mocks base method.
 func (m *MockTaskService) CreateTask(ctx context.Context, req dto.CreateTaskServiceRequest) (*models.Task, error) {
 	m.ctrl.T.Helper()
@@ -57,18 +73,77 @@ func (mr *MockTaskServiceMockRecorder) CreateTask(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockTaskService)(nil).CreateTask), ctx, req)
 }
 
+// CreateTasks mocks base method.
+func (m *MockTaskService) CreateTasks(ctx context.Context, reqs []dto.CreateTaskServiceRequest, mode string) (*dto.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTasks", ctx, reqs, mode)
+	ret0, _ := ret[0].(*dto.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTasks indicates an expected call of CreateTasks.
+func (mr *MockTaskServiceMockRecorder) CreateTasks(ctx, reqs, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTasks", reflect.TypeOf((*MockTaskService)(nil).CreateTasks), ctx, reqs, mode)
+}
+
+// DeleteOccurrence mocks base method.
+func (m *MockTaskService) DeleteOccurrence(ctx context.Context, id uint, day time.Time, version uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOccurrence", ctx, id, day, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOccurrence indicates an expected call of DeleteOccurrence.
+func (mr *MockTaskServiceMockRecorder) DeleteOccurrence(ctx, id, day, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOccurrence", reflect.TypeOf((*MockTaskService)(nil).DeleteOccurrence), ctx, id, day, version)
+}
+
 // DeleteTask mocks base method.
-func (m *MockTaskService) DeleteTask(ctx context.Context, id uint) error {
+func (m *MockTaskService) DeleteTask(ctx context.Context, id, version uint, cascade bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id, version, cascade)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockTaskServiceMockRecorder) DeleteTask(ctx, id any) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) DeleteTask(ctx, id, version, cascade any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskService)(nil).DeleteTask), ctx, id, version, cascade)
+}
+
+// DeleteTasks mocks base method.
+func (m *MockTaskService) DeleteTasks(ctx context.Context, sel dto.BatchSelection, cascade bool) (*dto.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTasks", ctx, sel, cascade)
+	ret0, _ := ret[0].(*dto.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTasks indicates an expected call of DeleteTasks.
+func (mr *MockTaskServiceMockRecorder) DeleteTasks(ctx, sel, cascade any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTasks", reflect.TypeOf((*MockTaskService)(nil).DeleteTasks), ctx, sel, cascade)
+}
+
+// GetTaskAsOf mocks base method.
+func (m *MockTaskService) GetTaskAsOf(ctx context.Context, id uint, asOf time.Time) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskAsOf", ctx, id, asOf)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskAsOf indicates an expected call of GetTaskAsOf.
+func (mr *MockTaskServiceMockRecorder) GetTaskAsOf(ctx, id, asOf any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskService)(nil).DeleteTask), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskAsOf", reflect.TypeOf((*MockTaskService)(nil).GetTaskAsOf), ctx, id, asOf)
 }
 
 // GetTaskByID mocks base method.
@@ -86,11 +161,56 @@ func (mr *MockTaskServiceMockRecorder) GetTaskByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockTaskService)(nil).GetTaskByID), ctx, id)
 }
 
+// ListBlockers mocks base method.
+func (m *MockTaskService) ListBlockers(ctx context.Context, id uint) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlockers", ctx, id)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlockers indicates an expected call of ListBlockers.
+func (mr *MockTaskServiceMockRecorder) ListBlockers(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlockers", reflect.TypeOf((*MockTaskService)(nil).ListBlockers), ctx, id)
+}
+
+// ListSubtasks mocks base method.
+func (m *MockTaskService) ListSubtasks(ctx context.Context, id uint, tree bool) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", ctx, id, tree)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks.
+func (mr *MockTaskServiceMockRecorder) ListSubtasks(ctx, id, tree any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*MockTaskService)(nil).ListSubtasks), ctx, id, tree)
+}
+
+// ListTaskHistory mocks base method.
+func (m *MockTaskService) ListTaskHistory(ctx context.Context, id uint, filter dto.HistoryFilter) (*dto.HistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskHistory", ctx, id, filter)
+	ret0, _ := ret[0].(*dto.HistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskHistory indicates an expected call of ListTaskHistory.
+func (mr *MockTaskServiceMockRecorder) ListTaskHistory(ctx, id, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskHistory", reflect.TypeOf((*MockTaskService)(nil).ListTaskHistory), ctx, id, filter)
+}
+
 // ListTasks mocks base method.
-func (m *MockTaskService) ListTasks(ctx context.Context, filter dto.TaskFilter) ([]models.Task, error) {
+func (m *MockTaskService) ListTasks(ctx context.Context, filter dto.TaskFilter) (*dto.TaskPage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListTasks", ctx, filter)
-	ret0, _ := ret[0].([]models.Task)
+	ret0, _ := ret[0].(*dto.TaskPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -101,6 +221,109 @@ func (mr *MockTaskServiceMockRecorder) ListTasks(ctx, filter any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskService)(nil).ListTasks), ctx, filter)
 }
 
+// ListTrash mocks base method.
+func (m *MockTaskService) ListTrash(ctx context.Context, filter dto.TaskFilter) (*dto.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, filter)
+	ret0, _ := ret[0].(*dto.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockTaskServiceMockRecorder) ListTrash(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockTaskService)(nil).ListTrash), ctx, filter)
+}
+
+// MoveTask mocks base method.
+func (m *MockTaskService) MoveTask(ctx context.Context, id uint, req dto.MoveTaskServiceRequest) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTask", ctx, id, req)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTask indicates an expected call of MoveTask.
+func (mr *MockTaskServiceMockRecorder) MoveTask(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTask", reflect.TypeOf((*MockTaskService)(nil).MoveTask), ctx, id, req)
+}
+
+// PurgeTask mocks base method.
+func (m *MockTaskService) PurgeTask(ctx context.Context, id, version uint, cascade bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", ctx, id, version, cascade)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockTaskServiceMockRecorder) PurgeTask(ctx, id, version, cascade any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockTaskService)(nil).PurgeTask), ctx, id, version, cascade)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockTaskService) RemoveDependency(ctx context.Context, id, blockerID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", ctx, id, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockTaskServiceMockRecorder) RemoveDependency(ctx, id, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockTaskService)(nil).RemoveDependency), ctx, id, blockerID)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTaskService) RestoreTask(ctx context.Context, id, version uint) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", ctx, id, version)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTaskServiceMockRecorder) RestoreTask(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTaskService)(nil).RestoreTask), ctx, id, version)
+}
+
+// RevertTask mocks base method.
+func (m *MockTaskService) RevertTask(ctx context.Context, id uint, req dto.RevertTaskServiceRequest) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertTask", ctx, id, req)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertTask indicates an expected call of RevertTask.
+func (mr *MockTaskServiceMockRecorder) RevertTask(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertTask", reflect.TypeOf((*MockTaskService)(nil).RevertTask), ctx, id, req)
+}
+
+// UpdateOccurrence mocks base method.
+func (m *MockTaskService) UpdateOccurrence(ctx context.Context, id uint, day time.Time, req dto.UpdateTaskServiceRequest, scope services.OccurrenceScope) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOccurrence", ctx, id, day, req, scope)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOccurrence indicates an expected call of UpdateOccurrence.
+func (mr *MockTaskServiceMockRecorder) UpdateOccurrence(ctx, id, day, req, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOccurrence", reflect.TypeOf((*MockTaskService)(nil).UpdateOccurrence), ctx, id, day, req, scope)
+}
+
 // UpdateTask mocks base method.
 func (m *MockTaskService) UpdateTask(ctx context.Context, id uint, req dto.UpdateTaskServiceRequest) (*models.Task, error) {
 	m.ctrl.T.Helper()
@@ -115,3 +338,18 @@ func (mr *MockTaskServiceMockRecorder) UpdateTask(ctx, id, req any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskService)(nil).UpdateTask), ctx, id, req)
 }
+
+// UpdateTasks mocks base method.
+func (m *MockTaskService) UpdateTasks(ctx context.Context, sel dto.BatchSelection, changes dto.BatchChanges) (*dto.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTasks", ctx, sel, changes)
+	ret0, _ := ret[0].(*dto.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTasks indicates an expected call of UpdateTasks.
+func (mr *MockTaskServiceMockRecorder) UpdateTasks(ctx, sel, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTasks", reflect.TypeOf((*MockTaskService)(nil).UpdateTasks), ctx, sel, changes)
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// EventConsumer is handed the task events the outbox relay publishes. An
+// event comes again when this or another consumer fails to take it, so
+// Consume must tolerate repeats of an Event.ID. An error makes the relay
+// retry the event later and hold back the events of its task behind it.
+type EventConsumer interface {
+	Consume(ctx context.Context, event models.Event) error
+}
+
+// OutboxRelay publishes the task events TaskRepository records in the
+// outbox to the registered consumers, at least once each and in order for
+// any one task. Like ReminderScheduler it claims every attempt first, so
+// relays in several processes never publish an event twice at once. A
+// failed event is retried with exponential backoff for as long as it
+// takes: events are never dropped, the events of other tasks go on.
+type OutboxRelay struct {
+	repo      repositories.OutboxRepository
+	consumers []namedConsumer
+	clock     clock.Clock
+	logger    *zap.Logger
+	cfg       SchedulerConfig
+	retention time.Duration
+}
+
+type namedConsumer struct {
+	name string
+	EventConsumer
+}
+
+// NewOutboxRelay returns a relay that deletes published events once they
+// are older than retention, or keeps them when it is 0. MaxAttempts of cfg
+// is not used.
+func NewOutboxRelay(
+	repo repositories.OutboxRepository,
+	clk clock.Clock,
+	logger *zap.Logger,
+	cfg SchedulerConfig,
+	retention time.Duration,
+) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		clock:     clk,
+		logger:    logger,
+		cfg:       cfg.withDefaults(),
+		retention: retention,
+	}
+}
+
+// Register adds a consumer, named in logs and errors. Consumers are handed
+// every event in the order they were registered, and must all be
+// registered before Run.
+func (r *OutboxRelay) Register(name string, consumer EventConsumer) {
+	r.consumers = append(r.consumers, namedConsumer{name: name, EventConsumer: consumer})
+}
+
+// Run publishes due events every Interval until ctx is done.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Tick(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("Failed to relay outbox events", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick publishes the events due now, BatchSize at a time until a batch
+// publishes none, then deletes the published events past retention. It
+// returns how many events were published; failed ones are scheduled for a
+// retry, not returned.
+func (r *OutboxRelay) Tick(ctx context.Context) (int, error) {
+	published := 0
+	for ctx.Err() == nil {
+		now := r.clock.Now()
+		events, err := r.repo.ListDue(ctx, now, r.cfg.BatchSize)
+		if err != nil {
+			return published, fmt.Errorf("failed to list due outbox events: %w", err)
+		}
+
+		batch := 0
+		for _, event := range events {
+			ok, err := r.publish(ctx, event, now)
+			if err != nil {
+				return published, fmt.Errorf("failed to record attempt at outbox event %d: %w", event.ID, err)
+			}
+			if ok {
+				batch++
+			}
+		}
+		published += batch
+		if batch == 0 {
+			break
+		}
+	}
+
+	if r.retention > 0 {
+		if _, err := r.repo.DeletePublished(ctx, r.clock.Now().Add(-r.retention)); err != nil {
+			return published, fmt.Errorf("failed to delete published outbox events: %w", err)
+		}
+	}
+	return published, nil
+}
+
+// publish makes one attempt at handing a due event to every consumer and
+// records how it went.
+func (r *OutboxRelay) publish(ctx context.Context, row models.OutboxEvent, now time.Time) (bool, error) {
+	claimed, err := r.repo.Claim(ctx, row.ID, now, now.Add(2*r.cfg.Timeout))
+	if err != nil || !claimed {
+		return false, err
+	}
+	row.Attempts++
+
+	consumeErr := r.consume(ctx, row)
+	done := r.clock.Now()
+	if consumeErr == nil {
+		return true, r.repo.Update(ctx, row.ID, map[string]interface{}{
+			"published_at": done,
+			"last_error":   "",
+		})
+	}
+
+	retry := done.Add(r.cfg.backoff(row.Attempts))
+	r.logger.Warn("Outbox event not published, will retry",
+		zap.Uint("outbox_id", row.ID),
+		zap.String("event_id", row.EventID),
+		zap.String("event_type", string(row.Type)),
+		zap.Uint("task_id", row.TaskID),
+		zap.Int("attempt", row.Attempts),
+		zap.Time("retry_at", retry),
+		zap.Error(consumeErr),
+	)
+	return false, r.repo.Update(ctx, row.ID, map[string]interface{}{
+		"last_error":      consumeErr.Error(),
+		"next_attempt_at": retry,
+	})
+}
+
+// consume hands the event of row to the consumers in turn, stopping at the
+// first that fails.
+func (r *OutboxRelay) consume(ctx context.Context, row models.OutboxEvent) error {
+	event, err := row.Event()
+	if err != nil {
+		return fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+	for _, consumer := range r.consumers {
+		if err = consumer.Consume(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", consumer.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+// recordingConsumer keeps the events it takes and refuses those of the
+// tasks in failing.
+type recordingConsumer struct {
+	mu      sync.Mutex
+	events  []models.Event
+	failing map[uint]bool
+}
+
+func (c *recordingConsumer) Consume(_ context.Context, event models.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failing[event.Task.ID] {
+		return errors.New("connection refused")
+	}
+	c.events = append(c.events, event)
+	return nil
+}
+
+func (c *recordingConsumer) fail(taskID uint, failing bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failing[taskID] = failing
+}
+
+type consumed struct {
+	TaskID uint
+	Type   models.EventType
+}
+
+func (c *recordingConsumer) consumed() []consumed {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := make([]consumed, len(c.events))
+	for i, event := range c.events {
+		list[i] = consumed{TaskID: event.Task.ID, Type: event.Type}
+	}
+	return list
+}
+
+func TestOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+	cfg := services.SchedulerConfig{Backoff: time.Minute, Timeout: time.Second}
+	setup := func(t *testing.T) (*clock.Fake, repositories.TaskRepository, repositories.OutboxRepository, *services.OutboxRelay) {
+		// The repository records events at the real time.
+		now := clock.NewFake(time.Now().Add(time.Minute))
+		store := repositories.NewMemoryStore(clock.Real())
+		outbox := repositories.NewOutboxRepositoryMemory(store)
+		relay := services.NewOutboxRelay(outbox, now, zaptest.NewLogger(t), cfg, 24*time.Hour)
+		return now, repositories.NewTaskRepositoryMemory(store), outbox, relay
+	}
+	task := func(t *testing.T, repo repositories.TaskRepository, title string) *models.Task {
+		task := &models.Task{Title: title, Date: time.Date(2030, 3, 10, 0, 0, 0, 0, time.UTC)}
+		require.NoError(t, repo.Create(ctx, task))
+		return task
+	}
+
+	t.Run("in order per task", func(t *testing.T) {
+		// Arrange
+		_, repo, outbox, relay := setup(t)
+		consumer := &recordingConsumer{failing: map[uint]bool{}}
+		relay.Register("recorder", consumer)
+		first, second := task(t, repo, "Call mom"), task(t, repo, "Buy milk")
+		require.NoError(t, repo.Update(ctx, first.ID, 0, map[string]interface{}{"completed": true}))
+
+		// Act
+		published, err := relay.Tick(ctx)
+		require.NoError(t, err)
+		again, err := relay.Tick(ctx)
+		require.NoError(t, err)
+		stats, err := outbox.Stats(ctx)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, 4, published)
+		assert.Zero(t, again)
+		assert.Equal(t, []consumed{
+			{first.ID, models.EventTaskCreated},
+			{second.ID, models.EventTaskCreated},
+			{first.ID, models.EventTaskUpdated},
+			{first.ID, models.EventTaskCompleted},
+		}, consumer.consumed())
+		assert.True(t, consumer.events[3].Task.Completed)
+		assert.Zero(t, stats.Pending)
+	})
+
+	t.Run("a failure holds back its task until a retry succeeds", func(t *testing.T) {
+		// Arrange
+		now, repo, outbox, relay := setup(t)
+		webhooks := &recordingConsumer{failing: map[uint]bool{}}
+		stream := &recordingConsumer{failing: map[uint]bool{}}
+		relay.Register("webhooks", webhooks)
+		relay.Register("stream", stream)
+		first, second := task(t, repo, "Call mom"), task(t, repo, "Buy milk")
+		require.NoError(t, repo.Update(ctx, first.ID, 0, map[string]interface{}{"title": "Call dad"}))
+		stream.fail(first.ID, true)
+
+		// Act
+		failed, err := relay.Tick(ctx)
+		require.NoError(t, err)
+		stats, err := outbox.Stats(ctx)
+		require.NoError(t, err)
+		stream.fail(first.ID, false)
+		early, err := relay.Tick(ctx)
+		require.NoError(t, err)
+		now.Advance(time.Minute)
+		retried, err := relay.Tick(ctx)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, 1, failed)
+		assert.Equal(t, int64(2), stats.Pending)
+		assert.Equal(t, int64(1), stats.Failing)
+		assert.Zero(t, early, "the retry waits for the backoff")
+		assert.Equal(t, 2, retried)
+		assert.Equal(t, []consumed{
+			{second.ID, models.EventTaskCreated},
+			{first.ID, models.EventTaskCreated},
+			{first.ID, models.EventTaskUpdated},
+		}, stream.consumed())
+		assert.Equal(t, []consumed{
+			{first.ID, models.EventTaskCreated},
+			{second.ID, models.EventTaskCreated},
+			{first.ID, models.EventTaskCreated},
+			{first.ID, models.EventTaskUpdated},
+		}, webhooks.consumed(), "consumers before the failing one see the event again")
+	})
+
+	t.Run("published events are kept for the retention", func(t *testing.T) {
+		// Arrange
+		now, repo, outbox, relay := setup(t)
+		relay.Register("recorder", &recordingConsumer{failing: map[uint]bool{}})
+		task(t, repo, "Call mom")
+		_, err := relay.Tick(ctx)
+		require.NoError(t, err)
+
+		// Act
+		now.Advance(23 * time.Hour)
+		_, err = relay.Tick(ctx)
+		require.NoError(t, err)
+		kept, err := outbox.Stats(ctx)
+		require.NoError(t, err)
+		now.Advance(2 * time.Hour)
+		_, err = relay.Tick(ctx)
+		require.NoError(t, err)
+		purged, err := outbox.Stats(ctx)
+		require.NoError(t, err)
+
+		// Assert
+		assert.NotZero(t, kept.LastPublishedID)
+		assert.Zero(t, purged.LastPublishedID)
+	})
+}
@@ -0,0 +1,13 @@
+//go:generate mockgen -source=./outbox_service.go -destination=./mock/outbox_service.go -package=mock
+package services
+
+import (
+	"context"
+
+	"todo-api/internal/dto"
+)
+
+type OutboxService interface {
+	// Status reports the backlog of the outbox relay.
+	Status(ctx context.Context) (*dto.OutboxStatus, error)
+}
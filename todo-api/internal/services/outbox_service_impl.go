@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+type OutboxServiceImpl struct {
+	repo  repositories.OutboxRepository
+	clock clock.Clock
+}
+
+func NewOutboxServiceImpl(repo repositories.OutboxRepository, clk clock.Clock) *OutboxServiceImpl {
+	return &OutboxServiceImpl{repo: repo, clock: clk}
+}
+
+func (s *OutboxServiceImpl) Status(ctx context.Context) (*dto.OutboxStatus, error) {
+	stats, err := s.repo.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox stats: %w", err)
+	}
+
+	status := &dto.OutboxStatus{
+		Pending:         stats.Pending,
+		Failing:         stats.Failing,
+		OldestPendingAt: stats.OldestPendingAt,
+		LastPublishedID: stats.LastPublishedID,
+		LastPublishedAt: stats.LastPublishedAt,
+	}
+	if stats.OldestPendingAt != nil {
+		status.LagSeconds = max(s.clock.Now().Sub(*stats.OldestPendingAt).Seconds(), 0)
+	}
+	return status, nil
+}
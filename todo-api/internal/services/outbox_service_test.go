@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+func TestOutboxService_Status(t *testing.T) {
+	now := time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("lag of the oldest pending event", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		repo := mock.NewMockOutboxRepository(ctrl)
+		service := services.NewOutboxServiceImpl(repo, clock.NewFake(now))
+		oldest, published := now.Add(-90*time.Second), now.Add(-time.Minute)
+		repo.EXPECT().Stats(gomock.Any()).Return(models.OutboxStats{
+			Pending:         12,
+			Failing:         1,
+			OldestPendingAt: &oldest,
+			LastPublishedID: 40,
+			LastPublishedAt: &published,
+		}, nil)
+
+		// Act
+		status, err := service.Status(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), status.Pending)
+		assert.Equal(t, int64(1), status.Failing)
+		assert.Equal(t, 90.0, status.LagSeconds)
+		assert.Equal(t, uint(40), status.LastPublishedID)
+	})
+
+	t.Run("nothing pending", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		repo := mock.NewMockOutboxRepository(ctrl)
+		service := services.NewOutboxServiceImpl(repo, clock.NewFake(now))
+		repo.EXPECT().Stats(gomock.Any()).Return(models.OutboxStats{}, nil)
+
+		// Act
+		status, err := service.Status(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Zero(t, status.LagSeconds)
+		assert.Nil(t, status.OldestPendingAt)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		repo := mock.NewMockOutboxRepository(ctrl)
+		service := services.NewOutboxServiceImpl(repo, clock.NewFake(now))
+		repo.EXPECT().Stats(gomock.Any()).Return(models.OutboxStats{}, errors.New("connection refused"))
+
+		// Act
+		status, err := service.Status(context.Background())
+
+		// Assert
+		assert.ErrorContains(t, err, "connection refused")
+		assert.Nil(t, status)
+	})
+}
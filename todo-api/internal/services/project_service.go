@@ -0,0 +1,21 @@
+//go:generate mockgen -source=./project_service.go -destination=./mock/project_service.go -package=mock
+package services
+
+import (
+	"context"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+type ProjectService interface {
+	CreateProject(ctx context.Context, project *models.Project) (*models.Project, error)
+	GetProject(ctx context.Context, id uint) (*models.Project, error)
+	ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	// UpdateProject changes project fields; archiving hides the project's
+	// tasks from default task listings.
+	UpdateProject(ctx context.Context, id uint, req dto.UpdateProjectServiceRequest) (*models.Project, error)
+	// DeleteProject deletes the project, keeping its tasks outside any
+	// project.
+	DeleteProject(ctx context.Context, id uint) error
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+type ProjectServiceImpl struct {
+	repo repositories.ProjectRepository
+}
+
+func NewProjectServiceImpl(repo repositories.ProjectRepository) *ProjectServiceImpl {
+	return &ProjectServiceImpl{repo: repo}
+}
+
+func (s *ProjectServiceImpl) CreateProject(ctx context.Context, project *models.Project) (*models.Project, error) {
+	if err := s.repo.Create(ctx, project); err != nil {
+		return nil, translateProjectError(err, 0, "failed to create project")
+	}
+	return project, nil
+}
+
+func (s *ProjectServiceImpl) GetProject(ctx context.Context, id uint) (*models.Project, error) {
+	project, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateProjectError(err, id, "failed to get project")
+	}
+	return project, nil
+}
+
+func (s *ProjectServiceImpl) ListProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	projects, err := s.repo.List(ctx, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (s *ProjectServiceImpl) UpdateProject(ctx context.Context, id uint, req dto.UpdateProjectServiceRequest) (*models.Project, error) {
+	updates := make(map[string]interface{})
+
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+
+	if req.Color != nil {
+		updates["color"] = *req.Color
+	}
+
+	if req.Archived != nil {
+		updates["archived"] = *req.Archived
+	}
+
+	if len(updates) == 0 {
+		return nil, newError(ErrValidation, "no fields to update")
+	}
+
+	if err := s.repo.Update(ctx, id, updates); err != nil {
+		return nil, translateProjectError(err, id, "failed to update project")
+	}
+	return s.GetProject(ctx, id)
+}
+
+func (s *ProjectServiceImpl) DeleteProject(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return translateProjectError(err, id, "failed to delete project")
+	}
+	return nil
+}
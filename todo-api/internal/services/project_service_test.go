@@ -0,0 +1,54 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+)
+
+func TestProjectService_UpdateProject(t *testing.T) {
+	t.Run("archive", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockProjectRepository(ctrl)
+		service := services.NewProjectServiceImpl(mockRepo)
+
+		archived := true
+		mockRepo.EXPECT().Update(gomock.Any(), uint(2), map[string]interface{}{"archived": true}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(2)).Return(&models.Project{ID: 2, Archived: true}, nil)
+
+		// Act
+		project, err := service.UpdateProject(context.Background(), 2, dto.UpdateProjectServiceRequest{Archived: &archived})
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, project.Archived)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockProjectRepository(ctrl)
+		service := services.NewProjectServiceImpl(mockRepo)
+
+		name := "Renamed"
+		mockRepo.EXPECT().Update(gomock.Any(), uint(9), gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+		// Act
+		project, err := service.UpdateProject(context.Background(), 9, dto.UpdateProjectServiceRequest{Name: &name})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.Contains(t, err.Error(), "project 9 not found")
+		assert.Nil(t, project)
+	})
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+// OccurrenceScope selects the occurrences of a recurring task an edit
+// applies to.
+type OccurrenceScope string
+
+const (
+	// ScopeThis edits one occurrence, leaving the rest of the series alone.
+	ScopeThis OccurrenceScope = "this"
+	// ScopeFollowing edits an occurrence and every later one, splitting the
+	// series in two unless it is the first occurrence.
+	ScopeFollowing OccurrenceScope = "following"
+)
+
+const (
+	// maxExpandedSeries caps the recurring tasks one list expands; a list
+	// matching more fails rather than leave some out.
+	maxExpandedSeries = 500
+	// maxOccurrences caps the occurrences one recurring task expands to in a
+	// list, so that a daily task over a range of decades stays cheap. A range
+	// holding more fails the same way.
+	maxOccurrences = 1000
+)
+
+// parseRecurrence parses an RRULE for a task dated start. Only whole-day
+// frequencies are accepted, as tasks have no time of day, and the task date
+// always serves as DTSTART.
+func parseRecurrence(rule string, start time.Time) (*rrule.RRule, error) {
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, newError(ErrValidation, "invalid recurrence rule: %v", err)
+	}
+	if !opt.Dtstart.IsZero() {
+		return nil, newError(ErrValidation, "recurrence rule cannot set DTSTART, the task date starts the series")
+	}
+	if opt.Freq > rrule.DAILY {
+		return nil, newError(ErrValidation, "recurrence frequency must be DAILY, WEEKLY, MONTHLY or YEARLY")
+	}
+	if len(opt.Byhour) > 0 || len(opt.Byminute) > 0 || len(opt.Bysecond) > 0 {
+		return nil, newError(ErrValidation, "recurrence rule cannot repeat within a day")
+	}
+
+	opt.Dtstart = start
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, newError(ErrValidation, "invalid recurrence rule: %v", err)
+	}
+	return r, nil
+}
+
+// normalizeRecurrence validates a recurrence rule and returns it in
+// canonical form, "" when the task does not recur.
+func normalizeRecurrence(rule string, start time.Time) (string, error) {
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		return "", nil
+	}
+	r, err := parseRecurrence(rule, start)
+	if err != nil {
+		return "", err
+	}
+	opt := r.OrigOptions
+	opt.Dtstart = time.Time{}
+	return opt.RRuleString(), nil
+}
+
+// exDates turns the days a client excludes from a series into a DateList,
+// sorted and without duplicates.
+func exDates(days []time.Time) models.DateList {
+	list := slices.Clone(days)
+	slices.SortFunc(list, time.Time.Compare)
+	return slices.CompactFunc(list, time.Time.Equal)
+}
+
+// occurrences returns the days of a recurring task within [from, to] that
+// are not excluded, failing when there are more than maxOccurrences.
+func occurrences(task *models.Task, from, to time.Time) ([]time.Time, error) {
+	r, err := parseRecurrence(task.Recurrence, task.Date)
+	if err != nil {
+		return nil, fmt.Errorf("task %d has an invalid recurrence: %w", task.ID, err)
+	}
+
+	var days []time.Time
+	next := r.Iterator()
+	for day, ok := next(); ok && !day.After(to); day, ok = next() {
+		if day.Before(from) || task.ExDates.Contains(day) {
+			continue
+		}
+		if len(days) == maxOccurrences {
+			return nil, newError(ErrValidation, "task %d occurs more than %d times between %s and %s, narrow the date range",
+				task.ID, maxOccurrences, from.Format(models.DateFormat), to.Format(models.DateFormat))
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// occurrence returns the copy of a recurring task that stands for its
+// occurrence on day.
+func occurrence(task models.Task, day time.Time) models.Task {
+	task.SeriesID = &task.ID
+	task.OccurrenceDate = &day
+	moveToDay(&task, day)
+	task.Recurrence = ""
+	task.ExDates = nil
+	return task
+}
+
+// copyTask returns a new task with the stored fields of task, except its
+// recurrence, and the same tags.
+func copyTask(task *models.Task) models.Task {
+	return models.Task{
+		Title:       task.Title,
+		Description: task.Description,
+		Date:        task.Date,
+		Completed:   task.Completed,
+		Priority:    task.Priority,
+		Rank:        task.Rank,
+		Tags:        slices.Clone(task.Tags),
+		ProjectID:   task.ProjectID,
+		ParentID:    task.ParentID,
+		DueAt:       task.DueAt,
+		TimeZone:    task.TimeZone,
+	}
+}
+
+// expandOccurrences lists the occurrences of the recurring tasks matching
+// filter within its date range, less the stored ones that replace them. It
+// fails when more than maxExpandedSeries recurring tasks match.
+func (s *TaskServiceImpl) expandOccurrences(ctx context.Context, filter dto.TaskFilter) ([]models.Task, error) {
+	recurring := true
+	seriesFilter := filter
+	seriesFilter.Recurring = &recurring
+	seriesFilter.DateFrom = nil // a series started before the range can still occur in it
+	seriesFilter.Cursor = nil
+	seriesFilter.Offset = 0
+	seriesFilter.Limit = maxExpandedSeries + 1
+	seriesFilter.Sort = dto.DefaultTaskSort()
+
+	series, err := s.repo.List(ctx, seriesFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring tasks: %w", err)
+	}
+	if len(series) > maxExpandedSeries {
+		return nil, newError(ErrValidation, "more than %d recurring tasks match, narrow the filter to list their occurrences", maxExpandedSeries)
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(series))
+	for i, task := range series {
+		ids[i] = task.ID
+	}
+	overrides, err := s.repo.ListOverrides(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list occurrences: %w", err)
+	}
+	// A stored occurrence replaces the expanded one even when deleted;
+	// live ones are listed like any other task.
+	replaced := make(map[uint]models.DateList)
+	for _, override := range overrides {
+		replaced[*override.SeriesID] = append(replaced[*override.SeriesID], *override.OccurrenceDate)
+	}
+
+	var expanded []models.Task
+	for _, task := range series {
+		days, err := occurrences(&task, *filter.DateFrom, *filter.DateTo)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range days {
+			if !replaced[task.ID].Contains(day) {
+				expanded = append(expanded, occurrence(task, day))
+			}
+		}
+	}
+	return expanded, nil
+}
+
+// occurrenceSort makes an order total over expanded occurrences, which
+// share their task's ID, by breaking ties on the date after the ID.
+func occurrenceSort(sort []dto.SortField) []dto.SortField {
+	if slices.ContainsFunc(sort, func(f dto.SortField) bool { return f.Field == "date" }) {
+		return sort
+	}
+	i := slices.IndexFunc(sort, func(f dto.SortField) bool { return f.Field == "id" })
+	return slices.Insert(slices.Clone(sort), i+1, dto.SortField{Field: "date"})
+}
+
+// mergeOccurrences merges the expanded occurrences past the filter's
+// position into a page of tasks listed by the repository, keeping the page
+// order and length.
+func mergeOccurrences(tasks, expanded []models.Task, filter dto.TaskFilter, limit int) []models.Task {
+	direction := 1
+	if filter.Cursor != nil && filter.Cursor.Backward {
+		direction = -1
+	}
+
+	merged := slices.Clone(tasks)
+	for _, task := range expanded {
+		if filter.Cursor == nil || filter.Cursor.Compare(&task)*direction > 0 {
+			merged = append(merged, task)
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b models.Task) int {
+		return dto.CompareTasks(&a, &b, filter.Sort) * direction
+	})
+
+	if filter.Offset >= len(merged) {
+		return nil
+	}
+	merged = merged[filter.Offset:]
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+// seriesOccurrence loads a recurring task and checks that day is one of its
+// occurrences.
+func (s *TaskServiceImpl) seriesOccurrence(ctx context.Context, id uint, day time.Time) (*models.Task, error) {
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+	if task.Recurrence == "" {
+		return nil, newError(ErrValidation, "task %d is not recurring", id)
+	}
+
+	days, err := occurrences(task, day, day)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, newError(ErrNotFound, "task %d has no occurrence on %s", id, day.Format(models.DateFormat))
+	}
+	return task, nil
+}
+
+// storedOccurrence returns the live stored occurrence of a series on day,
+// or nil when the occurrence is only expanded.
+func (s *TaskServiceImpl) storedOccurrence(ctx context.Context, id uint, day time.Time) (*models.Task, error) {
+	overrides, err := s.repo.ListOverrides(ctx, []uint{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list occurrences: %w", err)
+	}
+	for _, override := range overrides {
+		if override.OccurrenceDate.Equal(day) {
+			if override.DeletedAt.Valid {
+				return nil, newError(ErrNotFound, "task %d has no occurrence on %s", id, day.Format(models.DateFormat))
+			}
+			return &override, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *TaskServiceImpl) UpdateOccurrence(ctx context.Context, id uint, day time.Time, req dto.UpdateTaskServiceRequest, scope OccurrenceScope) (*models.Task, error) {
+	if isEmptyUpdate(req) {
+		return nil, newError(ErrValidation, "no fields to update")
+	}
+
+	task, err := s.seriesOccurrence(ctx, id, day)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version != 0 && req.Version != task.Version {
+		return nil, translateRepoError(repositories.ErrVersionMismatch, id, "failed to update occurrence")
+	}
+
+	if scope == ScopeFollowing {
+		return s.updateFollowing(ctx, task, day, req)
+	}
+	if req.Recurrence != nil || req.ExDates != nil {
+		return nil, newError(ErrValidation, "recurrence can only be changed for the whole series or following occurrences")
+	}
+
+	stored, err := s.storedOccurrence(ctx, id, day)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		req.Version = 0
+		return s.UpdateTask(ctx, stored.ID, req)
+	}
+
+	// The occurrence is stored with the edit applied, so that a rejected
+	// edit stores nothing.
+	override := copyTask(task)
+	moveToDay(&override, day)
+	override.SeriesID = &task.ID
+	override.OccurrenceDate = &day
+	updates, err := s.taskUpdates(ctx, 0, &override, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.StoreOccurrence(ctx, &override, updates); err != nil {
+		return nil, translateRepoError(err, id, "failed to store occurrence")
+	}
+
+	stored, err = s.repo.GetByID(ctx, override.ID)
+	if err != nil {
+		return nil, translateRepoError(err, override.ID, "failed to get task")
+	}
+	return stored, nil
+}
+
+// updateFollowing applies an edit to the occurrence of task on day and all
+// later ones. From the first occurrence on that is the whole series;
+// otherwise the series ends the day before and a new one, which the edit is
+// applied to, continues it.
+func (s *TaskServiceImpl) updateFollowing(ctx context.Context, task *models.Task, day time.Time, req dto.UpdateTaskServiceRequest) (*models.Task, error) {
+	r, err := parseRecurrence(task.Recurrence, task.Date)
+	if err != nil {
+		return nil, err
+	}
+	if first := r.After(task.Date, true); !day.After(first) {
+		return s.UpdateTask(ctx, task.ID, req)
+	}
+
+	head, tail := r.OrigOptions, r.OrigOptions
+	head.Dtstart, tail.Dtstart = time.Time{}, time.Time{}
+	if head.Count > 0 {
+		head.Count = len(r.Between(task.Date, day.AddDate(0, 0, -1), true))
+		tail.Count -= head.Count
+	} else {
+		head.Until = day.AddDate(0, 0, -1)
+	}
+
+	next := copyTask(task)
+	moveToDay(&next, day)
+	next.Recurrence = tail.RRuleString()
+	split := slices.IndexFunc(task.ExDates, func(d time.Time) bool { return !d.Before(day) })
+	if split < 0 {
+		split = len(task.ExDates)
+	}
+	next.ExDates = slices.Clone(task.ExDates[split:])
+
+	updates := map[string]interface{}{
+		"recurrence": head.RRuleString(),
+		"ex_dates":   slices.Clone(task.ExDates[:split]),
+	}
+	if err = s.repo.SplitSeries(ctx, task.ID, req.Version, updates, &next); err != nil {
+		return nil, translateRepoError(err, task.ID, "failed to split series")
+	}
+
+	req.Version = 0
+	return s.UpdateTask(ctx, next.ID, req)
+}
+
+func (s *TaskServiceImpl) DeleteOccurrence(ctx context.Context, id uint, day time.Time, version uint) error {
+	task, err := s.seriesOccurrence(ctx, id, day)
+	if err != nil {
+		return err
+	}
+	stored, err := s.storedOccurrence(ctx, id, day)
+	if err != nil {
+		return err
+	}
+
+	var storedID uint
+	if stored != nil {
+		storedID = stored.ID
+	}
+	excluded := exDates(append(slices.Clone(task.ExDates), day))
+	if err = s.repo.ExcludeOccurrence(ctx, id, version, map[string]interface{}{"ex_dates": excluded}, storedID); err != nil {
+		return translateRepoError(err, id, "failed to delete occurrence")
+	}
+	return nil
+}
+
+// updateRecurrence adds the recurrence changes of req to the updates of
+// task, checking the rule against the task's new or current date.
+func updateRecurrence(task *models.Task, req dto.UpdateTaskServiceRequest, updates map[string]interface{}) error {
+	if task.SeriesID != nil {
+		return newError(ErrValidation, "task %d is an occurrence of task %d and cannot recur itself", task.ID, *task.SeriesID)
+	}
+
+	recurrence, start := task.Recurrence, task.Date
+	if req.Date != nil {
+		start = *req.Date
+	}
+	if req.Recurrence != nil {
+		recurrence = *req.Recurrence
+	}
+	recurrence, err := normalizeRecurrence(recurrence, start)
+	if err != nil {
+		return err
+	}
+	if recurrence == "" && len(req.ExDates) > 0 {
+		return newError(ErrValidation, "exdates need a recurrence")
+	}
+
+	if req.Recurrence != nil {
+		updates["recurrence"] = recurrence
+	}
+	if req.ExDates != nil || recurrence == "" {
+		updates["ex_dates"] = exDates(req.ExDates)
+	}
+	return nil
+}
+
+// isEmptyUpdate reports whether req changes nothing.
+func isEmptyUpdate(req dto.UpdateTaskServiceRequest) bool {
+	return req.Title == nil && req.Description == nil && req.Date == nil && req.Completed == nil &&
+		req.Priority == nil && len(req.AddTags) == 0 && len(req.RemoveTags) == 0 &&
+		req.ProjectID == nil && req.ParentID == nil && req.Recurrence == nil && req.ExDates == nil &&
+		req.DueTime == nil
+}
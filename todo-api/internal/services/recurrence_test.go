@@ -0,0 +1,340 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+)
+
+// monday is a Monday far enough ahead never to be in the past.
+var monday = time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+func weekly(id uint) *models.Task {
+	return &models.Task{
+		Model:      gorm.Model{ID: id},
+		Title:      "Standup",
+		Date:       monday,
+		Version:    3,
+		Recurrence: "FREQ=WEEKLY;BYDAY=MO,WE",
+	}
+}
+
+func TestTaskService_CreateRecurringTask(t *testing.T) {
+	t.Run("normalizes the rule", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().LastRank(gomock.Any(), monday).Return("", nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		// Act
+		task, err := service.CreateTask(context.Background(), dto.CreateTaskServiceRequest{
+			Title:      "Standup",
+			Date:       monday,
+			Recurrence: "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10",
+			ExDates:    []time.Time{monday.AddDate(0, 0, 9), monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 9)},
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=10;BYDAY=MO,WE", task.Recurrence)
+		assert.Equal(t, models.DateList{monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 9)}, task.ExDates)
+	})
+
+	for name, req := range map[string]dto.CreateTaskServiceRequest{
+		"unknown property":     {Recurrence: "FREQ=WEEKLY;EVERY=MO"},
+		"within a day":         {Recurrence: "FREQ=DAILY;BYHOUR=9,17"},
+		"hourly":               {Recurrence: "FREQ=HOURLY"},
+		"own start":            {Recurrence: "DTSTART:20300107T000000Z\nRRULE:FREQ=DAILY"},
+		"exdates without rule": {ExDates: []time.Time{monday}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			service := services.NewTaskServiceImpl(mock.NewMockTaskRepository(ctrl))
+			req.Title, req.Date = "Standup", monday
+
+			// Act
+			task, err := service.CreateTask(context.Background(), req)
+
+			// Assert
+			assert.ErrorIs(t, err, services.ErrValidation)
+			assert.Nil(t, task)
+		})
+	}
+}
+
+func TestTaskService_ListTasksExpandsOccurrences(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockTaskRepository(ctrl)
+	service := services.NewTaskServiceImpl(mockRepo)
+
+	from, to := monday, monday.AddDate(0, 0, 6)
+	recurring, oneOff := true, false
+	wednesday := monday.AddDate(0, 0, 2)
+	seriesID := uint(1)
+	stored := models.Task{Model: gorm.Model{ID: 7}, Title: "Standup, moved", Date: monday.AddDate(0, 0, 3), SeriesID: &seriesID, OccurrenceDate: &wednesday}
+	single := models.Task{Model: gorm.Model{ID: 5}, Title: "Dentist", Date: monday.AddDate(0, 0, 1)}
+
+	mockRepo.EXPECT().List(gomock.Any(), dto.TaskFilter{
+		DateTo:    &to,
+		Limit:     501,
+		Sort:      dto.DefaultTaskSort(),
+		Recurring: &recurring,
+	}).Return([]models.Task{*weekly(1)}, nil)
+	mockRepo.EXPECT().ListOverrides(gomock.Any(), []uint{1}).Return([]models.Task{stored}, nil)
+	listFilter := dto.TaskFilter{
+		DateFrom:  &from,
+		DateTo:    &to,
+		Limit:     11,
+		Sort:      dto.DefaultTaskSort(),
+		Recurring: &oneOff,
+	}
+	mockRepo.EXPECT().List(gomock.Any(), listFilter).Return([]models.Task{single, stored}, nil)
+	mockRepo.EXPECT().Count(gomock.Any(), listFilter).Return(int64(2), nil)
+
+	// Act
+	page, err := service.ListTasks(context.Background(), dto.TaskFilter{DateFrom: &from, DateTo: &to})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 3)
+	assert.Equal(t, []uint{1, 5, 7}, []uint{page.Tasks[0].ID, page.Tasks[1].ID, page.Tasks[2].ID})
+	assert.Equal(t, uint(1), *page.Tasks[0].SeriesID)
+	assert.True(t, monday.Equal(*page.Tasks[0].OccurrenceDate))
+	assert.Empty(t, page.Tasks[0].Recurrence)
+	assert.Equal(t, int64(3), page.Meta.Total)
+}
+
+func TestTaskService_ListTasksTooManyOccurrences(t *testing.T) {
+	t.Run("too many series", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		from, to := monday, monday.AddDate(0, 0, 6)
+		series := make([]models.Task, 501)
+		for i := range series {
+			series[i] = *weekly(uint(i + 1))
+		}
+		mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(series, nil)
+
+		// Act
+		page, err := service.ListTasks(context.Background(), dto.TaskFilter{DateFrom: &from, DateTo: &to})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Nil(t, page)
+	})
+
+	t.Run("too many occurrences", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		from, to := monday, monday.AddDate(3, 0, 0)
+		daily := weekly(1)
+		daily.Recurrence = "FREQ=DAILY"
+		mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return([]models.Task{*daily}, nil)
+		mockRepo.EXPECT().ListOverrides(gomock.Any(), []uint{1}).Return(nil, nil)
+
+		// Act
+		page, err := service.ListTasks(context.Background(), dto.TaskFilter{DateFrom: &from, DateTo: &to})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Nil(t, page)
+	})
+}
+
+func TestTaskService_UpdateOccurrence(t *testing.T) {
+	title := "Retro"
+	wednesday := monday.AddDate(0, 0, 2)
+
+	t.Run("this occurrence", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(weekly(1), nil)
+		mockRepo.EXPECT().ListOverrides(gomock.Any(), []uint{1}).Return(nil, nil)
+		mockRepo.EXPECT().
+			StoreOccurrence(gomock.Any(), gomock.Any(), map[string]interface{}{"title": title}).
+			DoAndReturn(func(_ context.Context, task *models.Task, _ map[string]interface{}) error {
+				assert.True(t, wednesday.Equal(task.Date))
+				assert.Equal(t, uint(1), *task.SeriesID)
+				assert.True(t, wednesday.Equal(*task.OccurrenceDate))
+				assert.Empty(t, task.Recurrence)
+				task.ID = 8
+				return nil
+			})
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(8)).Return(&models.Task{Model: gorm.Model{ID: 8}, Title: title}, nil)
+
+		// Act
+		task, err := service.UpdateOccurrence(context.Background(), 1, wednesday,
+			dto.UpdateTaskServiceRequest{Title: &title, Version: 3}, services.ScopeThis)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, uint(8), task.ID)
+	})
+
+	t.Run("this and following", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		series := weekly(1)
+		series.ExDates = models.DateList{monday.AddDate(0, 0, 9), monday.AddDate(0, 0, 14)}
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(series, nil)
+		mockRepo.EXPECT().
+			SplitSeries(gomock.Any(), uint(1), uint(0), map[string]interface{}{
+				"recurrence": "FREQ=WEEKLY;UNTIL=20300113T000000Z;BYDAY=MO,WE",
+				"ex_dates":   models.DateList{},
+			}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uint, _ uint, _ map[string]interface{}, next *models.Task) error {
+				assert.True(t, monday.AddDate(0, 0, 7).Equal(next.Date))
+				assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,WE", next.Recurrence)
+				assert.Equal(t, series.ExDates, next.ExDates)
+				next.ID = 9
+				return nil
+			})
+		mockRepo.EXPECT().Update(gomock.Any(), uint(9), uint(0), map[string]interface{}{"title": title}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(9)).Return(&models.Task{Model: gorm.Model{ID: 9}, Title: title}, nil)
+
+		// Act
+		task, err := service.UpdateOccurrence(context.Background(), 1, monday.AddDate(0, 0, 7),
+			dto.UpdateTaskServiceRequest{Title: &title}, services.ScopeFollowing)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, uint(9), task.ID)
+	})
+
+	t.Run("following in a series with a count", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		series := weekly(1)
+		series.Recurrence = "FREQ=DAILY;COUNT=5"
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(series, nil)
+		mockRepo.EXPECT().
+			SplitSeries(gomock.Any(), uint(1), uint(0), map[string]interface{}{
+				"recurrence": "FREQ=DAILY;COUNT=2",
+				"ex_dates":   models.DateList(nil),
+			}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uint, _ uint, _ map[string]interface{}, next *models.Task) error {
+				assert.True(t, wednesday.Equal(next.Date))
+				assert.Equal(t, "FREQ=DAILY;COUNT=3", next.Recurrence)
+				next.ID = 9
+				return nil
+			})
+		mockRepo.EXPECT().Update(gomock.Any(), uint(9), uint(0), map[string]interface{}{"title": title}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(9)).Return(&models.Task{Model: gorm.Model{ID: 9}, Title: title}, nil)
+
+		// Act
+		_, err := service.UpdateOccurrence(context.Background(), 1, wednesday,
+			dto.UpdateTaskServiceRequest{Title: &title}, services.ScopeFollowing)
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("following from the first occurrence", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(weekly(1), nil)
+		mockRepo.EXPECT().Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"title": title}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(weekly(1), nil)
+
+		// Act
+		_, err := service.UpdateOccurrence(context.Background(), 1, monday,
+			dto.UpdateTaskServiceRequest{Title: &title}, services.ScopeFollowing)
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("no occurrence on the day", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(weekly(1), nil)
+
+		// Act
+		task, err := service.UpdateOccurrence(context.Background(), 1, monday.AddDate(0, 0, 1),
+			dto.UpdateTaskServiceRequest{Title: &title}, services.ScopeThis)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "task 1 has no occurrence on 2030-01-08")
+		assert.Nil(t, task)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(weekly(1), nil)
+
+		// Act
+		task, err := service.UpdateOccurrence(context.Background(), 1, wednesday,
+			dto.UpdateTaskServiceRequest{Title: &title, Version: 2}, services.ScopeThis)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+		assert.Nil(t, task)
+	})
+}
+
+func TestTaskService_DeleteOccurrence(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockTaskRepository(ctrl)
+	service := services.NewTaskServiceImpl(mockRepo)
+
+	wednesday := monday.AddDate(0, 0, 2)
+	seriesID := uint(1)
+	series := weekly(1)
+	series.ExDates = models.DateList{monday.AddDate(0, 0, 7)}
+	mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(series, nil)
+	mockRepo.EXPECT().ListOverrides(gomock.Any(), []uint{1}).
+		Return([]models.Task{{Model: gorm.Model{ID: 4}, SeriesID: &seriesID, OccurrenceDate: &wednesday}}, nil)
+	mockRepo.EXPECT().
+		ExcludeOccurrence(gomock.Any(), uint(1), uint(3), map[string]interface{}{
+			"ex_dates": models.DateList{wednesday, monday.AddDate(0, 0, 7)},
+		}, uint(4)).
+		Return(nil)
+
+	// Act
+	err := service.DeleteOccurrence(context.Background(), 1, wednesday, 3)
+
+	// Assert
+	require.NoError(t, err)
+}
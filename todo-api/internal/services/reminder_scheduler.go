@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"todo-api/internal/models"
+	"todo-api/internal/notify"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// SchedulerConfig tunes a ReminderScheduler, a WebhookDispatcher, an
+// OutboxRelay or a TrashPurger. Zero fields take the defaults of
+// DefaultSchedulerConfig.
+type SchedulerConfig struct {
+	Interval    time.Duration // between two looks for due work
+	BatchSize   int           // reminders, deliveries or events handled per look
+	MaxAttempts int           // attempts before one fails for good
+	Backoff     time.Duration // wait before the first retry, doubled for every next one
+	MaxBackoff  time.Duration
+	Timeout     time.Duration // of one delivery or publication attempt
+}
+
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Interval:    15 * time.Second,
+		BatchSize:   100,
+		MaxAttempts: 5,
+		Backoff:     time.Minute,
+		MaxBackoff:  time.Hour,
+		Timeout:     30 * time.Second,
+	}
+}
+
+func (c SchedulerConfig) withDefaults() SchedulerConfig {
+	defaults := DefaultSchedulerConfig()
+	if c.Interval <= 0 {
+		c.Interval = defaults.Interval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaults.BatchSize
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaults.MaxAttempts
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = defaults.Backoff
+	}
+	if c.MaxBackoff < c.Backoff {
+		c.MaxBackoff = max(defaults.MaxBackoff, c.Backoff)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaults.Timeout
+	}
+	return c
+}
+
+// backoff returns the wait before the retry following attempt.
+func (c SchedulerConfig) backoff(attempt int) time.Duration {
+	wait := c.Backoff
+	for i := 1; i < attempt && wait < c.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, c.MaxBackoff)
+}
+
+// ReminderScheduler delivers due reminders through the notifier of their
+// channel. Each attempt is claimed in the repository first, so schedulers
+// in several processes never send a reminder twice at once, and a restart
+// resumes from the stored state: an attempt cut short is retried once its
+// claim runs out.
+type ReminderScheduler struct {
+	repo      repositories.ReminderRepository
+	tasks     repositories.TaskRepository
+	notifiers map[string]notify.Notifier
+	clock     clock.Clock
+	logger    *zap.Logger
+	cfg       SchedulerConfig
+}
+
+func NewReminderScheduler(
+	repo repositories.ReminderRepository,
+	tasks repositories.TaskRepository,
+	notifiers map[string]notify.Notifier,
+	clk clock.Clock,
+	logger *zap.Logger,
+	cfg SchedulerConfig,
+) *ReminderScheduler {
+	return &ReminderScheduler{
+		repo:      repo,
+		tasks:     tasks,
+		notifiers: notifiers,
+		clock:     clk,
+		logger:    logger,
+		cfg:       cfg.withDefaults(),
+	}
+}
+
+// Channels returns the channels the scheduler has a notifier for.
+func (s *ReminderScheduler) Channels() []string {
+	channels := make([]string, 0, len(s.notifiers))
+	for channel := range s.notifiers {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Run delivers due reminders every Interval until ctx is done.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to deliver reminders", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick delivers up to BatchSize reminders due now and returns how many were
+// sent. Failed attempts are scheduled for a retry, not returned.
+func (s *ReminderScheduler) Tick(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	reminders, err := s.repo.ListDue(ctx, now, s.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		delivered, err := s.deliver(ctx, reminder, now)
+		if err != nil {
+			return sent, fmt.Errorf("failed to record delivery of reminder %d: %w", reminder.ID, err)
+		}
+		if delivered {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// deliver makes one attempt at sending a due reminder and records how it
+// went.
+func (s *ReminderScheduler) deliver(ctx context.Context, reminder models.Reminder, now time.Time) (bool, error) {
+	// The claim outlasts the attempt, so it is only retried after a crash.
+	claimed, err := s.repo.Claim(ctx, reminder.ID, now, now.Add(2*s.cfg.Timeout))
+	if err != nil || !claimed {
+		return false, err
+	}
+	reminder.Attempts++
+
+	sendErr := s.send(ctx, reminder)
+	done := s.clock.Now()
+	if sendErr == nil {
+		s.logger.Info("Reminder sent", zap.Uint("reminder_id", reminder.ID), zap.String("channel", reminder.Channel))
+		return true, s.repo.Update(ctx, reminder.ID, map[string]interface{}{
+			"status":          models.ReminderSent,
+			"sent_at":         done,
+			"next_attempt_at": nil,
+			"last_error":      "",
+		})
+	}
+
+	updates := map[string]interface{}{"last_error": sendErr.Error()}
+	if reminder.Attempts >= s.cfg.MaxAttempts {
+		updates["status"] = models.ReminderFailed
+		updates["next_attempt_at"] = nil
+		s.logger.Error("Reminder failed for good",
+			zap.Uint("reminder_id", reminder.ID),
+			zap.Int("attempts", reminder.Attempts),
+			zap.Error(sendErr),
+		)
+	} else {
+		retryAt := done.Add(s.cfg.backoff(reminder.Attempts))
+		updates["next_attempt_at"] = retryAt
+		s.logger.Warn("Reminder attempt failed",
+			zap.Uint("reminder_id", reminder.ID),
+			zap.Int("attempts", reminder.Attempts),
+			zap.Time("retry_at", retryAt),
+			zap.Error(sendErr),
+		)
+	}
+	return false, s.repo.Update(ctx, reminder.ID, updates)
+}
+
+func (s *ReminderScheduler) send(ctx context.Context, reminder models.Reminder) error {
+	notifier, ok := s.notifiers[reminder.Channel]
+	if !ok {
+		return fmt.Errorf("channel %s is not configured", reminder.Channel)
+	}
+	task, err := s.tasks.GetByID(ctx, reminder.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task %d: %w", reminder.TaskID, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	return notifier.Notify(ctx, notify.New(reminder, *task))
+}
@@ -0,0 +1,196 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/notify"
+	"todo-api/internal/repositories"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+// recordingNotifier keeps what it is asked to send and fails while err is
+// set.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []notify.Notification
+	err  error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification notify.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+var schedulerConfig = services.SchedulerConfig{
+	MaxAttempts: 3,
+	Backoff:     time.Minute,
+	MaxBackoff:  time.Hour,
+	Timeout:     10 * time.Second,
+}
+
+func TestReminderScheduler_Tick(t *testing.T) {
+	now := time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC)
+	task := &models.Task{Model: gorm.Model{ID: 1}, Title: "Call mom", Date: now.Truncate(24 * time.Hour)}
+	due := func(attempts int) models.Reminder {
+		return models.Reminder{ID: 4, TaskID: 1, Channel: models.ChannelLog, FireAt: &now, NextAttemptAt: &now, Attempts: attempts}
+	}
+
+	setup := func(t *testing.T, notifier notify.Notifier) (*mock.MockReminderRepository, *services.ReminderScheduler) {
+		ctrl := gomock.NewController(t)
+		reminders := mock.NewMockReminderRepository(ctrl)
+		tasks := mock.NewMockTaskRepository(ctrl)
+		tasks.EXPECT().GetByID(gomock.Any(), uint(1)).Return(task, nil).AnyTimes()
+		scheduler := services.NewReminderScheduler(reminders, tasks,
+			map[string]notify.Notifier{models.ChannelLog: notifier},
+			clock.NewFake(now), zaptest.NewLogger(t), schedulerConfig)
+		return reminders, scheduler
+	}
+
+	t.Run("sent", func(t *testing.T) {
+		// Arrange
+		notifier := &recordingNotifier{}
+		reminders, scheduler := setup(t, notifier)
+		reminders.EXPECT().ListDue(gomock.Any(), now, 100).Return([]models.Reminder{due(0)}, nil)
+		reminders.EXPECT().Claim(gomock.Any(), uint(4), now, now.Add(20*time.Second)).Return(true, nil)
+		reminders.EXPECT().Update(gomock.Any(), uint(4), map[string]interface{}{
+			"status":          models.ReminderSent,
+			"sent_at":         now,
+			"next_attempt_at": nil,
+			"last_error":      "",
+		}).Return(nil)
+
+		// Act
+		sent, err := scheduler.Tick(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		require.Len(t, notifier.sent, 1)
+		assert.Equal(t, "reminder-4-1899363600", notifier.sent[0].ID)
+		assert.Equal(t, "Call mom", notifier.sent[0].Task.Title)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		// Arrange
+		notifier := &recordingNotifier{err: errors.New("connection refused")}
+		reminders, scheduler := setup(t, notifier)
+		reminders.EXPECT().ListDue(gomock.Any(), now, 100).Return([]models.Reminder{due(1)}, nil)
+		reminders.EXPECT().Claim(gomock.Any(), uint(4), now, gomock.Any()).Return(true, nil)
+		reminders.EXPECT().Update(gomock.Any(), uint(4), map[string]interface{}{
+			"last_error":      "connection refused",
+			"next_attempt_at": now.Add(2 * time.Minute), // second attempt, so twice the backoff
+		}).Return(nil)
+
+		// Act
+		sent, err := scheduler.Tick(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+
+	t.Run("failed after the last attempt", func(t *testing.T) {
+		// Arrange
+		notifier := &recordingNotifier{err: errors.New("connection refused")}
+		reminders, scheduler := setup(t, notifier)
+		reminders.EXPECT().ListDue(gomock.Any(), now, 100).Return([]models.Reminder{due(2)}, nil)
+		reminders.EXPECT().Claim(gomock.Any(), uint(4), now, gomock.Any()).Return(true, nil)
+		reminders.EXPECT().Update(gomock.Any(), uint(4), map[string]interface{}{
+			"last_error":      "connection refused",
+			"status":          models.ReminderFailed,
+			"next_attempt_at": nil,
+		}).Return(nil)
+
+		// Act
+		_, err := scheduler.Tick(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("claimed elsewhere", func(t *testing.T) {
+		// Arrange
+		notifier := &recordingNotifier{}
+		reminders, scheduler := setup(t, notifier)
+		reminders.EXPECT().ListDue(gomock.Any(), now, 100).Return([]models.Reminder{due(0)}, nil)
+		reminders.EXPECT().Claim(gomock.Any(), uint(4), now, gomock.Any()).Return(false, nil)
+
+		// Act
+		sent, err := scheduler.Tick(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		assert.Empty(t, notifier.sent)
+	})
+}
+
+// TestReminderScheduler_Restart runs the scheduler over the in-memory
+// repositories to check that the persisted state carries it across a
+// crash: nothing is lost, and nothing is sent twice.
+func TestReminderScheduler_Restart(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := clock.NewFake(time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC))
+	store := repositories.NewMemoryStore(clock.Real())
+	tasks := repositories.NewTaskRepositoryMemory(store)
+	reminders := repositories.NewReminderRepositoryMemory(store)
+	task := &models.Task{Title: "Call mom", Date: time.Date(2030, 3, 10, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, tasks.Create(ctx, task))
+	fireAt := now.Now().Add(time.Minute)
+	require.NoError(t, reminders.Create(ctx, &models.Reminder{
+		TaskID: task.ID, Channel: models.ChannelLog, RemindAt: &fireAt, FireAt: &fireAt, NextAttemptAt: &fireAt,
+	}))
+	notifier := &recordingNotifier{}
+	newScheduler := func() *services.ReminderScheduler {
+		return services.NewReminderScheduler(reminders, tasks, map[string]notify.Notifier{models.ChannelLog: notifier},
+			now, zaptest.NewLogger(t), schedulerConfig)
+	}
+
+	// Act
+	early, err := newScheduler().Tick(ctx)
+	require.NoError(t, err)
+	now.Advance(time.Minute)
+	// A crash right after claiming leaves the attempt unfinished.
+	claimed, err := reminders.Claim(ctx, 1, now.Now(), now.Now().Add(20*time.Second))
+	require.NoError(t, err)
+	require.True(t, claimed)
+	duringClaim, err := newScheduler().Tick(ctx)
+	require.NoError(t, err)
+	now.Advance(20 * time.Second)
+	afterClaim, err := newScheduler().Tick(ctx)
+	require.NoError(t, err)
+	now.Advance(time.Hour)
+	later, err := newScheduler().Tick(ctx)
+	require.NoError(t, err)
+	stored, err := reminders.ListByTask(ctx, task.ID)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Zero(t, early)
+	assert.Zero(t, duringClaim)
+	assert.Equal(t, 1, afterClaim)
+	assert.Zero(t, later)
+	assert.Len(t, notifier.sent, 1)
+	require.Len(t, stored, 1)
+	assert.Equal(t, models.ReminderSent, stored[0].Status)
+	assert.Equal(t, 2, stored[0].Attempts)
+}
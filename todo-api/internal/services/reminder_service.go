@@ -0,0 +1,19 @@
+//go:generate mockgen -source=./reminder_service.go -destination=./mock/reminder_service.go -package=mock
+package services
+
+import (
+	"context"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+type ReminderService interface {
+	// CreateReminder adds a reminder to a task, either at a fixed moment or
+	// some minutes before the task's due time.
+	CreateReminder(ctx context.Context, taskID uint, req dto.CreateReminderServiceRequest) (*models.Reminder, error)
+	// ListReminders returns the reminders of a task with their delivery
+	// state, soonest first.
+	ListReminders(ctx context.Context, taskID uint) ([]models.Reminder, error)
+	DeleteReminder(ctx context.Context, taskID uint, id uint) error
+}
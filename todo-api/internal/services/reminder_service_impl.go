@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+type ReminderServiceImpl struct {
+	repo     repositories.ReminderRepository
+	tasks    repositories.TaskRepository
+	clock    clock.Clock
+	channels []string
+}
+
+// NewReminderServiceImpl returns a service accepting reminders on the given
+// channels, the ones a notifier is configured for.
+func NewReminderServiceImpl(repo repositories.ReminderRepository, tasks repositories.TaskRepository, clk clock.Clock, channels ...string) *ReminderServiceImpl {
+	return &ReminderServiceImpl{repo: repo, tasks: tasks, clock: clk, channels: channels}
+}
+
+func (s *ReminderServiceImpl) CreateReminder(ctx context.Context, taskID uint, req dto.CreateReminderServiceRequest) (*models.Reminder, error) {
+	task, err := s.tasks.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, translateRepoError(err, taskID, "failed to get task")
+	}
+
+	reminder := &models.Reminder{
+		TaskID:  taskID,
+		Channel: req.Channel,
+		Email:   req.Email,
+		Status:  models.ReminderPending,
+	}
+	if reminder.Channel == "" {
+		reminder.Channel = models.ChannelLog
+	}
+	if !slices.Contains(s.channels, reminder.Channel) {
+		return nil, newError(ErrValidation, "channel %s is not configured; available: %s", reminder.Channel, strings.Join(s.channels, ", "))
+	}
+	if (reminder.Channel == models.ChannelEmail) != (reminder.Email != "") {
+		return nil, newError(ErrValidation, "email is required by the email channel and only used by it")
+	}
+
+	switch {
+	case (req.RemindAt == nil) == (req.BeforeMinutes == nil):
+		return nil, newError(ErrValidation, "set either remind_at or before_minutes")
+	case req.RemindAt != nil:
+		if req.RemindAt.Before(s.clock.Now()) {
+			return nil, newError(ErrValidation, "reminder time is in the past")
+		}
+		remindAt := req.RemindAt.UTC()
+		reminder.RemindAt = &remindAt
+		reminder.FireAt = &remindAt
+		reminder.NextAttemptAt = &remindAt
+	default:
+		if task.DueAt == nil {
+			return nil, newError(ErrValidation, "task %d has no due time to remind before", taskID)
+		}
+		reminder.BeforeMinutes = req.BeforeMinutes
+		reminder.Reschedule(task.DueAt)
+	}
+
+	if err = s.repo.Create(ctx, reminder); err != nil {
+		return nil, translateReminderError(err, taskID, 0, "failed to create reminder")
+	}
+	return reminder, nil
+}
+
+func (s *ReminderServiceImpl) ListReminders(ctx context.Context, taskID uint) ([]models.Reminder, error) {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, translateRepoError(err, taskID, "failed to get task")
+	}
+	reminders, err := s.repo.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+func (s *ReminderServiceImpl) DeleteReminder(ctx context.Context, taskID uint, id uint) error {
+	if err := s.repo.Delete(ctx, taskID, id); err != nil {
+		return translateReminderError(err, taskID, id, "failed to delete reminder")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+func TestReminderService_CreateReminder(t *testing.T) {
+	now := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	due := now.Add(6 * time.Hour)
+	timed := &models.Task{Model: gorm.Model{ID: 1}, Title: "Call mom", Date: now.Truncate(24 * time.Hour), DueAt: &due}
+	allDay := &models.Task{Model: gorm.Model{ID: 2}, Title: "Groceries", Date: now.Truncate(24 * time.Hour)}
+	thirty := 30
+
+	setup := func(t *testing.T) (*mock.MockTaskRepository, *mock.MockReminderRepository, *services.ReminderServiceImpl) {
+		ctrl := gomock.NewController(t)
+		tasks := mock.NewMockTaskRepository(ctrl)
+		reminders := mock.NewMockReminderRepository(ctrl)
+		service := services.NewReminderServiceImpl(reminders, tasks, clock.NewFake(now), models.ChannelLog, models.ChannelEmail)
+		return tasks, reminders, service
+	}
+
+	t.Run("before the due time", func(t *testing.T) {
+		// Arrange
+		tasks, reminders, service := setup(t)
+		tasks.EXPECT().GetByID(gomock.Any(), uint(1)).Return(timed, nil)
+		reminders.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		// Act
+		reminder, err := service.CreateReminder(context.Background(), 1, dto.CreateReminderServiceRequest{BeforeMinutes: &thirty})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, models.ChannelLog, reminder.Channel)
+		assert.Equal(t, due.Add(-30*time.Minute), *reminder.FireAt)
+		assert.Equal(t, *reminder.FireAt, *reminder.NextAttemptAt)
+		assert.Equal(t, models.ReminderPending, reminder.Status)
+	})
+
+	t.Run("at a moment", func(t *testing.T) {
+		// Arrange
+		tasks, reminders, service := setup(t)
+		moscow := time.FixedZone("MSK", 3*60*60)
+		remindAt := time.Date(2030, 3, 10, 18, 0, 0, 0, moscow)
+		tasks.EXPECT().GetByID(gomock.Any(), uint(2)).Return(allDay, nil)
+		reminders.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		// Act
+		reminder, err := service.CreateReminder(context.Background(), 2, dto.CreateReminderServiceRequest{
+			Channel:  models.ChannelEmail,
+			Email:    "me@example.com",
+			RemindAt: &remindAt,
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2030, 3, 10, 15, 0, 0, 0, time.UTC), *reminder.FireAt)
+		assert.Equal(t, "me@example.com", reminder.Email)
+	})
+
+	past := now.Add(-time.Minute)
+	for name, tc := range map[string]struct {
+		task *models.Task
+		req  dto.CreateReminderServiceRequest
+	}{
+		"no due time":            {allDay, dto.CreateReminderServiceRequest{BeforeMinutes: &thirty}},
+		"in the past":            {timed, dto.CreateReminderServiceRequest{RemindAt: &past}},
+		"neither time":           {timed, dto.CreateReminderServiceRequest{}},
+		"both times":             {timed, dto.CreateReminderServiceRequest{RemindAt: &due, BeforeMinutes: &thirty}},
+		"channel not configured": {timed, dto.CreateReminderServiceRequest{Channel: models.ChannelWebhook, BeforeMinutes: &thirty}},
+		"email without address":  {timed, dto.CreateReminderServiceRequest{Channel: models.ChannelEmail, BeforeMinutes: &thirty}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			tasks, _, service := setup(t)
+			tasks.EXPECT().GetByID(gomock.Any(), tc.task.ID).Return(tc.task, nil)
+
+			// Act
+			reminder, err := service.CreateReminder(context.Background(), tc.task.ID, tc.req)
+
+			// Assert
+			assert.ErrorIs(t, err, services.ErrValidation)
+			assert.Nil(t, reminder)
+		})
+	}
+
+	t.Run("task not found", func(t *testing.T) {
+		// Arrange
+		tasks, _, service := setup(t)
+		tasks.EXPECT().GetByID(gomock.Any(), uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		_, err := service.CreateReminder(context.Background(), 9, dto.CreateReminderServiceRequest{BeforeMinutes: &thirty})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+}
+
+func TestReminderService_DeleteReminder(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	reminders := mock.NewMockReminderRepository(ctrl)
+	service := services.NewReminderServiceImpl(reminders, mock.NewMockTaskRepository(ctrl), clock.Real(), models.ChannelLog)
+	reminders.EXPECT().Delete(gomock.Any(), uint(1), uint(4)).Return(gorm.ErrRecordNotFound)
+
+	// Act
+	err := service.DeleteReminder(context.Background(), 1, 4)
+
+	// Assert
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.EqualError(t, err, "reminder 4 of task 1 not found")
+}
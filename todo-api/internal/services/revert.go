@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+// RevertTask rebuilds the task at the target version by taking back the
+// changes made since, then updates the task to it through UpdateTask. Only
+// the fields an update can set are reverted: the rank and the trash state
+// stay as they are.
+func (s *TaskServiceImpl) RevertTask(ctx context.Context, id uint, req dto.RevertTaskServiceRequest) (*models.Task, error) {
+	if err := s.checkHistory(); err != nil {
+		return nil, err
+	}
+	if (req.ToVersion == nil) == (req.HistoryID == nil) {
+		return nil, newError(ErrValidation, "set either version or history_id")
+	}
+
+	target := req.ToVersion
+	if req.HistoryID != nil {
+		entry, err := s.history.Get(ctx, id, *req.HistoryID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newError(ErrNotFound, "task %d has no history entry %d", id, *req.HistoryID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get history entry: %w", err)
+		}
+		target = &entry.Version
+	}
+
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+	if *target == task.Version {
+		return nil, newError(ErrValidation, "task %d is already at version %d", id, *target)
+	}
+
+	changes, err := s.history.ListFromVersion(ctx, id, *target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task history: %w", err)
+	}
+	if len(changes) == 0 || changes[0].Version != *target {
+		return nil, newError(ErrNotFound, "task %d has no version %d", id, *target)
+	}
+
+	past := *task
+	past.Tags = slices.Clone(task.Tags)
+	for i := len(changes) - 1; i > 0; i-- {
+		if err = changes[i].Changes.Revert(&past); err != nil {
+			return nil, fmt.Errorf("failed to revert change %d: %w", changes[i].ID, err)
+		}
+	}
+
+	update := revertUpdate(task, &past)
+	if update == nil {
+		return nil, newError(ErrValidation, "version %d of task %d has the same values as the current one", *target, id)
+	}
+	update.Version = req.Version
+	return s.UpdateTask(ctx, id, *update)
+}
+
+// revertUpdate returns the update that gives task the field values of past,
+// or nil when they are the same.
+func revertUpdate(task *models.Task, past *models.Task) *dto.UpdateTaskServiceRequest {
+	var update dto.UpdateTaskServiceRequest
+	changed := false
+
+	if past.Title != task.Title {
+		changed = true
+		update.Title = &past.Title
+	}
+	if past.Description != task.Description {
+		changed = true
+		update.Description = &past.Description
+	}
+	if !past.Date.Equal(task.Date) {
+		changed = true
+		update.Date = &past.Date
+	}
+	if past.Completed != task.Completed {
+		changed = true
+		update.Completed = &past.Completed
+	}
+	if past.Priority != task.Priority {
+		changed = true
+		update.Priority = &past.Priority
+	}
+	if !equalRef(past.ProjectID, task.ProjectID) {
+		changed = true
+		update.ProjectID = refOrZero(past.ProjectID)
+	}
+	if !equalRef(past.ParentID, task.ParentID) {
+		changed = true
+		update.ParentID = refOrZero(past.ParentID)
+	}
+	if past.Recurrence != task.Recurrence {
+		changed = true
+		update.Recurrence = &past.Recurrence
+	}
+	if !slices.EqualFunc(past.ExDates, task.ExDates, time.Time.Equal) {
+		changed = true
+		update.ExDates = append([]time.Time{}, past.ExDates...)
+	}
+
+	if !equalDue(past, task) {
+		changed = true
+		dueTime := ""
+		if past.DueAt != nil {
+			loc, err := time.LoadLocation(past.TimeZone)
+			if err != nil {
+				loc = time.UTC
+			}
+			dueTime = past.DueAt.In(loc).Format(dueTimeLayout)
+			update.Location = loc
+		}
+		update.DueTime = &dueTime
+	}
+
+	current, old := tagNames(task.Tags), tagNames(past.Tags)
+	for _, name := range old {
+		if !slices.Contains(current, name) {
+			changed = true
+			update.AddTags = append(update.AddTags, name)
+		}
+	}
+	for _, name := range current {
+		if !slices.Contains(old, name) {
+			changed = true
+			update.RemoveTags = append(update.RemoveTags, name)
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return &update
+}
+
+func equalRef(a, b *uint) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+// refOrZero returns id the way an update takes it, 0 standing for none.
+func refOrZero(id *uint) *uint {
+	if id == nil {
+		return new(uint)
+	}
+	return id
+}
+
+func equalDue(a, b *models.Task) bool {
+	if a.DueAt == nil || b.DueAt == nil {
+		return a.DueAt == nil && b.DueAt == nil
+	}
+	return a.DueAt.Equal(*b.DueAt) && a.TimeZone == b.TimeZone
+}
+
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
@@ -0,0 +1,206 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+func TestTaskService_RevertTask(t *testing.T) {
+	// Version 1 created the task, 2 renamed it and tagged it, 3 described it.
+	current := func() *models.Task {
+		return &models.Task{
+			Model:       gorm.Model{ID: 1},
+			Title:       "Renamed",
+			Description: "Details",
+			Tags:        []models.Tag{{Name: "work"}},
+			Version:     3,
+		}
+	}
+	history := []models.TaskHistory{
+		{ID: 10, Version: 1, Action: models.HistoryCreated},
+		{ID: 11, Version: 2, Action: models.HistoryUpdated, Changes: models.FieldChanges{
+			"title":   change(`"Original"`, `"Renamed"`),
+			"tags":    change(`[]`, `["work"]`),
+			"version": change(`1`, `2`),
+		}},
+		{ID: 12, Version: 3, Action: models.HistoryUpdated, Changes: models.FieldChanges{
+			"description": change(`""`, `"Details"`),
+			"version":     change(`2`, `3`),
+		}},
+	}
+	one := uint(1)
+
+	t.Run("to a version", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		mockHistory := mock.NewMockHistoryRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory))
+
+		reverted := &models.Task{Model: gorm.Model{ID: 1}, Title: "Original", Version: 4}
+		gomock.InOrder(
+			mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(current(), nil),
+			mockHistory.EXPECT().ListFromVersion(gomock.Any(), uint(1), uint(1)).Return(history, nil),
+			mockRepo.EXPECT().
+				Update(gomock.Any(), uint(1), uint(3), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ uint, _ uint, updates map[string]interface{}) error {
+					assert.Equal(t, map[string]interface{}{
+						"title":                    "Original",
+						"description":              "",
+						repositories.TagChangesKey: repositories.TagChanges{Detach: []string{"work"}},
+					}, updates)
+					return nil
+				}),
+			mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(reverted, nil),
+		)
+
+		// Act
+		task, err := service.RevertTask(context.Background(), 1, dto.RevertTaskServiceRequest{ToVersion: &one, Version: 3})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, reverted, task)
+	})
+
+	t.Run("to a history entry", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		mockHistory := mock.NewMockHistoryRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory))
+
+		entryID := uint(11)
+		mockHistory.EXPECT().Get(gomock.Any(), uint(1), entryID).Return(&history[1], nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(current(), nil)
+		mockHistory.EXPECT().ListFromVersion(gomock.Any(), uint(1), uint(2)).Return(history[1:], nil)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"description": ""}).
+			Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+		// Act
+		_, err := service.RevertTask(context.Background(), 1, dto.RevertTaskServiceRequest{HistoryID: &entryID})
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	two, three, nine := uint(2), uint(3), uint(9)
+	tests := []struct {
+		name    string
+		req     dto.RevertTaskServiceRequest
+		setup   func(repo *mock.MockTaskRepository, history *mock.MockHistoryRepository)
+		kind    error
+		message string
+	}{
+		{
+			name:    "no target",
+			req:     dto.RevertTaskServiceRequest{},
+			kind:    services.ErrValidation,
+			message: "set either version or history_id",
+		},
+		{
+			name: "both targets",
+			req:  dto.RevertTaskServiceRequest{ToVersion: &two, HistoryID: &nine},
+			kind: services.ErrValidation,
+		},
+		{
+			name: "unknown history entry",
+			req:  dto.RevertTaskServiceRequest{HistoryID: &nine},
+			setup: func(_ *mock.MockTaskRepository, history *mock.MockHistoryRepository) {
+				history.EXPECT().Get(gomock.Any(), uint(1), uint(9)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			kind:    services.ErrNotFound,
+			message: "task 1 has no history entry 9",
+		},
+		{
+			name: "current version",
+			req:  dto.RevertTaskServiceRequest{ToVersion: &three},
+			setup: func(repo *mock.MockTaskRepository, _ *mock.MockHistoryRepository) {
+				repo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(current(), nil)
+			},
+			kind:    services.ErrValidation,
+			message: "task 1 is already at version 3",
+		},
+		{
+			name: "version not in the history",
+			req:  dto.RevertTaskServiceRequest{ToVersion: &nine},
+			setup: func(repo *mock.MockTaskRepository, history *mock.MockHistoryRepository) {
+				repo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(current(), nil)
+				history.EXPECT().ListFromVersion(gomock.Any(), uint(1), uint(9)).Return(nil, nil)
+			},
+			kind:    services.ErrNotFound,
+			message: "task 1 has no version 9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockTaskRepository(ctrl)
+			mockHistory := mock.NewMockHistoryRepository(ctrl)
+			service := services.NewTaskServiceImpl(mockRepo, services.WithHistory(mockHistory))
+			if tt.setup != nil {
+				tt.setup(mockRepo, mockHistory)
+			}
+
+			// Act
+			_, err := service.RevertTask(context.Background(), 1, tt.req)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.kind)
+			if tt.message != "" {
+				assert.EqualError(t, err, tt.message)
+			}
+		})
+	}
+}
+
+func TestTaskService_RevertTask_Memory(t *testing.T) {
+	// Arrange
+	store := repositories.NewMemoryStore(clock.Real())
+	repo := repositories.NewTaskRepositoryMemory(store)
+	history := repositories.NewHistoryRepositoryMemory(store)
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	service := services.NewTaskServiceImpl(repo,
+		services.WithHistory(history),
+		services.WithClock(clock.NewFake(date)),
+	)
+	ctx := context.Background()
+
+	created, err := service.CreateTask(ctx, dto.CreateTaskServiceRequest{Title: "Original", Date: date})
+	require.NoError(t, err)
+	title, later, high := "Overwritten", date.AddDate(0, 0, 7), models.PriorityHigh
+	_, err = service.UpdateTask(ctx, created.ID, dto.UpdateTaskServiceRequest{Title: &title, Date: &later, Priority: &high})
+	require.NoError(t, err)
+
+	// Act
+	target := uint(1)
+	task, err := service.RevertTask(ctx, created.ID, dto.RevertTaskServiceRequest{ToVersion: &target, Version: 2})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Original", task.Title)
+	assert.True(t, date.Equal(task.Date))
+	assert.Equal(t, created.Priority, task.Priority)
+	assert.Equal(t, uint(3), task.Version)
+
+	entries, err := history.List(ctx, created.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "the revert is a change of its own")
+	assert.Equal(t, models.HistoryUpdated, entries[0].Action)
+	assert.JSONEq(t, `"Original"`, string(entries[0].Changes["title"].After))
+}
@@ -0,0 +1,19 @@
+//go:generate mockgen -source=./settings_service.go -destination=./mock/settings_service.go -package=mock
+package services
+
+import (
+	"context"
+	"time"
+
+	"todo-api/internal/models"
+)
+
+// SettingsService keeps the settings of each actor, the X-Actor of a
+// request. An actor who has saved none gets the defaults.
+type SettingsService interface {
+	GetSettings(ctx context.Context, actor string) (*models.UserSettings, error)
+	// UpdateSettings replaces the actor's settings.
+	UpdateSettings(ctx context.Context, settings *models.UserSettings) (*models.UserSettings, error)
+	// Location returns the actor's time zone, nil when they have not set one.
+	Location(ctx context.Context, actor string) (*time.Location, error)
+}
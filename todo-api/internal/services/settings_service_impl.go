@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+type SettingsServiceImpl struct {
+	repo repositories.SettingsRepository
+}
+
+func NewSettingsServiceImpl(repo repositories.SettingsRepository) *SettingsServiceImpl {
+	return &SettingsServiceImpl{repo: repo}
+}
+
+func (s *SettingsServiceImpl) GetSettings(ctx context.Context, actor string) (*models.UserSettings, error) {
+	if actor == "" {
+		return nil, newError(ErrValidation, "settings belong to an actor, name one in X-Actor")
+	}
+
+	settings, err := s.repo.Get(ctx, actor)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.UserSettings{Actor: actor}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (s *SettingsServiceImpl) UpdateSettings(ctx context.Context, settings *models.UserSettings) (*models.UserSettings, error) {
+	if settings.Actor == "" {
+		return nil, newError(ErrValidation, "settings belong to an actor, name one in X-Actor")
+	}
+	if _, err := loadTimeZone(settings.TimeZone); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save settings: %w", err)
+	}
+	return s.GetSettings(ctx, settings.Actor)
+}
+
+func (s *SettingsServiceImpl) Location(ctx context.Context, actor string) (*time.Location, error) {
+	if actor == "" {
+		return nil, nil
+	}
+
+	settings, err := s.repo.Get(ctx, actor)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	return loadTimeZone(settings.TimeZone)
+}
+
+// loadTimeZone returns the IANA time zone called name, nil when name is "".
+// LoadLocation treats "Local" as the server's zone, which a client cannot
+// mean.
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, newError(ErrValidation, "time zone %q is not an IANA time zone such as Europe/Moscow", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+)
+
+func TestSettingsService_GetSettings(t *testing.T) {
+	t.Run("defaults when never saved", func(t *testing.T) {
+		// Arrange
+		mockRepo := mock.NewMockSettingsRepository(gomock.NewController(t))
+		service := services.NewSettingsServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Get(gomock.Any(), "alice").Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		settings, err := service.GetSettings(context.Background(), "alice")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, &models.UserSettings{Actor: "alice"}, settings)
+	})
+
+	t.Run("no actor", func(t *testing.T) {
+		// Arrange
+		service := services.NewSettingsServiceImpl(mock.NewMockSettingsRepository(gomock.NewController(t)))
+
+		// Act
+		_, err := service.GetSettings(context.Background(), "")
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+	})
+}
+
+func TestSettingsService_UpdateSettings(t *testing.T) {
+	for _, zone := range []string{"Mars/Olympus_Mons", "Local"} {
+		t.Run("unknown zone "+zone, func(t *testing.T) {
+			// Arrange
+			service := services.NewSettingsServiceImpl(mock.NewMockSettingsRepository(gomock.NewController(t)))
+
+			// Act
+			_, err := service.UpdateSettings(context.Background(), &models.UserSettings{Actor: "alice", TimeZone: zone})
+
+			// Assert
+			assert.ErrorIs(t, err, services.ErrValidation)
+		})
+	}
+}
+
+func TestSettingsService_Location(t *testing.T) {
+	t.Run("saved zone", func(t *testing.T) {
+		// Arrange
+		mockRepo := mock.NewMockSettingsRepository(gomock.NewController(t))
+		service := services.NewSettingsServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Get(gomock.Any(), "alice").
+			Return(&models.UserSettings{Actor: "alice", TimeZone: "Asia/Tokyo"}, nil)
+
+		// Act
+		loc, err := service.Location(context.Background(), "alice")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", loc.String())
+	})
+
+	t.Run("no zone set", func(t *testing.T) {
+		// Arrange
+		mockRepo := mock.NewMockSettingsRepository(gomock.NewController(t))
+		service := services.NewSettingsServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Get(gomock.Any(), "alice").Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		loc, err := service.Location(context.Background(), "alice")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Nil(t, loc)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		// Arrange
+		mockRepo := mock.NewMockSettingsRepository(gomock.NewController(t))
+		service := services.NewSettingsServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Get(gomock.Any(), "alice").Return(nil, errors.New("connection refused"))
+
+		// Act
+		_, err := service.Location(context.Background(), "alice")
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,40 @@
+//go:generate mockgen -source=./stream_service.go -destination=./mock/stream_service.go -package=mock
+package services
+
+import (
+	"context"
+
+	"todo-api/internal/dto"
+)
+
+type StreamService interface {
+	// Subscribe opens a stream of the changes to the tasks matching filter.
+	// With lastEventID it resumes after that event. The caller must Close
+	// the subscription.
+	Subscribe(ctx context.Context, filter dto.TaskFilter, lastEventID string) *Subscription
+}
+
+// Subscription is an open task stream.
+type Subscription struct {
+	// Replay holds the events since Last-Event-ID, to be sent first. It may
+	// repeat events the client got before, and events it repeats may come
+	// again among the live ones; each carries the whole task, so the client
+	// ends up with the latest state either way.
+	Replay []dto.StreamEvent
+	// Reset is set when the events since Last-Event-ID are no longer
+	// known; the client has to reload its tasks.
+	Reset bool
+	// Events delivers the live events. It is closed when the stream is
+	// dropped, because the client fell behind or events may have been
+	// missed; the client reconnects with the ID of the last event it got.
+	Events <-chan dto.StreamEvent
+
+	close func()
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	if s.close != nil {
+		s.close()
+	}
+}
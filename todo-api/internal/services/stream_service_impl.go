@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+const (
+	// streamBuffer is how many live events a stream can fall behind before
+	// it is dropped.
+	streamBuffer = 64
+	// streamRetryDelay is the wait before listening on the event bus again
+	// after it failed.
+	streamRetryDelay = 5 * time.Second
+	// streamReplayTimeout bounds reading the outbox for a resuming client.
+	streamReplayTimeout = 5 * time.Second
+	// streamReplayMargin is how long before the client's last event a
+	// replay from the outbox starts. An event is marked published only
+	// once every consumer took it, so one streamed after the client's last
+	// event may have been marked a little before it.
+	streamReplayMargin = time.Minute
+)
+
+// StreamServiceImpl pushes task events to the streams open in this
+// process. As a consumer of the outbox relay it publishes every event on
+// the event bus, and Run listens on the bus, so the changes made through
+// any process reach the streams of all of them. The latest events are kept
+// for clients resuming with Last-Event-ID; older ones are read back from
+// the outbox.
+type StreamServiceImpl struct {
+	bus     repositories.EventBus
+	outbox  repositories.OutboxRepository
+	logger  *zap.Logger
+	backlog int
+
+	mu          sync.Mutex
+	recent      []models.Event // oldest first, at most backlog
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	filter dto.TaskFilter
+	events chan dto.StreamEvent
+}
+
+// NewStreamServiceImpl returns a service keeping the last backlog events
+// for resuming clients. A client that missed more than backlog events has
+// to reload.
+func NewStreamServiceImpl(
+	bus repositories.EventBus,
+	outbox repositories.OutboxRepository,
+	logger *zap.Logger,
+	backlog int,
+) *StreamServiceImpl {
+	return &StreamServiceImpl{
+		bus:         bus,
+		outbox:      outbox,
+		logger:      logger,
+		backlog:     backlog,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Consume publishes an event on the bus, unless it is of a type streams
+// leave out.
+func (s *StreamServiceImpl) Consume(ctx context.Context, event models.Event) error {
+	if !streamed(event.Type) {
+		return nil
+	}
+	return s.bus.Publish(ctx, event)
+}
+
+// Run listens on the event bus until ctx is done. Whenever listening stops
+// events may have been missed, so the open streams are dropped and the
+// kept events forgotten: resuming clients get theirs from the outbox.
+func (s *StreamServiceImpl) Run(ctx context.Context) {
+	for {
+		err := s.bus.Listen(ctx, s.broadcast)
+		s.reset()
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Error("Stopped listening for task events, will retry",
+			zap.Duration("retry_in", streamRetryDelay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamRetryDelay):
+		}
+	}
+}
+
+func (s *StreamServiceImpl) Subscribe(ctx context.Context, filter dto.TaskFilter, lastEventID string) *Subscription {
+	sub := &subscriber{filter: filter, events: make(chan dto.StreamEvent, streamBuffer)}
+	subscription := &Subscription{
+		Events: sub.events,
+		close: func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.drop(sub)
+		},
+	}
+
+	s.mu.Lock()
+	last := -1
+	if lastEventID != "" {
+		last = s.position(lastEventID)
+	}
+	if last >= 0 {
+		for _, event := range s.recent[last+1:] {
+			if message, ok := streamMessage(event, filter); ok {
+				subscription.Replay = append(subscription.Replay, message)
+			}
+		}
+	}
+	// Live events queue up for the subscriber while the outbox is read.
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	if lastEventID != "" && last < 0 {
+		subscription.Replay, subscription.Reset = s.replay(ctx, filter, lastEventID)
+	}
+	return subscription
+}
+
+// replay returns the messages of the events since lastEventID from the
+// outbox, or reports that the client has to reload when the outbox no
+// longer has the event or more than backlog followed it. Events of a
+// task are replayed from some point up to its latest one, so that the
+// events the client already got and gets again leave it with the latest
+// state of every task.
+func (s *StreamServiceImpl) replay(ctx context.Context, filter dto.TaskFilter, lastEventID string) ([]dto.StreamEvent, bool) {
+	ctx, cancel := context.WithTimeout(ctx, streamReplayTimeout)
+	defer cancel()
+
+	last, err := s.outbox.GetByEventID(ctx, lastEventID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("Failed to read the last event of a resuming stream",
+				zap.String("event_id", lastEventID), zap.Error(err))
+		}
+		return nil, true
+	}
+
+	since := time.Now()
+	if last.PublishedAt != nil {
+		since = *last.PublishedAt
+	}
+	rows, err := s.outbox.ListSince(ctx, since.Add(-streamReplayMargin), s.backlog+1)
+	if err != nil {
+		s.logger.Warn("Failed to read the events of a resuming stream",
+			zap.String("event_id", lastEventID), zap.Error(err))
+		return nil, true
+	}
+	if len(rows) > s.backlog {
+		return nil, true
+	}
+
+	var messages []dto.StreamEvent
+	for _, row := range rows {
+		if row.EventID == lastEventID || !streamed(row.Type) {
+			continue
+		}
+		event, err := row.Event()
+		if err != nil {
+			s.logger.Warn("Failed to decode an outbox event", zap.String("event_id", row.EventID), zap.Error(err))
+			return nil, true
+		}
+		if message, ok := streamMessage(event, filter); ok {
+			messages = append(messages, message)
+		}
+	}
+	return messages, false
+}
+
+// broadcast hands an event from the bus to the matching streams, dropping
+// those too far behind to take it. Repeats are ignored. It runs in the
+// bus's listener, so it must not wait on anything but the lock.
+func (s *StreamServiceImpl) broadcast(event models.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.position(event.ID) >= 0 {
+		return
+	}
+	s.recent = append(s.recent, event)
+	if len(s.recent) > s.backlog {
+		s.recent = s.recent[len(s.recent)-s.backlog:]
+	}
+
+	for sub := range s.subscribers {
+		message, ok := streamMessage(event, sub.filter)
+		if !ok {
+			continue
+		}
+		select {
+		case sub.events <- message:
+		default:
+			s.logger.Warn("Dropping a task stream that fell behind", zap.String("event_id", event.ID))
+			s.drop(sub)
+		}
+	}
+}
+
+// position returns the index of the kept event with id, or -1.
+func (s *StreamServiceImpl) position(id string) int {
+	for i := len(s.recent) - 1; i >= 0; i-- {
+		if s.recent[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *StreamServiceImpl) drop(sub *subscriber) {
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (s *StreamServiceImpl) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		s.drop(sub)
+	}
+	s.recent = nil
+}
+
+// streamed reports whether streams get events of type t. Completions are
+// left out, as every one comes along with an update, and so are purges: a
+// task is purged from the trash, so it left the streams when deleted.
+func streamed(t models.EventType) bool {
+	return t != models.EventTaskCompleted && t != models.EventTaskPurged
+}
+
+// streamMessage returns the message a stream with filter gets for event,
+// if any: the event itself when its task matches, and task.left when an
+// updated task does not.
+func streamMessage(event models.Event, filter dto.TaskFilter) (dto.StreamEvent, bool) {
+	task := event.Task
+	var t string
+	switch {
+	case streamMatches(&task, filter, event.ProjectArchived):
+		t = string(event.Type)
+	case event.Type == models.EventTaskUpdated:
+		t = dto.StreamTaskLeft
+	default:
+		return dto.StreamEvent{}, false
+	}
+	return dto.StreamEvent{ID: event.ID, Type: t, Task: &task}, true
+}
+
+// streamMatches reports whether a task belongs in a stream with filter. A
+// recurring task matches a date range it has an occurrence in.
+func streamMatches(task *models.Task, filter dto.TaskFilter, archived bool) bool {
+	if task.Recurrence != "" && filter.DateFrom != nil && filter.DateTo != nil {
+		days, err := occurrences(task, *filter.DateFrom, *filter.DateTo)
+		if err != nil || len(days) == 0 {
+			return false
+		}
+		filter.DateFrom, filter.DateTo = nil, nil
+	}
+	return repositories.TaskMatches(task, filter, archived)
+}
@@ -0,0 +1,280 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+// listeningStream runs a stream service on a mocked bus and returns the
+// handler it listens with. Sending on lose makes the bus fail once. A nil
+// outbox is an empty one.
+func listeningStream(t *testing.T, outbox repositories.OutboxRepository, backlog int) (*services.StreamServiceImpl, func(models.Event), chan<- error) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	bus := mock.NewMockEventBus(ctrl)
+	handles := make(chan func(models.Event))
+	lose := make(chan error)
+	bus.EXPECT().Listen(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, handle func(models.Event)) error {
+		select {
+		case handles <- handle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-lose:
+			return err
+		}
+	}).AnyTimes()
+
+	if outbox == nil {
+		outbox = repositories.NewOutboxRepositoryMemory(repositories.NewMemoryStore(clock.Real()))
+	}
+	service := services.NewStreamServiceImpl(bus, outbox, zaptest.NewLogger(t), backlog)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go service.Run(ctx)
+	return service, <-handles, lose
+}
+
+func taskEvent(id string, t models.EventType, task models.Task) models.Event {
+	return models.Event{ID: id, Type: t, OccurredAt: time.Now(), Task: task}
+}
+
+// received drains the messages already delivered to a subscription.
+func received(sub *services.Subscription) []dto.StreamEvent {
+	var messages []dto.StreamEvent
+	for {
+		select {
+		case message, ok := <-sub.Events:
+			if !ok {
+				return messages
+			}
+			messages = append(messages, message)
+		default:
+			return messages
+		}
+	}
+}
+
+func messageTypes(messages []dto.StreamEvent) []string {
+	types := make([]string, len(messages))
+	for i, message := range messages {
+		types[i] = message.ID + " " + message.Type
+	}
+	return types
+}
+
+func TestStreamService(t *testing.T) {
+	open := false
+	openTasks := dto.TaskFilter{Completed: &open}
+	call := models.Task{Model: gorm.Model{ID: 1}, Title: "Call mom", Date: monday}
+	done := call
+	done.Completed = true
+	milk := models.Task{Model: gorm.Model{ID: 2}, Title: "Buy milk", Date: monday, Completed: true}
+
+	t.Run("filters live events and resumes after an ID", func(t *testing.T) {
+		// Arrange
+		service, broadcast, _ := listeningStream(t, nil, 10)
+		sub := service.Subscribe(context.Background(), openTasks, "")
+		defer sub.Close()
+
+		// Act
+		broadcast(taskEvent("e1", models.EventTaskCreated, call))
+		broadcast(taskEvent("e2", models.EventTaskCreated, milk))
+		broadcast(taskEvent("e3", models.EventTaskUpdated, done))
+		broadcast(taskEvent("e3", models.EventTaskUpdated, done))
+		broadcast(taskEvent("e4", models.EventTaskDeleted, done))
+		live := received(sub)
+		resumed := service.Subscribe(context.Background(), openTasks, "e1")
+		defer resumed.Close()
+		unknown := service.Subscribe(context.Background(), openTasks, "e0")
+		defer unknown.Close()
+
+		// Assert
+		assert.Equal(t, []string{"e1 task.created", "e3 task.left"}, messageTypes(live))
+		assert.Equal(t, "Call mom", live[0].Task.Title)
+		assert.False(t, resumed.Reset)
+		assert.Equal(t, []string{"e3 task.left"}, messageTypes(resumed.Replay))
+		assert.True(t, unknown.Reset)
+		assert.Empty(t, unknown.Replay)
+	})
+
+	t.Run("backlog keeps the latest events", func(t *testing.T) {
+		// Arrange
+		service, broadcast, _ := listeningStream(t, nil, 2)
+		broadcast(taskEvent("e1", models.EventTaskCreated, call))
+		broadcast(taskEvent("e2", models.EventTaskUpdated, call))
+		broadcast(taskEvent("e3", models.EventTaskUpdated, call))
+
+		// Act
+		evicted := service.Subscribe(context.Background(), dto.TaskFilter{}, "e1")
+		defer evicted.Close()
+		kept := service.Subscribe(context.Background(), dto.TaskFilter{}, "e2")
+		defer kept.Close()
+
+		// Assert
+		assert.True(t, evicted.Reset)
+		assert.False(t, kept.Reset)
+		assert.Equal(t, []string{"e3 task.updated"}, messageTypes(kept.Replay))
+	})
+
+	t.Run("tasks of archived projects are hidden unless included", func(t *testing.T) {
+		// Arrange
+		service, broadcast, _ := listeningStream(t, nil, 10)
+		hidden := service.Subscribe(context.Background(), dto.TaskFilter{}, "")
+		defer hidden.Close()
+		included := service.Subscribe(context.Background(), dto.TaskFilter{IncludeArchived: true}, "")
+		defer included.Close()
+		task := call
+		projectID := uint(7)
+		task.ProjectID = &projectID
+		event := taskEvent("e1", models.EventTaskCreated, task)
+		event.ProjectArchived = true
+
+		// Act
+		broadcast(event)
+
+		// Assert
+		assert.Empty(t, received(hidden))
+		assert.Equal(t, []string{"e1 task.created"}, messageTypes(received(included)))
+	})
+
+	t.Run("recurring tasks match a range they occur in", func(t *testing.T) {
+		// Arrange
+		service, broadcast, _ := listeningStream(t, nil, 10)
+		from, to := monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 7)
+		sub := service.Subscribe(context.Background(), dto.TaskFilter{DateFrom: &from, DateTo: &to}, "")
+		defer sub.Close()
+		plants := models.Task{Model: gorm.Model{ID: 3}, Title: "Water plants", Date: monday, Recurrence: "FREQ=WEEKLY"}
+		daily := models.Task{Model: gorm.Model{ID: 4}, Title: "Standup", Date: monday, Recurrence: "FREQ=DAILY;COUNT=3"}
+
+		// Act
+		broadcast(taskEvent("e1", models.EventTaskCreated, plants))
+		broadcast(taskEvent("e2", models.EventTaskCreated, daily))
+
+		// Assert
+		assert.Equal(t, []string{"e1 task.created"}, messageTypes(received(sub)))
+	})
+
+	t.Run("a stream that falls behind is dropped", func(t *testing.T) {
+		// Arrange
+		service, broadcast, _ := listeningStream(t, nil, 1000)
+		sub := service.Subscribe(context.Background(), dto.TaskFilter{}, "")
+		defer sub.Close()
+
+		// Act
+		for i := 0; i < 100; i++ {
+			broadcast(taskEvent(fmt.Sprintf("e%d", i), models.EventTaskUpdated, call))
+		}
+		messages := received(sub)
+		_, open := <-sub.Events
+
+		// Assert
+		assert.Len(t, messages, 64)
+		assert.False(t, open)
+	})
+
+	t.Run("losing the bus drops streams and forgets events", func(t *testing.T) {
+		// Arrange
+		service, broadcast, lose := listeningStream(t, nil, 10)
+		broadcast(taskEvent("e1", models.EventTaskCreated, call))
+		sub := service.Subscribe(context.Background(), dto.TaskFilter{}, "")
+
+		// Act
+		lose <- errors.New("connection reset by peer")
+		_, open := <-sub.Events
+		resumed := service.Subscribe(context.Background(), dto.TaskFilter{}, "e1")
+		defer resumed.Close()
+
+		// Assert
+		assert.False(t, open)
+		assert.True(t, resumed.Reset)
+	})
+
+	t.Run("events the process does not keep are replayed from the outbox", func(t *testing.T) {
+		// Arrange
+		store := repositories.NewMemoryStore(clock.Real())
+		tasks := repositories.NewTaskRepositoryMemory(store)
+		outbox := repositories.NewOutboxRepositoryMemory(store)
+		ctx := context.Background()
+		report := &models.Task{Title: "Write report", Date: monday}
+		require.NoError(t, tasks.Create(ctx, report))
+		require.NoError(t, tasks.Update(ctx, report.ID, 0, map[string]interface{}{"completed": true}))
+		require.NoError(t, tasks.Create(ctx, &models.Task{Title: "Buy milk", Date: monday}))
+		rows, err := outbox.ListSince(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		service, _, _ := listeningStream(t, outbox, 10)
+
+		// Act
+		resumed := service.Subscribe(ctx, openTasks, rows[0].EventID)
+		defer resumed.Close()
+
+		// Assert
+		assert.False(t, resumed.Reset)
+		assert.Equal(t, []string{
+			rows[1].EventID + " task.left",
+			rows[3].EventID + " task.created",
+		}, messageTypes(resumed.Replay))
+	})
+
+	t.Run("too many events since the last one reset the client", func(t *testing.T) {
+		// Arrange
+		store := repositories.NewMemoryStore(clock.Real())
+		tasks := repositories.NewTaskRepositoryMemory(store)
+		outbox := repositories.NewOutboxRepositoryMemory(store)
+		ctx := context.Background()
+		for i := 0; i < 4; i++ {
+			require.NoError(t, tasks.Create(ctx, &models.Task{Title: fmt.Sprintf("Task %d", i), Date: monday}))
+		}
+		rows, err := outbox.ListSince(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		service, _, _ := listeningStream(t, outbox, 2)
+
+		// Act
+		resumed := service.Subscribe(ctx, dto.TaskFilter{}, rows[0].EventID)
+		defer resumed.Close()
+
+		// Assert
+		assert.True(t, resumed.Reset)
+		assert.Empty(t, resumed.Replay)
+	})
+}
+
+func TestStreamService_Consume(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	bus := mock.NewMockEventBus(ctrl)
+	service := services.NewStreamServiceImpl(bus, nil, zaptest.NewLogger(t), 10)
+	task := models.Task{Model: gorm.Model{ID: 1}, Title: "Call mom", Completed: true}
+	updated := taskEvent("e1", models.EventTaskUpdated, task)
+	bus.EXPECT().Publish(gomock.Any(), updated).Return(nil)
+
+	// Act
+	updateErr := service.Consume(context.Background(), updated)
+	completedErr := service.Consume(context.Background(), taskEvent("e2", models.EventTaskCompleted, task))
+	purgedErr := service.Consume(context.Background(), taskEvent("e3", models.EventTaskPurged, task))
+
+	// Assert
+	assert.NoError(t, updateErr)
+	assert.NoError(t, completedErr)
+	assert.NoError(t, purgedErr)
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+// CompletionPolicy decides what completing a task does to its open
+// subtasks.
+type CompletionPolicy string
+
+const (
+	// CompletionIndependent completes only the task itself.
+	CompletionIndependent CompletionPolicy = "independent"
+	// CompletionCascade completes every open descendant along with the task.
+	CompletionCascade CompletionPolicy = "cascade"
+	// CompletionBlock refuses to complete a task while a direct subtask is
+	// open.
+	CompletionBlock CompletionPolicy = "block"
+)
+
+func (s *TaskServiceImpl) ListSubtasks(ctx context.Context, id uint, tree bool) ([]models.Task, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+
+	subtasks, err := s.repo.ListChildren(ctx, []uint{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subtasks: %w", err)
+	}
+	if tree {
+		if err = s.attachSubtasks(ctx, subtasks); err != nil {
+			return nil, err
+		}
+	}
+	if subtasks == nil {
+		subtasks = []models.Task{}
+	}
+	return subtasks, nil
+}
+
+// attachSubtasks fills Subtasks of every task with its descendants, one
+// level per query.
+func (s *TaskServiceImpl) attachSubtasks(ctx context.Context, tasks []models.Task) error {
+	level := make([]*models.Task, len(tasks))
+	for i := range tasks {
+		level[i] = &tasks[i]
+	}
+
+	for len(level) > 0 {
+		byID := make(map[uint]*models.Task, len(level))
+		ids := make([]uint, len(level))
+		for i, task := range level {
+			byID[task.ID] = task
+			ids[i] = task.ID
+		}
+
+		children, err := s.repo.ListChildren(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to list subtasks: %w", err)
+		}
+		for _, child := range children {
+			parent := byID[*child.ParentID]
+			parent.Subtasks = append(parent.Subtasks, child)
+		}
+
+		var next []*models.Task
+		for _, task := range level {
+			for i := range task.Subtasks {
+				next = append(next, &task.Subtasks[i])
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// checkPlacement verifies that task id (0 for a new task) can become a
+// subtask of parentID: the parent exists, is not inside the task's own
+// subtree, and the task's subtree still fits within the maximum depth.
+func (s *TaskServiceImpl) checkPlacement(ctx context.Context, id uint, parentID uint) error {
+	if parentID == id {
+		return newError(ErrValidation, "task %d cannot be its own parent", id)
+	}
+
+	depth := 0
+	for ancestor := &parentID; ancestor != nil; depth++ {
+		parent, err := s.repo.GetByID(ctx, *ancestor)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &Error{Kind: ErrValidation, Message: fmt.Sprintf("parent task %d does not exist", *ancestor), Err: err}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get parent task: %w", err)
+		}
+		if id != 0 && parent.ID == id {
+			return newError(ErrValidation, "task %d cannot be moved under its own subtask %d", id, parentID)
+		}
+		ancestor = parent.ParentID
+	}
+
+	height := 0
+	if id != 0 {
+		var err error
+		if height, err = s.subtreeHeight(ctx, id); err != nil {
+			return err
+		}
+	}
+	if depth+height > s.maxDepth {
+		return newError(ErrValidation, "subtasks can be nested at most %d levels deep", s.maxDepth)
+	}
+	return nil
+}
+
+// subtreeHeight returns how many levels of subtasks are below task id.
+func (s *TaskServiceImpl) subtreeHeight(ctx context.Context, id uint) (int, error) {
+	height := 0
+	for level := []uint{id}; ; height++ {
+		children, err := s.repo.ListChildren(ctx, level)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list subtasks: %w", err)
+		}
+		if len(children) == 0 {
+			return height, nil
+		}
+		level = level[:0]
+		for _, child := range children {
+			level = append(level, child.ID)
+		}
+	}
+}
+
+// applyCompletionPolicy adjusts an update that completes task id according
+// to the service's completion policy.
+func (s *TaskServiceImpl) applyCompletionPolicy(ctx context.Context, id uint, updates map[string]interface{}) error {
+	switch s.completion {
+	case CompletionCascade:
+		updates[repositories.CompleteSubtasksKey] = true
+	case CompletionBlock:
+		task, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return translateRepoError(err, id, "failed to get task")
+		}
+		if open := task.SubtasksTotal - task.SubtasksCompleted; open > 0 {
+			return newError(ErrConflict, "task %d has %d open subtasks", id, open)
+		}
+	}
+	return nil
+}
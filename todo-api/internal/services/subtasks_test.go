@@ -0,0 +1,254 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+)
+
+func subtask(id, parentID uint) models.Task {
+	task := models.Task{Model: gorm.Model{ID: id}}
+	if parentID != 0 {
+		task.ParentID = &parentID
+	}
+	return task
+}
+
+func TestTaskService_CreateSubtask(t *testing.T) {
+	t.Run("too deep", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithMaxDepth(1))
+
+		parent, root := subtask(2, 1), subtask(1, 0)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(2)).Return(&parent, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(&root, nil)
+
+		// Act
+		task, err := service.CreateTask(context.Background(), dto.CreateTaskServiceRequest{
+			Title:    "Deep",
+			Date:     time.Now().Add(24 * time.Hour),
+			ParentID: &parent.ID,
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Contains(t, err.Error(), "at most 1 levels deep")
+		assert.Nil(t, task)
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		parentID := uint(9)
+		mockRepo.EXPECT().GetByID(gomock.Any(), parentID).Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		task, err := service.CreateTask(context.Background(), dto.CreateTaskServiceRequest{
+			Title:    "Orphan",
+			Date:     time.Now().Add(24 * time.Hour),
+			ParentID: &parentID,
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Contains(t, err.Error(), "parent task 9 does not exist")
+		assert.Nil(t, task)
+	})
+}
+
+func TestTaskService_MoveSubtask(t *testing.T) {
+	t.Run("under own subtask", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		target, middle := subtask(3, 2), subtask(2, 1)
+		root := subtask(1, 0)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&target, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(2)).Return(&middle, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(&root, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{ParentID: &target.ID})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Contains(t, err.Error(), "under its own subtask")
+		assert.Nil(t, task)
+	})
+
+	t.Run("subtree too deep", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithMaxDepth(2))
+
+		target := subtask(5, 0)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(5)).Return(&target, nil)
+		mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{1}).Return([]models.Task{subtask(2, 1)}, nil)
+		mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{2}).Return([]models.Task{subtask(3, 2)}, nil)
+		mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{3}).Return(nil, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{ParentID: &target.ID})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Nil(t, task)
+	})
+
+	t.Run("to top level", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		top := uint(0)
+		moved := subtask(1, 0)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"parent_id": nil}).
+			Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(&moved, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{ParentID: &top})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Nil(t, task.ParentID)
+	})
+}
+
+func TestTaskService_CompletionPolicy(t *testing.T) {
+	completed := true
+
+	t.Run("cascade", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithCompletionPolicy(services.CompletionCascade))
+
+		done := subtask(1, 0)
+		done.Completed = true
+		mockRepo.EXPECT().ListBlockers(gomock.Any(), uint(1)).Return(nil, nil)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{
+				"completed":                      true,
+				repositories.CompleteSubtasksKey: true,
+			}).
+			Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(&done, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{Completed: &completed})
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, task.Completed)
+	})
+
+	t.Run("block with open subtasks", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithCompletionPolicy(services.CompletionBlock))
+
+		open := subtask(1, 0)
+		open.SubtasksTotal, open.SubtasksCompleted = 3, 1
+		mockRepo.EXPECT().ListBlockers(gomock.Any(), uint(1)).Return(nil, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(&open, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{Completed: &completed})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrConflict)
+		assert.Contains(t, err.Error(), "task 1 has 2 open subtasks")
+		assert.Nil(t, task)
+	})
+}
+
+func TestTaskService_ListSubtasks(t *testing.T) {
+	t.Run("tree", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		root := subtask(1, 0)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(&root, nil)
+		mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{1}).Return([]models.Task{subtask(2, 1), subtask(3, 1)}, nil)
+		mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{2, 3}).Return([]models.Task{subtask(4, 3)}, nil)
+		mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{4}).Return(nil, nil)
+
+		// Act
+		subtasks, err := service.ListSubtasks(context.Background(), 1, true)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, subtasks, 2)
+		assert.Empty(t, subtasks[0].Subtasks)
+		require.Len(t, subtasks[1].Subtasks, 1)
+		assert.Equal(t, uint(4), subtasks[1].Subtasks[0].ID)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(8)).Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		subtasks, err := service.ListSubtasks(context.Background(), 8, false)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.Nil(t, subtasks)
+	})
+}
+
+func TestTaskService_ListTasksTree(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockTaskRepository(ctrl)
+	service := services.NewTaskServiceImpl(mockRepo)
+
+	top := uint(0)
+	expectedFilter := dto.TaskFilter{
+		Limit:    11,
+		Sort:     dto.DefaultTaskSort(),
+		ParentID: &top,
+	}
+	mockRepo.EXPECT().List(gomock.Any(), expectedFilter).Return([]models.Task{subtask(1, 0)}, nil)
+	mockRepo.EXPECT().Count(gomock.Any(), expectedFilter).Return(int64(1), nil)
+	mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{1}).Return([]models.Task{subtask(2, 1)}, nil)
+	mockRepo.EXPECT().ListChildren(gomock.Any(), []uint{2}).Return(nil, nil)
+
+	// Act
+	page, err := service.ListTasks(context.Background(), dto.TaskFilter{Limit: 10, Tree: true})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	require.Len(t, page.Tasks[0].Subtasks, 1)
+	assert.Equal(t, uint(2), page.Tasks[0].Subtasks[0].ID)
+}
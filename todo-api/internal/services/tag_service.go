@@ -0,0 +1,18 @@
+//go:generate mockgen -source=./tag_service.go -destination=./mock/tag_service.go -package=mock
+package services
+
+import (
+	"context"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+type TagService interface {
+	CreateTag(ctx context.Context, tag *models.Tag) (*models.Tag, error)
+	GetTag(ctx context.Context, id uint) (*models.Tag, error)
+	ListTags(ctx context.Context) ([]models.Tag, error)
+	UpdateTag(ctx context.Context, id uint, req dto.UpdateTagServiceRequest) (*models.Tag, error)
+	// DeleteTag deletes the tag and detaches it from every task.
+	DeleteTag(ctx context.Context, id uint) error
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+type TagServiceImpl struct {
+	repo repositories.TagRepository
+}
+
+func NewTagServiceImpl(repo repositories.TagRepository) *TagServiceImpl {
+	return &TagServiceImpl{repo: repo}
+}
+
+func (s *TagServiceImpl) CreateTag(ctx context.Context, tag *models.Tag) (*models.Tag, error) {
+	if err := s.repo.Create(ctx, tag); err != nil {
+		return nil, translateTagError(err, 0, "failed to create tag")
+	}
+	return tag, nil
+}
+
+func (s *TagServiceImpl) GetTag(ctx context.Context, id uint) (*models.Tag, error) {
+	tag, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateTagError(err, id, "failed to get tag")
+	}
+	return tag, nil
+}
+
+func (s *TagServiceImpl) ListTags(ctx context.Context) ([]models.Tag, error) {
+	tags, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
+
+func (s *TagServiceImpl) UpdateTag(ctx context.Context, id uint, req dto.UpdateTagServiceRequest) (*models.Tag, error) {
+	updates := make(map[string]interface{})
+
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+
+	if req.Color != nil {
+		updates["color"] = *req.Color
+	}
+
+	if len(updates) == 0 {
+		return nil, newError(ErrValidation, "no fields to update")
+	}
+
+	if err := s.repo.Update(ctx, id, updates); err != nil {
+		return nil, translateTagError(err, id, "failed to update tag")
+	}
+	return s.GetTag(ctx, id)
+}
+
+func (s *TagServiceImpl) DeleteTag(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return translateTagError(err, id, "failed to delete tag")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+)
+
+func TestTagService_CreateTag(t *testing.T) {
+	t.Run("duplicate name", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTagRepository(ctrl)
+		service := services.NewTagServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(gorm.ErrDuplicatedKey)
+
+		// Act
+		tag, err := service.CreateTag(context.Background(), &models.Tag{Name: "work"})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrConflict)
+		assert.Nil(t, tag)
+	})
+}
+
+func TestTagService_UpdateTag(t *testing.T) {
+	t.Run("successful update", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTagRepository(ctrl)
+		service := services.NewTagServiceImpl(mockRepo)
+
+		name := "office"
+		mockRepo.EXPECT().Update(gomock.Any(), uint(2), map[string]interface{}{"name": name}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(2)).Return(&models.Tag{ID: 2, Name: name}, nil)
+
+		// Act
+		tag, err := service.UpdateTag(context.Background(), 2, dto.UpdateTagServiceRequest{Name: &name})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, name, tag.Name)
+	})
+
+	t.Run("no updates provided", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		service := services.NewTagServiceImpl(mock.NewMockTagRepository(ctrl))
+
+		// Act
+		tag, err := service.UpdateTag(context.Background(), 2, dto.UpdateTagServiceRequest{})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Nil(t, tag)
+	})
+}
+
+func TestTagService_DeleteTag(t *testing.T) {
+	t.Run("tag not found", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTagRepository(ctrl)
+		service := services.NewTagServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Delete(gomock.Any(), uint(9)).Return(gorm.ErrRecordNotFound)
+
+		// Act
+		err := service.DeleteTag(context.Background(), 9)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.Contains(t, err.Error(), "tag 9 not found")
+	})
+}
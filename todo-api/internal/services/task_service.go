@@ -3,6 +3,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"todo-api/internal/dto"
 	"todo-api/internal/models"
@@ -12,6 +13,55 @@ type TaskService interface {
 	CreateTask(ctx context.Context, req dto.CreateTaskServiceRequest) (*models.Task, error)
 	GetTaskByID(ctx context.Context, id uint) (*models.Task, error)
 	UpdateTask(ctx context.Context, id uint, req dto.UpdateTaskServiceRequest) (*models.Task, error)
-	DeleteTask(ctx context.Context, id uint) error
-	ListTasks(ctx context.Context, filter dto.TaskFilter) ([]models.Task, error)
+	// DeleteTask deletes the task if its version equals version, 0 matching any.
+	// Its subtasks are deleted along with it when cascade is set, otherwise
+	// they move up to the task's parent.
+	DeleteTask(ctx context.Context, id uint, version uint, cascade bool) error
+	ListTasks(ctx context.Context, filter dto.TaskFilter) (*dto.TaskPage, error)
+	// MoveTask changes the task's place in the manual order of a day.
+	MoveTask(ctx context.Context, id uint, req dto.MoveTaskServiceRequest) (*models.Task, error)
+	// ListSubtasks returns the direct subtasks of a task, with their own
+	// subtasks nested when tree is set.
+	ListSubtasks(ctx context.Context, id uint, tree bool) ([]models.Task, error)
+	// ListBlockers returns the tasks blocking a task, open or completed.
+	ListBlockers(ctx context.Context, id uint) ([]models.Task, error)
+	// AddDependency makes blockerID block the task; a dependency that would
+	// close a loop is rejected.
+	AddDependency(ctx context.Context, id uint, blockerID uint) error
+	RemoveDependency(ctx context.Context, id uint, blockerID uint) error
+	// UpdateOccurrence edits the occurrence of a recurring task on day,
+	// alone or together with all later occurrences.
+	UpdateOccurrence(ctx context.Context, id uint, day time.Time, req dto.UpdateTaskServiceRequest, scope OccurrenceScope) (*models.Task, error)
+	// DeleteOccurrence removes the occurrence of a recurring task on day from
+	// its series if the task's version equals version, 0 matching any.
+	DeleteOccurrence(ctx context.Context, id uint, day time.Time, version uint) error
+	// ListTrash lists the deleted tasks in the trash, filtered and paged
+	// like ListTasks.
+	ListTrash(ctx context.Context, filter dto.TaskFilter) (*dto.TaskPage, error)
+	// RestoreTask brings a task back from the trash if its version equals
+	// version, 0 matching any, with the subtasks deleted along with it.
+	// Its parent must not be in the trash.
+	RestoreTask(ctx context.Context, id uint, version uint) (*models.Task, error)
+	// PurgeTask deletes a task for good, from the trash or live, with its
+	// deleted subtasks when cascade is set.
+	PurgeTask(ctx context.Context, id uint, version uint, cascade bool) error
+	// ListTaskHistory pages through the changes made to a task, live, in
+	// the trash or purged, newest first.
+	ListTaskHistory(ctx context.Context, id uint, filter dto.HistoryFilter) (*dto.HistoryPage, error)
+	// GetTaskAsOf returns a task as it was at asOf, rebuilt from its
+	// history; not found when it did not exist then or was in the trash.
+	GetTaskAsOf(ctx context.Context, id uint, asOf time.Time) (*models.Task, error)
+	// RevertTask sets the fields of a task back to their values at an
+	// earlier version, as an update that is checked and recorded like any
+	// other.
+	RevertTask(ctx context.Context, id uint, req dto.RevertTaskServiceRequest) (*models.Task, error)
+	// CreateTasks creates a batch of tasks, checked like CreateTask, with
+	// the outcome of each in the result.
+	CreateTasks(ctx context.Context, reqs []dto.CreateTaskServiceRequest, mode string) (*dto.BatchResult, error)
+	// UpdateTasks applies the same changes to the selected tasks, checked
+	// like UpdateTask but without versions.
+	UpdateTasks(ctx context.Context, sel dto.BatchSelection, changes dto.BatchChanges) (*dto.BatchResult, error)
+	// DeleteTasks moves the selected tasks to the trash, along with their
+	// subtasks when cascade is set.
+	DeleteTasks(ctx context.Context, sel dto.BatchSelection, cascade bool) (*dto.BatchResult, error)
 }
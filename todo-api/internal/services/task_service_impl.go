@@ -4,24 +4,145 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
+	"slices"
+	"strings"
 
 	"todo-api/internal/dto"
 	"todo-api/internal/models"
 	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+	"todo-api/pkg/lexorank"
+)
+
+const (
+	defaultListLimit = 10
+	defaultMaxLimit  = 100
+	defaultMaxDepth  = 3
+	defaultMaxBatch  = 500
 )
 
 type TaskServiceImpl struct {
-	repo repositories.TaskRepository
+	repo       repositories.TaskRepository
+	maxLimit   int
+	maxDepth   int
+	maxBatch   int
+	completion CompletionPolicy
+	clock      clock.Clock
+	history    repositories.HistoryRepository
 }
 
-func NewTaskServiceImpl(repo repositories.TaskRepository) *TaskServiceImpl {
-	return &TaskServiceImpl{repo: repo}
+type Option func(*TaskServiceImpl)
+
+// WithMaxLimit caps the page size of ListTasks. Larger limits are clamped.
+func WithMaxLimit(limit int) Option {
+	return func(s *TaskServiceImpl) {
+		if limit > 0 {
+			s.maxLimit = limit
+		}
+	}
+}
+
+// WithMaxDepth limits how deep subtasks can be nested: a top-level task is
+// at depth 0, its subtasks at depth 1 and so on.
+func WithMaxDepth(depth int) Option {
+	return func(s *TaskServiceImpl) {
+		if depth > 0 {
+			s.maxDepth = depth
+		}
+	}
+}
+
+// WithMaxBatch caps how many tasks one batch can create or select.
+func WithMaxBatch(size int) Option {
+	return func(s *TaskServiceImpl) {
+		if size > 0 {
+			s.maxBatch = size
+		}
+	}
+}
+
+// WithCompletionPolicy sets what completing a task with open subtasks does.
+func WithCompletionPolicy(policy CompletionPolicy) Option {
+	return func(s *TaskServiceImpl) {
+		if policy != "" {
+			s.completion = policy
+		}
+	}
+}
+
+// WithClock sets the clock the current day is read from.
+func WithClock(c clock.Clock) Option {
+	return func(s *TaskServiceImpl) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// WithHistory sets where the task history is read from. The history is
+// written by the task repository; without this option reading it fails
+// with ErrHistoryUnavailable.
+func WithHistory(history repositories.HistoryRepository) Option {
+	return func(s *TaskServiceImpl) {
+		s.history = history
+	}
+}
+
+func NewTaskServiceImpl(repo repositories.TaskRepository, opts ...Option) *TaskServiceImpl {
+	s := &TaskServiceImpl{
+		repo:       repo,
+		maxLimit:   defaultMaxLimit,
+		maxDepth:   defaultMaxDepth,
+		maxBatch:   defaultMaxBatch,
+		completion: CompletionIndependent,
+		clock:      clock.Real(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *TaskServiceImpl) CreateTask(ctx context.Context, req dto.CreateTaskServiceRequest) (*models.Task, error) {
-	if req.Date.Before(time.Now().Truncate(24 * time.Hour)) {
-		return nil, errors.New("task date cannot be in the past")
+	task, err := s.newTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := s.repo.LastRank(ctx, req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank task: %w", err)
+	}
+	if task.Rank, err = lexorank.Between(last, ""); err != nil {
+		return nil, fmt.Errorf("failed to rank task: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, task); err != nil {
+		return nil, translateRepoError(err, 0, "failed to create task")
+	}
+
+	return task, nil
+}
+
+// newTask checks req and builds the task it creates, yet unranked.
+func (s *TaskServiceImpl) newTask(ctx context.Context, req dto.CreateTaskServiceRequest) (*models.Task, error) {
+	loc := locationOrUTC(req.Location)
+	if req.Date.Before(s.today(loc)) {
+		return nil, newError(ErrValidation, "task date cannot be in the past")
+	}
+
+	if req.ParentID != nil {
+		if err := s.checkPlacement(ctx, 0, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	recurrence, err := normalizeRecurrence(req.Recurrence, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence == "" && len(req.ExDates) > 0 {
+		return nil, newError(ErrValidation, "exdates need a recurrence")
 	}
 
 	task := &models.Task{
@@ -29,24 +150,55 @@ func (s *TaskServiceImpl) CreateTask(ctx context.Context, req dto.CreateTaskServ
 		Description: req.Description,
 		Date:        req.Date,
 		Completed:   false,
+		Priority:    req.Priority,
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+		Recurrence:  recurrence,
+		ExDates:     exDates(req.ExDates),
 	}
-
-	if err := s.repo.Create(ctx, task); err != nil {
-		return nil, fmt.Errorf("failed to create task: %w", err)
+	if req.DueTime != "" {
+		due, err := dueAt(req.Date, req.DueTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		task.DueAt = &due
+		task.TimeZone = loc.String()
+	}
+	for _, name := range req.Tags {
+		task.Tags = append(task.Tags, models.Tag{Name: name})
 	}
-
 	return task, nil
 }
 
 func (s *TaskServiceImpl) GetTaskByID(ctx context.Context, id uint) (*models.Task, error) {
 	task, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get task: %w", err)
+		return nil, translateRepoError(err, id, "failed to get task")
 	}
 	return task, nil
 }
 
 func (s *TaskServiceImpl) UpdateTask(ctx context.Context, id uint, req dto.UpdateTaskServiceRequest) (*models.Task, error) {
+	updates, err := s.taskUpdates(ctx, id, nil, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, id, req.Version, updates); err != nil {
+		return nil, translateRepoError(err, id, "error while updating")
+	}
+
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+	return task, nil
+}
+
+// taskUpdates turns req into the updates to apply to the task with id. An
+// occurrence that is not stored yet is passed as unstored with id 0: it has
+// no blockers or subtasks, and req is checked against it instead.
+func (s *TaskServiceImpl) taskUpdates(ctx context.Context, id uint, unstored *models.Task, req dto.UpdateTaskServiceRequest) (map[string]interface{}, error) {
 	updates := make(map[string]interface{})
 
 	if req.Title != nil {
@@ -63,40 +215,269 @@ func (s *TaskServiceImpl) UpdateTask(ctx context.Context, id uint, req dto.Updat
 
 	if req.Completed != nil {
 		updates["completed"] = *req.Completed
+		if *req.Completed && unstored == nil {
+			if !req.Force {
+				if err := s.checkBlockers(ctx, id); err != nil {
+					return nil, err
+				}
+			}
+			if err := s.applyCompletionPolicy(ctx, id, updates); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	if len(updates) == 0 {
-		return nil, errors.New("no fields to update")
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+
+	if req.ProjectID != nil {
+		if *req.ProjectID == 0 {
+			updates["project_id"] = nil
+		} else {
+			updates["project_id"] = *req.ProjectID
+		}
+	}
+
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			updates["parent_id"] = nil
+		} else {
+			if err := s.checkPlacement(ctx, id, *req.ParentID); err != nil {
+				return nil, err
+			}
+			updates["parent_id"] = *req.ParentID
+		}
+	}
+
+	// Recurrence and due time are checked against the stored task.
+	if req.Recurrence != nil || req.ExDates != nil || req.DueTime != nil || req.Date != nil {
+		task := unstored
+		if task == nil {
+			var err error
+			if task, err = s.repo.GetByID(ctx, id); err != nil {
+				return nil, translateRepoError(err, id, "failed to get task")
+			}
+		}
+		if req.Recurrence != nil || req.ExDates != nil {
+			if err := updateRecurrence(task, req, updates); err != nil {
+				return nil, err
+			}
+		}
+		if err := updateDue(task, req, updates); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.repo.Update(ctx, id, updates); err != nil {
-		return nil, fmt.Errorf("error while updating: %w", err)
+	if len(req.AddTags) > 0 || len(req.RemoveTags) > 0 {
+		updates[repositories.TagChangesKey] = repositories.TagChanges{Attach: req.AddTags, Detach: req.RemoveTags}
 	}
 
-	return s.repo.GetByID(ctx, id)
+	if len(updates) == 0 {
+		return nil, newError(ErrValidation, "no fields to update")
+	}
+	return updates, nil
 }
 
-func (s *TaskServiceImpl) DeleteTask(ctx context.Context, id uint) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+func (s *TaskServiceImpl) DeleteTask(ctx context.Context, id uint, version uint, cascade bool) error {
+	if err := s.repo.Delete(ctx, id, version, cascade); err != nil {
+		return translateRepoError(err, id, "failed to delete task")
 	}
 	return nil
 }
 
-func (s *TaskServiceImpl) ListTasks(ctx context.Context, filter dto.TaskFilter) ([]models.Task, error) {
+// MoveTask places the task between its new neighbours in the manual order
+// of a day. Usually only the moved task gets a new rank; when the
+// neighbours' ranks leave no room between them (equal ranks, e.g. tasks
+// created before ranking existed) the whole day is renumbered.
+func (s *TaskServiceImpl) MoveTask(ctx context.Context, id uint, req dto.MoveTaskServiceRequest) (*models.Task, error) {
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+
+	date := task.Date
+	if req.Date != nil {
+		date = *req.Date
+	}
+
+	day, err := s.repo.ListByDate(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks of the day: %w", err)
+	}
+	day = slices.DeleteFunc(day, func(t models.Task) bool { return t.ID == id })
+
+	at, err := movePosition(day, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rank, err := rankAt(day, at)
+	if errors.Is(err, lexorank.ErrInvalidRange) {
+		rank, err = s.renumberDay(ctx, day, at)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank task: %w", err)
+	}
+
+	updates := map[string]interface{}{"rank": rank}
+	if !date.Equal(task.Date) {
+		updates["date"] = date
+		if task.DueAt != nil {
+			updates["due_at"] = dueOn(task, date)
+		}
+	}
+	if err = s.repo.Update(ctx, id, req.Version, updates); err != nil {
+		return nil, translateRepoError(err, id, "error while moving")
+	}
+
+	moved, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+	return moved, nil
+}
+
+// movePosition returns the index in day, which excludes the moved task,
+// that the task should be inserted at.
+func movePosition(day []models.Task, id uint, req dto.MoveTaskServiceRequest) (int, error) {
+	find := func(neighbour *uint, role string) (int, error) {
+		if neighbour == nil {
+			return -1, nil
+		}
+		if *neighbour == id {
+			return 0, newError(ErrValidation, "task %d cannot be placed %s itself", id, role)
+		}
+		i := slices.IndexFunc(day, func(t models.Task) bool { return t.ID == *neighbour })
+		if i < 0 {
+			return 0, newError(ErrValidation, "task %d to place %s is not on the target date", *neighbour, role)
+		}
+		return i, nil
+	}
+
+	after, err := find(req.AfterID, "after")
+	if err != nil {
+		return 0, err
+	}
+	before, err := find(req.BeforeID, "before")
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case after >= 0 && before >= 0:
+		if before != after+1 {
+			return 0, newError(ErrValidation, "tasks %d and %d are not adjacent", *req.AfterID, *req.BeforeID)
+		}
+		return before, nil
+	case after >= 0:
+		return after + 1, nil
+	case before >= 0:
+		return before, nil
+	default:
+		return len(day), nil
+	}
+}
+
+// rankAt returns a rank between the neighbours of position at in day, or
+// lexorank.ErrInvalidRange when they leave no room. An empty rank is only
+// "unbounded" past the ends of the day; on a task it is simply the lowest
+// rank, as for tasks created before ranking existed.
+func rankAt(day []models.Task, at int) (string, error) {
+	lo, hi := "", ""
+	if at > 0 {
+		lo = day[at-1].Rank
+	}
+	if at < len(day) {
+		if day[at].Rank == "" {
+			return "", lexorank.ErrInvalidRange
+		}
+		hi = day[at].Rank
+	}
+	return lexorank.Between(lo, hi)
+}
+
+// renumberDay spreads fresh ranks over day with a gap at position at and
+// returns the rank for that gap.
+func (s *TaskServiceImpl) renumberDay(ctx context.Context, day []models.Task, at int) (string, error) {
+	keys := lexorank.Spread(len(day) + 1)
+
+	ranks := make(map[uint]string, len(day))
+	for i, t := range day {
+		key := keys[i]
+		if i >= at {
+			key = keys[i+1]
+		}
+		if key != t.Rank {
+			ranks[t.ID] = key
+		}
+	}
+	if err := s.repo.SetRanks(ctx, ranks); err != nil {
+		return "", err
+	}
+	return keys[at], nil
+}
+
+func (s *TaskServiceImpl) ListTasks(ctx context.Context, filter dto.TaskFilter) (*dto.TaskPage, error) {
 	if filter.Limit <= 0 {
-		filter.Limit = 10
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > s.maxLimit {
+		filter.Limit = s.maxLimit
 	}
-	if filter.Offset < 0 {
+	if filter.Offset < 0 || filter.Cursor != nil {
 		filter.Offset = 0
 	}
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Sort = filter.OrderOrDefault()
+	// Recurring tasks are expanded into their occurrences within a date
+	// range, except in the trash.
+	expand := filter.DateFrom != nil && filter.DateTo != nil && !filter.Trashed
+	if expand {
+		filter.Sort = occurrenceSort(filter.Sort)
+	}
+	if filter.Cursor != nil {
+		// A cursor only makes sense in the order it was issued for.
+		filter.Sort = filter.Cursor.Sort
+	}
 
 	repoFilter := dto.TaskFilter{
-		Completed: filter.Completed,
-		DateFrom:  filter.DateFrom,
-		DateTo:    filter.DateTo,
-		Limit:     filter.Limit,
-		Offset:    filter.Offset,
+		Completed:       filter.Completed,
+		DateFrom:        filter.DateFrom,
+		DateTo:          filter.DateTo,
+		Limit:           filter.Limit + 1, // one extra row tells whether there is more
+		Offset:          filter.Offset,
+		Cursor:          filter.Cursor,
+		Sort:            filter.Sort,
+		Query:           filter.Query,
+		Highlight:       filter.Highlight && filter.Query != "",
+		Priorities:      filter.Priorities,
+		Tags:            filter.Tags,
+		TagMode:         filter.TagMode,
+		ProjectID:       filter.ProjectID,
+		IncludeArchived: filter.IncludeArchived,
+		ParentID:        filter.ParentID,
+		Blocked:         filter.Blocked,
+		Location:        filter.Location,
+		Trashed:         filter.Trashed,
+	}
+	if filter.Tree && filter.ParentID == nil {
+		top := uint(0)
+		repoFilter.ParentID = &top
+	}
+
+	var expanded []models.Task
+	if expand {
+		var err error
+		if expanded, err = s.expandOccurrences(ctx, repoFilter); err != nil {
+			return nil, err
+		}
+		oneOff := false
+		repoFilter.Recurring = &oneOff
+		// Occurrences can fall anywhere in the page, so it is cut after
+		// merging them in.
+		repoFilter.Limit += repoFilter.Offset
+		repoFilter.Offset = 0
 	}
 
 	tasks, err := s.repo.List(ctx, repoFilter)
@@ -104,5 +485,49 @@ func (s *TaskServiceImpl) ListTasks(ctx context.Context, filter dto.TaskFilter)
 		return nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
 
-	return tasks, nil
+	total, err := s.repo.Count(ctx, repoFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	if expand {
+		tasks = mergeOccurrences(tasks, expanded, filter, filter.Limit+1)
+		total += int64(len(expanded))
+	}
+
+	more := len(tasks) > filter.Limit
+	if more {
+		tasks = tasks[:filter.Limit]
+	}
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if backward {
+		slices.Reverse(tasks)
+	}
+
+	if filter.Tree {
+		if err = s.attachSubtasks(ctx, tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &dto.TaskPage{
+		Tasks: tasks,
+		Meta: dto.PageMeta{
+			Total:   total,
+			Limit:   filter.Limit,
+			HasMore: more || backward,
+		},
+	}
+
+	if len(tasks) > 0 {
+		if page.Meta.HasMore {
+			page.Meta.NextCursor = dto.CursorAfter(tasks[len(tasks)-1], filter.Sort).Encode()
+		}
+		if (backward && more) || (!backward && (filter.Cursor != nil || filter.Offset > 0)) {
+			page.Meta.PrevCursor = dto.CursorBefore(tasks[0], filter.Sort).Encode()
+		}
+	}
+
+	return page, nil
 }
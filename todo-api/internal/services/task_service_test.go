@@ -2,15 +2,18 @@ package services_test
 
 import (
 	"context"
+	"fmt"
 	"gorm.io/gorm"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"todo-api/internal/dto"
 	"todo-api/internal/models"
+	"todo-api/internal/repositories"
 	"todo-api/internal/repositories/mock"
 	"todo-api/internal/services"
 )
@@ -29,8 +32,12 @@ func TestTaskService_CreateTask(t *testing.T) {
 			Title:       "Test Task",
 			Description: "Test Description",
 			Date:        futureDate,
+			Priority:    models.PriorityHigh,
 		}
 
+		mockRepo.EXPECT().
+			LastRank(gomock.Any(), futureDate).
+			Return("i", nil)
 		mockRepo.EXPECT().
 			Create(
 				gomock.Any(),
@@ -41,7 +48,9 @@ func TestTaskService_CreateTask(t *testing.T) {
 						return ok &&
 							task.Title == req.Title &&
 							task.Description == req.Description &&
-							task.Completed == false
+							task.Completed == false &&
+							task.Priority == models.PriorityHigh &&
+							task.Rank == "r"
 					}),
 				),
 			).
@@ -75,12 +84,108 @@ func TestTaskService_CreateTask(t *testing.T) {
 		task, err := service.CreateTask(context.Background(), req)
 
 		// Assert
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, services.ErrValidation)
 		assert.Nil(t, task)
 		assert.Contains(t, err.Error(), "cannot be in the past")
 	})
 }
 
+func TestTaskService_MoveTask(t *testing.T) {
+	date := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	task := func(id uint, rank string) models.Task {
+		return models.Task{Model: gorm.Model{ID: id}, Date: date, Rank: rank}
+	}
+	uintPtr := func(v uint) *uint { return &v }
+
+	t.Run("between neighbours", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+		moving := task(3, "r")
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&moving, nil)
+		mockRepo.EXPECT().ListByDate(gomock.Any(), date).
+			Return([]models.Task{task(1, "a"), task(2, "c"), moving}, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), uint(3), uint(4), map[string]interface{}{"rank": "b"}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&moving, nil)
+
+		// Act
+		_, err := service.MoveTask(context.Background(), 3, dto.MoveTaskServiceRequest{AfterID: uintPtr(1), Version: 4})
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("to another day", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+		moving := task(3, "r")
+		nextDay := date.AddDate(0, 0, 1)
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&moving, nil)
+		mockRepo.EXPECT().ListByDate(gomock.Any(), nextDay).
+			Return([]models.Task{{Model: gorm.Model{ID: 5}, Date: nextDay, Rank: "i"}}, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), uint(3), uint(0), map[string]interface{}{"rank": "9", "date": nextDay}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&moving, nil)
+
+		// Act
+		_, err := service.MoveTask(context.Background(), 3, dto.MoveTaskServiceRequest{BeforeID: uintPtr(5), Date: &nextDay})
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("renumbers a day without room", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+		moving := task(3, "")
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&moving, nil)
+		mockRepo.EXPECT().ListByDate(gomock.Any(), date).
+			Return([]models.Task{task(1, ""), task(2, ""), moving}, nil)
+		mockRepo.EXPECT().SetRanks(gomock.Any(), map[uint]string{1: "9", 2: "r"}).Return(nil)
+		mockRepo.EXPECT().Update(gomock.Any(), uint(3), uint(0), map[string]interface{}{"rank": "i"}).Return(nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&moving, nil)
+
+		// Act
+		_, err := service.MoveTask(context.Background(), 3, dto.MoveTaskServiceRequest{AfterID: uintPtr(1)})
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("neighbour on another day", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+		moving := task(3, "r")
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(&moving, nil)
+		mockRepo.EXPECT().ListByDate(gomock.Any(), date).Return([]models.Task{moving}, nil)
+
+		// Act
+		_, err := service.MoveTask(context.Background(), 3, dto.MoveTaskServiceRequest{AfterID: uintPtr(8)})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Contains(t, err.Error(), "not on the target date")
+	})
+}
+
 func TestTaskService_GetTaskByID(t *testing.T) {
 	t.Run("task found", func(t *testing.T) {
 		// Arrange
@@ -117,6 +222,27 @@ func TestTaskService_GetTaskByID(t *testing.T) {
 		mockRepo := mock.NewMockTaskRepository(ctrl)
 		service := services.NewTaskServiceImpl(mockRepo)
 
+		mockRepo.EXPECT().
+			GetByID(gomock.Any(), uint(1)).
+			Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		task, err := service.GetTaskByID(context.Background(), 1)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.Nil(t, task)
+		assert.Equal(t, "task 1 not found", err.Error())
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
 		mockRepo.EXPECT().
 			GetByID(gomock.Any(), uint(1)).
 			Return(nil, assert.AnError)
@@ -157,7 +283,10 @@ func TestTaskService_UpdateTask(t *testing.T) {
 		}
 
 		mockRepo.EXPECT().
-			Update(gomock.Any(), uint(1), map[string]interface{}{
+			ListBlockers(gomock.Any(), uint(1)).
+			Return(nil, nil)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{
 				"title":     *req.Title,
 				"completed": *req.Completed,
 			}).
@@ -190,10 +319,132 @@ func TestTaskService_UpdateTask(t *testing.T) {
 		task, err := service.UpdateTask(context.Background(), 1, req)
 
 		// Assert
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, services.ErrValidation)
 		assert.Nil(t, task)
 		assert.Contains(t, err.Error(), "no fields to update")
 	})
+
+	t.Run("task not found", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		completed := true
+		mockRepo.EXPECT().
+			ListBlockers(gomock.Any(), uint(1)).
+			Return(nil, nil)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), gomock.Any()).
+			Return(gorm.ErrRecordNotFound)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{Completed: &completed})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.Nil(t, task)
+	})
+
+	t.Run("tag changes", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(3), map[string]interface{}{
+				repositories.TagChangesKey: repositories.TagChanges{Attach: []string{"home"}, Detach: []string{"work"}},
+			}).
+			Return(nil)
+		mockRepo.EXPECT().
+			GetByID(gomock.Any(), uint(1)).
+			Return(&models.Task{Model: gorm.Model{ID: 1}, Tags: []models.Tag{{Name: "home"}}}, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{
+			AddTags:    []string{"home"},
+			RemoveTags: []string{"work"},
+			Version:    3,
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "home", task.Tags[0].Name)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), gomock.Any()).
+			Return(fmt.Errorf("%w: wrok", repositories.ErrUnknownTag))
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{AddTags: []string{"wrok"}})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Contains(t, err.Error(), "wrok")
+		assert.Nil(t, task)
+	})
+}
+
+func TestTaskService_UpdateTaskProject(t *testing.T) {
+	t.Run("remove from project", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		none := uint(0)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"project_id": nil}).
+			Return(nil)
+		mockRepo.EXPECT().
+			GetByID(gomock.Any(), uint(1)).
+			Return(&models.Task{Model: gorm.Model{ID: 1}}, nil)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{ProjectID: &none})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Nil(t, task.ProjectID)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		projectID := uint(42)
+		mockRepo.EXPECT().
+			Update(gomock.Any(), uint(1), uint(0), map[string]interface{}{"project_id": projectID}).
+			Return(gorm.ErrForeignKeyViolated)
+
+		// Act
+		task, err := service.UpdateTask(context.Background(), 1, dto.UpdateTaskServiceRequest{ProjectID: &projectID})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Contains(t, err.Error(), "project or parent task does not exist")
+		assert.Nil(t, task)
+	})
 }
 
 func TestTaskService_DeleteTask(t *testing.T) {
@@ -206,16 +457,35 @@ func TestTaskService_DeleteTask(t *testing.T) {
 		service := services.NewTaskServiceImpl(mockRepo)
 
 		mockRepo.EXPECT().
-			Delete(gomock.Any(), uint(1)).
+			Delete(gomock.Any(), uint(1), uint(0), false).
 			Return(nil)
 
 		// Act
-		err := service.DeleteTask(context.Background(), 1)
+		err := service.DeleteTask(context.Background(), 1, 0, false)
 
 		// Assert
 		assert.NoError(t, err)
 	})
 
+	t.Run("task not found", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().
+			Delete(gomock.Any(), uint(1), uint(0), false).
+			Return(gorm.ErrRecordNotFound)
+
+		// Act
+		err := service.DeleteTask(context.Background(), 1, 0, false)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+
 	t.Run("delete error", func(t *testing.T) {
 		// Arrange
 		ctrl := gomock.NewController(t)
@@ -225,11 +495,11 @@ func TestTaskService_DeleteTask(t *testing.T) {
 		service := services.NewTaskServiceImpl(mockRepo)
 
 		mockRepo.EXPECT().
-			Delete(gomock.Any(), uint(1)).
+			Delete(gomock.Any(), uint(1), uint(0), false).
 			Return(assert.AnError)
 
 		// Act
-		err := service.DeleteTask(context.Background(), 1)
+		err := service.DeleteTask(context.Background(), 1, 0, false)
 
 		// Assert
 		assert.Error(t, err)
@@ -246,28 +516,37 @@ func TestTaskService_ListTasks(t *testing.T) {
 		mockRepo := mock.NewMockTaskRepository(ctrl)
 		service := services.NewTaskServiceImpl(mockRepo)
 
-		completed := true
+		completed, blocked := true, false
 		expectedTasks := []models.Task{
 			{Model: gorm.Model{ID: 1}, Title: "Task 1", Completed: true},
 		}
 
 		filter := dto.TaskFilter{
 			Completed: &completed,
+			Blocked:   &blocked,
 			Limit:     10,
 			Offset:    0,
 		}
 
+		repoFilter := filter
+		repoFilter.Limit = 11
+		repoFilter.Sort = dto.DefaultTaskSort()
+
 		mockRepo.EXPECT().
-			List(gomock.Any(), filter).
+			List(gomock.Any(), repoFilter).
 			Return(expectedTasks, nil)
+		mockRepo.EXPECT().
+			Count(gomock.Any(), repoFilter).
+			Return(int64(1), nil)
 
 		// Act
-		tasks, err := service.ListTasks(context.Background(), filter)
+		page, err := service.ListTasks(context.Background(), filter)
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Len(t, tasks, 1)
-		assert.Equal(t, "Task 1", tasks[0].Title)
+		assert.Len(t, page.Tasks, 1)
+		assert.Equal(t, "Task 1", page.Tasks[0].Title)
+		assert.Equal(t, dto.PageMeta{Total: 1, Limit: 10}, page.Meta)
 	})
 
 	t.Run("default pagination values", func(t *testing.T) {
@@ -281,22 +560,142 @@ func TestTaskService_ListTasks(t *testing.T) {
 		expectedTasks := []models.Task{{Model: gorm.Model{ID: 1}}}
 
 		expectedFilter := dto.TaskFilter{
-			Limit:  10,
+			Limit:  11,
 			Offset: 0,
+			Sort:   dto.DefaultTaskSort(),
 		}
 
 		mockRepo.EXPECT().
 			List(gomock.Any(), expectedFilter).
 			Return(expectedTasks, nil)
+		mockRepo.EXPECT().
+			Count(gomock.Any(), expectedFilter).
+			Return(int64(1), nil)
 
 		// Act
-		tasks, err := service.ListTasks(context.Background(), dto.TaskFilter{
+		page, err := service.ListTasks(context.Background(), dto.TaskFilter{
 			Limit:  -5,
 			Offset: -10,
 		})
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Len(t, tasks, 1)
+		assert.Len(t, page.Tasks, 1)
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo, services.WithMaxLimit(50))
+
+		mockRepo.EXPECT().
+			List(gomock.Any(), dto.TaskFilter{Limit: 51, Sort: dto.DefaultTaskSort()}).
+			Return(nil, nil)
+		mockRepo.EXPECT().
+			Count(gomock.Any(), gomock.Any()).
+			Return(int64(0), nil)
+
+		// Act
+		page, err := service.ListTasks(context.Background(), dto.TaskFilter{Limit: 500})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 50, page.Meta.Limit)
+	})
+
+	t.Run("search defaults to relevance order", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		expectedFilter := dto.TaskFilter{Limit: 11, Query: "milk", Highlight: true, Sort: dto.SearchTaskSort()}
+
+		mockRepo.EXPECT().
+			List(gomock.Any(), expectedFilter).
+			Return(nil, nil)
+		mockRepo.EXPECT().
+			Count(gomock.Any(), expectedFilter).
+			Return(int64(0), nil)
+
+		// Act
+		page, err := service.ListTasks(context.Background(), dto.TaskFilter{Query: " milk ", Highlight: true})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, page.Tasks)
+	})
+
+	t.Run("forward cursor with more pages", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		date := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+		cursor := dto.CursorAfter(models.Task{Model: gorm.Model{ID: 1}, Date: date}, dto.DefaultTaskSort())
+		rows := []models.Task{
+			{Model: gorm.Model{ID: 2}, Date: date},
+			{Model: gorm.Model{ID: 3}, Date: date},
+			{Model: gorm.Model{ID: 4}, Date: date},
+		}
+
+		mockRepo.EXPECT().
+			List(gomock.Any(), dto.TaskFilter{Limit: 3, Cursor: cursor, Sort: dto.DefaultTaskSort()}).
+			Return(rows, nil)
+		mockRepo.EXPECT().
+			Count(gomock.Any(), gomock.Any()).
+			Return(int64(4), nil)
+
+		// Act
+		page, err := service.ListTasks(context.Background(), dto.TaskFilter{Limit: 2, Cursor: cursor, Offset: 5})
+
+		// Assert
+		assert.NoError(t, err)
+		require.Len(t, page.Tasks, 2)
+		assert.True(t, page.Meta.HasMore)
+		assert.Equal(t, dto.CursorAfter(rows[1], dto.DefaultTaskSort()).Encode(), page.Meta.NextCursor)
+		assert.Equal(t, dto.CursorBefore(rows[0], dto.DefaultTaskSort()).Encode(), page.Meta.PrevCursor)
+	})
+
+	t.Run("backward cursor reaching the start", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		date := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+		cursor := dto.CursorBefore(models.Task{Model: gorm.Model{ID: 3}, Date: date}, dto.DefaultTaskSort())
+
+		mockRepo.EXPECT().
+			List(gomock.Any(), gomock.Any()).
+			Return([]models.Task{
+				{Model: gorm.Model{ID: 2}, Date: date},
+				{Model: gorm.Model{ID: 1}, Date: date},
+			}, nil)
+		mockRepo.EXPECT().
+			Count(gomock.Any(), gomock.Any()).
+			Return(int64(4), nil)
+
+		// Act
+		page, err := service.ListTasks(context.Background(), dto.TaskFilter{Limit: 2, Cursor: cursor})
+
+		// Assert
+		assert.NoError(t, err)
+		require.Len(t, page.Tasks, 2)
+		assert.Equal(t, uint(1), page.Tasks[0].ID)
+		assert.Equal(t, uint(2), page.Tasks[1].ID)
+		assert.True(t, page.Meta.HasMore)
+		assert.NotEmpty(t, page.Meta.NextCursor)
+		assert.Empty(t, page.Meta.PrevCursor)
 	})
 }
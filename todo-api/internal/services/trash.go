@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+)
+
+func (s *TaskServiceImpl) ListTrash(ctx context.Context, filter dto.TaskFilter) (*dto.TaskPage, error) {
+	filter.Trashed = true
+	filter.Tree = false
+	return s.ListTasks(ctx, filter)
+}
+
+func (s *TaskServiceImpl) RestoreTask(ctx context.Context, id uint, version uint) (*models.Task, error) {
+	if err := s.repo.Restore(ctx, id, version); err != nil {
+		return nil, translateTrashError(err, id, "failed to restore task")
+	}
+
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateRepoError(err, id, "failed to get task")
+	}
+	return task, nil
+}
+
+func (s *TaskServiceImpl) PurgeTask(ctx context.Context, id uint, version uint, cascade bool) error {
+	// A live task is deleted first, so that it is announced like any other
+	// deletion; one already in the trash is not found.
+	err := s.repo.Delete(ctx, id, version, cascade)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return translateRepoError(err, id, "failed to delete task")
+	}
+
+	if err = s.repo.Purge(ctx, id, version, cascade); err != nil {
+		return translateTrashError(err, id, "failed to purge task")
+	}
+	return nil
+}
+
+// translateTrashError is translateRepoError for tasks in the trash.
+func translateTrashError(err error, id uint, msg string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("task %d is not in the trash", id), Err: err}
+	case errors.Is(err, repositories.ErrParentTrashed):
+		return &Error{Kind: ErrConflict, Message: fmt.Sprintf("task %d cannot be restored: %v", id, err), Err: err}
+	default:
+		return translateRepoError(err, id, msg)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+// TrashPurger deletes the tasks that have been in the trash longer than the
+// retention period for good.
+type TrashPurger struct {
+	repo      repositories.TaskRepository
+	clock     clock.Clock
+	logger    *zap.Logger
+	cfg       SchedulerConfig
+	retention time.Duration
+}
+
+// NewTrashPurger returns a purger for tasks deleted more than retention
+// ago. Only Interval of cfg is used.
+func NewTrashPurger(
+	repo repositories.TaskRepository,
+	clk clock.Clock,
+	logger *zap.Logger,
+	cfg SchedulerConfig,
+	retention time.Duration,
+) *TrashPurger {
+	return &TrashPurger{
+		repo:      repo,
+		clock:     clk,
+		logger:    logger,
+		cfg:       cfg.withDefaults(),
+		retention: retention,
+	}
+}
+
+// Run purges the trash every Interval until ctx is done.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Tick(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("Failed to purge trash", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick purges the tasks deleted before the retention period and returns
+// how many there were.
+func (p *TrashPurger) Tick(ctx context.Context) (int64, error) {
+	purged, err := p.repo.PurgeDeleted(ctx, p.clock.Now().Add(-p.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+	if purged > 0 {
+		p.logger.Info("Purged tasks from the trash", zap.Int64("count", purged))
+	}
+	return purged, nil
+}
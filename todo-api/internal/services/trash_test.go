@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+func TestTaskService_ListTrash(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockTaskRepository(ctrl)
+	service := services.NewTaskServiceImpl(mockRepo)
+
+	from, to := time.Now(), time.Now().Add(7*24*time.Hour)
+	mockRepo.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter dto.TaskFilter) ([]models.Task, error) {
+			assert.True(t, filter.Trashed)
+			assert.False(t, filter.Tree)
+			assert.Equal(t, &from, filter.DateFrom)
+			return []models.Task{{Model: gorm.Model{ID: 3}}}, nil
+		})
+	mockRepo.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+	// Act
+	page, err := service.ListTrash(context.Background(), dto.TaskFilter{DateFrom: &from, DateTo: &to, Tree: true})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1, "recurring tasks are not expanded")
+	assert.Equal(t, uint(3), page.Tasks[0].ID)
+}
+
+func TestTaskService_RestoreTask(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		restored := &models.Task{Model: gorm.Model{ID: 1}, Version: 3}
+		gomock.InOrder(
+			mockRepo.EXPECT().Restore(gomock.Any(), uint(1), uint(2)).Return(nil),
+			mockRepo.EXPECT().GetByID(gomock.Any(), uint(1)).Return(restored, nil),
+		)
+
+		// Act
+		task, err := service.RestoreTask(context.Background(), 1, 2)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, restored, task)
+	})
+
+	tests := []struct {
+		name    string
+		repoErr error
+		kind    error
+		message string
+	}{
+		{"not in the trash", gorm.ErrRecordNotFound, services.ErrNotFound, "task 1 is not in the trash"},
+		{"parent in the trash", repositories.ErrParentTrashed, services.ErrConflict, "task 1 cannot be restored"},
+		{"stale version", repositories.ErrVersionMismatch, services.ErrPreconditionFailed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockTaskRepository(ctrl)
+			service := services.NewTaskServiceImpl(mockRepo)
+
+			mockRepo.EXPECT().Restore(gomock.Any(), uint(1), uint(0)).Return(tt.repoErr)
+
+			// Act
+			task, err := service.RestoreTask(context.Background(), 1, 0)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.kind)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.Nil(t, task)
+		})
+	}
+}
+
+func TestTaskService_PurgeTask(t *testing.T) {
+	t.Run("live task is deleted first", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		gomock.InOrder(
+			mockRepo.EXPECT().Delete(gomock.Any(), uint(1), uint(0), true).Return(nil),
+			mockRepo.EXPECT().Purge(gomock.Any(), uint(1), uint(0), true).Return(nil),
+		)
+
+		// Act
+		err := service.PurgeTask(context.Background(), 1, 0, true)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("task already in the trash", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Delete(gomock.Any(), uint(1), uint(0), false).Return(gorm.ErrRecordNotFound)
+		mockRepo.EXPECT().Purge(gomock.Any(), uint(1), uint(0), false).Return(nil)
+
+		// Act
+		err := service.PurgeTask(context.Background(), 1, 0, false)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Delete(gomock.Any(), uint(1), uint(0), false).Return(gorm.ErrRecordNotFound)
+		mockRepo.EXPECT().Purge(gomock.Any(), uint(1), uint(0), false).Return(gorm.ErrRecordNotFound)
+
+		// Act
+		err := service.PurgeTask(context.Background(), 1, 0, false)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+
+	t.Run("stale live task", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		service := services.NewTaskServiceImpl(mockRepo)
+
+		mockRepo.EXPECT().Delete(gomock.Any(), uint(1), uint(0), false).Return(repositories.ErrVersionMismatch)
+
+		// Act
+		err := service.PurgeTask(context.Background(), 1, 0, false)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+	})
+}
+
+func TestTrashPurger_Tick(t *testing.T) {
+	now := time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("purges before the retention period", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		purger := services.NewTrashPurger(mockRepo, clock.NewFake(now), zaptest.NewLogger(t),
+			services.SchedulerConfig{}, 30*24*time.Hour)
+
+		mockRepo.EXPECT().PurgeDeleted(gomock.Any(), now.Add(-30*24*time.Hour)).Return(int64(2), nil)
+
+		// Act
+		purged, err := purger.Tick(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		// Arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockTaskRepository(ctrl)
+		purger := services.NewTrashPurger(mockRepo, clock.NewFake(now), zaptest.NewLogger(t),
+			services.SchedulerConfig{}, 30*24*time.Hour)
+
+		mockRepo.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db down"))
+
+		// Act
+		purged, err := purger.Tick(context.Background())
+
+		// Assert
+		assert.ErrorContains(t, err, "db down")
+		assert.Zero(t, purged)
+	})
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+	"todo-api/pkg/hmacsig"
+)
+
+// WebhookDispatcher sends queued webhook deliveries, signed with the
+// secret of their subscription. Like ReminderScheduler it claims every
+// attempt first and keeps its state in the repository, retrying failures
+// with exponential backoff until MaxAttempts, when the delivery is dead.
+type WebhookDispatcher struct {
+	repo   repositories.WebhookRepository
+	client *http.Client
+	clock  clock.Clock
+	logger *zap.Logger
+	cfg    SchedulerConfig
+}
+
+// NewWebhookDispatcher returns a dispatcher posting with client,
+// http.DefaultClient when nil.
+func NewWebhookDispatcher(
+	repo repositories.WebhookRepository,
+	client *http.Client,
+	clk clock.Clock,
+	logger *zap.Logger,
+	cfg SchedulerConfig,
+) *WebhookDispatcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: client,
+		clock:  clk,
+		logger: logger,
+		cfg:    cfg.withDefaults(),
+	}
+}
+
+// Run sends due deliveries every Interval until ctx is done.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Tick(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error("Failed to dispatch webhooks", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick makes an attempt at up to BatchSize deliveries due now and returns
+// how many succeeded. Failed attempts are scheduled for a retry, not
+// returned.
+func (d *WebhookDispatcher) Tick(ctx context.Context) (int, error) {
+	now := d.clock.Now()
+	deliveries, err := d.repo.ListDueDeliveries(ctx, now, d.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due deliveries: %w", err)
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		ok, err := d.deliver(ctx, delivery, now)
+		if err != nil {
+			return delivered, fmt.Errorf("failed to record attempt at delivery %d: %w", delivery.ID, err)
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver makes one attempt at a due delivery and records how it went.
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery, now time.Time) (bool, error) {
+	claimed, err := d.repo.ClaimDelivery(ctx, delivery.ID, now, now.Add(2*d.cfg.Timeout))
+	if err != nil || !claimed {
+		return false, err
+	}
+	delivery.Attempts++
+
+	status, sendErr := d.send(ctx, delivery)
+	done := d.clock.Now()
+	if sendErr == nil {
+		d.logger.Info("Webhook delivered",
+			zap.Uint("delivery_id", delivery.ID),
+			zap.Uint("webhook_id", delivery.SubscriptionID),
+			zap.String("event_type", string(delivery.EventType)),
+		)
+		return true, d.repo.UpdateDelivery(ctx, delivery.ID, map[string]interface{}{
+			"status":          models.DeliveryDelivered,
+			"delivered_at":    done,
+			"next_attempt_at": nil,
+			"last_error":      "",
+			"response_status": status,
+		})
+	}
+
+	updates := map[string]interface{}{"last_error": sendErr.Error(), "response_status": status}
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		updates["status"] = models.DeliveryDead
+		updates["next_attempt_at"] = nil
+		d.logger.Error("Webhook delivery is dead",
+			zap.Uint("delivery_id", delivery.ID),
+			zap.Uint("webhook_id", delivery.SubscriptionID),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(sendErr),
+		)
+	} else {
+		retryAt := done.Add(d.cfg.backoff(delivery.Attempts))
+		updates["next_attempt_at"] = retryAt
+		d.logger.Warn("Webhook delivery attempt failed",
+			zap.Uint("delivery_id", delivery.ID),
+			zap.Uint("webhook_id", delivery.SubscriptionID),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Time("retry_at", retryAt),
+			zap.Error(sendErr),
+		)
+	}
+	return false, d.repo.UpdateDelivery(ctx, delivery.ID, updates)
+}
+
+// send POSTs a delivery to its subscription and returns the response
+// status, 0 when there was no response. Anything but 2xx is an error.
+func (d *WebhookDispatcher) send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	subscription, err := d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook %d: %w", delivery.SubscriptionID, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	sentAt := d.clock.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-api-webhooks")
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	// Redeliveries and retries keep the key, so receivers can drop repeats.
+	req.Header.Set("Idempotency-Key", delivery.EventID)
+	req.Header.Set(hmacsig.TimestampHeader, strconv.FormatInt(sentAt.Unix(), 10))
+	req.Header.Set(hmacsig.SignatureHeader, hmacsig.Sign(subscription.Secret, sentAt, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
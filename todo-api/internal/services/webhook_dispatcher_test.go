@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+	"todo-api/pkg/hmacsig"
+)
+
+const webhookSecret = "0123456789abcdef"
+
+// webhookReceiver answers webhook requests with status and keeps those
+// whose signature checks out.
+type webhookReceiver struct {
+	t      *testing.T
+	now    *clock.Fake
+	mu     sync.Mutex
+	status int
+	bodies []string
+	keys   []string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	err := hmacsig.Verify(webhookSecret, req.Header.Get(hmacsig.SignatureHeader), req.Header.Get(hmacsig.TimestampHeader),
+		body, r.now.Now(), 5*time.Minute)
+	assert.NoError(r.t, err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, string(body))
+	r.keys = append(r.keys, req.Header.Get("Idempotency-Key"))
+	w.WriteHeader(r.status)
+}
+
+func (r *webhookReceiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*clock.Fake, *webhookReceiver, repositories.WebhookRepository, *services.WebhookServiceImpl, *services.WebhookDispatcher) {
+		now := clock.NewFake(time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC))
+		receiver := &webhookReceiver{t: t, now: now, status: http.StatusNoContent}
+		server := httptest.NewServer(receiver)
+		t.Cleanup(server.Close)
+
+		repo := repositories.NewWebhookRepositoryMemory(repositories.NewMemoryStore(clock.Real()))
+		require.NoError(t, repo.CreateSubscription(ctx, &models.WebhookSubscription{URL: server.URL, Secret: webhookSecret, Active: true}))
+		service := services.NewWebhookServiceImpl(repo, now, zaptest.NewLogger(t))
+		dispatcher := services.NewWebhookDispatcher(repo, server.Client(), now, zaptest.NewLogger(t), schedulerConfig)
+		return now, receiver, repo, service, dispatcher
+	}
+	event := models.Event{ID: "e1", Type: models.EventTaskCreated, Task: models.Task{Model: gorm.Model{ID: 7}, Title: "Call mom"}}
+
+	t.Run("delivered", func(t *testing.T) {
+		// Arrange
+		_, receiver, repo, service, dispatcher := setup(t)
+		require.NoError(t, service.Consume(ctx, event))
+
+		// Act
+		delivered, err := dispatcher.Tick(ctx)
+		require.NoError(t, err)
+		again, err := dispatcher.Tick(ctx)
+		require.NoError(t, err)
+		log, err := repo.ListDeliveries(ctx, 1, "", 10)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, 1, delivered)
+		assert.Zero(t, again)
+		require.Len(t, receiver.bodies, 1)
+		assert.JSONEq(t, string(log[0].Payload), receiver.bodies[0])
+		assert.Contains(t, receiver.bodies[0], `"type":"task.created"`)
+		assert.Equal(t, []string{"e1"}, receiver.keys)
+		require.Len(t, log, 1)
+		assert.Equal(t, models.DeliveryDelivered, log[0].Status)
+		assert.Equal(t, http.StatusNoContent, log[0].ResponseStatus)
+		assert.NotNil(t, log[0].DeliveredAt)
+	})
+
+	t.Run("retried with backoff until dead, then redelivered", func(t *testing.T) {
+		// Arrange
+		now, receiver, repo, service, dispatcher := setup(t)
+		receiver.respond(http.StatusServiceUnavailable)
+		require.NoError(t, service.Consume(ctx, event))
+		start := now.Now()
+
+		// Act
+		var retries []time.Time
+		for range schedulerConfig.MaxAttempts {
+			_, err := dispatcher.Tick(ctx)
+			require.NoError(t, err)
+			delivery, err := repo.GetDelivery(ctx, 1, 1)
+			require.NoError(t, err)
+			if delivery.NextAttemptAt != nil {
+				retries = append(retries, *delivery.NextAttemptAt)
+				now.Set(*delivery.NextAttemptAt)
+			}
+		}
+		dead, err := repo.GetDelivery(ctx, 1, 1)
+		require.NoError(t, err)
+
+		receiver.respond(http.StatusOK)
+		redelivery, err := service.Redeliver(ctx, 1, 1)
+		require.NoError(t, err)
+		delivered, err := dispatcher.Tick(ctx)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []time.Time{start.Add(time.Minute), start.Add(3 * time.Minute)}, retries)
+		assert.Equal(t, models.DeliveryDead, dead.Status)
+		assert.Equal(t, 3, dead.Attempts)
+		assert.Equal(t, "webhook responded with 503 Service Unavailable", dead.LastError)
+		assert.Equal(t, http.StatusServiceUnavailable, dead.ResponseStatus)
+		assert.Equal(t, models.DeliveryPending, redelivery.Status)
+		assert.Zero(t, redelivery.Attempts)
+		assert.Equal(t, 1, delivered)
+		assert.Len(t, receiver.keys, 4)
+		assert.Equal(t, "e1", receiver.keys[3])
+	})
+
+	t.Run("paused subscription", func(t *testing.T) {
+		// Arrange
+		_, receiver, repo, service, dispatcher := setup(t)
+		require.NoError(t, service.Consume(ctx, event))
+		require.NoError(t, repo.UpdateSubscription(ctx, 1, map[string]interface{}{"active": false}))
+
+		// Act
+		paused, err := dispatcher.Tick(ctx)
+		require.NoError(t, err)
+		require.NoError(t, service.Consume(ctx, models.Event{ID: "e2", Type: models.EventTaskDeleted}))
+		require.NoError(t, repo.UpdateSubscription(ctx, 1, map[string]interface{}{"active": true}))
+		resumed, err := dispatcher.Tick(ctx)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Zero(t, paused)
+		assert.Equal(t, 1, resumed)
+		assert.Equal(t, []string{"e1"}, receiver.keys)
+	})
+}
@@ -0,0 +1,24 @@
+//go:generate mockgen -source=./webhook_service.go -destination=./mock/webhook_service.go -package=mock
+package services
+
+import (
+	"context"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+)
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id uint, req dto.UpdateWebhookServiceRequest) (*models.WebhookSubscription, error)
+	// DeleteSubscription deletes the subscription along with its deliveries.
+	DeleteSubscription(ctx context.Context, id uint) error
+	// ListDeliveries returns up to limit deliveries to a subscription, newest
+	// first, only those in status unless it is empty.
+	ListDeliveries(ctx context.Context, id uint, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error)
+	// Redeliver sends a delivered or dead delivery again right away, with a
+	// fresh set of attempts.
+	Redeliver(ctx context.Context, id uint, deliveryID uint) (*models.WebhookDelivery, error)
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories"
+	"todo-api/pkg/clock"
+)
+
+const defaultDeliveryLimit = 50
+
+// WebhookServiceImpl manages webhook subscriptions and, as a consumer of
+// the outbox relay, queues a delivery of every task event for each
+// subscription wanting it. A WebhookDispatcher sends them.
+type WebhookServiceImpl struct {
+	repo   repositories.WebhookRepository
+	clock  clock.Clock
+	logger *zap.Logger
+}
+
+func NewWebhookServiceImpl(repo repositories.WebhookRepository, clk clock.Clock, logger *zap.Logger) *WebhookServiceImpl {
+	return &WebhookServiceImpl{repo: repo, clock: clk, logger: logger}
+}
+
+func (s *WebhookServiceImpl) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	subscription.Events = eventList(subscription.Events)
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, translateWebhookError(err, 0, "failed to create webhook")
+	}
+	return subscription, nil
+}
+
+func (s *WebhookServiceImpl) GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	subscription, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, translateWebhookError(err, id, "failed to get webhook")
+	}
+	return subscription, nil
+}
+
+func (s *WebhookServiceImpl) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (s *WebhookServiceImpl) UpdateSubscription(ctx context.Context, id uint, req dto.UpdateWebhookServiceRequest) (*models.WebhookSubscription, error) {
+	updates := make(map[string]interface{})
+
+	if req.URL != nil {
+		updates["url"] = *req.URL
+	}
+
+	if req.Secret != nil {
+		updates["secret"] = *req.Secret
+	}
+
+	if req.Events != nil {
+		updates["events"] = eventList(req.Events)
+	}
+
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	if len(updates) == 0 {
+		return nil, newError(ErrValidation, "no fields to update")
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, id, updates); err != nil {
+		return nil, translateWebhookError(err, id, "failed to update webhook")
+	}
+	return s.GetSubscription(ctx, id)
+}
+
+func (s *WebhookServiceImpl) DeleteSubscription(ctx context.Context, id uint) error {
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		return translateWebhookError(err, id, "failed to delete webhook")
+	}
+	return nil
+}
+
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, id uint, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, id, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookServiceImpl) Redeliver(ctx context.Context, id uint, deliveryID uint) (*models.WebhookDelivery, error) {
+	delivery, err := s.repo.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, translateDeliveryError(err, id, deliveryID, "failed to get delivery")
+	}
+	if delivery.Status == models.DeliveryPending {
+		return nil, newError(ErrConflict, "delivery %d is still pending", deliveryID)
+	}
+
+	err = s.repo.UpdateDelivery(ctx, deliveryID, map[string]interface{}{
+		"status":          models.DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": s.clock.Now(),
+		"last_error":      "",
+		"response_status": 0,
+		"delivered_at":    nil,
+	})
+	if err != nil {
+		return nil, translateDeliveryError(err, id, deliveryID, "failed to redeliver")
+	}
+
+	delivery, err = s.repo.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, translateDeliveryError(err, id, deliveryID, "failed to get delivery")
+	}
+	return delivery, nil
+}
+
+// Consume queues a delivery of event to every subscription wanting it,
+// once however often the outbox relay hands it over.
+func (s *WebhookServiceImpl) Consume(ctx context.Context, event models.Event) error {
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	now := s.clock.Now()
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if subscription.Wants(event.Type) {
+			deliveries = append(deliveries, models.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Payload:        models.RawJSON(payload),
+				Status:         models.DeliveryPending,
+				NextAttemptAt:  &now,
+			})
+		}
+	}
+	return s.repo.CreateDeliveries(ctx, deliveries)
+}
+
+// eventList returns the event types of events, without duplicates and in
+// the order of models.EventTypes.
+func eventList(events []models.EventType) models.EventList {
+	list := models.EventList{}
+	for _, t := range models.EventTypes {
+		if slices.Contains(events, t) {
+			list = append(list, t)
+		}
+	}
+	return list
+}
@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/repositories/mock"
+	"todo-api/internal/services"
+	"todo-api/pkg/clock"
+)
+
+func setupWebhookService(t *testing.T, now time.Time) (*mock.MockWebhookRepository, *services.WebhookServiceImpl) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockWebhookRepository(ctrl)
+	return repo, services.NewWebhookServiceImpl(repo, clock.NewFake(now), zaptest.NewLogger(t))
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	// Arrange
+	repo, service := setupWebhookService(t, time.Now())
+	repo.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).Return(nil)
+
+	// Act
+	subscription, err := service.CreateSubscription(context.Background(), &models.WebhookSubscription{
+		URL:    "https://hooks.example.com",
+		Secret: "0123456789abcdef",
+		Events: models.EventList{models.EventTaskDeleted, models.EventTaskCreated, models.EventTaskDeleted},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, models.EventList{models.EventTaskCreated, models.EventTaskDeleted}, subscription.Events)
+}
+
+func TestWebhookService_UpdateSubscription(t *testing.T) {
+	t.Run("subscribe to every event", func(t *testing.T) {
+		// Arrange
+		repo, service := setupWebhookService(t, time.Now())
+		repo.EXPECT().UpdateSubscription(gomock.Any(), uint(1), map[string]interface{}{"events": models.EventList{}}).Return(nil)
+		repo.EXPECT().GetSubscription(gomock.Any(), uint(1)).Return(&models.WebhookSubscription{ID: 1}, nil)
+
+		// Act
+		_, err := service.UpdateSubscription(context.Background(), 1, dto.UpdateWebhookServiceRequest{Events: []models.EventType{}})
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("no fields", func(t *testing.T) {
+		// Arrange
+		_, service := setupWebhookService(t, time.Now())
+
+		// Act
+		subscription, err := service.UpdateSubscription(context.Background(), 1, dto.UpdateWebhookServiceRequest{})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrValidation)
+		assert.Nil(t, subscription)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		// Arrange
+		repo, service := setupWebhookService(t, time.Now())
+		active := false
+		repo.EXPECT().UpdateSubscription(gomock.Any(), uint(9), gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+		// Act
+		_, err := service.UpdateSubscription(context.Background(), 9, dto.UpdateWebhookServiceRequest{Active: &active})
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "webhook 9 not found")
+	})
+}
+
+func TestWebhookService_Consume(t *testing.T) {
+	now := time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC)
+	event := models.Event{ID: "e1", Type: models.EventTaskCompleted, OccurredAt: now, Task: models.Task{Model: gorm.Model{ID: 7}}}
+
+	t.Run("queues a delivery per interested subscription", func(t *testing.T) {
+		// Arrange
+		repo, service := setupWebhookService(t, now)
+		repo.EXPECT().ListSubscriptions(gomock.Any()).Return([]models.WebhookSubscription{
+			{ID: 1, Active: true},
+			{ID: 2, Active: true, Events: models.EventList{models.EventTaskCreated}},
+			{ID: 3, Active: true, Events: models.EventList{models.EventTaskCompleted}},
+			{ID: 4, Active: false},
+		}, nil)
+		repo.EXPECT().CreateDeliveries(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, deliveries []models.WebhookDelivery) error {
+			require.Len(t, deliveries, 2)
+			assert.Equal(t, []uint{1, 3}, []uint{deliveries[0].SubscriptionID, deliveries[1].SubscriptionID})
+			assert.Equal(t, "e1", deliveries[0].EventID)
+			assert.Equal(t, models.EventTaskCompleted, deliveries[0].EventType)
+			assert.Contains(t, string(deliveries[0].Payload), `"type":"task.completed"`)
+			assert.Equal(t, now, *deliveries[0].NextAttemptAt)
+			return nil
+		})
+
+		// Act
+		err := service.Consume(context.Background(), event)
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("failure is returned for a retry", func(t *testing.T) {
+		// Arrange
+		repo, service := setupWebhookService(t, now)
+		repo.EXPECT().ListSubscriptions(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		// Act
+		err := service.Consume(context.Background(), event)
+
+		// Assert
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	now := time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("dead delivery", func(t *testing.T) {
+		// Arrange
+		repo, service := setupWebhookService(t, now)
+		repo.EXPECT().GetDelivery(gomock.Any(), uint(1), uint(5)).
+			Return(&models.WebhookDelivery{ID: 5, SubscriptionID: 1, Status: models.DeliveryDead, Attempts: 8}, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), uint(5), map[string]interface{}{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"last_error":      "",
+			"response_status": 0,
+			"delivered_at":    nil,
+		}).Return(nil)
+		repo.EXPECT().GetDelivery(gomock.Any(), uint(1), uint(5)).
+			Return(&models.WebhookDelivery{ID: 5, SubscriptionID: 1, Status: models.DeliveryPending}, nil)
+
+		// Act
+		delivery, err := service.Redeliver(context.Background(), 1, 5)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, models.DeliveryPending, delivery.Status)
+	})
+
+	t.Run("still pending", func(t *testing.T) {
+		// Arrange
+		repo, service := setupWebhookService(t, now)
+		repo.EXPECT().GetDelivery(gomock.Any(), uint(1), uint(5)).
+			Return(&models.WebhookDelivery{ID: 5, SubscriptionID: 1, Status: models.DeliveryPending}, nil)
+
+		// Act
+		_, err := service.Redeliver(context.Background(), 1, 5)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrConflict)
+	})
+
+	t.Run("of another webhook", func(t *testing.T) {
+		// Arrange
+		repo, service := setupWebhookService(t, now)
+		repo.EXPECT().GetDelivery(gomock.Any(), uint(2), uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+		// Act
+		_, err := service.Redeliver(context.Background(), 2, 5)
+
+		// Assert
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "delivery 5 of webhook 2 not found")
+	})
+}
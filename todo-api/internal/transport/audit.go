@@ -0,0 +1,74 @@
+package transport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"todo-api/internal/audit"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	actorHeader     = "X-Actor"
+
+	maxRequestIDLength = 128
+	maxActorLength     = 255
+)
+
+// requestInfo puts who made a request and its ID on the request context for
+// the task history. The ID is taken from X-Request-ID when it is usable and
+// generated otherwise, and is echoed back either way so clients and logs can
+// match a history entry to the request that made it. The actor is whatever
+// X-Actor says: the API has no authentication to check it against.
+func requestInfo() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		ctx.Header(requestIDHeader, requestID)
+
+		info := audit.Info{
+			Actor:     truncate(strings.TrimSpace(ctx.GetHeader(actorHeader)), maxActorLength),
+			RequestID: requestID,
+		}
+		ctx.Request = ctx.Request.WithContext(audit.WithInfo(ctx.Request.Context(), info))
+
+		ctx.Next()
+	}
+}
+
+// validRequestID reports whether a client's request ID is short printable
+// ASCII, so that it is safe to store and to echo in a header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// truncate cuts s to at most n bytes without splitting a rune.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var (
+	// legacyDeprecatedAt and legacySunset bound the life of the verb-style
+	// /tasks/* routes that predate /api/v1.
+	legacyDeprecatedAt = time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	legacySunset       = time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// deprecated marks responses of a legacy route with Deprecation (RFC 9745),
+// Sunset (RFC 8594) and a Link to its successor, and logs every use with a
+// running count so we can tell when the route is safe to remove. An ":id" in
+// successor is replaced with the request's id parameter.
+func deprecated(successor string, logger *zap.Logger) gin.HandlerFunc {
+	var uses atomic.Int64
+
+	return func(ctx *gin.Context) {
+		count := uses.Add(1)
+		successor := strings.ReplaceAll(successor, ":id", ctx.Param("id"))
+
+		ctx.Header("Deprecation", fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()))
+		ctx.Header("Sunset", legacySunset.Format(http.TimeFormat))
+		ctx.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+
+		logger.Warn("Deprecated route used",
+			zap.String("method", ctx.Request.Method),
+			zap.String("route", ctx.FullPath()),
+			zap.String("successor", successor),
+			zap.Int64("uses", count),
+			zap.String("user_agent", ctx.Request.UserAgent()),
+		)
+
+		ctx.Next()
+	}
+}
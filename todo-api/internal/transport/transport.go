@@ -13,20 +13,92 @@ import (
 	"todo-api/internal/controllers"
 )
 
-func SetupRouter(taskController *controllers.TaskController, logger *zap.Logger) *gin.Engine {
+func SetupRouter(
+	taskController *controllers.TaskController,
+	tagController *controllers.TagController,
+	projectController *controllers.ProjectController,
+	reminderController *controllers.ReminderController,
+	webhookController *controllers.WebhookController,
+	adminController *controllers.AdminController,
+	settingsController *controllers.SettingsController,
+	logger *zap.Logger,
+) *gin.Engine {
 	router := gin.New()
 
 	router.Use(ginzap.Ginzap(logger, time.RFC3339, true))
 
 	router.Use(ginzap.RecoveryWithZap(logger, true))
 
+	router.Use(requestInfo())
+
+	v1 := router.Group("/api/v1")
+	{
+		tasks := v1.Group("/tasks")
+		tasks.GET("", taskController.ListTasks)
+		tasks.POST("", taskController.CreateTask)
+		tasks.GET("/stream", taskController.StreamTasks)
+		tasks.GET("/stream/ws", taskController.StreamTasksWebSocket)
+		tasks.GET("/trash", taskController.ListTrash)
+		tasks.POST("/batch/create", taskController.CreateTasks)
+		tasks.POST("/batch/update", taskController.UpdateTasks)
+		tasks.POST("/batch/complete", taskController.CompleteTasks)
+		tasks.POST("/batch/delete", taskController.DeleteTasks)
+		tasks.GET("/:id", taskController.GetTaskByID)
+		tasks.PATCH("/:id", taskController.UpdateTask)
+		tasks.PUT("/:id", taskController.ReplaceTask)
+		tasks.DELETE("/:id", taskController.DeleteTask)
+		tasks.POST("/:id/move", taskController.MoveTask)
+		tasks.POST("/:id/restore", taskController.RestoreTask)
+		tasks.GET("/:id/history", taskController.ListTaskHistory)
+		tasks.POST("/:id/revert", taskController.RevertTask)
+		tasks.GET("/:id/subtasks", taskController.ListSubtasks)
+		tasks.GET("/:id/dependencies", taskController.ListDependencies)
+		tasks.PUT("/:id/dependencies/:blocker_id", taskController.AddDependency)
+		tasks.DELETE("/:id/dependencies/:blocker_id", taskController.RemoveDependency)
+		tasks.PATCH("/:id/occurrences/:date", taskController.UpdateOccurrence)
+		tasks.DELETE("/:id/occurrences/:date", taskController.DeleteOccurrence)
+		tasks.GET("/:id/reminders", reminderController.ListReminders)
+		tasks.POST("/:id/reminders", reminderController.CreateReminder)
+		tasks.DELETE("/:id/reminders/:reminder_id", reminderController.DeleteReminder)
+
+		tags := v1.Group("/tags")
+		tags.GET("", tagController.ListTags)
+		tags.POST("", tagController.CreateTag)
+		tags.GET("/:id", tagController.GetTag)
+		tags.PATCH("/:id", tagController.UpdateTag)
+		tags.DELETE("/:id", tagController.DeleteTag)
+
+		projects := v1.Group("/projects")
+		projects.GET("", projectController.ListProjects)
+		projects.POST("", projectController.CreateProject)
+		projects.GET("/:id", projectController.GetProject)
+		projects.PATCH("/:id", projectController.UpdateProject)
+		projects.DELETE("/:id", projectController.DeleteProject)
+
+		webhooks := v1.Group("/webhooks")
+		webhooks.GET("", webhookController.ListWebhooks)
+		webhooks.POST("", webhookController.CreateWebhook)
+		webhooks.GET("/:id", webhookController.GetWebhook)
+		webhooks.PATCH("/:id", webhookController.UpdateWebhook)
+		webhooks.DELETE("/:id", webhookController.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", webhookController.ListDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookController.Redeliver)
+
+		admin := v1.Group("/admin")
+		admin.GET("/outbox", adminController.OutboxStatus)
+
+		v1.GET("/settings", settingsController.GetSettings)
+		v1.PUT("/settings", settingsController.UpdateSettings)
+	}
+
+	// Deprecated: verb-style routes kept until legacySunset.
 	taskRoutes := router.Group("/tasks")
 	{
-		taskRoutes.POST("create", taskController.CreateTask)
-		taskRoutes.GET("/get/:id", taskController.GetTaskByID)
-		taskRoutes.PUT("/update/:id", taskController.UpdateTask)
-		taskRoutes.DELETE("/delete/:id", taskController.DeleteTask)
-		taskRoutes.GET("list", taskController.ListTasks)
+		taskRoutes.POST("create", deprecated("/api/v1/tasks", logger), taskController.CreateTask)
+		taskRoutes.GET("/get/:id", deprecated("/api/v1/tasks/:id", logger), taskController.GetTaskByID)
+		taskRoutes.PUT("/update/:id", deprecated("/api/v1/tasks/:id", logger), taskController.UpdateTask)
+		taskRoutes.DELETE("/delete/:id", deprecated("/api/v1/tasks/:id", logger), taskController.DeleteTask)
+		taskRoutes.GET("list", deprecated("/api/v1/tasks", logger), taskController.ListTasks)
 	}
 
 	router.GET("/health", func(c *gin.Context) {
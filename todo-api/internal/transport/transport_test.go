@@ -0,0 +1,160 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"todo-api/internal/audit"
+	"todo-api/internal/controllers"
+	"todo-api/internal/dto"
+	"todo-api/internal/models"
+	"todo-api/internal/services/mock"
+)
+
+func setupTestRouter(t *testing.T) (http.Handler, *mock.MockTaskService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockTaskService(ctrl)
+	logger := zaptest.NewLogger(t)
+	tagController := controllers.NewTagController(mock.NewMockTagService(ctrl), logger)
+	projectController := controllers.NewProjectController(mock.NewMockProjectService(ctrl), logger)
+	reminderController := controllers.NewReminderController(mock.NewMockReminderService(ctrl), logger)
+	webhookController := controllers.NewWebhookController(mock.NewMockWebhookService(ctrl), logger)
+	adminController := controllers.NewAdminController(mock.NewMockOutboxService(ctrl), logger)
+	settingsController := controllers.NewSettingsController(mock.NewMockSettingsService(ctrl), logger)
+	router := SetupRouter(controllers.NewTaskController(mockService, logger),
+		tagController, projectController, reminderController, webhookController, adminController, settingsController,
+		logger)
+	return router, mockService
+}
+
+func TestSetupRouter_V1Routes(t *testing.T) {
+	t.Run("delete returns 204", func(t *testing.T) {
+		// Arrange
+		router, mockService := setupTestRouter(t)
+		mockService.EXPECT().DeleteTask(gomock.Any(), uint(3), uint(0), false).Return(nil)
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/3", nil)
+
+		// Act
+		router.ServeHTTP(recorder, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Deprecation"))
+	})
+
+	t.Run("get is not deprecated", func(t *testing.T) {
+		// Arrange
+		router, mockService := setupTestRouter(t)
+		mockService.EXPECT().GetTaskByID(gomock.Any(), uint(3)).
+			Return(&models.Task{Model: gorm.Model{ID: 3}}, nil)
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/3", nil)
+
+		// Act
+		router.ServeHTTP(recorder, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Sunset"))
+	})
+}
+
+func TestSetupRouter_LegacyRoutes(t *testing.T) {
+	// Arrange
+	router, mockService := setupTestRouter(t)
+	mockService.EXPECT().GetTaskByID(gomock.Any(), uint(5)).
+		Return(&models.Task{Model: gorm.Model{ID: 5}}, nil)
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tasks/get/5", nil)
+
+	// Act
+	router.ServeHTTP(recorder, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "@1792108800", recorder.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", recorder.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/tasks/5>; rel="successor-version"`, recorder.Header().Get("Link"))
+}
+
+func TestSetupRouter_StreamRoute(t *testing.T) {
+	// Arrange
+	router, _ := setupTestRouter(t)
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/stream?parent_id=abc", nil)
+
+	// Act
+	router.ServeHTTP(recorder, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "the stream route is not taken for a task ID")
+	assert.Contains(t, recorder.Body.String(), "parent_id")
+}
+
+func TestSetupRouter_TrashRoute(t *testing.T) {
+	// Arrange
+	router, mockService := setupTestRouter(t)
+	mockService.EXPECT().ListTrash(gomock.Any(), gomock.Any()).Return(&dto.TaskPage{}, nil)
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/trash", nil)
+
+	// Act
+	router.ServeHTTP(recorder, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code, "the trash route is not taken for a task ID")
+}
+
+func TestSetupRouter_RequestInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		actor     string
+		echoed    func(t *testing.T, id string)
+	}{
+		{"client request ID", "req-42", " alice ", func(t *testing.T, id string) {
+			assert.Equal(t, "req-42", id)
+		}},
+		{"generated request ID", "", "", func(t *testing.T, id string) {
+			assert.Len(t, id, 32)
+		}},
+		{"unusable request ID", "bad id", "", func(t *testing.T, id string) {
+			assert.Len(t, id, 32, "an ID with spaces is replaced")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router, mockService := setupTestRouter(t)
+			mockService.EXPECT().
+				ListTaskHistory(gomock.Any(), uint(1), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ uint, _ dto.HistoryFilter) (*dto.HistoryPage, error) {
+					info := audit.FromContext(ctx)
+					assert.Equal(t, strings.TrimSpace(tt.actor), info.Actor)
+					tt.echoed(t, info.RequestID)
+					return &dto.HistoryPage{}, nil
+				})
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/1/history", nil)
+			req.Header.Set("X-Request-ID", tt.requestID)
+			req.Header.Set("X-Actor", tt.actor)
+
+			// Act
+			router.ServeHTTP(recorder, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			tt.echoed(t, recorder.Header().Get("X-Request-ID"))
+		})
+	}
+}
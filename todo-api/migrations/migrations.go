@@ -0,0 +1,27 @@
+// Package migrations embeds the versioned SQL migrations into the binary.
+//
+// Each supported driver has its own directory of files named
+// <version>_<name>.up.sql / <version>_<name>.down.sql. Both directories must
+// stay at the same latest version; a version with nothing to do on one
+// driver is left out of its directory. A released migration must never be
+// edited; add a new version instead.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Source returns the migrations for the given database driver.
+func Source(driver string) (fs.FS, error) {
+	switch driver {
+	case "postgres", "sqlite":
+		return fs.Sub(files, driver)
+	default:
+		return nil, fmt.Errorf("no migrations for driver %q", driver)
+	}
+}
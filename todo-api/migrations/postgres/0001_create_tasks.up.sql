@@ -0,0 +1,15 @@
+-- IF NOT EXISTS lets databases previously created by gorm AutoMigrate adopt
+-- this migration without changes.
+CREATE TABLE IF NOT EXISTS tasks (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    title       VARCHAR(255) NOT NULL,
+    description TEXT,
+    date        TIMESTAMPTZ NOT NULL,
+    completed   BOOLEAN DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_date ON tasks (date);
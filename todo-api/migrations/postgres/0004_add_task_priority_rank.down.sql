@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_date_rank;
+ALTER TABLE tasks DROP COLUMN rank;
+ALTER TABLE tasks DROP COLUMN priority;
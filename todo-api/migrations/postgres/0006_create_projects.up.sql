@@ -0,0 +1,13 @@
+CREATE TABLE projects (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    color       VARCHAR(7) NOT NULL DEFAULT '',
+    archived    BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+ALTER TABLE tasks ADD COLUMN project_id BIGINT REFERENCES projects (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_project_id ON tasks (project_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_due_at;
+ALTER TABLE tasks DROP COLUMN time_zone;
+ALTER TABLE tasks DROP COLUMN due_at;
+
+ALTER TABLE tasks ALTER COLUMN occurrence_date TYPE TIMESTAMPTZ USING occurrence_date::timestamp AT TIME ZONE 'UTC';
+ALTER TABLE tasks ALTER COLUMN date TYPE TIMESTAMPTZ USING date::timestamp AT TIME ZONE 'UTC';
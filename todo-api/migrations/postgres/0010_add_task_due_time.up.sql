@@ -0,0 +1,9 @@
+-- Task dates are calendar days. They were stored as midnight UTC, which the
+-- conversion keeps whatever the session time zone.
+ALTER TABLE tasks ALTER COLUMN date TYPE DATE USING (date AT TIME ZONE 'UTC')::date;
+ALTER TABLE tasks ALTER COLUMN occurrence_date TYPE DATE USING (occurrence_date AT TIME ZONE 'UTC')::date;
+
+ALTER TABLE tasks ADD COLUMN due_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_tasks_due_at ON tasks (due_at);
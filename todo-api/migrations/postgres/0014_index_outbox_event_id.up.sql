@@ -0,0 +1,2 @@
+-- Stream listeners read a published event by the ID they are notified of.
+CREATE UNIQUE INDEX idx_outbox_events_event_id ON outbox_events (event_id);
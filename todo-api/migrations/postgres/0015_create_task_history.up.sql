@@ -0,0 +1,13 @@
+CREATE TABLE task_history (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    task_id    BIGINT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    version    BIGINT NOT NULL,
+    action     VARCHAR(16) NOT NULL,
+    actor      VARCHAR(255) NOT NULL DEFAULT '',
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    changes    TEXT NOT NULL
+);
+
+-- The history of a task is read in the order it was written, from either end.
+CREATE INDEX idx_task_history_task_id ON task_history (task_id, id);
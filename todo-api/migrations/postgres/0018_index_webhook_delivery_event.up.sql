@@ -0,0 +1,5 @@
+-- The outbox relay may hand an event over more than once; webhook deliveries
+-- are queued once per event. The index came along with the outbox in 0013
+-- and now belongs to the deliveries, under a name of their own.
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+CREATE UNIQUE INDEX idx_webhook_deliveries_subscription_event ON webhook_deliveries (subscription_id, event_id);
@@ -0,0 +1,4 @@
+DELETE FROM task_history WHERE task_id NOT IN (SELECT id FROM tasks);
+ALTER TABLE task_history DROP CONSTRAINT IF EXISTS task_history_task_id_fkey;
+ALTER TABLE task_history
+    ADD CONSTRAINT task_history_task_id_fkey FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE;
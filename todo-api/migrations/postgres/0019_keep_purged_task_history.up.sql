@@ -0,0 +1,3 @@
+-- The history of a task outlives it: purging a task records its purge
+-- instead of deleting what came before.
+ALTER TABLE task_history DROP CONSTRAINT IF EXISTS task_history_task_id_fkey;
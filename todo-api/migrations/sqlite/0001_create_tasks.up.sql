@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS tasks (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME,
+    title       VARCHAR(255) NOT NULL,
+    description TEXT,
+    date        DATETIME NOT NULL,
+    completed   BOOLEAN DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_date ON tasks (date);
@@ -0,0 +1,3 @@
+-- SQLite has no tsvector; searches fall back to LIKE on title and
+-- description. Kept so both dialects stay at the same version.
+SELECT 1;
@@ -0,0 +1,7 @@
+-- priority: 0 none, 1 low, 2 medium, 3 high, 4 urgent.
+ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+
+-- rank orders tasks within a date; keys from pkg/lexorank compare bytewise.
+ALTER TABLE tasks ADD COLUMN rank TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_tasks_date_rank ON tasks (date, rank, id);
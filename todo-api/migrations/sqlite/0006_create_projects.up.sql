@@ -0,0 +1,13 @@
+CREATE TABLE projects (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    color       VARCHAR(7) NOT NULL DEFAULT '',
+    archived    BOOLEAN NOT NULL DEFAULT false
+);
+
+ALTER TABLE tasks ADD COLUMN project_id INTEGER REFERENCES projects (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_project_id ON tasks (project_id);
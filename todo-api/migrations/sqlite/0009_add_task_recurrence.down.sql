@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_series_occurrence;
+DROP INDEX IF EXISTS idx_tasks_series_id;
+ALTER TABLE tasks DROP COLUMN occurrence_date;
+ALTER TABLE tasks DROP COLUMN series_id;
+ALTER TABLE tasks DROP COLUMN ex_dates;
+ALTER TABLE tasks DROP COLUMN recurrence;
@@ -0,0 +1,7 @@
+ALTER TABLE tasks ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN ex_dates TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN series_id INTEGER REFERENCES tasks (id) ON DELETE CASCADE;
+ALTER TABLE tasks ADD COLUMN occurrence_date DATETIME;
+
+CREATE INDEX idx_tasks_series_id ON tasks (series_id);
+CREATE UNIQUE INDEX idx_tasks_series_occurrence ON tasks (series_id, occurrence_date) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN due_at DATETIME;
+ALTER TABLE tasks ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_tasks_due_at ON tasks (due_at);
@@ -0,0 +1,21 @@
+CREATE TABLE reminders (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at      DATETIME,
+    updated_at      DATETIME,
+    task_id         INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    channel         VARCHAR(16) NOT NULL,
+    email           VARCHAR(254) NOT NULL DEFAULT '',
+    remind_at       DATETIME,
+    before_minutes  INTEGER,
+    fire_at         DATETIME,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME,
+    last_error      TEXT NOT NULL DEFAULT '',
+    sent_at         DATETIME,
+    CHECK ((remind_at IS NULL) <> (before_minutes IS NULL))
+);
+
+CREATE INDEX idx_reminders_task_id ON reminders (task_id);
+-- The scheduler polls pending reminders by their next attempt.
+CREATE INDEX idx_reminders_next_attempt_at ON reminders (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,20 @@
+CREATE TABLE outbox_events (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at      DATETIME,
+    event_id        VARCHAR(64) NOT NULL,
+    type            VARCHAR(32) NOT NULL,
+    task_id         INTEGER NOT NULL,
+    payload         TEXT NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME NOT NULL,
+    last_error      TEXT NOT NULL DEFAULT '',
+    published_at    DATETIME
+);
+
+-- The relay reads the oldest pending event of every task.
+CREATE INDEX idx_outbox_events_pending ON outbox_events (task_id, id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_published_at ON outbox_events (published_at);
+
+-- The relay may hand an event over more than once; webhook deliveries are
+-- queued once per event.
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (subscription_id, event_id);
@@ -0,0 +1,2 @@
+UPDATE tasks SET date = date || 'T00:00:00Z' WHERE length(date) = 10;
+UPDATE tasks SET occurrence_date = occurrence_date || 'T00:00:00Z' WHERE length(occurrence_date) = 10;
@@ -0,0 +1,4 @@
+-- Task dates are stored as the day alone, "2006-01-02", the form they are
+-- compared with; earlier rows hold the text of a whole time at midnight UTC.
+UPDATE tasks SET date = substr(date, 1, 10) WHERE length(date) > 10;
+UPDATE tasks SET occurrence_date = substr(occurrence_date, 1, 10) WHERE length(occurrence_date) > 10;
@@ -0,0 +1,6 @@
+CREATE TABLE user_settings (
+    actor      VARCHAR(255) PRIMARY KEY,
+    created_at DATETIME,
+    updated_at DATETIME,
+    time_zone  VARCHAR(64) NOT NULL DEFAULT ''
+);
@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS task_history_old;
+CREATE TABLE task_history_old (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME NOT NULL,
+    task_id    INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    version    INTEGER NOT NULL,
+    action     VARCHAR(16) NOT NULL,
+    actor      VARCHAR(255) NOT NULL DEFAULT '',
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    changes    TEXT NOT NULL
+);
+INSERT INTO task_history_old (id, created_at, task_id, version, action, actor, request_id, changes)
+SELECT id, created_at, task_id, version, action, actor, request_id, changes FROM task_history
+WHERE task_id IN (SELECT id FROM tasks);
+DROP TABLE task_history;
+ALTER TABLE task_history_old RENAME TO task_history;
+
+CREATE INDEX idx_task_history_task_id ON task_history (task_id, id);
@@ -0,0 +1,21 @@
+-- The history of a task outlives it: purging a task records its purge
+-- instead of deleting what came before. SQLite cannot drop a foreign key,
+-- so the table is rebuilt without it.
+DROP TABLE IF EXISTS task_history_new;
+CREATE TABLE task_history_new (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME NOT NULL,
+    task_id    INTEGER NOT NULL,
+    version    INTEGER NOT NULL,
+    action     VARCHAR(16) NOT NULL,
+    actor      VARCHAR(255) NOT NULL DEFAULT '',
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    changes    TEXT NOT NULL
+);
+INSERT INTO task_history_new (id, created_at, task_id, version, action, actor, request_id, changes)
+SELECT id, created_at, task_id, version, action, actor, request_id, changes FROM task_history;
+DROP TABLE task_history;
+ALTER TABLE task_history_new RENAME TO task_history;
+
+-- The history of a task is read in the order it was written, from either end.
+CREATE INDEX idx_task_history_task_id ON task_history (task_id, id);
@@ -1,19 +1,116 @@
 package config
 
 import (
+	"time"
+
 	"github.com/caarlos0/env/v11"
 	"github.com/go-playground/validator/v10"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
 type Config struct {
 	Port string `env:"APP_PORT" envDefault:"8081"`
+	// RequireIfMatch rejects task writes without an If-Match header (428).
+	RequireIfMatch bool `env:"REQUIRE_IF_MATCH" envDefault:"false"`
+	// ListMaxLimit caps the page size of list endpoints.
+	ListMaxLimit int `env:"LIST_MAX_LIMIT" envDefault:"100" validate:"min=1"`
+	// BatchMaxSize caps how many tasks one batch request can create or
+	// select.
+	BatchMaxSize int `env:"BATCH_MAX_SIZE" envDefault:"500" validate:"min=1"`
+	// SubtaskMaxDepth limits how deep subtasks can be nested.
+	SubtaskMaxDepth int `env:"SUBTASK_MAX_DEPTH" envDefault:"3" validate:"min=1"`
+	// SubtaskCompletion decides what completing a task does to its open
+	// subtasks: independent, cascade or block.
+	SubtaskCompletion string `env:"SUBTASK_COMPLETION" envDefault:"independent" validate:"oneof=independent cascade block"`
+	// TimeZone is the zone of requests that name none, used to tell which
+	// day "today" is and to place due times.
+	TimeZone *time.Location `env:"TIME_ZONE" envDefault:"UTC"`
+
+	Reminders struct {
+		// PollInterval is how often the scheduler looks for due reminders.
+		PollInterval time.Duration `env:"REMINDER_POLL_INTERVAL" envDefault:"15s" validate:"min=1s"`
+		// MaxAttempts is how many times a reminder is tried before it is
+		// marked failed.
+		MaxAttempts int `env:"REMINDER_MAX_ATTEMPTS" envDefault:"5" validate:"min=1"`
+		// RetryBackoff is the wait before the first retry, doubled for every
+		// next one up to an hour.
+		RetryBackoff time.Duration `env:"REMINDER_RETRY_BACKOFF" envDefault:"1m" validate:"min=1s"`
+		// WebhookURL enables the webhook channel, which POSTs reminders there.
+		WebhookURL string `env:"REMINDER_WEBHOOK_URL" validate:"omitempty,url"`
+	}
+
+	Webhooks struct {
+		// PollInterval is how often the dispatcher looks for due deliveries.
+		PollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"5s" validate:"min=1s"`
+		// MaxAttempts is how many times a delivery is tried before it is
+		// dead.
+		MaxAttempts int `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8" validate:"min=1"`
+		// RetryBackoff is the wait before the first retry, doubled for every
+		// next one up to an hour.
+		RetryBackoff time.Duration `env:"WEBHOOK_RETRY_BACKOFF" envDefault:"30s" validate:"min=1s"`
+		// Timeout bounds one delivery attempt.
+		Timeout time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s" validate:"min=1s"`
+	}
+
+	Outbox struct {
+		// PollInterval is how often the relay looks for unpublished events.
+		PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s" validate:"min=100ms"`
+		// RetryBackoff is the wait before an event is handed to the consumers
+		// again after one failed, doubled for every next try up to an hour.
+		RetryBackoff time.Duration `env:"OUTBOX_RETRY_BACKOFF" envDefault:"5s" validate:"min=1s"`
+		// Retention is how long published events are kept; 0 keeps them.
+		Retention time.Duration `env:"OUTBOX_RETENTION" envDefault:"168h" validate:"min=0"`
+	}
+
+	Trash struct {
+		// RetentionDays is how long deleted tasks stay in the trash before
+		// they are purged; 0 keeps them until deleted with permanent=true.
+		RetentionDays int `env:"TRASH_RETENTION_DAYS" envDefault:"30" validate:"min=0"`
+		// PurgeInterval is how often the trash is checked for expired tasks.
+		PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" envDefault:"1h" validate:"min=1s"`
+	}
+
+	Stream struct {
+		// Backlog is how many of the latest task events are kept for clients
+		// resuming a stream with Last-Event-ID, and how many a resuming client
+		// is sent from the outbox at most.
+		Backlog int `env:"STREAM_BACKLOG" envDefault:"1000" validate:"min=1"`
+		// Heartbeat is how long a stream stays idle before a heartbeat is
+		// sent, so that proxies keep it open.
+		Heartbeat time.Duration `env:"STREAM_HEARTBEAT" envDefault:"15s" validate:"min=1s"`
+		// AllowedOrigins lists the origins, besides the API's own, whose
+		// pages may open the WebSocket stream; "*" allows all.
+		AllowedOrigins []string `env:"STREAM_ALLOWED_ORIGINS"`
+	}
+
+	// SMTP enables the email channel when Host is set.
+	SMTP struct {
+		Host     string `env:"SMTP_HOST"`
+		Port     int    `env:"SMTP_PORT" envDefault:"587"`
+		Username string `env:"SMTP_USERNAME"`
+		Password string `env:"SMTP_PASSWORD"`
+		From     string `env:"SMTP_FROM" envDefault:"todo@localhost"`
+	}
 
 	DB struct {
+		// Driver selects the storage backend: postgres, sqlite or memory.
+		// memory keeps everything in the process and needs no migrations.
+		Driver   string `env:"DB_DRIVER" envDefault:"postgres" validate:"oneof=postgres sqlite memory"`
 		Host     string `env:"DB_HOST" envDefault:"db"`
 		Port     int    `env:"DB_PORT" envDefault:"5432"`
 		User     string `env:"DB_USER" envDefault:"postgres"`
 		Password string `env:"DB_PASSWORD" envDefault:"postgres"`
 		Name     string `env:"DB_NAME" envDefault:"todo"`
+		// Path is the SQLite database file, ":memory:" for a throwaway database.
+		Path string `env:"DB_PATH" envDefault:"todo.db"`
+		// AutoMigrate applies pending migrations on startup instead of
+		// refusing to serve. Meant for local runs and CI, not production.
+		AutoMigrate bool `env:"DB_AUTO_MIGRATE" envDefault:"false"`
 	}
 }
 
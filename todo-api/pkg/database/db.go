@@ -1,18 +1,80 @@
 package database
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"strings"
 
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"todo-api/pkg/clock"
 	"todo-api/pkg/config"
 )
 
-func Connect(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
-		cfg.DB.Host, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.Port,
-	)
-	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func init() {
+	// The built-in lower() of SQLite folds ASCII letters only, so that a
+	// search for "отчёт" would miss "Отчёт". This one folds every letter,
+	// like lower() on Postgres.
+	gosqlite.MustRegisterDeterministicScalarFunction("lower", 1, unicodeLower)
+}
+
+func unicodeLower(_ *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	switch v := args[0].(type) {
+	case string:
+		return strings.ToLower(v), nil
+	case []byte:
+		return strings.ToLower(string(v)), nil
+	default:
+		return v, nil
+	}
+}
+
+// Connect opens the database cfg names. Gorm takes the time it stamps rows
+// with, and the repositories the time of events and history, from clk.
+func Connect(cfg *config.Config, clk clock.Clock) (*gorm.DB, error) {
+	switch cfg.DB.Driver {
+	case config.DriverPostgres:
+		dsn := fmt.Sprintf(
+			"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
+			cfg.DB.Host, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.Port,
+		)
+		return gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true, NowFunc: clk.Now})
+	case config.DriverSQLite:
+		return ConnectSQLite(cfg.DB.Path, clk)
+	default:
+		return nil, fmt.Errorf("driver %q does not use a database connection", cfg.DB.Driver)
+	}
+}
+
+// ConnectSQLite opens the SQLite database at path with foreign keys enabled.
+// An in-memory database is pinned to a single connection, since every new
+// connection would otherwise see its own empty database.
+func ConnectSQLite(path string, clk clock.Clock) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(path)), &gorm.Config{TranslateError: true, NowFunc: clk.Now})
+	if err != nil {
+		return nil, err
+	}
+
+	if path == ":memory:" {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	return db, nil
+}
+
+// sqliteDSN adds the connection pragmas to path, keeping any query
+// parameters it already has, such as "todo.db?mode=ro".
+func sqliteDSN(path string) string {
+	const pragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	if strings.Contains(path, "?") {
+		return path + "&" + pragmas
+	}
+	return path + "?" + pragmas
 }
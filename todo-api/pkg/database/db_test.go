@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "file", path: "todo.db", want: "todo.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"},
+		{name: "memory", path: ":memory:", want: ":memory:?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"},
+		{
+			name: "existing parameters",
+			path: "file:todo.db?mode=rwc",
+			want: "file:todo.db?mode=rwc&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := sqliteDSN(tt.path)
+
+			// Assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
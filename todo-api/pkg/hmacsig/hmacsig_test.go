@@ -0,0 +1,52 @@
+package hmacsig_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"todo-api/pkg/hmacsig"
+)
+
+func TestSign(t *testing.T) {
+	// Act
+	signature := hmacsig.Sign("secret", time.Unix(1700000000, 0), []byte(`{"id":"1"}`))
+
+	// Assert
+	assert.Equal(t, "sha256=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54", signature)
+}
+
+func TestVerify(t *testing.T) {
+	sent := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"1"}`)
+	signature := hmacsig.Sign("secret", sent, body)
+	timestamp := strconv.FormatInt(sent.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      string
+		now       time.Time
+		want      error
+	}{
+		{name: "valid", secret: "secret", signature: signature, timestamp: timestamp, body: string(body), now: sent.Add(time.Minute)},
+		{name: "wrong secret", secret: "other", signature: signature, timestamp: timestamp, body: string(body), now: sent, want: hmacsig.ErrInvalidSignature},
+		{name: "tampered body", secret: "secret", signature: signature, timestamp: timestamp, body: `{"id":"2"}`, now: sent, want: hmacsig.ErrInvalidSignature},
+		{name: "other timestamp", secret: "secret", signature: signature, timestamp: "1700000001", body: string(body), now: sent, want: hmacsig.ErrInvalidSignature},
+		{name: "missing prefix", secret: "secret", signature: signature[len("sha256="):], timestamp: timestamp, body: string(body), now: sent, want: hmacsig.ErrInvalidSignature},
+		{name: "replayed", secret: "secret", signature: signature, timestamp: timestamp, body: string(body), now: sent.Add(time.Hour), want: hmacsig.ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := hmacsig.Verify(tt.secret, tt.signature, tt.timestamp, []byte(tt.body), tt.now, 5*time.Minute)
+
+			// Assert
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package jsonpatch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"todo-api/pkg/jsonpatch"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc, patch string
+		want       string
+	}{
+		{name: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "null removes", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "arrays are replaced", doc: `{"a":["b"]}`, patch: `{"a":["c","d"]}`, want: `{"a":["c","d"]}`},
+		{name: "nested objects merge", doc: `{"a":{"b":"c","d":"e"}}`, patch: `{"a":{"d":null,"f":1}}`, want: `{"a":{"b":"c","f":1}}`},
+		{name: "non-object patch replaces", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "large integers survive", doc: `{"id":9007199254740993}`, patch: `{}`, want: `{"id":9007199254740993}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := jsonpatch.MergePatch([]byte(tt.doc), []byte(tt.patch))
+
+			// Assert
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("invalid patch", func(t *testing.T) {
+		// Act
+		_, err := jsonpatch.MergePatch([]byte(`{}`), []byte(`{"a":`))
+
+		// Assert
+		assert.ErrorIs(t, err, jsonpatch.ErrInvalid)
+	})
+}
+
+func TestApply(t *testing.T) {
+	doc := `{"title":"Task","tags":["home","work"],"project_id":3,"due":{"time":"18:30"}}`
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "add member",
+			patch: `[{"op":"add","path":"/description","value":"Details"}]`,
+			want:  `{"title":"Task","description":"Details","tags":["home","work"],"project_id":3,"due":{"time":"18:30"}}`,
+		},
+		{
+			name:  "add to array",
+			patch: `[{"op":"add","path":"/tags/1","value":"urgent"},{"op":"add","path":"/tags/-","value":"zzz"}]`,
+			want:  `{"title":"Task","tags":["home","urgent","work","zzz"],"project_id":3,"due":{"time":"18:30"}}`,
+		},
+		{
+			name:  "remove",
+			patch: `[{"op":"remove","path":"/tags/0"},{"op":"remove","path":"/project_id"}]`,
+			want:  `{"title":"Task","tags":["work"],"due":{"time":"18:30"}}`,
+		},
+		{
+			name:  "replace with null",
+			patch: `[{"op":"replace","path":"/project_id","value":null}]`,
+			want:  `{"title":"Task","tags":["home","work"],"project_id":null,"due":{"time":"18:30"}}`,
+		},
+		{
+			name:  "move and copy",
+			patch: `[{"op":"move","path":"/time","from":"/due/time"},{"op":"copy","path":"/tags/-","from":"/title"}]`,
+			want:  `{"title":"Task","tags":["home","work","Task"],"project_id":3,"due":{},"time":"18:30"}`,
+		},
+		{
+			name:  "escaped pointer",
+			patch: `[{"op":"add","path":"/a~1b~0c","value":1}]`,
+			want:  `{"title":"Task","tags":["home","work"],"project_id":3,"due":{"time":"18:30"},"a/b~c":1}`,
+		},
+		{
+			name: "passing tests",
+			patch: `[{"op":"test","path":"/project_id","value":3.0},` +
+				`{"op":"test","path":"/due","value":{"time":"18:30"}},` +
+				`{"op":"replace","path":"/title","value":"Renamed"}]`,
+			want: `{"title":"Renamed","tags":["home","work"],"project_id":3,"due":{"time":"18:30"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := jsonpatch.Apply([]byte(doc), []byte(tt.patch))
+
+			// Assert
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	failures := []struct {
+		name  string
+		patch string
+		want  error
+	}{
+		{name: "failed test", patch: `[{"op":"test","path":"/title","value":"Other"}]`, want: jsonpatch.ErrConflict},
+		{name: "missing member", patch: `[{"op":"replace","path":"/description","value":"x"}]`, want: jsonpatch.ErrConflict},
+		{name: "index out of range", patch: `[{"op":"remove","path":"/tags/2"}]`, want: jsonpatch.ErrConflict},
+		{name: "unknown op", patch: `[{"op":"merge","path":"/title","value":"x"}]`, want: jsonpatch.ErrInvalid},
+		{name: "missing value", patch: `[{"op":"add","path":"/title"}]`, want: jsonpatch.ErrInvalid},
+		{name: "relative path", patch: `[{"op":"remove","path":"title"}]`, want: jsonpatch.ErrInvalid},
+		{name: "not an array", patch: `{"op":"remove","path":"/title"}`, want: jsonpatch.ErrInvalid},
+		{name: "move into itself", patch: `[{"op":"move","path":"/due/time/x","from":"/due"}]`, want: jsonpatch.ErrInvalid},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := jsonpatch.Apply([]byte(doc), []byte(tt.patch))
+
+			// Assert
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	t.Run("all or nothing", func(t *testing.T) {
+		// Act
+		got, err := jsonpatch.Apply([]byte(doc), []byte(
+			`[{"op":"replace","path":"/title","value":"Renamed"},{"op":"test","path":"/project_id","value":4}]`))
+
+		// Assert
+		assert.ErrorIs(t, err, jsonpatch.ErrConflict)
+		assert.Nil(t, got)
+	})
+}
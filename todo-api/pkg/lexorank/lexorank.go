@@ -0,0 +1,111 @@
+// Package lexorank generates string keys that sort between two other keys,
+// so an item can be moved by rewriting only its own key.
+//
+// Keys use the digits 0-9a-z, compare bytewise and never end in '0'. The
+// empty string stands for "no bound": the start of the list as a lower
+// bound and its end as an upper bound.
+package lexorank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var ErrInvalidRange = errors.New("lexorank: lower bound must sort before upper bound")
+
+// Between returns a key strictly between lo and hi. Either may be empty.
+func Between(lo, hi string) (string, error) {
+	if hi != "" && lo >= hi {
+		return "", ErrInvalidRange
+	}
+	if !valid(lo) || !valid(hi) {
+		return "", errors.New("lexorank: malformed key")
+	}
+	return midpoint(lo, hi), nil
+}
+
+// midpoint implements Between for valid, ordered keys.
+func midpoint(lo, hi string) string {
+	if hi != "" {
+		// Keep the common prefix and split the rest.
+		n := 0
+		for n < len(hi) && digitAt(lo, n) == hi[n] {
+			n++
+		}
+		if n > 0 {
+			return hi[:n] + midpoint(tail(lo, n), hi[n:])
+		}
+	}
+
+	digitLo := 0
+	if lo != "" {
+		digitLo = strings.IndexByte(digits, lo[0])
+	}
+	digitHi := base
+	if hi != "" {
+		digitHi = strings.IndexByte(digits, hi[0])
+	}
+
+	if digitHi-digitLo > 1 {
+		return string(digits[(digitLo+digitHi+1)/2])
+	}
+	// The first digits are adjacent: a longer hi can be cut short, otherwise
+	// keep lo's digit and go one level deeper.
+	if len(hi) > 1 {
+		return hi[:1]
+	}
+	return string(digits[digitLo]) + midpoint(tail(lo, 1), "")
+}
+
+// Spread returns n ascending keys evenly spaced over the whole key space,
+// for renumbering a list whose keys ran into each other.
+func Spread(n int) []string {
+	width, space := 1, base
+	for space <= n {
+		width++
+		space *= base
+	}
+
+	keys := make([]string, n)
+	step := space / (n + 1)
+	for i := range keys {
+		keys[i] = strings.TrimRight(format((i+1)*step, width), "0")
+	}
+	return keys
+}
+
+func format(value, width int) string {
+	b := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		b[i] = digits[value%base]
+		value /= base
+	}
+	return string(b)
+}
+
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return digits[0]
+}
+
+func tail(key string, n int) string {
+	if n < len(key) {
+		return key[n:]
+	}
+	return ""
+}
+
+func valid(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(key, "0")
+}
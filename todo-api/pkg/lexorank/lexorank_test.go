@@ -0,0 +1,90 @@
+package lexorank_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"todo-api/pkg/lexorank"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name   string
+		lo, hi string
+		want   string
+	}{
+		{name: "empty list", want: "i"},
+		{name: "append", lo: "i", want: "r"},
+		{name: "prepend", hi: "i", want: "9"},
+		{name: "adjacent digits", lo: "a", hi: "b", want: "ai"},
+		{name: "shorter upper bound", lo: "a", hi: "b5", want: "b"},
+		{name: "common prefix", lo: "a1", hi: "a3", want: "a2"},
+		{name: "after last digit", lo: "z", want: "zi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := lexorank.Between(tt.lo, tt.hi)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		// Act
+		_, equalErr := lexorank.Between("a", "a")
+		_, reversedErr := lexorank.Between("b", "a")
+		_, malformedErr := lexorank.Between("a0", "")
+
+		// Assert
+		assert.ErrorIs(t, equalErr, lexorank.ErrInvalidRange)
+		assert.ErrorIs(t, reversedErr, lexorank.ErrInvalidRange)
+		assert.Error(t, malformedErr)
+	})
+
+	t.Run("repeated inserts stay ordered", func(t *testing.T) {
+		// Arrange
+		rng := rand.New(rand.NewSource(1))
+		keys := []string{}
+
+		// Act
+		for i := 0; i < 500; i++ {
+			at := rng.Intn(len(keys) + 1)
+			lo, hi := "", ""
+			if at > 0 {
+				lo = keys[at-1]
+			}
+			if at < len(keys) {
+				hi = keys[at]
+			}
+			key, err := lexorank.Between(lo, hi)
+			require.NoError(t, err)
+			keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+		}
+
+		// Assert
+		assert.True(t, sort.StringsAreSorted(keys))
+	})
+}
+
+func TestSpread(t *testing.T) {
+	// Act
+	keys := lexorank.Spread(100)
+
+	// Assert
+	require.Len(t, keys, 100)
+	assert.True(t, sort.StringsAreSorted(keys))
+	for i, key := range keys {
+		assert.NotEmpty(t, key)
+		assert.NotEqual(t, byte('0'), key[len(key)-1])
+		if i > 0 {
+			assert.NotEqual(t, keys[i-1], key)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package migrator_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"todo-api/migrations"
+	"todo-api/pkg/migrator"
+)
+
+func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       db,
+		DriverName: "postgres",
+	})
+	gormDB, err := gorm.Open(dialector, &gorm.Config{})
+	assert.NoError(t, err)
+
+	return gormDB, mock
+}
+
+func testSource() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT)")},
+		"0001_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+		"0002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT)")},
+		"0002_create_b.down.sql": {Data: []byte("DROP TABLE b")},
+		"README.md":              {Data: []byte("ignored")},
+	}
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...uint) {
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, v := range versions {
+		rows.AddRow(v, time.Now())
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+		WillReturnRows(rows)
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("sorted by version", func(t *testing.T) {
+		// Act
+		list, err := migrator.Load(testSource())
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, uint(1), list[0].Version)
+		assert.Equal(t, "create_a", list[0].Name)
+		assert.Equal(t, "DROP TABLE a", list[0].Down)
+		assert.Equal(t, uint(2), list[1].Version)
+	})
+
+	t.Run("missing down file", func(t *testing.T) {
+		// Arrange
+		source := testSource()
+		delete(source, "0002_create_b.down.sql")
+
+		// Act
+		_, err := migrator.Load(source)
+
+		// Assert
+		assert.ErrorContains(t, err, "must have both up and down files")
+	})
+
+	t.Run("embedded migrations are valid and in step", func(t *testing.T) {
+		// Arrange
+		pgSource, err := migrations.Source("postgres")
+		require.NoError(t, err)
+		sqliteSource, err := migrations.Source("sqlite")
+		require.NoError(t, err)
+
+		// Act
+		pg, err := migrator.Load(pgSource)
+		require.NoError(t, err)
+		lite, err := migrator.Load(sqliteSource)
+		require.NoError(t, err)
+
+		// Assert
+		require.NotEmpty(t, pg)
+		require.NotEmpty(t, lite)
+		assert.Equal(t, pg[len(pg)-1].Version, lite[len(lite)-1].Version)
+		names := make(map[uint]string, len(lite))
+		for _, migration := range lite {
+			names[migration.Version] = migration.Name
+		}
+		for _, migration := range pg {
+			if name, ok := names[migration.Version]; ok {
+				assert.Equal(t, name, migration.Name, "version %d", migration.Version)
+			}
+		}
+	})
+}
+
+func TestMigrator_Up(t *testing.T) {
+	// Arrange
+	gormDB, mock := setupMockDB(t)
+	m, err := migrator.New(gormDB, testSource())
+	require.NoError(t, err)
+
+	expectApplied(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b (id INT)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)")).
+		WithArgs(2, "create_b", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	applied, err := m.Up(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, uint(2), applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	// Arrange
+	gormDB, mock := setupMockDB(t)
+	m, err := migrator.New(gormDB, testSource())
+	require.NoError(t, err)
+
+	expectApplied(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE b")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	reverted, err := m.Down(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	require.NotNil(t, reverted)
+	assert.Equal(t, uint(2), reverted.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_To(t *testing.T) {
+	t.Run("unknown version", func(t *testing.T) {
+		// Arrange
+		gormDB, _ := setupMockDB(t)
+		m, err := migrator.New(gormDB, testSource())
+		require.NoError(t, err)
+
+		// Act
+		_, err = m.To(context.Background(), 7)
+
+		// Assert
+		assert.ErrorIs(t, err, migrator.ErrUnknownVersion)
+	})
+
+	t.Run("failed migration is rolled back", func(t *testing.T) {
+		// Arrange
+		gormDB, mock := setupMockDB(t)
+		m, err := migrator.New(gormDB, testSource())
+		require.NoError(t, err)
+
+		expectApplied(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a (id INT)")).
+			WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+
+		// Act
+		done, err := m.To(context.Background(), 2)
+
+		// Assert
+		assert.ErrorContains(t, err, "failed to apply migration 1_create_a")
+		assert.Empty(t, done)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Check(t *testing.T) {
+	t.Run("schema behind", func(t *testing.T) {
+		// Arrange
+		gormDB, mock := setupMockDB(t)
+		m, err := migrator.New(gormDB, testSource())
+		require.NoError(t, err)
+		expectApplied(mock, 1)
+
+		// Act
+		err = m.Check(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, migrator.ErrSchemaOutdated)
+	})
+
+	t.Run("schema up to date", func(t *testing.T) {
+		// Arrange
+		gormDB, mock := setupMockDB(t)
+		m, err := migrator.New(gormDB, testSource())
+		require.NoError(t, err)
+		expectApplied(mock, 1, 2)
+
+		// Act
+		err = m.Check(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}